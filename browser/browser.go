@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Typed package-level errors.
+var (
+	// ErrInvalidArgument indicates a caller-provided input was invalid.
+	ErrInvalidArgument = errors.New("browser: invalid argument")
+	// ErrInvalidURL indicates a URL could not be parsed or is not absolute.
+	ErrInvalidURL = errors.New("browser: invalid url")
+	// ErrBrowserNotFound indicates the requested browser is not installed.
+	ErrBrowserNotFound = errors.New("browser: browser not found")
+	// ErrUnsupported indicates the operation is unsupported on this platform.
+	ErrUnsupported = errors.New("browser: unsupported")
+	// ErrSessionClosed indicates the connection to the browser is gone,
+	// usually because the browser exited or the session was closed.
+	ErrSessionClosed = errors.New("browser: session closed")
+	// ErrProtocol indicates the browser rejected a DevTools or WebDriver
+	// command.
+	ErrProtocol = errors.New("browser: protocol error")
+	// ErrNavigation indicates a page failed to load.
+	ErrNavigation = errors.New("browser: navigation failed")
+	// ErrStaleRef indicates an element ref no longer resolves, because the
+	// element was removed or the page navigated.
+	ErrStaleRef = errors.New("browser: stale element ref")
+	// ErrScript indicates JavaScript run in the page failed to compile or
+	// threw an exception.
+	ErrScript = errors.New("browser: script error")
+	// ErrNotInteractable indicates an element is hidden, disabled, covered,
+	// or otherwise cannot receive the requested input.
+	ErrNotInteractable = errors.New("browser: element not interactable")
+	// ErrNotFound indicates a selector matched no visible element.
+	ErrNotFound = errors.New("browser: element not found")
+	// ErrAmbiguous indicates a selector used to address an action matched
+	// more than one visible element.
+	ErrAmbiguous = errors.New("browser: ambiguous selector")
+	// ErrVerificationFailed indicates an action ran but reading the page
+	// back showed it did not take effect.
+	ErrVerificationFailed = errors.New("browser: verification failed")
+	// ErrDownloadFailed indicates the browser cancelled or failed a
+	// download, or the file could not be saved.
+	ErrDownloadFailed = errors.New("browser: download failed")
+	// ErrProfileInUse indicates a profile is held by another browser
+	// process. Chrome allows one process per profile.
+	ErrProfileInUse = errors.New("browser: profile in use")
+)
+
+// OpError captures operation-level failures with typed causes.
+type OpError struct {
+	Op  string
+	ID  string
+	Err error
+}
+
+func (e *OpError) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.ID != "" {
+		return fmt.Sprintf("browser: %s (%s): %v", e.Op, e.ID, e.Err)
+	}
+	return fmt.Sprintf("browser: %s: %v", e.Op, e.Err)
+}
+
+func (e *OpError) Unwrap() error { return e.Err }
+
+func newInvalidArg(op, id, msg string) error {
+	return &OpError{Op: op, ID: id, Err: fmt.Errorf("%w: %s", ErrInvalidArgument, msg)}
+}
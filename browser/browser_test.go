@@ -0,0 +1,101 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func TestOpenCommand(t *testing.T) {
+	cases := []struct {
+		goos  string
+		input OpenURLInput
+		name  string
+		args  []string
+	}{
+		{"darwin", OpenURLInput{URL: "https://example.com"}, "open", []string{"https://example.com"}},
+		{"darwin", OpenURLInput{URL: "https://example.com", Browser: "Safari", Background: true}, "open", []string{"-g", "-a", "Safari", "https://example.com"}},
+		{"linux", OpenURLInput{URL: "https://example.com"}, "xdg-open", []string{"https://example.com"}},
+		{"linux", OpenURLInput{URL: "https://example.com", Browser: "firefox"}, "firefox", []string{"https://example.com"}},
+		{"windows", OpenURLInput{URL: "https://example.com/?a=1&b=2"}, "cmd", []string{"/c", "start", "", "https://example.com/?a=1^&b=2"}},
+		{"windows", OpenURLInput{URL: "https://example.com", Browser: "chrome"}, "cmd", []string{"/c", "start", "", "chrome", "https://example.com"}},
+		{"darwin", OpenURLInput{URL: "mailto:someone@example.com"}, "open", []string{"mailto:someone@example.com"}},
+		{"darwin", OpenURLInput{URL: "https://example.com", Browser: "Google Chrome", Profile: "Profile 2", Background: true}, "open", []string{"-g", "-n", "-a", "Google Chrome", "--args", "--profile-directory=Profile 2", "https://example.com"}},
+		{"darwin", OpenURLInput{URL: "https://example.com", Browser: "Microsoft Edge", Private: true}, "open", []string{"-n", "-a", "Microsoft Edge", "--args", "--inprivate", "https://example.com"}},
+		{"darwin", OpenURLInput{URL: "https://example.com", Browser: "Firefox", Profile: "work", Private: true}, "open", []string{"-n", "-a", "Firefox", "--args", "-P", "work", "-private-window", "https://example.com"}},
+		{"linux", OpenURLInput{URL: "https://example.com", Browser: "/usr/bin/chromium", Private: true}, "/usr/bin/chromium", []string{"--incognito", "https://example.com"}},
+		{"windows", OpenURLInput{URL: "https://example.com", Browser: "brave.exe", Profile: "Default", Private: true}, "cmd", []string{"/c", "start", "", "brave.exe", "--profile-directory=Default", "--incognito", "https://example.com"}},
+	}
+	for _, c := range cases {
+		name, args, err := openCommand(c.goos, c.input)
+		be.Err(t, err, nil)
+		be.Equal(t, name, c.name)
+		be.Equal(t, args, c.args)
+	}
+}
+
+func TestOpenCommandErrors(t *testing.T) {
+	for _, raw := range []string{"example.com", "/tmp/x.html", "https://", "://bad", "-a"} {
+		_, _, err := openCommand("darwin", OpenURLInput{URL: raw})
+		be.True(t, errors.Is(err, ErrInvalidURL))
+	}
+	_, _, err := openCommand("darwin", OpenURLInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, _, err = openCommand("linux", OpenURLInput{URL: "https://example.com", Background: true})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, _, err = openCommand("darwin", OpenURLInput{URL: "https://example.com", Private: true})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, _, err = openCommand("linux", OpenURLInput{URL: "https://example.com", Browser: "firefox", Profile: "-new-instance"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, _, err = openCommand("darwin", OpenURLInput{URL: "https://example.com", Browser: "Safari", Private: true})
+	be.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestOpenURLBrowserNotFound(t *testing.T) {
+	err := OpenURL(context.Background(), OpenURLInput{URL: "https://example.com", Browser: "cuh-no-such-browser"})
+	be.Err(t, err)
+	var opErr *OpError
+	be.True(t, errors.As(err, &opErr))
+	be.Equal(t, opErr.Op, "OpenURL")
+}
+
+func TestOpenURLDirectBrowserDetached(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("browsers are started through an opener on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "pid")
+	script := filepath.Join(dir, "fake-browser")
+	be.Err(t, os.WriteFile(script, []byte("#!/bin/sh\necho $$ > "+pidFile+".tmp && mv "+pidFile+".tmp "+pidFile+"\nexec sleep 30\n"), 0o755), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	err := OpenURL(ctx, OpenURLInput{URL: "https://example.com", Browser: script})
+	be.Err(t, err, nil)
+	be.True(t, time.Since(start) < 5*time.Second)
+	cancel()
+
+	var pid int
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if data, err := os.ReadFile(pidFile); err == nil {
+			pid, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+			break
+		}
+	}
+	be.True(t, pid > 0)
+	proc, err := os.FindProcess(pid)
+	be.Err(t, err, nil)
+	defer proc.Kill()
+	// The browser keeps running after ctx is cancelled.
+	time.Sleep(100 * time.Millisecond)
+	be.Err(t, proc.Signal(syscall.Signal(0)), nil)
+}
@@ -0,0 +1,626 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxBodySize bounds recorded bodies when CaptureInput.MaxBodySize is
+// zero.
+const defaultMaxBodySize = 1 << 20
+
+// CaptureInput configures [Session.StartCapture].
+type CaptureInput struct {
+	// URLPattern, when set, is a regular expression a request URL must
+	// match to be recorded, for example `/api/`.
+	URLPattern string `json:"url_pattern,omitempty"`
+	// Bodies also records response bodies. Text bodies are kept as is and
+	// binary ones base64-encoded.
+	Bodies bool `json:"bodies,omitempty"`
+	// MaxBodySize skips recording bodies larger than this many bytes. Zero
+	// means 1 MiB.
+	MaxBodySize int64 `json:"max_body_size,omitempty"`
+}
+
+// NetworkTimings breaks a request's time down by phase, in milliseconds, as
+// in HAR. Phases that did not happen, such as DNS for a reused connection,
+// are -1.
+type NetworkTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NetworkEntry is one request recorded by a [Capture]. A redirect is
+// recorded as its own entry, whose response is the redirect.
+type NetworkEntry struct {
+	URL    string `json:"url"`
+	Method string `json:"method"`
+	// ResourceType is the browser's classification, such as "Document",
+	// "XHR", "Fetch", "Script", or "Image".
+	ResourceType   string            `json:"resource_type,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// RequestBody is the posted data, when the browser exposes it.
+	RequestBody string `json:"request_body,omitempty"`
+
+	// Status is zero until a response arrives, and stays zero for requests
+	// that fail without one.
+	Status          int               `json:"status"`
+	StatusText      string            `json:"status_text,omitempty"`
+	Protocol        string            `json:"protocol,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	MIMEType        string            `json:"mime_type,omitempty"`
+	RemoteAddress   string            `json:"remote_address,omitempty"`
+	FromCache       bool              `json:"from_cache,omitempty"`
+	// Size is the number of bytes received over the network, including
+	// headers.
+	Size int64 `json:"size"`
+	// Body is the response body when CaptureInput.Bodies is set and it was
+	// small enough; BodyBase64 reports that it is base64-encoded.
+	Body       string `json:"body,omitempty"`
+	BodyBase64 bool   `json:"body_base64,omitempty"`
+
+	Started time.Time `json:"started"`
+	// Duration is the time from the request to the end of the response.
+	Duration time.Duration `json:"duration"`
+	// Timings is nil when the browser reported no phase breakdown, as for
+	// cached responses.
+	Timings *NetworkTimings `json:"timings,omitempty"`
+	// Done is false while the request is in flight.
+	Done bool `json:"done"`
+	// Error is the browser's error text for a failed request, such as
+	// "net::ERR_NAME_NOT_RESOLVED".
+	Error string `json:"error,omitempty"`
+
+	start  float64 // monotonic seconds, from CDP timestamps
+	timing *cdpResourceTiming
+}
+
+// Capture records the network traffic of a session from
+// [Session.StartCapture] until Stop. Its methods are safe for concurrent
+// use.
+type Capture struct {
+	s        *Session
+	input    CaptureInput
+	pattern  *regexp.Regexp
+	unlisten []func()
+
+	mu      sync.Mutex
+	entries []*NetworkEntry
+	open    map[string]*NetworkEntry // in-flight entries by request id
+	bodies  sync.WaitGroup
+	stopped bool
+}
+
+// cdpResourceTiming is Network.ResourceTiming: requestTime in seconds, the
+// rest in milliseconds relative to it, with -1 for phases that did not
+// happen.
+type cdpResourceTiming struct {
+	RequestTime       float64 `json:"requestTime"`
+	DNSStart          float64 `json:"dnsStart"`
+	DNSEnd            float64 `json:"dnsEnd"`
+	ConnectStart      float64 `json:"connectStart"`
+	ConnectEnd        float64 `json:"connectEnd"`
+	SSLStart          float64 `json:"sslStart"`
+	SSLEnd            float64 `json:"sslEnd"`
+	SendStart         float64 `json:"sendStart"`
+	SendEnd           float64 `json:"sendEnd"`
+	ReceiveHeadersEnd float64 `json:"receiveHeadersEnd"`
+}
+
+type cdpResponse struct {
+	URL               string             `json:"url"`
+	Status            int                `json:"status"`
+	StatusText        string             `json:"statusText"`
+	Headers           map[string]any     `json:"headers"`
+	MIMEType          string             `json:"mimeType"`
+	RemoteIPAddress   string             `json:"remoteIPAddress"`
+	RemotePort        int                `json:"remotePort"`
+	FromDiskCache     bool               `json:"fromDiskCache"`
+	FromServiceWorker bool               `json:"fromServiceWorker"`
+	FromPrefetchCache bool               `json:"fromPrefetchCache"`
+	EncodedDataLength float64            `json:"encodedDataLength"`
+	Protocol          string             `json:"protocol"`
+	Timing            *cdpResourceTiming `json:"timing"`
+}
+
+// StartCapture begins recording the session's requests and responses: URL,
+// method, headers, status, timing, and optionally bodies. Call Stop on the
+// returned [Capture] when done, then read Entries or export them with HAR.
+// Several captures may run at once, each with its own filter.
+//
+// Only requests that start after StartCapture are recorded. Bodies are
+// fetched once a response completes; a navigation can discard a body
+// before it is read, in which case the entry has none.
+func (s *Session) StartCapture(ctx context.Context, input CaptureInput) (*Capture, error) {
+	if input.MaxBodySize < 0 {
+		return nil, newInvalidArg("StartCapture", "", "maxBodySize must not be negative")
+	}
+	if input.MaxBodySize == 0 {
+		input.MaxBodySize = defaultMaxBodySize
+	}
+	c := &Capture{s: s, input: input, open: make(map[string]*NetworkEntry)}
+	if input.URLPattern != "" {
+		re, err := regexp.Compile(input.URLPattern)
+		if err != nil {
+			return nil, newInvalidArg("StartCapture", input.URLPattern, err.Error())
+		}
+		c.pattern = re
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, &OpError{Op: "StartCapture", Err: err}
+	}
+	c.unlisten = []func(){
+		s.conn.on(s.sessionID, "Network.requestWillBeSent", c.requestWillBeSent),
+		s.conn.on(s.sessionID, "Network.responseReceived", c.responseReceived),
+		s.conn.on(s.sessionID, "Network.loadingFinished", c.loadingFinished),
+		s.conn.on(s.sessionID, "Network.loadingFailed", c.loadingFailed),
+	}
+	return c, nil
+}
+
+// Stop ends recording and waits, bounded by ctx, for bodies still being
+// fetched. Requests in flight stay recorded with Done false. Stop is
+// idempotent.
+func (c *Capture) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.stopped {
+		c.stopped = true
+		for _, fn := range c.unlisten {
+			fn()
+		}
+	}
+	c.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		c.bodies.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return &OpError{Op: "StopCapture", Err: ctx.Err()}
+	}
+}
+
+// Entries returns the requests recorded so far, in the order they started.
+func (c *Capture) Entries() []NetworkEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]NetworkEntry, len(c.entries))
+	for i, e := range c.entries {
+		out[i] = *e
+		out[i].RequestHeaders = cloneMap(e.RequestHeaders)
+		out[i].ResponseHeaders = cloneMap(e.ResponseHeaders)
+		if e.Timings != nil {
+			t := *e.Timings
+			out[i].Timings = &t
+		}
+	}
+	return out
+}
+
+func cloneMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func (c *Capture) requestWillBeSent(params json.RawMessage) {
+	var ev struct {
+		RequestID string `json:"requestId"`
+		Request   struct {
+			URL      string         `json:"url"`
+			Method   string         `json:"method"`
+			Headers  map[string]any `json:"headers"`
+			PostData string         `json:"postData"`
+		} `json:"request"`
+		Timestamp        float64      `json:"timestamp"`
+		WallTime         float64      `json:"wallTime"`
+		Type             string       `json:"type"`
+		RedirectResponse *cdpResponse `json:"redirectResponse"`
+	}
+	if json.Unmarshal(params, &ev) != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	// A redirect reuses the request id: the response ends the previous hop.
+	if prev := c.open[ev.RequestID]; prev != nil && ev.RedirectResponse != nil {
+		prev.setResponse(ev.RedirectResponse)
+		prev.Size = int64(ev.RedirectResponse.EncodedDataLength)
+		prev.finish(ev.Timestamp)
+		delete(c.open, ev.RequestID)
+	}
+	if c.pattern != nil && !c.pattern.MatchString(ev.Request.URL) {
+		return
+	}
+	e := &NetworkEntry{
+		URL:            ev.Request.URL,
+		Method:         ev.Request.Method,
+		ResourceType:   ev.Type,
+		RequestHeaders: headerMap(ev.Request.Headers),
+		RequestBody:    ev.Request.PostData,
+		Started:        time.UnixMicro(int64(math.Round(ev.WallTime * 1e6))),
+		start:          ev.Timestamp,
+	}
+	c.entries = append(c.entries, e)
+	c.open[ev.RequestID] = e
+}
+
+func (c *Capture) responseReceived(params json.RawMessage) {
+	var ev struct {
+		RequestID string      `json:"requestId"`
+		Response  cdpResponse `json:"response"`
+	}
+	if json.Unmarshal(params, &ev) != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	if e := c.open[ev.RequestID]; e != nil {
+		e.setResponse(&ev.Response)
+	}
+}
+
+func (c *Capture) loadingFinished(params json.RawMessage) {
+	var ev struct {
+		RequestID         string  `json:"requestId"`
+		Timestamp         float64 `json:"timestamp"`
+		EncodedDataLength float64 `json:"encodedDataLength"`
+	}
+	if json.Unmarshal(params, &ev) != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	e := c.open[ev.RequestID]
+	if e == nil {
+		return
+	}
+	delete(c.open, ev.RequestID)
+	e.Size = int64(ev.EncodedDataLength)
+	e.finish(ev.Timestamp)
+	if c.input.Bodies && e.Status != 0 && (e.Status < 300 || e.Status >= 400) {
+		// Event handlers run on the connection's read loop, so the body is
+		// fetched from another goroutine.
+		c.bodies.Add(1)
+		go c.fetchBody(e, ev.RequestID)
+	}
+}
+
+func (c *Capture) loadingFailed(params json.RawMessage) {
+	var ev struct {
+		RequestID string  `json:"requestId"`
+		Timestamp float64 `json:"timestamp"`
+		ErrorText string  `json:"errorText"`
+		Canceled  bool    `json:"canceled"`
+	}
+	if json.Unmarshal(params, &ev) != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	e := c.open[ev.RequestID]
+	if e == nil {
+		return
+	}
+	delete(c.open, ev.RequestID)
+	e.Error = ev.ErrorText
+	if e.Error == "" && ev.Canceled {
+		e.Error = "canceled"
+	}
+	e.finish(ev.Timestamp)
+}
+
+// fetchBody records the body of the completed entry e.
+func (c *Capture) fetchBody(e *NetworkEntry, requestID string) {
+	defer c.bodies.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var res struct {
+		Body          string `json:"body"`
+		Base64Encoded bool   `json:"base64Encoded"`
+	}
+	if err := c.s.call(ctx, "Network.getResponseBody", map[string]any{"requestId": requestID}, &res); err != nil {
+		return
+	}
+	size := int64(len(res.Body))
+	if res.Base64Encoded {
+		size = size / 4 * 3
+	}
+	if size > c.input.MaxBodySize {
+		return
+	}
+	c.mu.Lock()
+	e.Body, e.BodyBase64 = res.Body, res.Base64Encoded
+	c.mu.Unlock()
+}
+
+func (e *NetworkEntry) setResponse(r *cdpResponse) {
+	e.Status, e.StatusText, e.Protocol = r.Status, r.StatusText, r.Protocol
+	e.ResponseHeaders = headerMap(r.Headers)
+	e.MIMEType = r.MIMEType
+	e.FromCache = r.FromDiskCache || r.FromPrefetchCache || r.FromServiceWorker
+	if r.RemoteIPAddress != "" {
+		ip := strings.Trim(r.RemoteIPAddress, "[]")
+		e.RemoteAddress = net.JoinHostPort(ip, strconv.Itoa(r.RemotePort))
+	}
+	e.timing = r.Timing
+}
+
+// finish marks e done at the CDP timestamp end and derives its timings.
+func (e *NetworkEntry) finish(end float64) {
+	e.Done = true
+	if end > e.start && e.start > 0 {
+		e.Duration = time.Duration(math.Round((end-e.start)*1e6)) * time.Microsecond
+	}
+	t := e.timing
+	if t == nil || t.RequestTime == 0 {
+		return
+	}
+	phase := func(start, end float64) float64 {
+		if start < 0 || end < 0 {
+			return -1
+		}
+		return round3(end - start)
+	}
+	blocked := -1.0
+	for _, v := range []float64{t.DNSStart, t.ConnectStart, t.SendStart} {
+		if v >= 0 {
+			blocked = v
+			break
+		}
+	}
+	total := (end - t.RequestTime) * 1000
+	e.Timings = &NetworkTimings{
+		Blocked: blocked,
+		DNS:     phase(t.DNSStart, t.DNSEnd),
+		Connect: phase(t.ConnectStart, t.ConnectEnd),
+		SSL:     phase(t.SSLStart, t.SSLEnd),
+		Send:    max(phase(t.SendStart, t.SendEnd), 0),
+		Wait:    max(phase(t.SendEnd, t.ReceiveHeadersEnd), 0),
+		Receive: max(round3(total-t.ReceiveHeadersEnd), 0),
+	}
+}
+
+// round3 rounds milliseconds to microseconds, dropping float noise from
+// converting CDP's seconds.
+func round3(ms float64) float64 {
+	return math.Round(ms*1000) / 1000
+}
+
+// headerMap flattens CDP headers, whose values may be strings or numbers.
+func headerMap(h map[string]any) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if s, ok := v.(string); ok {
+			out[k] = s
+		} else {
+			out[k] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// HAR exports the recorded entries as an HTTP Archive 1.2 document, which
+// browser devtools and HAR viewers can open. Requests still in flight are
+// left out. Failed requests have status 0 and their error in the
+// entry's "_error" field.
+func (c *Capture) HAR() ([]byte, error) {
+	entries := c.Entries()
+	log := harLog{
+		Version: "1.2",
+		Creator: harNameVersion{Name: "cuh", Version: "1"},
+		Pages:   []struct{}{},
+		Entries: []harEntry{},
+	}
+	for _, e := range entries {
+		if !e.Done {
+			continue
+		}
+		log.Entries = append(log.Entries, e.har())
+	}
+	data, err := json.MarshalIndent(map[string]any{"log": log}, "", "  ")
+	if err != nil {
+		return nil, &OpError{Op: "HAR", Err: err}
+	}
+	return data, nil
+}
+
+type harLog struct {
+	Version string         `json:"version"`
+	Creator harNameVersion `json:"creator"`
+	Pages   []struct{}     `json:"pages"`
+	Entries []harEntry     `json:"entries"`
+}
+
+type harNameVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harEntry struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	Time            float64        `json:"time"`
+	Request         harRequest     `json:"request"`
+	Response        harResponse    `json:"response"`
+	Cache           struct{}       `json:"cache"`
+	Timings         NetworkTimings `json:"timings"`
+	ServerIPAddress string         `json:"serverIPAddress,omitempty"`
+	ResourceType    string         `json:"_resourceType,omitempty"`
+	Error           string         `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+func (e NetworkEntry) har() harEntry {
+	h := harEntry{
+		StartedDateTime: e.Started.UTC().Format(time.RFC3339Nano),
+		Time:            millis(e.Duration),
+		Request: harRequest{
+			Method:      e.Method,
+			URL:         e.URL,
+			HTTPVersion: harHTTPVersion(e.Protocol),
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(e.RequestHeaders),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(e.RequestBody),
+		},
+		Response: harResponse{
+			Status:      e.Status,
+			StatusText:  e.StatusText,
+			HTTPVersion: harHTTPVersion(e.Protocol),
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(e.ResponseHeaders),
+			Content:     harContent{Size: int64(len(e.Body)), MimeType: e.MIMEType, Text: e.Body},
+			RedirectURL: headerValue(e.ResponseHeaders, "Location"),
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings:      NetworkTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: millis(e.Duration)},
+		ResourceType: e.ResourceType,
+		Error:        e.Error,
+	}
+	if e.Timings != nil {
+		h.Timings = *e.Timings
+	}
+	if e.BodyBase64 {
+		h.Response.Content.Encoding = "base64"
+		h.Response.Content.Size = int64(len(e.Body)) / 4 * 3
+	}
+	if e.FromCache {
+		h.Response.BodySize = 0
+	}
+	if host, _, err := net.SplitHostPort(e.RemoteAddress); err == nil {
+		h.ServerIPAddress = host
+	}
+	if u, err := url.Parse(e.URL); err == nil {
+		for k, vs := range u.Query() {
+			for _, v := range vs {
+				h.Request.QueryString = append(h.Request.QueryString, harNameValue{Name: k, Value: v})
+			}
+		}
+		slices.SortStableFunc(h.Request.QueryString, func(a, b harNameValue) int { return strings.Compare(a.Name, b.Name) })
+	}
+	if e.RequestBody != "" {
+		h.Request.PostData = &harPostData{MimeType: headerValue(e.RequestHeaders, "Content-Type"), Text: e.RequestBody}
+	}
+	return h
+}
+
+// millis is d in milliseconds, the HAR unit.
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// harHTTPVersion maps CDP's ALPN protocol names to HAR's version strings.
+func harHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "":
+		return "HTTP/1.1"
+	case "h2":
+		return "HTTP/2.0"
+	case "h3", "h3-29", "quic":
+		return "HTTP/3"
+	}
+	return strings.ToUpper(protocol)
+}
+
+// harHeaders lists headers sorted by name, for stable output.
+func harHeaders(h map[string]string) []harNameValue {
+	out := make([]harNameValue, 0, len(h))
+	for k, v := range h {
+		// CDP joins repeated headers with newlines.
+		for line := range strings.SplitSeq(v, "\n") {
+			out = append(out, harNameValue{Name: k, Value: line})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b harNameValue) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// headerValue looks up name case-insensitively.
+func headerValue(h map[string]string, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func TestCapture(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Network.getResponseBody", func(msg cdpMessage) (any, *cdpError) {
+		var p struct{ RequestID string }
+		_ = json.Unmarshal(msg.Params, &p)
+		if p.RequestID == "big" {
+			return map[string]any{"body": "AAAAAAAAAAAAAAAA", "base64Encoded": true}, nil
+		}
+		return map[string]any{"body": `{"id":7}`}, nil
+	})
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	c, err := s.StartCapture(ctx, CaptureInput{URLPattern: `/api/|/login`, Bodies: true, MaxBodySize: 10})
+	be.Err(t, err, nil)
+	request := func(id, method, url string, ts float64, extra map[string]any) {
+		ev := map[string]any{
+			"requestId": id, "timestamp": ts, "wallTime": 1700000000 + ts, "type": "Fetch",
+			"request": map[string]any{"url": url, "method": method, "headers": map[string]any{"Accept": "*/*"}},
+		}
+		for k, v := range extra {
+			ev[k] = v
+		}
+		f.emit("S1", "Network.requestWillBeSent", ev)
+	}
+	response := func(status int, headers map[string]any) map[string]any {
+		return map[string]any{
+			"status": status, "statusText": "OK", "protocol": "h2", "mimeType": "application/json",
+			"headers": headers, "remoteIPAddress": "10.0.0.1", "remotePort": 443,
+			"timing": map[string]any{
+				"requestTime": 100, "dnsStart": -1, "dnsEnd": -1, "connectStart": -1, "connectEnd": -1,
+				"sslStart": -1, "sslEnd": -1, "sendStart": 2, "sendEnd": 3, "receiveHeadersEnd": 40,
+			},
+		}
+	}
+
+	// A redirect from /login to /api/me, an image skipped by the pattern, a
+	// body too big to keep, and a failure.
+	request("r1", "POST", "https://app.example/login?next=me", 100, nil)
+	request("r1", "GET", "https://app.example/api/me?b=2&a=1", 100.05, map[string]any{
+		"redirectResponse": response(302, map[string]any{"Location": "/api/me"}),
+	})
+	f.emit("S1", "Network.responseReceived", map[string]any{"requestId": "r1", "response": response(200, map[string]any{"Set-Cookie": "a=1\nb=2"})})
+	f.emit("S1", "Network.loadingFinished", map[string]any{"requestId": "r1", "timestamp": 100.1, "encodedDataLength": 120})
+	request("img", "GET", "https://app.example/logo.png", 100.2, nil)
+	f.emit("S1", "Network.loadingFinished", map[string]any{"requestId": "img", "timestamp": 100.3})
+	request("big", "GET", "https://app.example/api/export", 100.3, nil)
+	f.emit("S1", "Network.responseReceived", map[string]any{"requestId": "big", "response": response(200, nil)})
+	f.emit("S1", "Network.loadingFinished", map[string]any{"requestId": "big", "timestamp": 100.4})
+	request("r4", "GET", "https://down.example/api/x", 100.4, nil)
+	f.emit("S1", "Network.loadingFailed", map[string]any{"requestId": "r4", "timestamp": 100.5, "errorText": "net::ERR_NAME_NOT_RESOLVED"})
+	request("r5", "GET", "https://app.example/api/poll", 100.5, nil)
+	// Events are handled in order, so a reply means all of them were seen.
+	be.Err(t, s.call(ctx, "Page.enable", nil, nil), nil)
+	be.Err(t, c.Stop(ctx), nil)
+	be.Err(t, c.Stop(ctx), nil)
+	request("late", "GET", "https://app.example/api/late", 101, nil)
+	be.Err(t, s.call(ctx, "Page.enable", nil, nil), nil)
+
+	entries := c.Entries()
+	be.Equal(t, len(entries), 5)
+	redirect, me, big, failed, poll := entries[0], entries[1], entries[2], entries[3], entries[4]
+	be.Equal(t, redirect.Status, 302)
+	be.Equal(t, redirect.Done, true)
+	be.Equal(t, redirect.Body, "")
+	be.Equal(t, redirect.Duration, 50*time.Millisecond)
+	be.Equal(t, me.URL, "https://app.example/api/me?b=2&a=1")
+	be.Equal(t, me.Status, 200)
+	be.Equal(t, me.Body, `{"id":7}`)
+	be.Equal(t, me.Size, int64(120))
+	be.Equal(t, me.RemoteAddress, "10.0.0.1:443")
+	be.Equal(t, me.Started, time.UnixMicro(1700000100050000))
+	be.Equal(t, *me.Timings, NetworkTimings{Blocked: 2, DNS: -1, Connect: -1, SSL: -1, Send: 1, Wait: 37, Receive: 60})
+	be.Equal(t, big.Body, "")
+	be.Equal(t, failed.Error, "net::ERR_NAME_NOT_RESOLVED")
+	be.Equal(t, failed.Status, 0)
+	be.Equal(t, poll.Done, false)
+
+	data, err := c.HAR()
+	be.Err(t, err, nil)
+	var har struct {
+		Log struct {
+			Version string
+			Entries []struct {
+				Time    float64
+				Request struct {
+					Method      string
+					QueryString []harNameValue
+				}
+				Response struct {
+					Status      int
+					HTTPVersion string
+					Headers     []harNameValue
+					Content     harContent
+					RedirectURL string
+				}
+				ServerIPAddress string
+				Error           string `json:"_error"`
+			}
+		}
+	}
+	be.Err(t, json.Unmarshal(data, &har), nil)
+	be.Equal(t, har.Log.Version, "1.2")
+	be.Equal(t, len(har.Log.Entries), 4)
+	be.Equal(t, har.Log.Entries[0].Request.Method, "POST")
+	be.Equal(t, har.Log.Entries[0].Response.RedirectURL, "/api/me")
+	got := har.Log.Entries[1]
+	be.Equal(t, got.Request.QueryString, []harNameValue{{"a", "1"}, {"b", "2"}})
+	be.Equal(t, got.Response.HTTPVersion, "HTTP/2.0")
+	be.Equal(t, got.Response.Headers, []harNameValue{{"Set-Cookie", "a=1"}, {"Set-Cookie", "b=2"}})
+	be.Equal(t, got.Response.Content, harContent{Size: 8, MimeType: "application/json", Text: `{"id":7}`})
+	be.Equal(t, got.ServerIPAddress, "10.0.0.1")
+	be.Equal(t, har.Log.Entries[3].Error, "net::ERR_NAME_NOT_RESOLVED")
+}
+
+func TestCaptureInvalid(t *testing.T) {
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	_, err := s.StartCapture(ctx, CaptureInput{URLPattern: "("})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.StartCapture(ctx, CaptureInput{MaxBodySize: -1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
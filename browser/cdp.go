@@ -0,0 +1,190 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// cdpMessage is one DevTools protocol frame: a command, its response, or an
+// event.
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *cdpError       `json:"error,omitempty"`
+}
+
+// cdpError is an error response from the browser.
+type cdpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *cdpError) Error() string {
+	if e.Data != "" {
+		return fmt.Sprintf("%s (%d): %s", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+type cdpListener struct {
+	fn func(json.RawMessage)
+}
+
+// cdpConn multiplexes commands and events for every target session over one
+// browser WebSocket.
+type cdpConn struct {
+	ws     *wsConn
+	nextID atomic.Int64
+
+	mu        sync.Mutex
+	pending   map[int64]chan cdpMessage
+	listeners map[string][]*cdpListener
+
+	done    chan struct{}
+	readErr error
+}
+
+func newCDPConn(ws *wsConn) *cdpConn {
+	c := &cdpConn{
+		ws:        ws,
+		pending:   make(map[int64]chan cdpMessage),
+		listeners: make(map[string][]*cdpListener),
+		done:      make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+func listenerKey(sessionID, method string) string {
+	return sessionID + "\x00" + method
+}
+
+func (c *cdpConn) readLoop() {
+	var err error
+	defer func() {
+		c.mu.Lock()
+		c.readErr = err
+		c.mu.Unlock()
+		close(c.done)
+	}()
+	for {
+		var data []byte
+		data, err = c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg cdpMessage
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+		if msg.ID != 0 {
+			c.mu.Lock()
+			ch := c.pending[msg.ID]
+			delete(c.pending, msg.ID)
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- msg
+			}
+			continue
+		}
+		c.mu.Lock()
+		listeners := append([]*cdpListener(nil), c.listeners[listenerKey(msg.SessionID, msg.Method)]...)
+		c.mu.Unlock()
+		for _, l := range listeners {
+			l.fn(msg.Params)
+		}
+	}
+}
+
+// call sends method to the target session (empty for the browser itself) and
+// decodes the result into result, which may be nil.
+func (c *cdpConn) call(ctx context.Context, sessionID, method string, params, result any) error {
+	if params == nil {
+		params = struct{}{}
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := c.nextID.Add(1)
+	data, err := json.Marshal(cdpMessage{ID: id, SessionID: sessionID, Method: method, Params: raw})
+	if err != nil {
+		return err
+	}
+	ch := make(chan cdpMessage, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-c.done:
+		return c.closedError()
+	default:
+	}
+	if err := c.ws.WriteText(data); err != nil {
+		return fmt.Errorf("%w: %v", ErrSessionClosed, err)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return c.closedError()
+	case msg := <-ch:
+		if msg.Error != nil {
+			return fmt.Errorf("%w: %s: %w", ErrProtocol, method, msg.Error)
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// on registers fn for method events from sessionID. fn runs on the read
+// goroutine and must not block or call c. The returned func unregisters it.
+func (c *cdpConn) on(sessionID, method string, fn func(json.RawMessage)) func() {
+	l := &cdpListener{fn: fn}
+	key := listenerKey(sessionID, method)
+	c.mu.Lock()
+	c.listeners[key] = append(c.listeners[key], l)
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		ls := c.listeners[key]
+		for i, x := range ls {
+			if x == l {
+				c.listeners[key] = append(ls[:i:i], ls[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (c *cdpConn) closedError() error {
+	c.mu.Lock()
+	err := c.readErr
+	c.mu.Unlock()
+	if err == nil {
+		return ErrSessionClosed
+	}
+	return fmt.Errorf("%w: %v", ErrSessionClosed, err)
+}
+
+func (c *cdpConn) close() error {
+	err := c.ws.Close()
+	<-c.done
+	return err
+}
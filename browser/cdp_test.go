@@ -0,0 +1,185 @@
+package browser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nalgeon/be"
+)
+
+// fakeCDP is an in-process DevTools endpoint. It serves /json/version and a
+// browser WebSocket, answering commands from handlers keyed by method.
+type fakeCDP struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]fakeHandler
+	calls    []cdpMessage
+	conn     net.Conn
+	wmu      sync.Mutex
+}
+
+// fakeHandler answers one command. A non-nil *cdpError is sent as the error
+// response.
+type fakeHandler func(msg cdpMessage) (any, *cdpError)
+
+func newFakeCDP(t *testing.T) *fakeCDP {
+	f := &fakeCDP{t: t, handlers: map[string]fakeHandler{}}
+	ok := func(cdpMessage) (any, *cdpError) { return struct{}{}, nil }
+	f.handle("Target.createTarget", func(cdpMessage) (any, *cdpError) { return map[string]any{"targetId": "T1"}, nil })
+	f.handle("Target.attachToTarget", func(cdpMessage) (any, *cdpError) { return map[string]any{"sessionId": "S1"}, nil })
+	f.handle("Target.closeTarget", func(cdpMessage) (any, *cdpError) { return map[string]any{"success": true}, nil })
+	f.handle("Page.enable", ok)
+	f.handle("Network.enable", ok)
+	f.handle("Page.navigate", func(msg cdpMessage) (any, *cdpError) {
+		f.emit(msg.SessionID, "Page.loadEventFired", map[string]any{"timestamp": 1})
+		return map[string]any{"frameId": "F1", "loaderId": "L1"}, nil
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/version", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"webSocketDebuggerUrl": f.wsURL()})
+	})
+	mux.HandleFunc("/devtools/browser/fake", f.serveWebSocket)
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.close)
+	return f
+}
+
+func (f *fakeCDP) handle(method string, h fakeHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = h
+}
+
+func (f *fakeCDP) wsURL() string {
+	return "ws" + strings.TrimPrefix(f.srv.URL, "http") + "/devtools/browser/fake"
+}
+
+// methods returns the methods received so far, in order.
+func (f *fakeCDP) methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]string, len(f.calls))
+	for i, c := range f.calls {
+		out[i] = c.Method
+	}
+	return out
+}
+
+// lastCall returns the most recent command for method.
+func (f *fakeCDP) lastCall(method string) (cdpMessage, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.calls) - 1; i >= 0; i-- {
+		if f.calls[i].Method == method {
+			return f.calls[i], true
+		}
+	}
+	return cdpMessage{}, false
+}
+
+func (f *fakeCDP) send(msg cdpMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		f.t.Errorf("marshal: %v", err)
+		return
+	}
+	f.wmu.Lock()
+	defer f.wmu.Unlock()
+	writeWSFrame(f.conn, wsOpText, data, false)
+}
+
+// emit sends an event to the client.
+func (f *fakeCDP) emit(sessionID, method string, params any) {
+	raw, _ := json.Marshal(params)
+	f.send(cdpMessage{SessionID: sessionID, Method: method, Params: raw})
+}
+
+// disconnect drops the WebSocket as a crashing browser would.
+func (f *fakeCDP) disconnect() {
+	f.mu.Lock()
+	conn := f.conn
+	f.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (f *fakeCDP) close() {
+	f.disconnect()
+	f.srv.Close()
+}
+
+func (f *fakeCDP) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		f.t.Errorf("hijack: %v", err)
+		return
+	}
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+	rw.Flush()
+	go f.serve(conn, rw.Reader)
+}
+
+func (f *fakeCDP) serve(conn net.Conn, br *bufio.Reader) {
+	defer conn.Close()
+	for {
+		_, op, payload, err := readWSFrame(br)
+		if err != nil || op == wsOpClose {
+			return
+		}
+		var msg cdpMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			f.t.Errorf("unmarshal command: %v", err)
+			return
+		}
+		f.mu.Lock()
+		f.calls = append(f.calls, msg)
+		h := f.handlers[msg.Method]
+		f.mu.Unlock()
+
+		reply := cdpMessage{ID: msg.ID, SessionID: msg.SessionID}
+		if h == nil {
+			reply.Error = &cdpError{Code: -32601, Message: "'" + msg.Method + "' wasn't found"}
+		} else if result, cerr := h(msg); cerr != nil {
+			reply.Error = cerr
+		} else {
+			reply.Result, _ = json.Marshal(result)
+		}
+		f.send(reply)
+	}
+}
+
+func TestWebSocketFrames(t *testing.T) {
+	for _, n := range []int{0, 125, 126, 65535, 65536, 70000} {
+		for _, mask := range []bool{true, false} {
+			payload := bytes.Repeat([]byte{'x'}, n)
+			var buf bytes.Buffer
+			be.Err(t, writeWSFrame(&buf, wsOpText, payload, mask), nil)
+			fin, op, got, err := readWSFrame(bufio.NewReader(&buf))
+			be.Err(t, err, nil)
+			be.True(t, fin)
+			be.Equal(t, op, byte(wsOpText))
+			be.Equal(t, got, payload)
+		}
+	}
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	be.Equal(t, websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+}
@@ -0,0 +1,304 @@
+// Package browser provides agent-oriented primitives for driving web
+// browsers.
+//
+// Like the other cuh packages, the API is primitive-first: callers compose
+// recipes from small explicit operations rather than relying on one-off
+// workflows.
+//
+// Primitive groups:
+//
+//   - Launching: [OpenURL] hands a URL to the system default or a chosen
+//     browser, profile, or private window.
+//   - Sessions: [Launch] and [Attach] open a [Session] on a Chrome or
+//     Chromium tab over the DevTools protocol; [Session.Navigate],
+//     [Session.WaitLoad], [Session.Screenshot], and [Session.Close] drive it.
+//     [ListProfiles] and [DeleteProfile] manage the named persistent
+//     profiles Launch can run on.
+//     [OpenWebDriver] opens a [WebDriverSession] through chromedriver,
+//     geckodriver, msedgedriver, or safaridriver instead; both implement
+//     [Driver].
+//   - Elements: [Session.Find] selects element refs, [Session.Get] hydrates
+//     them, and [Session.Act] applies explicit ops to them.
+//     [Session.Snapshot] outlines the page's roles, names, and refs for a
+//     model to read.
+//   - Waiting: [Session.Wait] blocks until an element appears or disappears,
+//     a selector count, URL, or script predicate holds, or the network goes
+//     idle.
+//   - Downloading: [Session.Download] fetches a file by URL or click into a
+//     chosen directory and reports its path and checksum.
+//   - Printing: [Session.PrintToPDF] renders a page as a PDF with paper,
+//     margin, and header/footer options.
+//   - State: [Session.Cookies], [Session.SetCookies], and
+//     [Session.ClearCookies] manage the profile's cookies;
+//     [Session.LocalStorage], [Session.SetLocalStorage], and
+//     [Session.ClearLocalStorage] manage the current origin's localStorage.
+//   - Network: [Session.StartCapture] records requests and responses, with
+//     optional bodies, and [Capture.HAR] exports them as a HAR file.
+//   - Reading: [Session.ExtractReadable] returns a page's article text and
+//     metadata without the surrounding navigation and ads.
+//   - Scripting: [Session.Eval] runs JavaScript in the page and decodes its
+//     JSON result, for steps the typed primitives do not cover.
+//
+// Suggested import path from calling code:
+//
+//	import "github.com/spachava753/cuh/browser"
+//
+// # Opening URLs
+//
+// [OpenURL] uses the platform opener (`open` on macOS, `xdg-open` on Linux,
+// `start` on Windows) and returns as soon as the browser has the URL. Set
+// [OpenURLInput].Browser to pick an application, and Background (macOS only)
+// to leave the current app in front:
+//
+//	err := browser.OpenURL(ctx, browser.OpenURLInput{
+//		URL:        "https://example.com/report",
+//		Browser:    "Safari",
+//		Background: true,
+//	})
+//
+// To keep work and personal contexts apart, Profile picks a browser profile
+// and Private an incognito or private window:
+//
+//	err := browser.OpenURL(ctx, browser.OpenURLInput{
+//		URL:     link,
+//		Browser: "Google Chrome",
+//		Profile: "Profile 2", // the work profile, per chrome://version
+//	})
+//
+// # Sessions
+//
+// A [Session] controls one tab through the Chrome DevTools Protocol. [Launch]
+// starts a private browser process (with a throwaway profile unless
+// UserDataDir is set) and Close shuts it down. [Attach] joins a browser the
+// user already started with --remote-debugging-port; it opens its own tab and
+// Close closes only that tab.
+//
+//	s, err := browser.Launch(ctx, browser.LaunchInput{Headless: true})
+//	if err != nil {
+//		return err
+//	}
+//	defer s.Close()
+//	if err := s.Navigate(ctx, "https://example.com"); err != nil {
+//		return err
+//	}
+//	if err := s.WaitLoad(ctx); err != nil {
+//		return err
+//	}
+//	png, err := s.Screenshot(ctx, browser.ScreenshotInput{FullPage: true})
+//
+// Navigate returns once the browser commits to the new page; WaitLoad then
+// waits for its load event. Bound both with ctx deadlines.
+//
+// # WebDriver
+//
+// Where attaching the DevTools protocol to a browser is not permitted, or
+// the browser is Firefox or Safari, [OpenWebDriver] drives it through a W3C
+// WebDriver server instead. It starts the named driver, or connects to one
+// already running at URL, such as a Selenium grid:
+//
+//	wd, err := browser.OpenWebDriver(ctx, browser.WebDriverInput{Driver: "geckodriver", Headless: true})
+//	if err != nil {
+//		return err
+//	}
+//	defer wd.Close()
+//
+// [Session] and [WebDriverSession] both implement [Driver], and find, act,
+// snapshot, wait, eval, and storage behave the same on either, since they
+// run the same page script. Write recipes against Driver to keep the backend
+// a configuration choice. WebDriver lacks downloads, network capture,
+// network-idle waits, and reading cookies beyond the current page; those
+// fail with [ErrUnsupported] or are available on Session only.
+//
+// # Profiles
+//
+// A throwaway profile forgets logins when the session closes. To keep them
+// across agent runs, launch on a named profile; it is created under
+// [DefaultProfilesDir] on first use and reused by name afterwards. Each name
+// is an independent persona with its own cookies and storage, and any number
+// can run side by side, but one profile can be open in only one browser at a
+// time; a second Launch on it fails with [ErrProfileInUse].
+//
+//	s, err := browser.Launch(ctx, browser.LaunchInput{Headless: true, Profile: "work"})
+//
+// [ListProfiles] shows the saved profiles and whether each is in use, and
+// [DeleteProfile] removes one and everything logged in with it.
+//
+// # Find, Get, Act
+//
+// Page automation follows the same composition model as the other packages:
+//
+//  1. Find selects elements with a CSS selector, visible text, ARIA role,
+//     and/or accessible name, and returns opaque [ElementRef] values.
+//  2. Get hydrates refs with text, attributes, bounding boxes, and
+//     visible/enabled state so the caller can decide what to do.
+//  3. Act applies an explicit list of [ElementOp] values (click, type,
+//     select, check, uncheck, submit, scroll) and reports one [ActResult]
+//     per op. DryRun checks every op against the live page without acting.
+//
+// For example, to sign in:
+//
+//	refs, err := s.Find(ctx, browser.FindInput{Role: "textbox", Name: "Email"})
+//	if err != nil || len(refs) != 1 {
+//		return fmt.Errorf("want one email field, got %d: %v", len(refs), err)
+//	}
+//	submit, err := s.Find(ctx, browser.FindInput{Role: "button", Name: "Sign in", Limit: 1})
+//	if err != nil {
+//		return err
+//	}
+//	results, err := s.Act(ctx, browser.ActInput{Ops: []browser.ElementOp{
+//		{Kind: browser.OpType, Ref: refs[0], Text: "me@example.com", Clear: true},
+//		{Kind: browser.OpClick, Ref: submit[0]},
+//	}})
+//
+// Refs belong to the current document. After a navigation, Find again; old
+// refs fail with [ErrStaleRef]. Ops on hidden, disabled, or covered elements
+// fail with [ErrNotInteractable].
+//
+// An op may name its element with a CSS Selector instead of a ref when the
+// page has a stable one. The selector must match exactly one visible
+// element, so a typo fails with [ErrNotFound] and a loose selector with
+// [ErrAmbiguous] rather than acting on the wrong element. After each op Act
+// waits for any navigation and DOM updates it triggered, so a form can be
+// filled and submitted in one call:
+//
+//	results, err := s.Act(ctx, browser.ActInput{Ops: []browser.ElementOp{
+//		{Kind: browser.OpType, Selector: "#q", Text: "running shoes"},
+//		{Kind: browser.OpCheck, Selector: "input[name=in_stock]"},
+//		{Kind: browser.OpSubmit, Selector: "#q"},
+//	}})
+//
+// When a step needs page logic Find and Act cannot express, pass refs to
+// [Session.Eval]; they arrive in the script as elements:
+//
+//	var rows [][]string
+//	err := s.Eval(ctx, browser.EvalInput{
+//		Script: `(table) => [...table.rows].map(r => [...r.cells].map(c => c.innerText))`,
+//		Args:   []any{tableRef},
+//	}, &rows)
+//
+// # Snapshots
+//
+// When the agent does not yet know what to look for, [Session.Snapshot]
+// gives it the lay of the page: an outline of roles, names, values, states,
+// and boxes, with a ref on every element. It costs a fraction of the tokens
+// of the HTML, and its refs feed straight into Act:
+//
+//	snap, err := s.Snapshot(ctx, browser.SnapshotInput{InteractiveOnly: true, MaxText: 80})
+//	if err != nil {
+//		return err
+//	}
+//	prompt := snap.String() // - textbox "Email" [e6] @57,144 179x21 (required)
+//
+// Refs from a snapshot go stale like any other when the page changes, so
+// take a new snapshot after an action that re-renders the page.
+//
+// # Waiting
+//
+// Pages change after the load event as scripts fetch data and re-render.
+// Rather than sleeping, wait for the state the next step needs, with a ctx
+// deadline as the upper bound:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//	if _, err := s.Wait(ctx, browser.WaitInput{Kind: browser.WaitHidden, Selector: ".spinner"}); err != nil {
+//		return err
+//	}
+//	res, err := s.Wait(ctx, browser.WaitInput{Kind: browser.WaitCount, Selector: "table tr", Count: 1, AtLeast: true})
+//
+// On timeout the error wraps context.DeadlineExceeded and describes the last
+// state seen, such as how many elements matched.
+//
+// # Downloads
+//
+// [Session.Download] triggers a download, follows its progress, and saves
+// the file in the directory the caller names. Downloads that sit behind a
+// login are usually a click away once the session is signed in:
+//
+//	d, err := s.Download(ctx, browser.DownloadInput{
+//		Selector: "a.statement-pdf",
+//		Dir:      filepath.Join(home, "Documents", "Statements"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(d.Path, d.Size, d.SHA256)
+//
+// Server-suggested names are reduced to a plain file name, and an existing
+// file is never replaced unless Overwrite is set.
+//
+// # Printing
+//
+// [Session.PrintToPDF] keeps a record of a page, such as an order
+// confirmation, as the browser would print it. It returns the PDF bytes, or
+// writes them to Path:
+//
+//	_, err := s.PrintToPDF(ctx, browser.PDFInput{
+//		Path:           filepath.Join(home, "Documents", "order-42.pdf"),
+//		Paper:          browser.PaperA4,
+//		FooterTemplate: `<div style="font-size:8px"><span class="url"></span></div>`,
+//	})
+//
+// Printing needs a headless browser.
+//
+// # Cookies and Storage
+//
+// Signed-in state lives in cookies and localStorage. Inspect it to check
+// whether a session is still logged in, inject it to reuse a login from
+// another run, or clear it to start fresh:
+//
+//	if _, err := s.ClearCookies(ctx, browser.ClearCookiesInput{Domain: "example.com"}); err != nil {
+//		return err
+//	}
+//	results, err := s.SetCookies(ctx, browser.SetCookiesInput{Cookies: saved})
+//
+// Cookies span the whole browser profile, while localStorage belongs to an
+// origin, so the localStorage primitives act on the origin of the page the
+// session has open. Writes and deletes are read back and fail with
+// [ErrVerificationFailed] if they did not take effect, and every mutating
+// primitive supports DryRun.
+//
+// # Network Capture
+//
+// A page's data often arrives from a JSON API behind it. Capturing the
+// session's traffic gets that data directly, and shows which request failed
+// when a flow breaks:
+//
+//	c, err := s.StartCapture(ctx, browser.CaptureInput{URLPattern: `/api/`, Bodies: true})
+//	if err != nil {
+//		return err
+//	}
+//	// ... navigate and act ...
+//	if err := c.Stop(ctx); err != nil {
+//		return err
+//	}
+//	for _, e := range c.Entries() {
+//		fmt.Println(e.Status, e.Method, e.URL, e.Duration)
+//	}
+//	har, err := c.HAR() // for devtools or a HAR viewer
+//
+// Only requests made while the capture runs are recorded, so start it
+// before navigating.
+//
+// # Readable Articles
+//
+// [Session.ExtractReadable] is the starting point for summarising a page. It
+// loads the URL (or reads the current page) and returns an [Article] with the
+// title, byline, publication date, and body text as plain paragraphs:
+//
+//	a, err := s.ExtractReadable(ctx, browser.ExtractReadableInput{URL: link})
+//	if err != nil {
+//		return err
+//	}
+//	if a.WordCount < 50 {
+//		// Probably not an article; fall back to Find/Get on specific elements.
+//	}
+//
+// # Error Handling Pattern
+//
+// Errors are typed sentinels ([ErrInvalidURL], [ErrBrowserNotFound],
+// [ErrUnsupported], [ErrInvalidArgument], [ErrNavigation], [ErrProtocol],
+// [ErrSessionClosed], [ErrStaleRef], [ErrNotInteractable], [ErrNotFound],
+// [ErrAmbiguous], [ErrVerificationFailed], [ErrDownloadFailed],
+// [ErrProfileInUse], [ErrScript]) wrapped in [OpError] for operation context.
+// Check them with errors.Is, or use [ErrorCode] for a stable string code.
+package browser
@@ -0,0 +1,332 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownloadInput configures [Session.Download]. Set exactly one trigger: URL,
+// Ref, or Selector.
+type DownloadInput struct {
+	// URL is loaded in the session's tab. It must be served as a download
+	// (for example with Content-Disposition: attachment); a URL that renders
+	// as a page navigates the tab and fails with [ErrNavigation].
+	URL string `json:"url,omitempty"`
+	// Ref or Selector names a link or button that starts the download when
+	// clicked, as with [OpClick].
+	Ref      ElementRef `json:"ref,omitempty"`
+	Selector string     `json:"selector,omitempty"`
+	// Dir is the directory the file is saved in. It is required and is
+	// created if missing. The saved file never leaves Dir, whatever name the
+	// server suggests.
+	Dir string `json:"dir"`
+	// Filename overrides the server-suggested file name. It must be a plain
+	// name without directory separators.
+	Filename string `json:"filename,omitempty"`
+	// Overwrite replaces an existing file of the same name. By default a
+	// numbered suffix is added instead, as in "statement (1).pdf".
+	Overwrite bool `json:"overwrite,omitempty"`
+	// DryRun validates the input and, for click triggers, checks the element
+	// as [ActInput].DryRun does, without downloading.
+	DryRun bool `json:"dry_run,omitempty"`
+	// OnProgress, if set, is called as bytes arrive. It runs on the calling
+	// goroutine.
+	OnProgress func(DownloadProgress) `json:"-"`
+}
+
+// DownloadProgress reports a download in flight.
+type DownloadProgress struct {
+	URL           string `json:"url"`
+	ReceivedBytes int64  `json:"received_bytes"`
+	// TotalBytes is zero when the server did not send a length.
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// Download is a completed download.
+type Download struct {
+	// URL is the address the file was fetched from, after redirects.
+	URL string `json:"url"`
+	// SuggestedFilename is the name the server or link proposed.
+	SuggestedFilename string `json:"suggested_filename,omitempty"`
+	// Path is the absolute path of the saved file.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// SHA256 is the hex-encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// downloadPoll bounds how long Download sleeps between progress checks when
+// no events arrive.
+const downloadPoll = 500 * time.Millisecond
+
+// Download starts a download by loading input.URL or clicking an element,
+// waits for it to finish, and moves the file into input.Dir. It reports the
+// final path with the file's size and SHA-256 so callers can verify what
+// they saved. The first download the browser starts after the trigger is
+// the one tracked.
+//
+// Bound the wait with ctx; if ctx ends mid-download the download is
+// cancelled and its partial file removed. A download the browser cancels
+// or fails fails with [ErrDownloadFailed]. While Download runs, downloads
+// anywhere in the browser are saved to Dir, so avoid concurrent downloads in
+// other sessions of the same browser.
+func (s *Session) Download(ctx context.Context, input DownloadInput) (Download, error) {
+	id := input.URL
+	if id == "" {
+		id = ElementOp{Ref: input.Ref, Selector: input.Selector}.id()
+	}
+	set := 0
+	for _, v := range []string{input.URL, string(input.Ref), input.Selector} {
+		if strings.TrimSpace(v) != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return Download{}, newInvalidArg("Download", id, "exactly one of url, ref, or selector is required")
+	}
+	if input.URL != "" {
+		var err error
+		if input.URL, err = validateURL("Download", input.URL); err != nil {
+			return Download{}, err
+		}
+	}
+	dir, err := filepath.Abs(strings.TrimSpace(input.Dir))
+	if strings.TrimSpace(input.Dir) == "" || err != nil {
+		return Download{}, newInvalidArg("Download", id, "dir is required")
+	}
+	if input.Filename != "" && !isPlainFilename(input.Filename) {
+		return Download{}, newInvalidArg("Download", id, fmt.Sprintf("filename %q must be a plain file name", input.Filename))
+	}
+	if input.DryRun {
+		if input.URL == "" {
+			if err := callPage(ctx, s, "check", []any{clickOp(input)}, nil); err != nil {
+				return Download{}, &OpError{Op: "Download", ID: id, Err: err}
+			}
+		}
+		return Download{}, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Download{}, &OpError{Op: "Download", ID: id, Err: err}
+	}
+	d, err := s.download(ctx, dir, input)
+	if err != nil {
+		return Download{}, &OpError{Op: "Download", ID: id, Err: err}
+	}
+	return d, nil
+}
+
+// triggerDownload loads input.URL or clicks the element input names.
+func (s *Session) triggerDownload(ctx context.Context, input DownloadInput) error {
+	if input.URL == "" {
+		_, err := applyElementOp(ctx, s, clickOp(input))
+		return err
+	}
+	var res struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := s.call(ctx, "Page.navigate", map[string]any{"url": input.URL}, &res); err != nil {
+		return err
+	}
+	// A download aborts the navigation; anything else is a page.
+	switch res.ErrorText {
+	case "net::ERR_ABORTED":
+		return nil
+	case "":
+		return fmt.Errorf("%w: %s loaded as a page, not a download", ErrNavigation, input.URL)
+	default:
+		return fmt.Errorf("%w: %s", ErrNavigation, res.ErrorText)
+	}
+}
+
+func clickOp(input DownloadInput) ElementOp {
+	return ElementOp{Kind: OpClick, Ref: input.Ref, Selector: input.Selector}
+}
+
+// downloadState is the latest Browser.download* event data for one
+// download.
+type downloadState struct {
+	GUID              string `json:"guid"`
+	URL               string `json:"url"`
+	SuggestedFilename string `json:"suggestedFilename"`
+	State             string `json:"state"`
+	ReceivedBytes     int64  `json:"receivedBytes"`
+	TotalBytes        int64  `json:"totalBytes"`
+}
+
+// download routes browser downloads into dir, triggers input, and follows
+// the first download that begins until it completes.
+func (s *Session) download(ctx context.Context, dir string, input DownloadInput) (Download, error) {
+	var (
+		mu      sync.Mutex
+		current *downloadState
+		wake    = make(chan struct{}, 1)
+	)
+	notify := func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	unlistenBegin := s.conn.on("", "Browser.downloadWillBegin", func(params json.RawMessage) {
+		var ev downloadState
+		if json.Unmarshal(params, &ev) != nil {
+			return
+		}
+		mu.Lock()
+		if current == nil {
+			ev.State = "inProgress"
+			current = &ev
+		}
+		mu.Unlock()
+		notify()
+	})
+	defer unlistenBegin()
+	unlistenProgress := s.conn.on("", "Browser.downloadProgress", func(params json.RawMessage) {
+		var ev downloadState
+		if json.Unmarshal(params, &ev) != nil {
+			return
+		}
+		mu.Lock()
+		if current != nil && current.GUID == ev.GUID {
+			current.State, current.ReceivedBytes, current.TotalBytes = ev.State, ev.ReceivedBytes, ev.TotalBytes
+		}
+		mu.Unlock()
+		notify()
+	})
+	defer unlistenProgress()
+
+	behavior := map[string]any{"behavior": "allowAndName", "downloadPath": dir, "eventsEnabled": true}
+	if err := s.conn.call(ctx, "", "Browser.setDownloadBehavior", behavior, nil); err != nil {
+		return Download{}, err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.conn.call(ctx, "", "Browser.setDownloadBehavior", map[string]any{"behavior": "default"}, nil)
+	}()
+
+	if err := s.triggerDownload(ctx, input); err != nil {
+		return Download{}, err
+	}
+	var reported int64 = -1
+	for {
+		mu.Lock()
+		var st downloadState
+		if current != nil {
+			st = *current
+		}
+		mu.Unlock()
+		if st.GUID != "" && input.OnProgress != nil && st.ReceivedBytes != reported {
+			reported = st.ReceivedBytes
+			input.OnProgress(DownloadProgress{URL: st.URL, ReceivedBytes: st.ReceivedBytes, TotalBytes: st.TotalBytes})
+		}
+		switch st.State {
+		case "completed":
+			return finishDownload(dir, input, st)
+		case "canceled":
+			os.Remove(filepath.Join(dir, st.GUID))
+			return Download{}, fmt.Errorf("%w: browser cancelled the download of %s", ErrDownloadFailed, st.URL)
+		}
+		select {
+		case <-ctx.Done():
+			if st.GUID != "" {
+				cctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				s.conn.call(cctx, "", "Browser.cancelDownload", map[string]any{"guid": st.GUID}, nil)
+				cancel()
+				os.Remove(filepath.Join(dir, st.GUID))
+				return Download{}, fmt.Errorf("%w (received %d of %d bytes)", ctx.Err(), st.ReceivedBytes, st.TotalBytes)
+			}
+			return Download{}, fmt.Errorf("%w (no download started)", ctx.Err())
+		case <-s.conn.done:
+			return Download{}, s.conn.closedError()
+		case <-wake:
+		case <-time.After(downloadPoll):
+		}
+	}
+}
+
+// finishDownload moves the completed file, saved under its GUID, to its
+// final name in dir and hashes it.
+func finishDownload(dir string, input DownloadInput, st downloadState) (Download, error) {
+	src := filepath.Join(dir, st.GUID)
+	name := input.Filename
+	if name == "" {
+		name = safeFilename(st.SuggestedFilename)
+	}
+	dst := filepath.Join(dir, name)
+	if !input.Overwrite {
+		dst = uniquePath(dst)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		os.Remove(src)
+		return Download{}, fmt.Errorf("%w: %v", ErrDownloadFailed, err)
+	}
+	f, err := os.Open(dst)
+	if err != nil {
+		return Download{}, fmt.Errorf("%w: %v", ErrDownloadFailed, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return Download{}, fmt.Errorf("%w: %v", ErrDownloadFailed, err)
+	}
+	return Download{
+		URL:               st.URL,
+		SuggestedFilename: st.SuggestedFilename,
+		Path:              dst,
+		Size:              size,
+		SHA256:            hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// isPlainFilename reports whether name is a single path element.
+func isPlainFilename(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, `/\`) && filepath.Base(name) == name
+}
+
+// safeFilename reduces a server-suggested name to a plain file name.
+func safeFilename(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "download"
+	}
+	return name
+}
+
+// uniquePath returns path, or path with a " (n)" suffix before the extension
+// if path already exists.
+func uniquePath(path string) string {
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 1; ; n++ {
+		p := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if _, err := os.Lstat(p); errors.Is(err, fs.ErrNotExist) {
+			return p
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// fakeDownload makes Page.navigate on f behave like a download of body: it
+// writes the file under its GUID in the directory set by
+// Browser.setDownloadBehavior and emits the download events, ending in
+// state.
+func fakeDownload(t *testing.T, f *fakeCDP, name, body, state string) {
+	var dir string
+	f.handle("Browser.setDownloadBehavior", func(msg cdpMessage) (any, *cdpError) {
+		var p struct {
+			Behavior     string `json:"behavior"`
+			DownloadPath string `json:"downloadPath"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &p), nil)
+		if p.Behavior == "allowAndName" {
+			dir = p.DownloadPath
+		}
+		return struct{}{}, nil
+	})
+	f.handle("Page.navigate", func(msg cdpMessage) (any, *cdpError) {
+		var p struct {
+			URL string `json:"url"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &p), nil)
+		f.emit("", "Browser.downloadWillBegin", map[string]any{
+			"frameId": "T1", "guid": "g1", "url": p.URL, "suggestedFilename": name,
+		})
+		go func() {
+			be.Err(t, os.WriteFile(filepath.Join(dir, "g1"), []byte(body), 0o644), nil)
+			total := len(body)
+			f.emit("", "Browser.downloadProgress", map[string]any{"guid": "g1", "state": "inProgress", "receivedBytes": total / 2, "totalBytes": total})
+			f.emit("", "Browser.downloadProgress", map[string]any{"guid": "g1", "state": state, "receivedBytes": total, "totalBytes": total})
+		}()
+		return map[string]any{"frameId": "T1", "errorText": "net::ERR_ABORTED"}, nil
+	})
+}
+
+func TestDownloadURL(t *testing.T) {
+	f := newFakeCDP(t)
+	fakeDownload(t, f, "../statement.pdf", "hello", "completed")
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+	be.Err(t, os.WriteFile(filepath.Join(dir, "statement.pdf"), []byte("old"), 0o644), nil)
+
+	var progress []DownloadProgress
+	d, err := s.Download(ctx, DownloadInput{
+		URL:        "https://bank.example/statement",
+		Dir:        dir,
+		OnProgress: func(p DownloadProgress) { progress = append(progress, p) },
+	})
+	be.Err(t, err, nil)
+	// The suggested name is confined to dir and does not replace the
+	// existing file.
+	be.Equal(t, d.Path, filepath.Join(dir, "statement (1).pdf"))
+	be.Equal(t, d.SuggestedFilename, "../statement.pdf")
+	be.Equal(t, d.URL, "https://bank.example/statement")
+	be.Equal(t, d.Size, int64(5))
+	be.Equal(t, d.SHA256, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+	data, err := os.ReadFile(d.Path)
+	be.Err(t, err, nil)
+	be.Equal(t, string(data), "hello")
+	_, err = os.Stat(filepath.Join(dir, "g1"))
+	be.True(t, errors.Is(err, os.ErrNotExist))
+	be.True(t, len(progress) > 0)
+	be.Equal(t, progress[len(progress)-1], DownloadProgress{URL: d.URL, ReceivedBytes: 5, TotalBytes: 5})
+
+	reset, _ := f.lastCall("Browser.setDownloadBehavior")
+	be.Equal(t, string(reset.Params), `{"behavior":"default"}`)
+
+	d, err = s.Download(ctx, DownloadInput{URL: "https://bank.example/statement", Dir: dir, Filename: "statement.pdf", Overwrite: true})
+	be.Err(t, err, nil)
+	be.Equal(t, d.Path, filepath.Join(dir, "statement.pdf"))
+}
+
+func TestDownloadFailures(t *testing.T) {
+	f := newFakeCDP(t)
+	fakeDownload(t, f, "a.bin", "partial", "canceled")
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+
+	_, err := s.Download(ctx, DownloadInput{URL: "https://example.com/a.bin", Dir: dir})
+	be.True(t, errors.Is(err, ErrDownloadFailed))
+	be.Equal(t, ErrorCode(err), "download_failed")
+	entries, err := os.ReadDir(dir)
+	be.Err(t, err, nil)
+	be.Equal(t, len(entries), 0)
+
+	// A URL that loads as a page is not a download.
+	f.handle("Page.navigate", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"frameId": "T1", "loaderId": "L1"}, nil
+	})
+	_, err = s.Download(ctx, DownloadInput{URL: "https://example.com/", Dir: dir})
+	be.True(t, errors.Is(err, ErrNavigation))
+}
+
+func TestDownloadInvalid(t *testing.T) {
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	ctx := context.Background()
+	dir := t.TempDir()
+	invalid := []DownloadInput{
+		{Dir: dir},
+		{URL: "https://example.com/a", Selector: "a.download", Dir: dir},
+		{URL: "https://example.com/a"},
+		{URL: "https://example.com/a", Dir: dir, Filename: "../a"},
+		{URL: "https://example.com/a", Dir: dir, Filename: ".."},
+	}
+	for _, in := range invalid {
+		_, err := s.Download(ctx, in)
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+	_, err := s.Download(ctx, DownloadInput{URL: "statement.pdf", Dir: dir})
+	be.True(t, errors.Is(err, ErrInvalidURL))
+
+	// DryRun leaves the browser untouched.
+	_, err = s.Download(ctx, DownloadInput{URL: "https://example.com/a", Dir: filepath.Join(dir, "new"), DryRun: true})
+	be.Err(t, err, nil)
+	_, called := f.lastCall("Browser.setDownloadBehavior")
+	be.True(t, !called)
+	_, err = os.Stat(filepath.Join(dir, "new"))
+	be.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestSafeFilename(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":         "report.pdf",
+		"../../etc/passwd":   "passwd",
+		`C:\tmp\a.txt`:       "a.txt",
+		"..":                 "download",
+		"":                   "download",
+		".hidden":            "hidden",
+		"what?:<>.txt":       "what____.txt",
+		"line\nbreak.csv":    "line_break.csv",
+		"  spaced name.zip ": "spaced name.zip",
+	}
+	for in, want := range cases {
+		be.Equal(t, safeFilename(in), want)
+	}
+}
@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"context"
+	"time"
+)
+
+// Driver is the set of session primitives every backend provides. [Session]
+// drives Chrome over the DevTools protocol; [WebDriverSession] drives
+// Chrome, Firefox, Edge, or Safari through a W3C WebDriver server. Code
+// written against Driver runs on either, so the backend can be chosen by
+// what the environment permits.
+//
+// Some primitives exist only on [Session], because WebDriver has no
+// equivalent: Download, StartCapture, and waiting for network idle.
+type Driver interface {
+	Navigate(ctx context.Context, url string) error
+	WaitLoad(ctx context.Context) error
+	Screenshot(ctx context.Context, input ScreenshotInput) ([]byte, error)
+	Find(ctx context.Context, input FindInput) ([]ElementRef, error)
+	Get(ctx context.Context, input GetInput) ([]Element, error)
+	Act(ctx context.Context, input ActInput) ([]ActResult, error)
+	Snapshot(ctx context.Context, input SnapshotInput) (Snapshot, error)
+	Wait(ctx context.Context, input WaitInput) (WaitResult, error)
+	Eval(ctx context.Context, input EvalInput, out any) error
+	ExtractReadable(ctx context.Context, input ExtractReadableInput) (Article, error)
+	PrintToPDF(ctx context.Context, input PDFInput) (PDF, error)
+	Cookies(ctx context.Context, input CookiesInput) ([]Cookie, error)
+	SetCookies(ctx context.Context, input SetCookiesInput) ([]CookieResult, error)
+	ClearCookies(ctx context.Context, input ClearCookiesInput) ([]Cookie, error)
+	LocalStorage(ctx context.Context, input LocalStorageInput) (StorageItems, error)
+	SetLocalStorage(ctx context.Context, input SetLocalStorageInput) (StorageItems, error)
+	ClearLocalStorage(ctx context.Context, input ClearLocalStorageInput) (StorageItems, error)
+	Close() error
+}
+
+var (
+	_ Driver = (*Session)(nil)
+	_ Driver = (*WebDriverSession)(nil)
+)
+
+// page is what the page.js-based primitives need from a backend: script
+// evaluation, trusted input, and load tracking.
+type page interface {
+	Navigate(ctx context.Context, url string) error
+	WaitLoad(ctx context.Context) error
+	// evaluate runs expression in the page, awaiting it if it is a promise,
+	// and decodes its JSON value into out. A thrown exception returns
+	// [ErrScript].
+	evaluate(ctx context.Context, expression string, out any) error
+	click(ctx context.Context, x, y float64) error
+	insertText(ctx context.Context, text string) error
+	settle(ctx context.Context, timeout time.Duration)
+	waitNetworkIdle(ctx context.Context, input WaitInput) error
+	// done is closed when the session ends; closedError then explains why.
+	done() <-chan struct{}
+	closedError() error
+}
+
+func (s *Session) insertText(ctx context.Context, text string) error {
+	return s.call(ctx, "Input.insertText", map[string]any{"text": text}, nil)
+}
+
+func (s *Session) done() <-chan struct{} { return s.conn.done }
+
+func (s *Session) closedError() error { return s.conn.closedError() }
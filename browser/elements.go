@@ -0,0 +1,410 @@
+package browser
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//go:embed page.js
+var pageLib string
+
+// ElementRef identifies an element found by [Session.Find]. Refs are scoped to
+// the current document: they stay valid until the element is removed or the
+// tab navigates, after which operations on them fail with [ErrStaleRef].
+// A ref carries a random per-document nonce, so one from a previous page
+// never resolves to an element of the next; treat refs as opaque.
+type ElementRef string
+
+// FindInput selects elements in the session's page. Every non-empty criterion
+// must match; at least one is required.
+type FindInput struct {
+	// Selector is a CSS selector, for example "form#login input[type=email]".
+	Selector string `json:"selector,omitempty"`
+	// Text matches elements whose rendered text contains this value,
+	// case-insensitively with whitespace collapsed. Only the innermost
+	// matching elements are returned, not their ancestors.
+	Text string `json:"text,omitempty"`
+	// Role matches the ARIA role, explicit or implied by the tag, such as
+	// "button", "link", "textbox", or "heading".
+	Role string `json:"role,omitempty"`
+	// Name matches elements whose accessible name (aria-label, label text,
+	// alt, title, placeholder, or text) contains this value,
+	// case-insensitively.
+	Name string `json:"name,omitempty"`
+	// IncludeHidden also returns elements that are not rendered or have no
+	// size.
+	IncludeHidden bool `json:"include_hidden,omitempty"`
+	// Limit caps the number of refs returned. Zero means no limit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// GetInput lists the elements to hydrate with [Session.Get].
+type GetInput struct {
+	Refs []ElementRef `json:"refs"`
+	// MaxText truncates each element's Text to this many characters. Zero
+	// means no limit.
+	MaxText int `json:"max_text,omitempty"`
+}
+
+// Rect is a box in CSS pixels relative to the top-left of the viewport.
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Element is the state of one element as reported by [Session.Get].
+type Element struct {
+	Ref  ElementRef `json:"ref"`
+	Tag  string     `json:"tag"`
+	Role string     `json:"role,omitempty"`
+	Name string     `json:"name,omitempty"`
+	// Text is the rendered text with whitespace collapsed.
+	Text string `json:"text,omitempty"`
+	// Value is the current value of form controls.
+	Value      string            `json:"value,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Box        Rect              `json:"box"`
+	Visible    bool              `json:"visible"`
+	Enabled    bool              `json:"enabled"`
+	// Err is set, and the other fields are empty, when Ref no longer
+	// resolves.
+	Err error `json:"-"`
+}
+
+// ElementOpKind names an action applied by [Session.Act].
+type ElementOpKind string
+
+const (
+	// OpClick clicks the centre of the element with trusted mouse events.
+	OpClick ElementOpKind = "click"
+	// OpType focuses the element and inserts Text as if typed.
+	OpType ElementOpKind = "type"
+	// OpSelect chooses the <select> option whose value or label equals
+	// Value.
+	OpSelect ElementOpKind = "select"
+	// OpCheck clicks a checkbox, switch, or radio button unless it is
+	// already checked.
+	OpCheck ElementOpKind = "check"
+	// OpUncheck clicks a checkbox or switch unless it is already unchecked.
+	OpUncheck ElementOpKind = "uncheck"
+	// OpSubmit submits the element's form: a submit button is clicked, and
+	// any other element submits its enclosing form as if Enter were pressed.
+	OpSubmit ElementOpKind = "submit"
+	// OpScroll scrolls the element into view, or with no element scrolls
+	// the page by DeltaX and DeltaY.
+	OpScroll ElementOpKind = "scroll"
+)
+
+// ElementOp is one explicit action for [Session.Act]. The element is given
+// by Ref or Selector; exactly one is required except for a page OpScroll.
+type ElementOp struct {
+	Kind ElementOpKind `json:"kind"`
+	Ref  ElementRef    `json:"ref,omitempty"`
+	// Selector is a CSS selector that must match exactly one visible
+	// element, otherwise the op fails with [ErrNotFound] or [ErrAmbiguous].
+	Selector string `json:"selector,omitempty"`
+	// Text is inserted by OpType.
+	Text string `json:"text,omitempty"`
+	// Clear empties the field before OpType inserts Text.
+	Clear bool `json:"clear,omitempty"`
+	// Value is the option value or label chosen by OpSelect.
+	Value string `json:"value,omitempty"`
+	// DeltaX and DeltaY are the page scroll offsets, in CSS pixels, for an
+	// OpScroll without an element.
+	DeltaX float64 `json:"delta_x,omitempty"`
+	DeltaY float64 `json:"delta_y,omitempty"`
+}
+
+// ActInput is an ordered list of element operations.
+type ActInput struct {
+	Ops []ElementOp `json:"ops"`
+	// DryRun checks every op against the page (the element resolves and is
+	// visible, enabled, and not covered, the option exists) without acting.
+	// Targets may be scrolled into view.
+	DryRun bool `json:"dry_run,omitempty"`
+	// ContinueOnError applies the remaining ops after one fails. By default
+	// they are skipped, since later steps usually depend on earlier ones.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+	// SettleTimeout bounds the wait after each applied op for navigation and
+	// DOM updates it triggered to finish. Zero means 5 seconds; negative
+	// disables the wait.
+	SettleTimeout time.Duration `json:"settle_timeout,omitempty"`
+}
+
+// ActResult is the outcome of one [ElementOp].
+type ActResult struct {
+	Op ElementOp `json:"op"`
+	// Ref is the element the op acted on, useful when it was addressed by
+	// Selector. It is empty for page scrolls and when the element could not
+	// be resolved.
+	Ref ElementRef `json:"ref,omitempty"`
+	// Applied is true once the op has been performed. It is always false for
+	// dry runs.
+	Applied bool `json:"applied"`
+	// Skipped is true when an earlier op failed and ContinueOnError is off.
+	Skipped bool  `json:"skipped,omitempty"`
+	Err     error `json:"-"`
+}
+
+// Find returns refs for the elements matching input, in document order.
+// Hidden elements are excluded unless IncludeHidden is set. No match is not an
+// error: the result is empty.
+func (s *Session) Find(ctx context.Context, input FindInput) ([]ElementRef, error) {
+	return find(ctx, s, input)
+}
+
+func find(ctx context.Context, s page, input FindInput) ([]ElementRef, error) {
+	if strings.TrimSpace(input.Selector) == "" && strings.TrimSpace(input.Text) == "" &&
+		strings.TrimSpace(input.Role) == "" && strings.TrimSpace(input.Name) == "" {
+		return nil, newInvalidArg("Find", "", "one of selector, text, role, or name is required")
+	}
+	if input.Limit < 0 {
+		return nil, newInvalidArg("Find", "", "limit must not be negative")
+	}
+	var refs []ElementRef
+	if err := callPage(ctx, s, "find", []any{input}, &refs); err != nil {
+		return nil, &OpError{Op: "Find", Err: err}
+	}
+	return refs, nil
+}
+
+// Get hydrates refs with their text, attributes, bounding box, and state.
+// Results follow the order of input.Refs; a ref that no longer resolves gets
+// an Element whose Err wraps [ErrStaleRef].
+func (s *Session) Get(ctx context.Context, input GetInput) ([]Element, error) {
+	return get(ctx, s, input)
+}
+
+func get(ctx context.Context, s page, input GetInput) ([]Element, error) {
+	if input.MaxText < 0 {
+		return nil, newInvalidArg("Get", "", "maxText must not be negative")
+	}
+	if len(input.Refs) == 0 {
+		return nil, nil
+	}
+	var raw []struct {
+		Element
+		Error *pageError `json:"error"`
+	}
+	if err := callPage(ctx, s, "get", []any{input.Refs, input.MaxText}, &raw); err != nil {
+		return nil, &OpError{Op: "Get", Err: err}
+	}
+	out := make([]Element, len(raw))
+	for i, r := range raw {
+		out[i] = r.Element
+		if r.Error != nil {
+			out[i] = Element{Ref: r.Ref, Err: &OpError{Op: "Get", ID: string(r.Ref), Err: r.Error.err()}}
+		}
+	}
+	return out, nil
+}
+
+// Act applies input.Ops in order and reports one [ActResult] per op. Clicks
+// and typing go through the browser's input pipeline, so pages see trusted
+// events. Before acting on an element Act scrolls it into view and checks that
+// it is visible, enabled, and not covered by another element; otherwise the
+// op fails with [ErrNotInteractable]. After each op Act waits for any page
+// load and DOM updates it caused to settle (see ActInput.SettleTimeout), so
+// the next op sees the resulting page. OpCheck and OpUncheck read the state
+// back and fail with [ErrVerificationFailed] if the click did not change it.
+//
+// Per-op failures are reported in ActResult.Err. The returned error is
+// non-nil only for invalid input or when ctx is done.
+func (s *Session) Act(ctx context.Context, input ActInput) ([]ActResult, error) {
+	return act(ctx, s, input)
+}
+
+func act(ctx context.Context, s page, input ActInput) ([]ActResult, error) {
+	if len(input.Ops) == 0 {
+		return nil, newInvalidArg("Act", "", "at least one op is required")
+	}
+	for i, op := range input.Ops {
+		if err := validateElementOp(op); err != nil {
+			return nil, newInvalidArg("Act", fmt.Sprintf("op %d", i), err.Error())
+		}
+	}
+	results := make([]ActResult, len(input.Ops))
+	failed := false
+	for i, op := range input.Ops {
+		results[i].Op = op
+		if failed && !input.ContinueOnError {
+			results[i].Skipped = true
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		var (
+			t   opTarget
+			err error
+		)
+		if input.DryRun {
+			err = callPage(ctx, s, "check", []any{op}, &t)
+		} else {
+			t, err = applyElementOp(ctx, s, op)
+			results[i].Applied = err == nil
+		}
+		results[i].Ref = t.Ref
+		if err != nil {
+			results[i].Err = &OpError{Op: "Act", ID: op.id(), Err: err}
+			failed = true
+			continue
+		}
+		if !input.DryRun {
+			s.settle(ctx, input.SettleTimeout)
+		}
+	}
+	return results, nil
+}
+
+// id names op's element in errors.
+func (op ElementOp) id() string {
+	if op.Ref != "" {
+		return string(op.Ref)
+	}
+	return op.Selector
+}
+
+func validateElementOp(op ElementOp) error {
+	if op.Ref != "" && op.Selector != "" {
+		return fmt.Errorf("%s takes a ref or a selector, not both", op.Kind)
+	}
+	switch op.Kind {
+	case OpClick, OpCheck, OpUncheck, OpSubmit:
+	case OpType:
+		if op.Text == "" && !op.Clear {
+			return fmt.Errorf("type needs text or clear")
+		}
+	case OpSelect:
+		if op.Value == "" {
+			return fmt.Errorf("select needs a value")
+		}
+	case OpScroll:
+		if op.Ref == "" && op.Selector == "" && op.DeltaX == 0 && op.DeltaY == 0 {
+			return fmt.Errorf("scroll needs a ref, a selector, or a delta")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported op kind %q", op.Kind)
+	}
+	if op.Ref == "" && strings.TrimSpace(op.Selector) == "" {
+		return fmt.Errorf("%s needs a ref or a selector", op.Kind)
+	}
+	return nil
+}
+
+// opTarget is page.js's answer to check and perform: the resolved element
+// and, when Click is set, the viewport point to click.
+type opTarget struct {
+	Ref   ElementRef `json:"ref"`
+	Click bool       `json:"click"`
+	X     float64    `json:"x"`
+	Y     float64    `json:"y"`
+}
+
+// applyElementOp performs op. The page script prepares the element; clicks
+// and text then go through the backend's trusted input.
+func applyElementOp(ctx context.Context, s page, op ElementOp) (opTarget, error) {
+	var t opTarget
+	if err := callPage(ctx, s, "perform", []any{op}, &t); err != nil {
+		return t, err
+	}
+	if t.Click {
+		if err := s.click(ctx, t.X, t.Y); err != nil {
+			return t, err
+		}
+	}
+	switch op.Kind {
+	case OpType:
+		if op.Text != "" {
+			return t, s.insertText(ctx, op.Text)
+		}
+	case OpCheck, OpUncheck:
+		var checked bool
+		if err := callPage(ctx, s, "checked", []any{t.Ref}, &checked); err != nil {
+			return t, err
+		}
+		if checked != (op.Kind == OpCheck) {
+			return t, fmt.Errorf("%w: %s did not change the checked state", ErrVerificationFailed, op.Kind)
+		}
+	}
+	return t, nil
+}
+
+// click sends a left-button press and release at viewport point (x, y).
+func (s *Session) click(ctx context.Context, x, y float64) error {
+	for _, typ := range []string{"mouseMoved", "mousePressed", "mouseReleased"} {
+		params := map[string]any{"type": typ, "x": x, "y": y}
+		if typ != "mouseMoved" {
+			params["button"] = "left"
+			params["clickCount"] = 1
+		}
+		if err := s.call(ctx, "Input.dispatchMouseEvent", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageError is an error reported by page.js.
+type pageError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *pageError) err() error {
+	switch e.Code {
+	case "stale_ref":
+		return fmt.Errorf("%w: %s", ErrStaleRef, e.Message)
+	case "not_interactable":
+		return fmt.Errorf("%w: %s", ErrNotInteractable, e.Message)
+	case "invalid_argument":
+		return fmt.Errorf("%w: %s", ErrInvalidArgument, e.Message)
+	case "not_found":
+		return fmt.Errorf("%w: %s", ErrNotFound, e.Message)
+	case "ambiguous":
+		return fmt.Errorf("%w: %s", ErrAmbiguous, e.Message)
+	case "unsupported":
+		return fmt.Errorf("%w: %s", ErrUnsupported, e.Message)
+	default:
+		return fmt.Errorf("%w: %s", ErrProtocol, e.Message)
+	}
+}
+
+// callPage evaluates fn from page.js with args and decodes its value into
+// out, which may be nil.
+func callPage(ctx context.Context, s page, fn string, args []any, out any) error {
+	if args == nil {
+		args = []any{}
+	}
+	rawArgs, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	return evaluatePage(ctx, s, "("+pageLib+")()."+fn+"(..."+string(rawArgs)+")", out)
+}
+
+// evaluatePage evaluates an expression that yields a page.js {value, error}
+// envelope and decodes the value into out, which may be nil.
+func evaluatePage(ctx context.Context, s page, expr string, out any) error {
+	var res struct {
+		Value json.RawMessage `json:"value"`
+		Error *pageError      `json:"error"`
+	}
+	if err := s.evaluate(ctx, expr, &res); err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return res.Error.err()
+	}
+	if out == nil || len(res.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Value, out)
+}
@@ -0,0 +1,316 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// handlePage answers page.js calls on f with fn, which receives the method
+// name and its JSON arguments and returns the {value}/{error} envelope.
+// Settle calls made by Act are answered directly.
+func handlePage(t *testing.T, f *fakeCDP, fn func(method string, args []json.RawMessage) any) {
+	f.handle("Runtime.evaluate", func(msg cdpMessage) (any, *cdpError) {
+		var params struct {
+			Expression string `json:"expression"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &params), nil)
+		// The expression is "(<page.js>)().<method>(...<json args>)".
+		call := strings.TrimPrefix(params.Expression, "("+pageLib+")().")
+		method, rawArgs, ok := strings.Cut(call, "(...")
+		be.True(t, ok)
+		var args []json.RawMessage
+		be.Err(t, json.Unmarshal([]byte(strings.TrimSuffix(rawArgs, ")")), &args), nil)
+		var value any = map[string]any{"value": true}
+		if method != "settle" {
+			value = fn(method, args)
+		}
+		return map[string]any{"result": map[string]any{"type": "object", "value": value}}, nil
+	})
+}
+
+func TestFind(t *testing.T) {
+	f := newFakeCDP(t)
+	var gotQuery map[string]any
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "find")
+		be.Err(t, json.Unmarshal(args[0], &gotQuery), nil)
+		return map[string]any{"value": []string{"e1", "e2"}}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	refs, err := s.Find(ctx, FindInput{Role: "button", Name: "Sign in", Limit: 2})
+	be.Err(t, err, nil)
+	be.Equal(t, refs, []ElementRef{"e1", "e2"})
+	be.Equal(t, gotQuery, map[string]any{"role": "button", "name": "Sign in", "limit": 2.0})
+
+	_, err = s.Find(ctx, FindInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.Find(ctx, FindInput{Selector: "a", Limit: -1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestFindInvalidSelector(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(string, []json.RawMessage) any {
+		return map[string]any{"error": map[string]any{"code": "invalid_argument", "message": "invalid selector"}}
+	})
+	s := attachFake(t, f)
+
+	_, err := s.Find(context.Background(), FindInput{Selector: "(("})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestGet(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "get")
+		be.Equal(t, string(args[1]), "100")
+		return map[string]any{"value": []any{
+			map[string]any{
+				"ref": "e1", "tag": "a", "role": "link", "name": "Docs", "text": "Docs",
+				"attributes": map[string]string{"href": "/docs"},
+				"box":        map[string]any{"x": 10, "y": 20, "width": 30, "height": 40},
+				"visible":    true, "enabled": true,
+			},
+			map[string]any{"ref": "e9", "error": map[string]any{"code": "stale_ref", "message": "gone"}},
+		}}
+	})
+	s := attachFake(t, f)
+
+	els, err := s.Get(context.Background(), GetInput{Refs: []ElementRef{"e1", "e9"}, MaxText: 100})
+	be.Err(t, err, nil)
+	be.Equal(t, len(els), 2)
+	be.Err(t, els[0].Err, nil)
+	be.Equal(t, els[0].Attributes["href"], "/docs")
+	be.Equal(t, els[0].Box, Rect{X: 10, Y: 20, Width: 30, Height: 40})
+	be.True(t, els[0].Visible)
+	be.Equal(t, els[1].Ref, ElementRef("e9"))
+	be.True(t, errors.Is(els[1].Err, ErrStaleRef))
+
+	data, err := json.Marshal(els[1])
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(data), `"code":"stale_ref"`))
+}
+
+func TestAct(t *testing.T) {
+	f := newFakeCDP(t)
+	var pageCalls []string
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		var op ElementOp
+		be.Err(t, json.Unmarshal(args[0], &op), nil)
+		pageCalls = append(pageCalls, method+":"+string(op.Kind))
+		if op.Ref == "covered" {
+			return map[string]any{"error": map[string]any{"code": "not_interactable", "message": "covered"}}
+		}
+		if op.Kind == OpClick {
+			return map[string]any{"value": map[string]any{"ref": op.Ref, "click": true, "x": 5, "y": 6}}
+		}
+		return map[string]any{"value": map[string]any{"ref": op.Ref}}
+	})
+	f.handle("Input.dispatchMouseEvent", func(cdpMessage) (any, *cdpError) { return struct{}{}, nil })
+	f.handle("Input.insertText", func(cdpMessage) (any, *cdpError) { return struct{}{}, nil })
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	results, err := s.Act(ctx, ActInput{SettleTimeout: -1, Ops: []ElementOp{
+		{Kind: OpType, Ref: "e1", Text: "me@example.com", Clear: true},
+		{Kind: OpSelect, Ref: "e2", Value: "CA"},
+		{Kind: OpClick, Ref: "e3"},
+		{Kind: OpScroll, DeltaY: 400},
+	}})
+	be.Err(t, err, nil)
+	for _, r := range results {
+		be.Err(t, r.Err, nil)
+		be.True(t, r.Applied)
+		be.Equal(t, r.Ref, r.Op.Ref)
+	}
+	be.Equal(t, pageCalls, []string{"perform:type", "perform:select", "perform:click", "perform:scroll"})
+	text, _ := f.lastCall("Input.insertText")
+	be.Equal(t, string(text.Params), `{"text":"me@example.com"}`)
+	mouse, _ := f.lastCall("Input.dispatchMouseEvent")
+	be.Equal(t, string(mouse.Params), `{"button":"left","clickCount":1,"type":"mouseReleased","x":5,"y":6}`)
+
+	// A failure skips the remaining ops unless ContinueOnError is set.
+	pageCalls = nil
+	results, err = s.Act(ctx, ActInput{SettleTimeout: -1, Ops: []ElementOp{
+		{Kind: OpClick, Ref: "covered"},
+		{Kind: OpClick, Ref: "e3"},
+	}})
+	be.Err(t, err, nil)
+	be.True(t, errors.Is(results[0].Err, ErrNotInteractable))
+	be.True(t, !results[0].Applied)
+	be.True(t, results[1].Skipped)
+	be.Equal(t, pageCalls, []string{"perform:click"})
+
+	data, err := json.Marshal(results[0])
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(data), `"code":"not_interactable"`))
+}
+
+func TestActSelectorCheckSubmit(t *testing.T) {
+	f := newFakeCDP(t)
+	var pageCalls []string
+	checked := map[string]bool{}
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		if method == "checked" {
+			var ref string
+			be.Err(t, json.Unmarshal(args[0], &ref), nil)
+			pageCalls = append(pageCalls, "checked:"+ref)
+			return map[string]any{"value": checked[ref]}
+		}
+		var op ElementOp
+		be.Err(t, json.Unmarshal(args[0], &op), nil)
+		pageCalls = append(pageCalls, method+":"+string(op.Kind))
+		switch op.Selector {
+		case "#missing":
+			return map[string]any{"error": map[string]any{"code": "not_found", "message": "no match"}}
+		case "input":
+			return map[string]any{"error": map[string]any{"code": "ambiguous", "message": "3 matches"}}
+		case "#terms":
+			return map[string]any{"value": map[string]any{"ref": "e7", "click": true, "x": 1, "y": 2}}
+		case "#stuck":
+			return map[string]any{"value": map[string]any{"ref": "e8", "click": true, "x": 1, "y": 2}}
+		}
+		return map[string]any{"value": map[string]any{"ref": "e9"}}
+	})
+	f.handle("Input.dispatchMouseEvent", func(msg cdpMessage) (any, *cdpError) {
+		if strings.Contains(string(msg.Params), "mouseReleased") {
+			checked["e7"] = true
+		}
+		return struct{}{}, nil
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	results, err := s.Act(ctx, ActInput{SettleTimeout: -1, ContinueOnError: true, Ops: []ElementOp{
+		{Kind: OpCheck, Selector: "#terms"},
+		{Kind: OpSubmit, Selector: "form"},
+		{Kind: OpCheck, Selector: "#stuck"},
+		{Kind: OpClick, Selector: "#missing"},
+		{Kind: OpType, Selector: "input", Text: "x"},
+	}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+	be.Equal(t, results[0].Ref, ElementRef("e7"))
+	be.Err(t, results[1].Err, nil)
+	be.Equal(t, results[1].Ref, ElementRef("e9"))
+	be.True(t, errors.Is(results[2].Err, ErrVerificationFailed))
+	be.True(t, errors.Is(results[3].Err, ErrNotFound))
+	be.True(t, errors.Is(results[4].Err, ErrAmbiguous))
+	be.Equal(t, pageCalls, []string{
+		"perform:check", "checked:e7", "perform:submit", "perform:check", "checked:e8",
+		"perform:click", "perform:type",
+	})
+
+	data, err := json.Marshal(results[4])
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(data), `"code":"ambiguous"`))
+	be.True(t, strings.Contains(string(data), `"id":"input"`))
+}
+
+func TestActSettle(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(string, []json.RawMessage) any {
+		return map[string]any{"value": map[string]any{"ref": "e1", "click": true, "x": 1, "y": 2}}
+	})
+	f.handle("Input.dispatchMouseEvent", func(msg cdpMessage) (any, *cdpError) {
+		if strings.Contains(string(msg.Params), "mouseReleased") {
+			// The click starts a navigation that finishes a little later.
+			f.emit("S1", "Page.frameStartedLoading", map[string]any{"frameId": "T1"})
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				f.emit("S1", "Page.frameStoppedLoading", map[string]any{"frameId": "T1"})
+			}()
+		}
+		return struct{}{}, nil
+	})
+	s := attachFake(t, f)
+
+	start := time.Now()
+	results, err := s.Act(context.Background(), ActInput{Ops: []ElementOp{{Kind: OpClick, Ref: "e1"}}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+	be.True(t, time.Since(start) >= 200*time.Millisecond)
+	settle, _ := f.lastCall("Runtime.evaluate")
+	be.True(t, strings.Contains(string(settle.Params), "().settle("))
+
+	// Loading events for subframes do not hold Act up.
+	f.handle("Input.dispatchMouseEvent", func(msg cdpMessage) (any, *cdpError) {
+		f.emit("S1", "Page.frameStartedLoading", map[string]any{"frameId": "F2"})
+		return struct{}{}, nil
+	})
+	start = time.Now()
+	_, err = s.Act(context.Background(), ActInput{Ops: []ElementOp{{Kind: OpClick, Ref: "e1"}}})
+	be.Err(t, err, nil)
+	be.True(t, time.Since(start) < time.Second)
+}
+
+func TestActDryRun(t *testing.T) {
+	f := newFakeCDP(t)
+	var pageCalls []string
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		pageCalls = append(pageCalls, method)
+		return map[string]any{"value": nil}
+	})
+	s := attachFake(t, f)
+
+	results, err := s.Act(context.Background(), ActInput{
+		DryRun: true,
+		Ops:    []ElementOp{{Kind: OpClick, Ref: "e1"}, {Kind: OpType, Ref: "e2", Text: "x"}},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, pageCalls, []string{"check", "check"})
+	for _, r := range results {
+		be.Err(t, r.Err, nil)
+		be.True(t, !r.Applied)
+	}
+	for _, m := range f.methods() {
+		be.True(t, !strings.HasPrefix(m, "Input."))
+	}
+}
+
+func TestValidateElementOp(t *testing.T) {
+	valid := []ElementOp{
+		{Kind: OpClick, Ref: "e1"},
+		{Kind: OpType, Ref: "e1", Text: "hi"},
+		{Kind: OpType, Ref: "e1", Clear: true},
+		{Kind: OpSelect, Ref: "e1", Value: "a"},
+		{Kind: OpScroll, Ref: "e1"},
+		{Kind: OpScroll, DeltaY: -100},
+		{Kind: OpClick, Selector: "#go"},
+		{Kind: OpCheck, Ref: "e1"},
+		{Kind: OpUncheck, Selector: "#terms"},
+		{Kind: OpSubmit, Ref: "e1"},
+		{Kind: OpScroll, Selector: "footer"},
+	}
+	for _, op := range valid {
+		be.Err(t, validateElementOp(op), nil)
+	}
+	invalid := []ElementOp{
+		{Kind: "hover", Ref: "e1"},
+		{Kind: OpClick},
+		{Kind: OpType, Ref: "e1"},
+		{Kind: OpSelect, Ref: "e1"},
+		{Kind: OpScroll},
+		{Kind: OpClick, Ref: "e1", Selector: "#go"},
+		{Kind: OpCheck},
+		{Kind: OpSubmit, Selector: "  "},
+	}
+	for _, op := range invalid {
+		be.Err(t, validateElementOp(op))
+	}
+
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	_, err := s.Act(context.Background(), ActInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.Act(context.Background(), ActInput{Ops: invalid[:1]})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
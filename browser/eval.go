@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EvalInput is JavaScript to run in the session's page.
+type EvalInput struct {
+	// Script is a JavaScript expression. If it evaluates to a function, the
+	// function is called with Args; if the result is a promise, it is
+	// awaited. For example:
+	//
+	//	"document.title"
+	//	"(sel) => document.querySelectorAll(sel).length"
+	//	"async (el) => { el.click(); return el.dataset.state }"
+	Script string `json:"script"`
+	// Args are JSON-encoded and passed to the function. An [ElementRef] is
+	// passed as the element it refers to.
+	Args []any `json:"args,omitempty"`
+}
+
+// Eval runs input.Script in the page and decodes its JSON-serialised result
+// into out, as json.Unmarshal would; out may be nil to discard it. Results
+// must survive JSON.stringify: DOM nodes, functions, and cycles do not, and
+// undefined leaves out unchanged.
+//
+// Eval is the escape hatch for extraction and interaction steps that
+// [Session.Find], [Session.Get], and [Session.Act] do not cover. A syntax
+// error or thrown exception fails with [ErrScript]; a stale ElementRef
+// argument fails with [ErrStaleRef].
+func (s *Session) Eval(ctx context.Context, input EvalInput, out any) error {
+	return eval(ctx, s, input, out)
+}
+
+func eval(ctx context.Context, s page, input EvalInput, out any) error {
+	if strings.TrimSpace(input.Script) == "" {
+		return newInvalidArg("Eval", "", "script is required")
+	}
+	expr, err := evalExpr(input.Script, input.Args)
+	if err != nil {
+		return newInvalidArg("Eval", "", err.Error())
+	}
+	if err := evaluatePage(ctx, s, expr, out); err != nil {
+		return &OpError{Op: "Eval", Err: err}
+	}
+	return nil
+}
+
+// evalExpr wraps script in an expression that resolves ElementRef args,
+// calls script if it is a function, and yields a page.js {value, error}
+// envelope.
+func evalExpr(script string, rawArgs []any) (string, error) {
+	args := make([]any, len(rawArgs))
+	for i, a := range rawArgs {
+		if ref, ok := a.(ElementRef); ok {
+			a = map[string]string{"__cuh_ref": string(ref)}
+		}
+		args[i] = a
+	}
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("args are not JSON-encodable: %v", err)
+	}
+	// The script is parenthesised on its own lines so a trailing line
+	// comment cannot swallow the rest of the wrapper.
+	return "(async () => {\n" +
+		"const args = (" + pageLib + ")().args(" + string(encoded) + ");\n" +
+		"if (args.error) return args;\n" +
+		"const fn = (\n" + script + "\n);\n" +
+		"return { value: typeof fn === \"function\" ? await fn(...args.value) : await fn };\n" +
+		"})()", nil
+}
@@ -0,0 +1,58 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nalgeon/be"
+)
+
+func TestEval(t *testing.T) {
+	f := newFakeCDP(t)
+	var expr string
+	f.handle("Runtime.evaluate", func(msg cdpMessage) (any, *cdpError) {
+		var params struct {
+			Expression   string `json:"expression"`
+			AwaitPromise bool   `json:"awaitPromise"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &params), nil)
+		be.True(t, params.AwaitPromise)
+		expr = params.Expression
+		if strings.Contains(expr, "throw") {
+			return map[string]any{
+				"result":           map[string]any{"type": "object"},
+				"exceptionDetails": map[string]any{"text": "Uncaught", "exception": map[string]any{"description": "Error: boom"}},
+			}, nil
+		}
+		return map[string]any{"result": map[string]any{"type": "object", "value": map[string]any{
+			"value": map[string]any{"count": 3, "names": []string{"a", "b"}},
+		}}}, nil
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	var got struct {
+		Count int      `json:"count"`
+		Names []string `json:"names"`
+	}
+	err := s.Eval(ctx, EvalInput{Script: "(el, sel) => el.querySelectorAll(sel).length", Args: []any{ElementRef("e4"), "li"}}, &got)
+	be.Err(t, err, nil)
+	be.Equal(t, got.Count, 3)
+	be.Equal(t, got.Names, []string{"a", "b"})
+	be.True(t, strings.Contains(expr, `.args([{"__cuh_ref":"e4"},"li"])`))
+	be.True(t, strings.Contains(expr, "(el, sel) => el.querySelectorAll(sel).length"))
+
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "document.title"}, nil), nil)
+
+	err = s.Eval(ctx, EvalInput{Script: "() => { throw new Error('boom') }"}, nil)
+	be.True(t, errors.Is(err, ErrScript))
+	be.True(t, strings.Contains(err.Error(), "Error: boom"))
+
+	err = s.Eval(ctx, EvalInput{Script: "  "}, nil)
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = s.Eval(ctx, EvalInput{Script: "x => x", Args: []any{func() {}}}, nil)
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
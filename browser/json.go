@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// JSON encoding
+//
+// Public types carry snake_case JSON tags so they can be used directly as
+// agent tool payloads. Errors encode as {"op", "id", "code", "message"}
+// objects.
+
+// ErrorCode returns a stable snake_case code for err's sentinel cause, such
+// as "stale_ref" or "invalid_argument", or "internal" for other errors. It
+// returns "" for a nil error.
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, ErrInvalidURL):
+		return "invalid_url"
+	case errors.Is(err, ErrBrowserNotFound):
+		return "browser_not_found"
+	case errors.Is(err, ErrUnsupported):
+		return "unsupported"
+	case errors.Is(err, ErrSessionClosed):
+		return "session_closed"
+	case errors.Is(err, ErrProtocol):
+		return "protocol"
+	case errors.Is(err, ErrNavigation):
+		return "navigation"
+	case errors.Is(err, ErrStaleRef):
+		return "stale_ref"
+	case errors.Is(err, ErrNotInteractable):
+		return "not_interactable"
+	case errors.Is(err, ErrScript):
+		return "script"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrAmbiguous):
+		return "ambiguous"
+	case errors.Is(err, ErrVerificationFailed):
+		return "verification_failed"
+	case errors.Is(err, ErrDownloadFailed):
+		return "download_failed"
+	case errors.Is(err, ErrProfileInUse):
+		return "profile_in_use"
+	default:
+		return "internal"
+	}
+}
+
+type errorJSON struct {
+	Op      string `json:"op,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	out := &errorJSON{Code: ErrorCode(err), Message: err.Error()}
+	var op *OpError
+	if errors.As(err, &op) {
+		out.Op, out.ID = op.Op, op.ID
+	}
+	return out
+}
+
+// MarshalJSON encodes the error as {"op", "id", "code", "message"}.
+func (e *OpError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newErrorJSON(e))
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (e Element) MarshalJSON() ([]byte, error) {
+	type plain Element
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(e), newErrorJSON(e.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r ActResult) MarshalJSON() ([]byte, error) {
+	type plain ActResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r CookieResult) MarshalJSON() ([]byte, error) {
+	type plain CookieResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
@@ -0,0 +1,235 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// OpenURLInput specifies a URL to hand to a browser.
+type OpenURLInput struct {
+	// URL must be absolute, for example "https://example.com" or
+	// "file:///tmp/report.html".
+	URL string `json:"url"`
+	// Browser selects the application. Empty means the system default. On
+	// macOS it is an application name or bundle path passed to `open -a`
+	// ("Safari", "Google Chrome"); elsewhere it is an executable name or
+	// path ("firefox", "chromium").
+	Browser string `json:"browser,omitempty"`
+	// Background opens the URL without bringing the browser to the front.
+	// Only macOS supports it; elsewhere it fails with [ErrUnsupported].
+	Background bool `json:"background,omitempty"`
+	// Profile opens the URL in a browser profile instead of the last used
+	// one. For Chromium-based browsers (Chrome, Chromium, Edge, Brave,
+	// Vivaldi) it is the profile directory name, such as "Default" or
+	// "Profile 2", shown as the last element of "Profile Path" on
+	// chrome://version. For Firefox it is the profile name from
+	// about:profiles. It requires Browser.
+	Profile string `json:"profile,omitempty"`
+	// Private opens the URL in an incognito or private window. It requires
+	// a Chromium-based browser or Firefox in Browser; Safari has no way to
+	// request one and fails with [ErrUnsupported].
+	Private bool `json:"private,omitempty"`
+}
+
+// browserFamily groups browsers by the command-line flags they accept.
+type browserFamily int
+
+const (
+	familyUnknown browserFamily = iota
+	familyChromium
+	familyFirefox
+	familySafari
+)
+
+// familyOf classifies a Browser value by its base name, so "Google Chrome",
+// "/usr/bin/chromium", and "msedge.exe" all resolve.
+func familyOf(browser string) browserFamily {
+	name := strings.ToLower(browser)
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".app"), ".exe")
+	switch {
+	case strings.Contains(name, "firefox"):
+		return familyFirefox
+	case strings.Contains(name, "safari"):
+		return familySafari
+	}
+	for _, s := range []string{"chrome", "chromium", "edge", "brave", "vivaldi"} {
+		if strings.Contains(name, s) {
+			return familyChromium
+		}
+	}
+	return familyUnknown
+}
+
+// targetFlags returns the browser arguments that select input's profile and
+// private mode, placed before the URL.
+func targetFlags(goos string, input OpenURLInput, raw string) ([]string, error) {
+	browser := strings.TrimSpace(input.Browser)
+	profile := strings.TrimSpace(input.Profile)
+	if profile == "" && !input.Private {
+		return nil, nil
+	}
+	if browser == "" {
+		return nil, newInvalidArg("OpenURL", raw, "profile and private require browser")
+	}
+	// Firefox takes the profile as a separate argument.
+	if strings.HasPrefix(profile, "-") {
+		return nil, newInvalidArg("OpenURL", profile, "profile must not start with '-'")
+	}
+	var flags []string
+	switch familyOf(browser) {
+	case familyChromium:
+		if profile != "" {
+			flags = append(flags, "--profile-directory="+profile)
+		}
+		if input.Private {
+			if strings.Contains(strings.ToLower(browser), "edge") {
+				flags = append(flags, "--inprivate")
+			} else {
+				flags = append(flags, "--incognito")
+			}
+		}
+	case familyFirefox:
+		if profile != "" {
+			flags = append(flags, "-P", profile)
+		}
+		if input.Private {
+			flags = append(flags, "-private-window")
+		}
+	default:
+		return nil, &OpError{Op: "OpenURL", ID: browser, Err: fmt.Errorf("%w: profile and private windows for %s on %s", ErrUnsupported, browser, goos)}
+	}
+	return flags, nil
+}
+
+// OpenURL opens input.URL with the platform opener: `open` on macOS,
+// `xdg-open` on Linux and the BSDs, and `start` on Windows. It returns once
+// the opener has handed off the URL; it does not wait for the page to load.
+// On Linux and the BSDs a named Browser is started directly and left
+// running; cancelling ctx afterwards does not stop it.
+//
+// Profile and Private are passed to the browser as command-line flags, so
+// the browser must be named in Browser. If it is already running, it opens
+// the URL in a new window of that profile or mode.
+//
+// An unparseable or relative URL fails with [ErrInvalidURL], and a Browser
+// that is not installed fails with [ErrBrowserNotFound].
+func OpenURL(ctx context.Context, input OpenURLInput) error {
+	name, args, err := openCommand(runtime.GOOS, input)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" && strings.TrimSpace(input.Browser) != "" {
+		if _, err := exec.LookPath(name); err != nil {
+			return &OpError{Op: "OpenURL", ID: input.Browser, Err: fmt.Errorf("%w: %v", ErrBrowserNotFound, err)}
+		}
+		// Without an opener the command is the browser itself, which runs
+		// until the user quits it: start it unbound from ctx and reap it in
+		// the background.
+		cmd := exec.Command(name, args...)
+		if err := cmd.Start(); err != nil {
+			return &OpError{Op: "OpenURL", ID: input.Browser, Err: fmt.Errorf("start %s: %w", name, err)}
+		}
+		go cmd.Wait()
+		return nil
+	}
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if errors.Is(err, exec.ErrNotFound) {
+			return &OpError{Op: "OpenURL", ID: input.URL, Err: fmt.Errorf("%w: opener %q not found", ErrUnsupported, name)}
+		}
+		if runtime.GOOS == "darwin" && strings.Contains(msg, "Unable to find application") {
+			return &OpError{Op: "OpenURL", ID: input.Browser, Err: fmt.Errorf("%w: %s", ErrBrowserNotFound, msg)}
+		}
+		return &OpError{Op: "OpenURL", ID: input.URL, Err: fmt.Errorf("%s failed: %s (output: %s)", name, err, msg)}
+	}
+	return nil
+}
+
+// openCommand returns the command that opens input on goos.
+func openCommand(goos string, input OpenURLInput) (string, []string, error) {
+	raw, err := validateURL("OpenURL", input.URL)
+	if err != nil {
+		return "", nil, err
+	}
+	browser := strings.TrimSpace(input.Browser)
+	flags, err := targetFlags(goos, input, raw)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch goos {
+	case "darwin":
+		args := make([]string, 0, 6+len(flags))
+		if input.Background {
+			args = append(args, "-g")
+		}
+		if len(flags) > 0 {
+			// Flags reach the browser only through a new instance, which
+			// hands the URL to the running one and exits.
+			args = append(args, "-n", "-a", browser, "--args")
+			return "open", append(append(args, flags...), raw), nil
+		}
+		if browser != "" {
+			args = append(args, "-a", browser)
+		}
+		return "open", append(args, raw), nil
+	case "windows":
+		if input.Background {
+			return "", nil, &OpError{Op: "OpenURL", ID: raw, Err: fmt.Errorf("%w: background activation on %s", ErrUnsupported, goos)}
+		}
+		// The empty argument is the window title `start` expects first.
+		args := []string{"/c", "start", ""}
+		if browser != "" {
+			args = append(args, browser)
+		}
+		args = append(args, flags...)
+		// cmd.exe treats & and ^ as metacharacters; escape them so query
+		// strings survive.
+		escaped := strings.NewReplacer("^", "^^", "&", "^&").Replace(raw)
+		return "cmd", append(args, escaped), nil
+	default:
+		if input.Background {
+			return "", nil, &OpError{Op: "OpenURL", ID: raw, Err: fmt.Errorf("%w: background activation on %s", ErrUnsupported, goos)}
+		}
+		if browser != "" {
+			return browser, append(flags, raw), nil
+		}
+		return "xdg-open", []string{raw}, nil
+	}
+}
+
+// validateURL trims raw and checks that it is an absolute URL, returning
+// errors attributed to op.
+func validateURL(op, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", newInvalidArg(op, "", "url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", &OpError{Op: op, ID: raw, Err: fmt.Errorf("%w: %v", ErrInvalidURL, err)}
+	}
+	if !u.IsAbs() || (u.Host == "" && u.Opaque == "" && u.Path == "") {
+		return "", &OpError{Op: op, ID: raw, Err: fmt.Errorf("%w: url must be absolute", ErrInvalidURL)}
+	}
+	// Leading dashes would be read as options by an opener.
+	if strings.HasPrefix(raw, "-") {
+		return "", &OpError{Op: op, ID: raw, Err: fmt.Errorf("%w: url must not start with '-'", ErrInvalidURL)}
+	}
+	return raw, nil
+}
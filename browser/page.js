@@ -0,0 +1,630 @@
+// Page-side helpers for the browser package. The file is a single function
+// expression; Go evaluates it and calls one method on the returned object.
+// Every method returns {value} on success or {error: {code, message}}, where
+// code is one of the names decoded by pageError in elements.go.
+//
+// Element refs live in window.__cuhRefs, so they survive between calls but
+// not across navigations. Each document draws a random nonce that prefixes
+// its refs ("<nonce>:e3"), so a ref from an earlier document never resolves
+// to an element of the current one even though the counter restarts.
+(() => {
+  const newNonce = () => {
+    const bytes = crypto.getRandomValues(new Uint8Array(6));
+    return Array.from(bytes, (b) => b.toString(16).padStart(2, "0")).join("");
+  };
+  const state = window.__cuhRefs ||
+    (window.__cuhRefs = { nonce: newNonce(), n: 0, refs: new Map(), ids: new WeakMap() });
+
+  const ok = (value) => ({ value });
+  const fail = (code, message) => ({ error: { code, message } });
+
+  const register = (el) => {
+    let id = state.ids.get(el);
+    if (!id) {
+      id = state.nonce + ":e" + ++state.n;
+      state.ids.set(el, id);
+      state.refs.set(id, new WeakRef(el));
+    }
+    return id;
+  };
+
+  const resolve = (id) => {
+    if (!String(id).startsWith(state.nonce + ":")) return null;
+    const weak = state.refs.get(id);
+    const el = weak && weak.deref();
+    return el && el.isConnected ? el : null;
+  };
+
+  const norm = (s) => String(s || "").replace(/\s+/g, " ").trim();
+
+  const visible = (el) => {
+    const r = el.getBoundingClientRect();
+    if (r.width === 0 || r.height === 0) return false;
+    const st = getComputedStyle(el);
+    return st.visibility !== "hidden" && st.display !== "none" && st.opacity !== "0";
+  };
+
+  const inputRoles = {
+    button: "button", submit: "button", reset: "button", image: "button",
+    checkbox: "checkbox", radio: "radio", range: "slider", number: "spinbutton",
+    search: "searchbox", email: "textbox", tel: "textbox", text: "textbox",
+    url: "textbox", password: "textbox",
+  };
+  const tagRoles = {
+    article: "article", aside: "complementary", button: "button",
+    dialog: "dialog", footer: "contentinfo", form: "form", h1: "heading",
+    h2: "heading", h3: "heading", h4: "heading", h5: "heading", h6: "heading",
+    header: "banner", hr: "separator", img: "img", li: "listitem",
+    main: "main", nav: "navigation", ol: "list", option: "option",
+    progress: "progressbar", section: "region", table: "table", tbody: "rowgroup",
+    td: "cell", textarea: "textbox", th: "columnheader", thead: "rowgroup",
+    tr: "row", ul: "list",
+  };
+
+  const role = (el) => {
+    const explicit = norm(el.getAttribute("role")).split(" ")[0];
+    if (explicit) return explicit;
+    const tag = el.tagName.toLowerCase();
+    if (tag === "a" || tag === "area") return el.hasAttribute("href") ? "link" : "";
+    if (tag === "input") return inputRoles[(el.getAttribute("type") || "text").toLowerCase()] || "";
+    if (tag === "select") return el.multiple || el.size > 1 ? "listbox" : "combobox";
+    return tagRoles[tag] || "";
+  };
+
+  // name computes the accessible name; fromContent false skips the
+  // element's own text, for containers whose text is their children.
+  const name = (el, fromContent = true) => {
+    const labelledBy = el.getAttribute("aria-labelledby");
+    if (labelledBy) {
+      const text = labelledBy.split(/\s+/)
+        .map((id) => document.getElementById(id))
+        .filter(Boolean)
+        .map((n) => n.innerText || n.textContent)
+        .join(" ");
+      if (norm(text)) return norm(text);
+    }
+    const candidates = [
+      el.getAttribute("aria-label"),
+      el.labels && el.labels.length ? el.labels[0].innerText : "",
+      el.getAttribute("alt"),
+      el.getAttribute("title"),
+      el.tagName === "INPUT" && ["button", "submit", "reset"].includes(el.type) ? el.value : "",
+      el.getAttribute("placeholder"),
+      !fromContent || ["INPUT", "TEXTAREA", "SELECT"].includes(el.tagName) ? "" : el.innerText,
+    ];
+    for (const c of candidates) {
+      if (norm(c)) return norm(c);
+    }
+    return "";
+  };
+
+  const text = (el) => norm(el.innerText !== undefined ? el.innerText : el.textContent);
+
+  const enabled = (el) => !el.disabled && el.getAttribute("aria-disabled") !== "true";
+
+  // interactable scrolls el into view and checks that a pointer would reach
+  // it, returning the click point or an error result.
+  const interactable = (el) => {
+    el.scrollIntoView({ block: "center", inline: "center" });
+    if (!visible(el)) return fail("not_interactable", "element is not visible");
+    if (!enabled(el)) return fail("not_interactable", "element is disabled");
+    const r = el.getBoundingClientRect();
+    const x = r.left + r.width / 2;
+    const y = r.top + r.height / 2;
+    const hit = document.elementFromPoint(x, y);
+    if (hit && hit !== el && !el.contains(hit) && !hit.contains(el)) {
+      return fail("not_interactable", "element is covered by <" + hit.tagName.toLowerCase() + ">");
+    }
+    return ok({ x, y });
+  };
+
+  const findOption = (el, value) => {
+    const want = norm(value);
+    return Array.from(el.options).find((o) => o.value === value || norm(o.label || o.text) === want);
+  };
+
+  // target resolves op.ref, or op.selector to its single visible match.
+  const target = (op) => {
+    if (op.selector) {
+      let els;
+      try {
+        els = Array.from(document.querySelectorAll(op.selector)).filter(visible);
+      } catch (e) {
+        return fail("invalid_argument", "invalid selector: " + e.message);
+      }
+      if (!els.length) return fail("not_found", "no visible element matches " + JSON.stringify(op.selector));
+      if (els.length > 1) return fail("ambiguous", els.length + " visible elements match " + JSON.stringify(op.selector));
+      return ok(els[0]);
+    }
+    const el = resolve(op.ref);
+    if (!el) return fail("stale_ref", "element " + op.ref + " is no longer in the page");
+    return ok(el);
+  };
+
+  const toggleRoles = ["checkbox", "radio", "switch", "menuitemcheckbox", "menuitemradio"];
+  const isToggle = (el) => (el.tagName === "INPUT" && (el.type === "checkbox" || el.type === "radio")) || toggleRoles.includes(role(el));
+  const isChecked = (el) => (el.tagName === "INPUT" ? el.checked : el.getAttribute("aria-checked") === "true");
+  const isSubmitter = (el) => (el.tagName === "BUTTON" && el.type === "submit") ||
+    (el.tagName === "INPUT" && (el.type === "submit" || el.type === "image"));
+  const formOf = (el) => (el.tagName === "FORM" ? el : el.form || el.closest("form"));
+
+  // check validates op against the page without changing anything beyond
+  // scrolling the target into view. It returns the target's ref and, for ops
+  // that click, the click point.
+  const check = (op) => {
+    if (op.kind === "scroll" && !op.ref && !op.selector) return ok({ ref: "" });
+    const t = target(op);
+    if (t.error) return t;
+    const el = t.value;
+    const ref = register(el);
+    const at = (res) => (res.error ? res : ok({ ref, x: res.value.x, y: res.value.y }));
+    switch (op.kind) {
+      case "scroll":
+        return ok({ ref });
+      case "click":
+        return at(interactable(el));
+      case "type": {
+        const res = interactable(el);
+        if (res.error) return res;
+        if (!(el.isContentEditable || el.tagName === "INPUT" || el.tagName === "TEXTAREA") || el.readOnly) {
+          return fail("not_interactable", "element does not accept text");
+        }
+        return at(res);
+      }
+      case "select": {
+        if (el.tagName !== "SELECT") return fail("invalid_argument", "element is not a <select>");
+        if (!enabled(el)) return fail("not_interactable", "element is disabled");
+        if (!findOption(el, op.value)) return fail("invalid_argument", "no option matches " + JSON.stringify(op.value));
+        return ok({ ref });
+      }
+      case "check":
+      case "uncheck": {
+        if (!isToggle(el)) return fail("invalid_argument", "element is not a checkbox or radio button");
+        const want = op.kind === "check";
+        if (isChecked(el) === want) return ok({ ref });
+        if (!want && (el.type === "radio" || role(el) === "radio")) {
+          return fail("invalid_argument", "a radio button is unchecked by checking another");
+        }
+        return at(interactable(el));
+      }
+      case "submit": {
+        if (!formOf(el)) return fail("invalid_argument", "element is not in a form");
+        return isSubmitter(el) ? at(interactable(el)) : ok({ ref });
+      }
+    }
+    return fail("invalid_argument", "unknown op " + op.kind);
+  };
+
+  // Snapshot tables: roles whose content is summed up by their name, roles
+  // that take input, and tags that never render content.
+  const leafRoles = new Set(["button", "link", "heading", "checkbox", "radio", "switch", "tab",
+    "menuitem", "menuitemcheckbox", "menuitemradio", "option", "textbox", "searchbox", "combobox",
+    "slider", "spinbutton", "img", "progressbar", "separator", "treeitem"]);
+  const interactiveRoles = new Set(["button", "link", "checkbox", "radio", "switch", "tab",
+    "menuitem", "menuitemcheckbox", "menuitemradio", "option", "textbox", "searchbox", "combobox",
+    "listbox", "slider", "spinbutton", "treeitem"]);
+  const skipSnapshot = new Set(["SCRIPT", "STYLE", "NOSCRIPT", "TEMPLATE", "HEAD", "META", "LINK", "IFRAME"]);
+  const toggleInputs = new Set(["checkbox", "radio", "button", "submit", "reset", "image", "file"]);
+
+  const negativeHint = /comment|footer|foot|nav|sidebar|menu|masthead|share|social|related|promo|banner|sponsor|advert|\bads?\b|cookie|newsletter|subscribe|popup|modal/i;
+  const positiveHint = /article|body|content|entry|main|post|story|text|blog/i;
+
+  const hints = (el) => (el.className && typeof el.className === "string" ? el.className : "") + " " + (el.id || "");
+
+  const linkDensity = (el) => {
+    const total = text(el).length;
+    if (!total) return 0;
+    let links = 0;
+    for (const a of el.querySelectorAll("a")) links += text(a).length;
+    return links / total;
+  };
+
+  const meta = (...keys) => {
+    for (const k of keys) {
+      const el = document.querySelector('meta[property="' + k + '"], meta[name="' + k + '"], meta[itemprop="' + k + '"]');
+      if (el && norm(el.getAttribute("content"))) return norm(el.getAttribute("content"));
+    }
+    return "";
+  };
+
+  // jsonLD returns the first Article-like object from JSON-LD scripts.
+  const jsonLD = () => {
+    const items = [];
+    for (const s of document.querySelectorAll('script[type="application/ld+json"]')) {
+      try {
+        const data = JSON.parse(s.textContent);
+        const queue = Array.isArray(data) ? data.slice() : [data];
+        while (queue.length) {
+          const item = queue.shift();
+          if (!item || typeof item !== "object") continue;
+          if (Array.isArray(item["@graph"])) queue.push(...item["@graph"]);
+          items.push(item);
+        }
+      } catch (e) {
+        // Malformed JSON-LD is common; ignore it.
+      }
+    }
+    return items.find((i) => /Article|Posting|Report|Blog/.test([].concat(i["@type"] || []).join(" "))) || {};
+  };
+
+  const personNames = (v) => [].concat(v || [])
+    .map((p) => (typeof p === "string" ? p : p && p.name))
+    .filter(Boolean)
+    .map(norm);
+
+  // articleRoot picks the element most likely to hold the main text,
+  // scoring paragraph parents the way Readability does.
+  const articleRoot = () => {
+    const articles = Array.from(document.querySelectorAll("article")).filter((a) => text(a).length > 200);
+    if (articles.length === 1) return articles[0];
+    const scores = new Map();
+    const add = (el, v) => {
+      if (!el || el === document.documentElement) return;
+      if (!scores.has(el)) {
+        let base = 0;
+        if (negativeHint.test(hints(el))) base -= 25;
+        if (positiveHint.test(hints(el))) base += 25;
+        if (el.tagName === "ARTICLE" || el.tagName === "MAIN") base += 25;
+        scores.set(el, base);
+      }
+      scores.set(el, scores.get(el) + v);
+    };
+    for (const p of document.querySelectorAll("p, pre, td, blockquote")) {
+      const t = text(p);
+      if (t.length < 25) continue;
+      const v = 1 + t.split(",").length + Math.min(Math.floor(t.length / 100), 3);
+      add(p.parentElement, v);
+      add(p.parentElement && p.parentElement.parentElement, v / 2);
+    }
+    let best = null;
+    let bestScore = 0;
+    for (const [el, score] of scores) {
+      const adjusted = score * (1 - linkDensity(el));
+      if (adjusted > bestScore) {
+        best = el;
+        bestScore = adjusted;
+      }
+    }
+    return best || document.body;
+  };
+
+  const skipTags = new Set(["SCRIPT", "STYLE", "NOSCRIPT", "NAV", "ASIDE", "FOOTER", "FORM", "BUTTON", "IFRAME", "SVG", "FIGCAPTION"]);
+  const blockTags = "p, h1, h2, h3, h4, h5, h6, li, blockquote, pre";
+
+  // articleText joins the root's text blocks with blank lines, dropping
+  // navigation, boilerplate, and link lists.
+  const articleText = (root) => {
+    const skipped = (el) => {
+      for (let n = el; n && n !== root.parentElement; n = n.parentElement) {
+        if (skipTags.has(n.tagName)) return true;
+        if (n !== root && negativeHint.test(hints(n)) && !positiveHint.test(hints(n))) return true;
+      }
+      return false;
+    };
+    const blocks = Array.from(root.querySelectorAll(blockTags));
+    const chosen = new Set();
+    const out = [];
+    for (const el of blocks) {
+      if (skipped(el)) continue;
+      let nested = false;
+      for (let p = el.parentElement; p && p !== root; p = p.parentElement) {
+        if (chosen.has(p)) nested = true;
+      }
+      if (nested) continue;
+      const t = el.tagName === "PRE" ? el.textContent.trim() : text(el);
+      if (!t || (el.tagName !== "PRE" && linkDensity(el) > 0.5)) continue;
+      chosen.add(el);
+      out.push(t);
+    }
+    if (!out.length && !skipped(root)) {
+      const t = text(root);
+      if (t) out.push(t);
+    }
+    return out.join("\n\n");
+  };
+
+  return {
+    find(q) {
+      let els;
+      try {
+        els = Array.from(document.querySelectorAll(q.selector || "body *"));
+      } catch (e) {
+        return fail("invalid_argument", "invalid selector: " + e.message);
+      }
+      if (q.role) els = els.filter((el) => role(el) === q.role);
+      if (q.name) {
+        const want = norm(q.name).toLowerCase();
+        els = els.filter((el) => name(el).toLowerCase().includes(want));
+      }
+      if (q.text) {
+        const want = norm(q.text).toLowerCase();
+        els = els.filter((el) => text(el).toLowerCase().includes(want));
+        // Keep the innermost matches so a text query does not also return
+        // every ancestor of the element that holds the text.
+        const matched = new Set(els);
+        const outer = new Set();
+        for (const el of els) {
+          for (let p = el.parentElement; p; p = p.parentElement) {
+            if (matched.has(p)) outer.add(p);
+          }
+        }
+        els = els.filter((el) => !outer.has(el));
+      }
+      if (!q.include_hidden) els = els.filter(visible);
+      if (q.limit > 0) els = els.slice(0, q.limit);
+      return ok(els.map(register));
+    },
+
+    get(refs, maxText) {
+      return ok(refs.map((ref) => {
+        const el = resolve(ref);
+        if (!el) return { ref, error: { code: "stale_ref", message: "element " + ref + " is no longer in the page" } };
+        const r = el.getBoundingClientRect();
+        const attributes = {};
+        for (const a of el.attributes) attributes[a.name] = a.value;
+        let t = text(el);
+        if (maxText > 0 && t.length > maxText) t = t.slice(0, maxText);
+        return {
+          ref,
+          tag: el.tagName.toLowerCase(),
+          role: role(el),
+          name: name(el),
+          text: t,
+          value: ["INPUT", "TEXTAREA", "SELECT", "OPTION"].includes(el.tagName) ? String(el.value) : "",
+          attributes,
+          box: { x: r.x, y: r.y, width: r.width, height: r.height },
+          visible: visible(el),
+          enabled: enabled(el),
+        };
+      }));
+    },
+
+    check,
+
+    // args replaces {__cuh_ref: id} placeholders with their elements.
+    args(list) {
+      const out = [];
+      for (const a of list) {
+        if (a && typeof a === "object" && !Array.isArray(a) && Object.keys(a).length === 1 && "__cuh_ref" in a) {
+          const el = resolve(a.__cuh_ref);
+          if (!el) return fail("stale_ref", "element " + a.__cuh_ref + " is no longer in the page");
+          out.push(el);
+        } else {
+          out.push(a);
+        }
+      }
+      return ok(out);
+    },
+
+    readable() {
+      const ld = jsonLD();
+      const root = articleRoot();
+      const h1s = root.querySelectorAll("h1");
+      const byline = personNames(ld.author).join(", ") ||
+        meta("author", "article:author", "parsely-author", "dc.creator") ||
+        norm((document.querySelector('[rel="author"], [itemprop="author"], .byline, .author') || {}).innerText);
+      const time = root.querySelector("time[datetime]") || document.querySelector("time[datetime]");
+      return ok({
+        url: location.href,
+        title: meta("og:title", "twitter:title") || norm(ld.headline) ||
+          (h1s.length === 1 ? text(h1s[0]) : "") || norm(document.title),
+        byline,
+        site_name: meta("og:site_name", "application-name") || norm(ld.publisher && ld.publisher.name),
+        published: meta("article:published_time", "datePublished", "pubdate", "publishdate", "date", "dc.date", "sailthru.date") ||
+          norm(ld.datePublished) || (time ? time.getAttribute("datetime") : ""),
+        excerpt: meta("og:description", "description", "twitter:description"),
+        lang: document.documentElement.lang || "",
+        text: articleText(root),
+      });
+    },
+
+    // perform applies the in-page part of op. It returns {ref, click, x, y};
+    // when click is set, Go sends trusted mouse events at (x, y), and for
+    // typing Go inserts the text after the element is focused here.
+    perform(op) {
+      const res = check(op);
+      if (res.error) return res;
+      const { ref, x, y } = res.value;
+      const el = ref ? resolve(ref) : null;
+      switch (op.kind) {
+        case "scroll":
+          if (el) el.scrollIntoView({ block: "center", inline: "center" });
+          else window.scrollBy(op.delta_x || 0, op.delta_y || 0);
+          return ok({ ref });
+        case "type":
+          el.focus();
+          if (op.clear) {
+            if (el.isContentEditable) el.textContent = "";
+            else el.value = "";
+            el.dispatchEvent(new Event("input", { bubbles: true }));
+          }
+          return ok({ ref });
+        case "select":
+          el.value = findOption(el, op.value).value;
+          el.dispatchEvent(new Event("input", { bubbles: true }));
+          el.dispatchEvent(new Event("change", { bubbles: true }));
+          return ok({ ref });
+        case "submit":
+          if (!isSubmitter(el)) {
+            const form = formOf(el);
+            if (form.requestSubmit) form.requestSubmit();
+            else form.submit();
+            return ok({ ref });
+          }
+          break;
+      }
+      // click, a submit button, or a check/uncheck that changes state.
+      return ok({ ref, click: x !== undefined, x, y });
+    },
+
+    // probe reports whether q.ref is attached and visible, or how many
+    // visible elements match q.selector.
+    probe(q) {
+      if (q.ref) {
+        const el = resolve(q.ref);
+        return ok({ attached: !!el, visible: !!el && visible(el) });
+      }
+      try {
+        return ok({ count: Array.from(document.querySelectorAll(q.selector)).filter(visible).length });
+      } catch (e) {
+        return fail("invalid_argument", "invalid selector: " + e.message);
+      }
+    },
+
+    // storage reads, writes, or removes localStorage items for the page's
+    // origin. op is "get" (keys, or all when empty), "set" (items), or
+    // "remove" (keys, or all when empty); every op returns the items present
+    // afterwards for the keys it touched.
+    storage(op, arg) {
+      let store;
+      try {
+        store = window.localStorage;
+      } catch (e) {
+        return fail("unsupported", "localStorage is not available on " + location.href + ": " + e.message);
+      }
+      if (!store) return fail("unsupported", "localStorage is not available on " + location.href);
+      let keys = Array.isArray(arg) ? arg : Object.keys(arg || {});
+      if (!keys.length) keys = Array.from({ length: store.length }, (_, i) => store.key(i));
+      try {
+        if (op === "set") for (const k of keys) store.setItem(k, arg[k]);
+        if (op === "remove") for (const k of keys) store.removeItem(k);
+      } catch (e) {
+        return fail("invalid_argument", e.message);
+      }
+      const items = {};
+      for (const k of keys) {
+        const v = store.getItem(k);
+        if (v !== null) items[k] = v;
+      }
+      return ok({ origin: location.origin, items });
+    },
+
+    // settle resolves once the DOM has gone quietMs without mutations, or
+    // after maxMs regardless.
+    settle(quietMs, maxMs) {
+      return new Promise((done) => {
+        let quiet;
+        const finish = () => {
+          obs.disconnect();
+          clearTimeout(quiet);
+          clearTimeout(limit);
+          done(ok(true));
+        };
+        const obs = new MutationObserver(() => {
+          clearTimeout(quiet);
+          quiet = setTimeout(finish, quietMs);
+        });
+        obs.observe(document, { subtree: true, childList: true, attributes: true, characterData: true });
+        quiet = setTimeout(finish, quietMs);
+        const limit = setTimeout(finish, maxMs);
+      });
+    },
+
+    // snapshot outlines the visible page, or the subtree at q.ref or
+    // q.selector, as nested nodes. Elements without a role are flattened
+    // into their parent; roles named by their content, such as buttons and
+    // links, are leaves. With q.interactive only controls are kept.
+    snapshot(q) {
+      let root = document.body || document.documentElement;
+      if (q.ref || q.selector) {
+        const t = target(q);
+        if (t.error) return t;
+        root = t.value;
+      }
+      let count = 0;
+      let truncated = false;
+      const clip = (s) => (q.max_text > 0 && s.length > q.max_text ? s.slice(0, q.max_text) + "…" : s);
+      const hidden = (el) => {
+        if (el.getAttribute("aria-hidden") === "true" || el.hidden) return true;
+        const st = getComputedStyle(el);
+        return st.display === "none" || st.visibility === "hidden";
+      };
+      const box = (el) => {
+        const r = el.getBoundingClientRect();
+        return { x: r.x, y: r.y, width: r.width, height: r.height };
+      };
+      const node = (el, r) => {
+        const n = { ref: register(el), role: r, name: clip(name(el, leafRoles.has(r))), box: box(el) };
+        if (el.tagName === "SELECT") {
+          n.value = clip(Array.from(el.selectedOptions).map((o) => norm(o.label || o.text)).join(", "));
+        } else if ((el.tagName === "INPUT" && !toggleInputs.has(el.type)) || el.tagName === "TEXTAREA") {
+          // Password values are never exposed.
+          if (el.type !== "password") n.value = clip(String(el.value));
+        } else if (el.isContentEditable) {
+          n.value = clip(text(el));
+        }
+        const level = /^H([1-6])$/.exec(el.tagName);
+        if (r === "heading") n.level = Number(el.getAttribute("aria-level")) || (level ? Number(level[1]) : 0);
+        const states = [];
+        if (isToggle(el) && isChecked(el)) states.push("checked");
+        if (!enabled(el)) states.push("disabled");
+        const expanded = el.getAttribute("aria-expanded");
+        if (expanded === "true") states.push("expanded");
+        if (expanded === "false") states.push("collapsed");
+        if (el.getAttribute("aria-selected") === "true" || (el.tagName === "OPTION" && el.selected)) states.push("selected");
+        if (el.required || el.getAttribute("aria-required") === "true") states.push("required");
+        if (el.readOnly && (el.tagName === "INPUT" || el.tagName === "TEXTAREA")) states.push("readonly");
+        if (document.activeElement === el) states.push("focused");
+        if (states.length) n.states = states;
+        return n;
+      };
+      // walk returns the nodes el contributes to its parent.
+      const walk = (el, depth) => {
+        if (hidden(el) || skipSnapshot.has(el.tagName)) return [];
+        let r = role(el);
+        if (!r && (el.isContentEditable && !(el.parentElement && el.parentElement.isContentEditable))) r = "textbox";
+        if (!r && el.tabIndex >= 0 && el.hasAttribute("tabindex")) r = "generic";
+        if (r === "presentation" || r === "none") r = "";
+        const keep = r && (!q.interactive || interactiveRoles.has(r) || r === "generic");
+        let self = null;
+        if (keep) {
+          if (q.max_nodes > 0 && count >= q.max_nodes) {
+            truncated = true;
+            return [];
+          }
+          count++;
+          self = node(el, r);
+          if (leafRoles.has(r) || (q.max_depth > 0 && depth + 1 >= q.max_depth)) return [self];
+        }
+        const children = [];
+        for (const c of el.childNodes) {
+          if (c.nodeType === Node.ELEMENT_NODE) {
+            children.push(...walk(c, self ? depth + 1 : depth));
+          } else if (c.nodeType === Node.TEXT_NODE && !q.interactive) {
+            const t = norm(c.textContent);
+            if (!t) continue;
+            if (q.max_nodes > 0 && count >= q.max_nodes) {
+              truncated = true;
+              continue;
+            }
+            // Text that only repeats the element's label, or labels a
+            // control that carries it as its name, adds nothing.
+            if (self && self.name === clip(t)) continue;
+            const label = el.closest("label");
+            if (label && label.control) continue;
+            count++;
+            children.push({ role: "text", name: clip(t) });
+          }
+        }
+        if (!self) {
+          // Runs of inline text split by wrappers like <b> read as one.
+          if (children.length > 1 && children.every((n) => n.role === "text")) {
+            count -= children.length - 1;
+            return [{ role: "text", name: clip(children.map((n) => n.name).join(" ")) }];
+          }
+          return children;
+        }
+        if (children.length) self.children = children;
+        return [self];
+      };
+      const nodes = walk(root, 0);
+      return ok({ url: location.href, title: norm(document.title), nodes, truncated });
+    },
+
+    checked(ref) {
+      const el = resolve(ref);
+      if (!el) return fail("stale_ref", "element " + ref + " is no longer in the page");
+      return ok(isChecked(el));
+    },
+  };
+})
@@ -0,0 +1,273 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PaperSize is a named paper size for [Session.PrintToPDF].
+type PaperSize string
+
+const (
+	PaperLetter  PaperSize = "letter"
+	PaperLegal   PaperSize = "legal"
+	PaperTabloid PaperSize = "tabloid"
+	PaperA3      PaperSize = "a3"
+	PaperA4      PaperSize = "a4"
+	PaperA5      PaperSize = "a5"
+)
+
+// paperSizes maps each PaperSize to its width and height in inches.
+var paperSizes = map[PaperSize][2]float64{
+	PaperLetter:  {8.5, 11},
+	PaperLegal:   {8.5, 14},
+	PaperTabloid: {11, 17},
+	PaperA3:      {11.69, 16.54},
+	PaperA4:      {8.27, 11.69},
+	PaperA5:      {5.83, 8.27},
+}
+
+// PDFMargins are page margins in inches.
+type PDFMargins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+// PDFInput configures [Session.PrintToPDF].
+type PDFInput struct {
+	// URL, when set, is loaded and waited for before printing. Empty prints
+	// the page the session is on.
+	URL string `json:"url,omitempty"`
+	// Path, when set, is where the PDF is written instead of being returned
+	// in [PDF].Data. Missing parent directories are created.
+	Path string `json:"path,omitempty"`
+	// Overwrite replaces an existing file at Path. By default an existing
+	// file fails with an error matching fs.ErrExist.
+	Overwrite bool `json:"overwrite,omitempty"`
+
+	// Paper is the paper size and defaults to US letter. PaperWidth and
+	// PaperHeight, in inches, set a custom size instead.
+	Paper       PaperSize `json:"paper,omitempty"`
+	PaperWidth  float64   `json:"paper_width,omitempty"`
+	PaperHeight float64   `json:"paper_height,omitempty"`
+	Landscape   bool      `json:"landscape,omitempty"`
+	// Margins defaults to the browser's print margins of about 0.4 inches.
+	Margins *PDFMargins `json:"margins,omitempty"`
+	// Scale zooms the page, from 0.1 to 2. Zero means 1.
+	Scale float64 `json:"scale,omitempty"`
+	// PageRanges limits the pages printed, as in "1-3, 5". Empty prints all.
+	PageRanges string `json:"page_ranges,omitempty"`
+	// Background prints background colors and images.
+	Background bool `json:"background,omitempty"`
+	// PreferCSSPageSize lets an @page size in the page's CSS override
+	// Paper.
+	PreferCSSPageSize bool `json:"prefer_css_page_size,omitempty"`
+
+	// HeaderFooter prints a header and footer on every page: the browser's
+	// default date, title, URL, and page number, or the templates below.
+	HeaderFooter bool `json:"header_footer,omitempty"`
+	// HeaderTemplate and FooterTemplate are HTML for the header and footer;
+	// setting either turns HeaderFooter on. Elements with the classes date,
+	// title, url, pageNumber, and totalPages are filled in, as in
+	// `<span class="pageNumber"></span> / <span class="totalPages"></span>`.
+	// Templates must set their own font-size; the default is tiny. An empty
+	// template prints nothing in that position.
+	HeaderTemplate string `json:"header_template,omitempty"`
+	FooterTemplate string `json:"footer_template,omitempty"`
+}
+
+// PDF is a printed page.
+type PDF struct {
+	// Data holds the PDF when no Path was given.
+	Data []byte `json:"-"`
+	// Path is the absolute path of the written file, if any.
+	Path string `json:"path,omitempty"`
+	Size int    `json:"size"`
+}
+
+// PrintToPDF renders the page, or input.URL once loaded, as a PDF, for
+// saving confirmation pages and archiving what an agent saw. Printing uses
+// the print stylesheet, so the output can differ from a screenshot. Chrome
+// prints only when headless; a windowed browser fails with [ErrUnsupported].
+func (s *Session) PrintToPDF(ctx context.Context, input PDFInput) (PDF, error) {
+	id := pdfID(input)
+	params, err := pdfParams(input)
+	if err != nil {
+		return PDF{}, newInvalidArg("PrintToPDF", id, err.Error())
+	}
+	path, err := pdfPath(input)
+	if err != nil {
+		return PDF{}, err
+	}
+	if input.URL != "" {
+		if err := s.Navigate(ctx, input.URL); err != nil {
+			return PDF{}, err
+		}
+		if err := s.WaitLoad(ctx); err != nil {
+			return PDF{}, err
+		}
+	}
+
+	var res struct {
+		Data string `json:"data"`
+	}
+	if err := s.call(ctx, "Page.printToPDF", params, &res); err != nil {
+		var cdpErr *cdpError
+		if errors.As(err, &cdpErr) {
+			switch {
+			case strings.Contains(cdpErr.Message, "not implemented"):
+				err = fmt.Errorf("%w: printing to PDF needs a headless browser: %v", ErrUnsupported, err)
+			case strings.Contains(cdpErr.Message, "Page range"):
+				err = fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+			}
+		}
+		return PDF{}, &OpError{Op: "PrintToPDF", ID: id, Err: err}
+	}
+	return savePDF(id, path, res.Data)
+}
+
+// pdfID names input's page or file in errors.
+func pdfID(input PDFInput) string {
+	if input.URL != "" {
+		return input.URL
+	}
+	return input.Path
+}
+
+// pdfPath returns the absolute output path for input, or empty to return
+// the bytes. An existing file fails with [os.ErrExist] unless Overwrite is
+// set.
+func pdfPath(input PDFInput) (string, error) {
+	raw := strings.TrimSpace(input.Path)
+	if raw == "" {
+		return "", nil
+	}
+	path, err := filepath.Abs(raw)
+	if err != nil {
+		return "", newInvalidArg("PrintToPDF", pdfID(input), err.Error())
+	}
+	if !input.Overwrite {
+		if _, err := os.Lstat(path); err == nil {
+			return "", &OpError{Op: "PrintToPDF", ID: pdfID(input), Err: fmt.Errorf("%s: %w", path, os.ErrExist)}
+		}
+	}
+	return path, nil
+}
+
+// savePDF decodes the base64 PDF the browser returned and writes it to
+// path, or returns it in the PDF when path is empty.
+func savePDF(id, path, encoded string) (PDF, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return PDF{}, &OpError{Op: "PrintToPDF", ID: id, Err: fmt.Errorf("%w: decode pdf: %v", ErrProtocol, err)}
+	}
+	if path == "" {
+		return PDF{Data: data, Size: len(data)}, nil
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return PDF{}, &OpError{Op: "PrintToPDF", ID: id, Err: err}
+	}
+	return PDF{Path: path, Size: len(data)}, nil
+}
+
+// pdfParams validates input and builds the Page.printToPDF parameters.
+func pdfParams(input PDFInput) (map[string]any, error) {
+	width, height := input.PaperWidth, input.PaperHeight
+	switch {
+	case width < 0 || height < 0:
+		return nil, errors.New("paper width and height must not be negative")
+	case (width == 0) != (height == 0):
+		return nil, errors.New("paper width and height must be set together")
+	case width > 0 && input.Paper != "":
+		return nil, errors.New("paper and a custom paper size are mutually exclusive")
+	case width == 0:
+		paper := input.Paper
+		if paper == "" {
+			paper = PaperLetter
+		}
+		size, ok := paperSizes[paper]
+		if !ok {
+			return nil, fmt.Errorf("unsupported paper size %q", paper)
+		}
+		width, height = size[0], size[1]
+	}
+	if input.Scale != 0 && (input.Scale < 0.1 || input.Scale > 2) {
+		return nil, errors.New("scale must be between 0.1 and 2")
+	}
+	params := map[string]any{
+		"paperWidth":        width,
+		"paperHeight":       height,
+		"landscape":         input.Landscape,
+		"printBackground":   input.Background,
+		"preferCSSPageSize": input.PreferCSSPageSize,
+	}
+	if input.Scale != 0 {
+		params["scale"] = input.Scale
+	}
+	if m := input.Margins; m != nil {
+		if m.Top < 0 || m.Right < 0 || m.Bottom < 0 || m.Left < 0 {
+			return nil, errors.New("margins must not be negative")
+		}
+		params["marginTop"], params["marginRight"] = m.Top, m.Right
+		params["marginBottom"], params["marginLeft"] = m.Bottom, m.Left
+	}
+	if r := strings.TrimSpace(input.PageRanges); r != "" {
+		params["pageRanges"] = r
+	}
+	if input.HeaderFooter || input.HeaderTemplate != "" || input.FooterTemplate != "" {
+		params["displayHeaderFooter"] = true
+		if input.HeaderTemplate != "" || input.FooterTemplate != "" {
+			params["headerTemplate"] = orEmptySpan(input.HeaderTemplate)
+			params["footerTemplate"] = orEmptySpan(input.FooterTemplate)
+		}
+	}
+	return params, nil
+}
+
+// orEmptySpan returns tmpl, or an empty element for an empty template, which
+// the browser would otherwise replace with its default.
+func orEmptySpan(tmpl string) string {
+	if tmpl == "" {
+		return "<span></span>"
+	}
+	return tmpl
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it
+// into place, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
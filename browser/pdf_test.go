@@ -0,0 +1,102 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func TestPrintToPDF(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Page.printToPDF", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"data": base64.StdEncoding.EncodeToString([]byte("%PDF-1.4"))}, nil
+	})
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	pdf, err := s.PrintToPDF(ctx, PDFInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, pdf, PDF{Data: []byte("%PDF-1.4"), Size: 8})
+	call, _ := f.lastCall("Page.printToPDF")
+	be.Equal(t, string(call.Params), `{"landscape":false,"paperHeight":11,"paperWidth":8.5,"preferCSSPageSize":false,"printBackground":false}`)
+	_, navigated := f.lastCall("Page.navigate")
+	be.True(t, !navigated)
+
+	path := filepath.Join(t.TempDir(), "out", "confirmation.pdf")
+	pdf, err = s.PrintToPDF(ctx, PDFInput{
+		URL:            "https://shop.example/confirmation",
+		Path:           path,
+		Paper:          PaperA4,
+		Landscape:      true,
+		Margins:        &PDFMargins{Top: 1, Bottom: 1},
+		PageRanges:     "1-2",
+		FooterTemplate: `<span class="pageNumber"></span>`,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, pdf, PDF{Path: path, Size: 8})
+	data, err := os.ReadFile(path)
+	be.Err(t, err, nil)
+	be.Equal(t, string(data), "%PDF-1.4")
+	nav, _ := f.lastCall("Page.navigate")
+	be.Equal(t, string(nav.Params), `{"url":"https://shop.example/confirmation"}`)
+	call, _ = f.lastCall("Page.printToPDF")
+	var params map[string]any
+	be.Err(t, json.Unmarshal(call.Params, &params), nil)
+	be.Equal(t, params["paperWidth"], 8.27)
+	be.Equal(t, params["landscape"], true)
+	be.Equal(t, params["marginTop"], 1.0)
+	be.Equal(t, params["marginLeft"], 0.0)
+	be.Equal(t, params["pageRanges"], "1-2")
+	be.Equal(t, params["displayHeaderFooter"], true)
+	be.Equal(t, params["headerTemplate"], "<span></span>")
+	be.Equal(t, params["footerTemplate"], `<span class="pageNumber"></span>`)
+
+	// An existing file is kept unless Overwrite is set.
+	_, err = s.PrintToPDF(ctx, PDFInput{Path: path})
+	be.True(t, errors.Is(err, fs.ErrExist))
+	_, err = s.PrintToPDF(ctx, PDFInput{Path: path, Overwrite: true})
+	be.Err(t, err, nil)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	be.Err(t, err, nil)
+	be.Equal(t, len(entries), 1)
+}
+
+func TestPrintToPDFErrors(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Page.printToPDF", func(cdpMessage) (any, *cdpError) {
+		return nil, &cdpError{Code: -32000, Message: "PrintToPDF is not implemented"}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	_, err := s.PrintToPDF(ctx, PDFInput{})
+	be.True(t, errors.Is(err, ErrUnsupported))
+
+	f.handle("Page.printToPDF", func(cdpMessage) (any, *cdpError) {
+		return nil, &cdpError{Code: -32000, Message: "Page range exceeds page count"}
+	})
+	_, err = s.PrintToPDF(ctx, PDFInput{PageRanges: "9"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	invalid := []PDFInput{
+		{Paper: "b5"},
+		{PaperWidth: 4},
+		{PaperWidth: -4, PaperHeight: 6},
+		{Paper: PaperA4, PaperWidth: 4, PaperHeight: 6},
+		{Scale: 3},
+		{Margins: &PDFMargins{Left: -1}},
+	}
+	for _, in := range invalid {
+		_, err := s.PrintToPDF(ctx, in)
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+}
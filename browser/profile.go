@@ -0,0 +1,179 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Profile is a named persistent browser profile: a Chrome user data
+// directory kept under a profiles directory so cookies, storage, and logins
+// survive across [Launch] calls.
+type Profile struct {
+	Name string `json:"name"`
+	// Path is the profile's user data directory.
+	Path string `json:"path"`
+	// LastUsed is when the browser last wrote the profile's state.
+	LastUsed time.Time `json:"last_used,omitzero"`
+	// InUse is true while a browser holds the profile. A lock left by a
+	// crashed Chrome also reads as in use until the profile is launched
+	// again.
+	InUse bool `json:"in_use"`
+}
+
+// ListProfilesInput configures [ListProfiles].
+type ListProfilesInput struct {
+	// Dir is the profiles directory. Empty uses [DefaultProfilesDir].
+	Dir string `json:"dir,omitempty"`
+}
+
+// DeleteProfileInput configures [DeleteProfile].
+type DeleteProfileInput struct {
+	Name string `json:"name"`
+	// Dir is the profiles directory. Empty uses [DefaultProfilesDir].
+	Dir string `json:"dir,omitempty"`
+	// DryRun resolves the profile without deleting it.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+var profileNameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// profileLockFiles are the files Chrome holds in a user data directory while
+// it runs: SingletonLock on macOS and Linux, lockfile on Windows.
+var profileLockFiles = []string{"SingletonLock", "lockfile"}
+
+// DefaultProfilesDir returns the directory named profiles live in by default,
+// "cuh/browser-profiles" under the user's configuration directory (for
+// example ~/Library/Application Support on macOS).
+func DefaultProfilesDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cuh", "browser-profiles"), nil
+}
+
+// ListProfiles returns the named profiles in input.Dir, sorted by name. A
+// missing directory has no profiles.
+func ListProfiles(ctx context.Context, input ListProfilesInput) ([]Profile, error) {
+	root, err := profilesDir("ListProfiles", input.Dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Profile{}, nil
+	}
+	if err != nil {
+		return nil, &OpError{Op: "ListProfiles", ID: root, Err: err}
+	}
+	out := []Profile{}
+	for _, e := range entries {
+		if e.IsDir() && profileNameRE.MatchString(e.Name()) {
+			out = append(out, readProfile(ctx, e.Name(), filepath.Join(root, e.Name())))
+		}
+	}
+	return out, nil
+}
+
+// DeleteProfile removes a named profile and everything saved in it, and
+// returns the profile as it was. A profile a browser holds fails with
+// [ErrProfileInUse]; a missing one fails with an error matching
+// fs.ErrNotExist.
+func DeleteProfile(ctx context.Context, input DeleteProfileInput) (Profile, error) {
+	root, err := profilesDir("DeleteProfile", input.Dir)
+	if err != nil {
+		return Profile{}, err
+	}
+	path, err := profilePath("DeleteProfile", root, input.Name)
+	if err != nil {
+		return Profile{}, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return Profile{}, &OpError{Op: "DeleteProfile", ID: input.Name, Err: err}
+	}
+	p := readProfile(ctx, input.Name, path)
+	if p.InUse {
+		return p, &OpError{Op: "DeleteProfile", ID: input.Name, Err: fmt.Errorf("%w: close the browser using it first", ErrProfileInUse)}
+	}
+	if input.DryRun {
+		return p, nil
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return p, &OpError{Op: "DeleteProfile", ID: input.Name, Err: err}
+	}
+	return p, nil
+}
+
+// profilesDir resolves a profiles directory input to an absolute path.
+func profilesDir(op, dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		d, err := DefaultProfilesDir()
+		if err != nil {
+			return "", &OpError{Op: op, Err: err}
+		}
+		return d, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", newInvalidArg(op, dir, err.Error())
+	}
+	return abs, nil
+}
+
+// profilePath validates name and returns its directory under root.
+func profilePath(op, root, name string) (string, error) {
+	if !profileNameRE.MatchString(name) {
+		return "", newInvalidArg(op, name, "profile name must be 1-64 letters, digits, '.', '_', or '-', starting with a letter or digit")
+	}
+	return filepath.Join(root, name), nil
+}
+
+func readProfile(ctx context.Context, name, path string) Profile {
+	p := Profile{Name: name, Path: path, InUse: profileLocked(path) || devToolsActive(ctx, path)}
+	// Chrome rewrites Local State whenever it runs.
+	for _, f := range []string{"Local State", "."} {
+		if fi, err := os.Stat(filepath.Join(path, f)); err == nil {
+			p.LastUsed = fi.ModTime()
+			break
+		}
+	}
+	return p
+}
+
+// profileLocked reports whether a Chrome lock is present in the user data
+// directory dir. Chrome holds it even when started without remote debugging;
+// headless shells do not take it.
+func profileLocked(dir string) bool {
+	return slices.ContainsFunc(profileLockFiles, func(name string) bool {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		return err == nil
+	})
+}
+
+// devToolsActive reports whether a browser with remote debugging is running
+// on the user data directory dir. Browsers record their DevTools port and
+// path in DevToolsActivePort, which outlives them if they crash, so the
+// address is probed rather than trusted.
+func devToolsActive(ctx context.Context, dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "DevToolsActivePort"))
+	if err != nil {
+		return false
+	}
+	port, path, ok := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	if !ok {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	wsURL, err := browserWebSocketURL(ctx, "127.0.0.1:"+strings.TrimSpace(port))
+	return err == nil && strings.HasSuffix(wsURL, strings.TrimSpace(path))
+}
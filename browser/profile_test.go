@@ -0,0 +1,102 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nalgeon/be"
+)
+
+func TestProfiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	got, err := ListProfiles(ctx, ListProfilesInput{Dir: filepath.Join(dir, "missing")})
+	be.Err(t, err, nil)
+	be.Equal(t, len(got), 0)
+
+	for _, name := range []string{"work", "personal", ".hidden"} {
+		be.Err(t, os.MkdirAll(filepath.Join(dir, name), 0o700), nil)
+	}
+	be.Err(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644), nil)
+	be.Err(t, os.Symlink("host-123", filepath.Join(dir, "work", "SingletonLock")), nil)
+
+	got, err = ListProfiles(ctx, ListProfilesInput{Dir: dir})
+	be.Err(t, err, nil)
+	be.Equal(t, len(got), 2)
+	be.Equal(t, got[0].Name, "personal")
+	be.Equal(t, got[0].Path, filepath.Join(dir, "personal"))
+	be.True(t, !got[0].InUse && !got[0].LastUsed.IsZero())
+	be.Equal(t, got[1].Name, "work")
+	be.True(t, got[1].InUse)
+
+	_, err = DeleteProfile(ctx, DeleteProfileInput{Name: "work", Dir: dir})
+	be.True(t, errors.Is(err, ErrProfileInUse))
+	be.Equal(t, ErrorCode(err), "profile_in_use")
+
+	p, err := DeleteProfile(ctx, DeleteProfileInput{Name: "personal", Dir: dir, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, p.Name, "personal")
+	_, err = os.Stat(p.Path)
+	be.Err(t, err, nil)
+
+	_, err = DeleteProfile(ctx, DeleteProfileInput{Name: "personal", Dir: dir})
+	be.Err(t, err, nil)
+	_, err = os.Stat(p.Path)
+	be.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = DeleteProfile(ctx, DeleteProfileInput{Name: "personal", Dir: dir})
+	be.True(t, errors.Is(err, fs.ErrNotExist))
+	for _, name := range []string{"", "..", "../work", "a/b", ".hidden"} {
+		_, err = DeleteProfile(ctx, DeleteProfileInput{Name: name, Dir: dir})
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+}
+
+func TestLaunchProfile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	_, err := Launch(ctx, LaunchInput{Profile: "work", UserDataDir: dir})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = Launch(ctx, LaunchInput{Profile: "../work", ProfilesDir: dir})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	// The profile directory is created before the browser starts.
+	_, err = Launch(ctx, LaunchInput{Profile: "work", ProfilesDir: dir, ExecPath: "/nonexistent/cuh-chrome"})
+	be.True(t, errors.Is(err, ErrBrowserNotFound))
+	fi, err := os.Stat(filepath.Join(dir, "work"))
+	be.Err(t, err, nil)
+	be.True(t, fi.IsDir())
+}
+
+func TestDevToolsActive(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"webSocketDebuggerUrl":"ws://` + r.Host + `/devtools/browser/abc"}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	be.Err(t, err, nil)
+
+	dir := t.TempDir()
+	be.True(t, !devToolsActive(ctx, dir))
+	write := func(content string) {
+		be.Err(t, os.WriteFile(filepath.Join(dir, "DevToolsActivePort"), []byte(content), 0o644), nil)
+	}
+	write(u.Port() + "\n/devtools/browser/abc")
+	be.True(t, devToolsActive(ctx, dir))
+	_, err = Launch(ctx, LaunchInput{UserDataDir: dir, ExecPath: "/nonexistent/cuh-chrome"})
+	be.True(t, errors.Is(err, ErrProfileInUse))
+	// The port now belongs to a different browser.
+	write(u.Port() + "\n/devtools/browser/old")
+	be.True(t, !devToolsActive(ctx, dir))
+	srv.Close()
+	write(u.Port() + "\n/devtools/browser/abc")
+	be.True(t, !devToolsActive(ctx, dir))
+}
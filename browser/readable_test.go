@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func TestParsePublished(t *testing.T) {
+	cases := map[string]time.Time{
+		"2026-03-04T09:30:00Z":          time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		"2026-03-04T09:30:00.250Z":      time.Date(2026, 3, 4, 9, 30, 0, 250e6, time.UTC),
+		"2026-03-04T09:30:00+0000":      time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		"2026-03-04":                    time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		" 2026-03-04 09:30:00 ":         time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		"Wed, 04 Mar 2026 09:30:00 GMT": time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		"March 4, 2026":                 time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+	for raw, want := range cases {
+		be.True(t, parsePublished(raw).Equal(want))
+	}
+	be.True(t, parsePublished("last Tuesday").IsZero())
+	be.True(t, parsePublished("").IsZero())
+}
+
+func TestExtractReadable(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "readable")
+		be.Equal(t, len(args), 0)
+		return map[string]any{"value": map[string]any{
+			"url": "https://example.com/post", "title": "Post", "byline": "Ada",
+			"published": "2026-03-04", "text": "One two.\n\nThree.",
+		}}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	a, err := s.ExtractReadable(ctx, ExtractReadableInput{URL: "https://example.com/post"})
+	be.Err(t, err, nil)
+	be.Equal(t, a.Title, "Post")
+	be.Equal(t, a.Byline, "Ada")
+	be.Equal(t, a.WordCount, 3)
+	be.True(t, a.PublishedTime.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)))
+	_, navigated := f.lastCall("Page.navigate")
+	be.True(t, navigated)
+
+	// Without a URL the current page is read as is.
+	before := len(f.methods())
+	_, err = s.ExtractReadable(ctx, ExtractReadableInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, f.methods()[before:], []string{"Runtime.evaluate"})
+}
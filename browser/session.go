@@ -0,0 +1,692 @@
+package browser
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session is one browser tab driven over the Chrome DevTools Protocol (CDP).
+// Create it with [Launch] or [Attach] and release it with [Session.Close].
+// Methods are safe for concurrent use, though commands against one tab are
+// applied in the order the browser receives them.
+type Session struct {
+	conn      *cdpConn
+	targetID  string
+	sessionID string
+
+	// Set when the session launched its own browser.
+	cmd         *exec.Cmd
+	waitDone    chan struct{}
+	tempDataDir string
+
+	closeOnce sync.Once
+	closeErr  error
+
+	// mu guards the page state below, which CDP events update. changed is
+	// closed and replaced on every update.
+	mu        sync.Mutex
+	loads     uint64
+	navBase   uint64
+	loading   bool
+	inflight  map[string]bool
+	netActive time.Time
+	changed   chan struct{}
+	navigate  bool
+	unlisten  func()
+}
+
+// LaunchInput configures [Launch].
+type LaunchInput struct {
+	// ExecPath is the Chrome or Chromium binary. Empty searches the usual
+	// install locations for the current platform.
+	ExecPath string `json:"exec_path,omitempty"`
+	// Headless runs the browser without a window.
+	Headless bool `json:"headless,omitempty"`
+	// UserDataDir is the profile directory. Empty uses a fresh temporary
+	// profile that Close removes, unless Profile is set.
+	UserDataDir string `json:"user_data_dir,omitempty"`
+	// Profile names a persistent profile under ProfilesDir, created on first
+	// use. Cookies, storage, and logins saved in it survive Close, so a later
+	// Launch with the same name resumes them, and differently named profiles
+	// stay independent. It is mutually exclusive with UserDataDir. A profile
+	// can be open in only one browser at a time; see [ErrProfileInUse].
+	Profile string `json:"profile,omitempty"`
+	// ProfilesDir holds named profiles. Empty uses [DefaultProfilesDir].
+	ProfilesDir string `json:"profiles_dir,omitempty"`
+	// Args are extra command-line flags passed to the browser.
+	Args []string `json:"args,omitempty"`
+}
+
+// AttachInput configures [Attach].
+type AttachInput struct {
+	// Endpoint is the browser's DevTools address: either the HTTP endpoint
+	// (for example "http://127.0.0.1:9222") of a browser started with
+	// --remote-debugging-port, or its browser-level ws:// URL.
+	Endpoint string `json:"endpoint"`
+}
+
+// ScreenshotFormat is the image encoding produced by [Session.Screenshot].
+type ScreenshotFormat string
+
+const (
+	ScreenshotPNG  ScreenshotFormat = "png"
+	ScreenshotJPEG ScreenshotFormat = "jpeg"
+	ScreenshotWebP ScreenshotFormat = "webp"
+)
+
+// ScreenshotInput configures [Session.Screenshot].
+type ScreenshotInput struct {
+	// Format defaults to PNG.
+	Format ScreenshotFormat `json:"format,omitempty"`
+	// Quality is the 0-100 compression quality for JPEG and WebP. Zero uses
+	// the browser default.
+	Quality int `json:"quality,omitempty"`
+	// FullPage captures the whole scrollable page rather than the viewport.
+	FullPage bool `json:"full_page,omitempty"`
+}
+
+// Launch starts a new Chrome or Chromium process with remote debugging
+// enabled and opens a session on a fresh tab. Close shuts the browser down.
+// With input.Profile set, the browser runs on that named persistent profile
+// instead of a throwaway one.
+func Launch(ctx context.Context, input LaunchInput) (*Session, error) {
+	dataDir := strings.TrimSpace(input.UserDataDir)
+	if profile := strings.TrimSpace(input.Profile); profile != "" {
+		if dataDir != "" {
+			return nil, newInvalidArg("Launch", profile, "profile and userDataDir are mutually exclusive")
+		}
+		root, err := profilesDir("Launch", input.ProfilesDir)
+		if err != nil {
+			return nil, err
+		}
+		if dataDir, err = profilePath("Launch", root, profile); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(dataDir, 0o700); err != nil {
+			return nil, &OpError{Op: "Launch", ID: profile, Err: err}
+		}
+	}
+	if dataDir != "" && devToolsActive(ctx, dataDir) {
+		return nil, &OpError{Op: "Launch", ID: dataDir, Err: fmt.Errorf("%w: close the browser using it first", ErrProfileInUse)}
+	}
+	execPath := strings.TrimSpace(input.ExecPath)
+	if execPath == "" {
+		var err error
+		if execPath, err = findChrome(runtime.GOOS); err != nil {
+			return nil, &OpError{Op: "Launch", Err: err}
+		}
+	}
+	tempDataDir := ""
+	if dataDir == "" {
+		dir, err := os.MkdirTemp("", "cuh-browser-")
+		if err != nil {
+			return nil, &OpError{Op: "Launch", Err: err}
+		}
+		dataDir, tempDataDir = dir, dir
+	}
+
+	cmd := exec.Command(execPath, launchArgs(input, dataDir)...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		removeTemp(tempDataDir)
+		return nil, &OpError{Op: "Launch", Err: err}
+	}
+	if err := cmd.Start(); err != nil {
+		removeTemp(tempDataDir)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return nil, &OpError{Op: "Launch", ID: execPath, Err: fmt.Errorf("%w: %v", ErrBrowserNotFound, err)}
+		}
+		return nil, &OpError{Op: "Launch", ID: execPath, Err: err}
+	}
+	waitDone := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(waitDone)
+	}()
+	kill := func() {
+		cmd.Process.Kill()
+		<-waitDone
+		removeTemp(tempDataDir)
+	}
+
+	wsURL, err := readDevToolsURL(ctx, stderr, waitDone)
+	if err != nil {
+		kill()
+		// A browser that finds its profile locked hands off to the browser
+		// holding it and exits.
+		if tempDataDir == "" && errors.Is(err, ErrSessionClosed) && profileLocked(dataDir) {
+			err = fmt.Errorf("%w: %s is open in another browser: %v", ErrProfileInUse, dataDir, err)
+		}
+		return nil, &OpError{Op: "Launch", ID: execPath, Err: err}
+	}
+	s, err := newSession(ctx, wsURL)
+	if err != nil {
+		kill()
+		return nil, &OpError{Op: "Launch", ID: execPath, Err: err}
+	}
+	s.cmd, s.waitDone, s.tempDataDir = cmd, waitDone, tempDataDir
+	return s, nil
+}
+
+// Attach connects to an already running browser and opens a session on a new
+// tab. Close closes only that tab and leaves the browser running.
+func Attach(ctx context.Context, input AttachInput) (*Session, error) {
+	endpoint := strings.TrimSpace(input.Endpoint)
+	if endpoint == "" {
+		return nil, newInvalidArg("Attach", "", "endpoint is required")
+	}
+	wsURL := endpoint
+	if !isWebSocketURL(endpoint) {
+		var err error
+		if wsURL, err = browserWebSocketURL(ctx, endpoint); err != nil {
+			return nil, &OpError{Op: "Attach", ID: endpoint, Err: err}
+		}
+	}
+	s, err := newSession(ctx, wsURL)
+	if err != nil {
+		return nil, &OpError{Op: "Attach", ID: endpoint, Err: err}
+	}
+	return s, nil
+}
+
+// newSession connects to the browser WebSocket, opens a blank tab, and
+// attaches to it.
+func newSession(ctx context.Context, wsURL string) (*Session, error) {
+	ws, err := dialWebSocket(ctx, wsURL)
+	if err != nil {
+		return nil, err
+	}
+	conn := newCDPConn(ws)
+	var created struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := conn.call(ctx, "", "Target.createTarget", map[string]any{"url": "about:blank"}, &created); err != nil {
+		conn.close()
+		return nil, err
+	}
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := conn.call(ctx, "", "Target.attachToTarget", map[string]any{"targetId": created.TargetID, "flatten": true}, &attached); err != nil {
+		conn.call(ctx, "", "Target.closeTarget", map[string]any{"targetId": created.TargetID}, nil)
+		conn.close()
+		return nil, err
+	}
+	s := &Session{
+		conn:      conn,
+		targetID:  created.TargetID,
+		sessionID: attached.SessionID,
+		inflight:  make(map[string]bool),
+		netActive: time.Now(),
+		changed:   make(chan struct{}),
+	}
+	unlistenLoad := conn.on(s.sessionID, "Page.loadEventFired", func(json.RawMessage) {
+		s.update(func() { s.loads++ })
+	})
+	unlistenStart := conn.on(s.sessionID, "Page.frameStartedLoading", func(params json.RawMessage) {
+		if s.isMainFrame(params) {
+			s.update(func() { s.loading = true })
+		}
+	})
+	unlistenStop := conn.on(s.sessionID, "Page.frameStoppedLoading", func(params json.RawMessage) {
+		if s.isMainFrame(params) {
+			s.update(func() { s.loading = false })
+		}
+	})
+	unlistenNet := s.trackRequests()
+	s.unlisten = func() {
+		unlistenLoad()
+		unlistenStart()
+		unlistenStop()
+		unlistenNet()
+	}
+	for _, method := range []string{"Page.enable", "Network.enable"} {
+		if err := s.call(ctx, method, nil, nil); err != nil {
+			s.unlisten()
+			conn.call(ctx, "", "Target.closeTarget", map[string]any{"targetId": s.targetID}, nil)
+			conn.close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// trackRequests keeps s.inflight and s.netActive current from Network
+// events and returns a func that stops tracking.
+func (s *Session) trackRequests() func() {
+	requestID := func(params json.RawMessage) string {
+		var ev struct {
+			RequestID string `json:"requestId"`
+		}
+		json.Unmarshal(params, &ev)
+		return ev.RequestID
+	}
+	started := func(params json.RawMessage) {
+		id := requestID(params)
+		s.update(func() {
+			s.inflight[id] = true
+			s.netActive = time.Now()
+		})
+	}
+	finished := func(params json.RawMessage) {
+		id := requestID(params)
+		s.update(func() {
+			delete(s.inflight, id)
+			s.netActive = time.Now()
+		})
+	}
+	unlisten := []func(){
+		s.conn.on(s.sessionID, "Network.requestWillBeSent", started),
+		s.conn.on(s.sessionID, "Network.loadingFinished", finished),
+		s.conn.on(s.sessionID, "Network.loadingFailed", finished),
+	}
+	return func() {
+		for _, fn := range unlisten {
+			fn()
+		}
+	}
+}
+
+// TargetID identifies the session's tab in the DevTools protocol.
+func (s *Session) TargetID() string { return s.targetID }
+
+// call sends a command to the session's tab.
+func (s *Session) call(ctx context.Context, method string, params, result any) error {
+	return s.conn.call(ctx, s.sessionID, method, params, result)
+}
+
+// update applies fn to the page state and wakes waiters.
+func (s *Session) update(fn func()) {
+	s.mu.Lock()
+	fn()
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// isMainFrame reports whether a Page frame event is for the tab's top-level
+// frame, whose id is the target id.
+func (s *Session) isMainFrame(params json.RawMessage) bool {
+	var ev struct {
+		FrameID string `json:"frameId"`
+	}
+	return json.Unmarshal(params, &ev) == nil && ev.FrameID == s.targetID
+}
+
+// Navigate loads url in the session's tab. It returns once the browser has
+// committed to the navigation; call [Session.WaitLoad] to wait for the page
+// to finish loading. A network or HTTP-level failure reported by the browser
+// returns [ErrNavigation].
+func (s *Session) Navigate(ctx context.Context, url string) error {
+	url, err := validateURL("Navigate", url)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	base := s.loads
+	s.mu.Unlock()
+
+	var res struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := s.call(ctx, "Page.navigate", map[string]any{"url": url}, &res); err != nil {
+		return &OpError{Op: "Navigate", ID: url, Err: err}
+	}
+	if res.ErrorText != "" {
+		return &OpError{Op: "Navigate", ID: url, Err: fmt.Errorf("%w: %s", ErrNavigation, res.ErrorText)}
+	}
+	s.mu.Lock()
+	s.navBase, s.navigate = base, true
+	s.mu.Unlock()
+	return nil
+}
+
+// WaitLoad blocks until the page from the last [Session.Navigate] fires its
+// load event, or, before any navigation, until the current document is
+// complete. Bound the wait with ctx.
+func (s *Session) WaitLoad(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		navigated, done, ch := s.navigate, s.loads > s.navBase, s.changed
+		s.mu.Unlock()
+		if done {
+			return nil
+		}
+		if !navigated {
+			var state string
+			if err := s.evaluate(ctx, "document.readyState", &state); err != nil {
+				return &OpError{Op: "WaitLoad", Err: err}
+			}
+			if state == "complete" {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return &OpError{Op: "WaitLoad", Err: ctx.Err()}
+		case <-s.conn.done:
+			return &OpError{Op: "WaitLoad", Err: s.conn.closedError()}
+		case <-ch:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Settle timings. settleGrace lets a navigation triggered by an action
+// start before settle checks whether the page is loading; the DOM counts as
+// quiet after settleQuiet without mutations, waiting at most settleDOMMax.
+const (
+	defaultSettleTimeout = 5 * time.Second
+	settleGrace          = 50 * time.Millisecond
+	settleQuiet          = 100 * time.Millisecond
+	settleDOMMax         = time.Second
+)
+
+// settle waits, best effort, for the page to react to an action: for a
+// main-frame load it started to finish, then for the DOM to go quiet. It
+// gives up silently after timeout; a negative timeout skips it.
+func (s *Session) settle(ctx context.Context, timeout time.Duration) {
+	if timeout < 0 {
+		return
+	}
+	if timeout == 0 {
+		timeout = defaultSettleTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(settleGrace):
+	}
+	for {
+		s.mu.Lock()
+		loading, ch := s.loading, s.changed
+		s.mu.Unlock()
+		if !loading {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.conn.done:
+			return
+		case <-ch:
+		}
+	}
+	// The page may still navigate away mid-wait; any error just ends it.
+	callPage(ctx, s, "settle", []any{settleQuiet.Milliseconds(), settleDOMMax.Milliseconds()}, nil)
+}
+
+// evaluate runs expression in the page, awaiting it if it is a promise, and
+// decodes its JSON value into out. A thrown exception returns [ErrScript].
+func (s *Session) evaluate(ctx context.Context, expression string, out any) error {
+	var res struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception *struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	params := map[string]any{"expression": expression, "returnByValue": true, "awaitPromise": true}
+	if err := s.call(ctx, "Runtime.evaluate", params, &res); err != nil {
+		return err
+	}
+	if d := res.ExceptionDetails; d != nil {
+		msg := d.Text
+		if d.Exception != nil && d.Exception.Description != "" {
+			msg = d.Exception.Description
+		}
+		return fmt.Errorf("%w: %s", ErrScript, msg)
+	}
+	if out == nil || len(res.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Result.Value, out)
+}
+
+// Screenshot captures the tab and returns the encoded image bytes.
+func (s *Session) Screenshot(ctx context.Context, input ScreenshotInput) ([]byte, error) {
+	format, err := screenshotFormat(input)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]any{"format": string(format)}
+	if input.Quality > 0 && format != ScreenshotPNG {
+		params["quality"] = input.Quality
+	}
+	if input.FullPage {
+		var metrics struct {
+			CSSContentSize struct {
+				Width  float64 `json:"width"`
+				Height float64 `json:"height"`
+			} `json:"cssContentSize"`
+		}
+		if err := s.call(ctx, "Page.getLayoutMetrics", nil, &metrics); err != nil {
+			return nil, &OpError{Op: "Screenshot", Err: err}
+		}
+		params["captureBeyondViewport"] = true
+		params["clip"] = map[string]any{
+			"x": 0, "y": 0, "scale": 1,
+			"width":  metrics.CSSContentSize.Width,
+			"height": metrics.CSSContentSize.Height,
+		}
+	}
+	var res struct {
+		Data string `json:"data"`
+	}
+	if err := s.call(ctx, "Page.captureScreenshot", params, &res); err != nil {
+		return nil, &OpError{Op: "Screenshot", Err: err}
+	}
+	img, err := base64.StdEncoding.DecodeString(res.Data)
+	if err != nil {
+		return nil, &OpError{Op: "Screenshot", Err: fmt.Errorf("%w: decode image: %v", ErrProtocol, err)}
+	}
+	return img, nil
+}
+
+// screenshotFormat validates input and returns its format, defaulting to
+// PNG.
+func screenshotFormat(input ScreenshotInput) (ScreenshotFormat, error) {
+	format := input.Format
+	if format == "" {
+		format = ScreenshotPNG
+	}
+	switch format {
+	case ScreenshotPNG, ScreenshotJPEG, ScreenshotWebP:
+	default:
+		return "", newInvalidArg("Screenshot", "", fmt.Sprintf("unsupported format %q", format))
+	}
+	if input.Quality < 0 || input.Quality > 100 {
+		return "", newInvalidArg("Screenshot", "", "quality must be between 0 and 100")
+	}
+	return format, nil
+}
+
+// Close ends the session. For a launched browser it shuts the browser down
+// and removes any temporary profile; for an attached browser it closes only
+// the session's tab. Close is idempotent.
+func (s *Session) Close() error {
+	s.closeOnce.Do(func() { s.closeErr = s.close() })
+	return s.closeErr
+}
+
+func (s *Session) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.unlisten()
+
+	if s.cmd == nil {
+		err := s.conn.call(ctx, "", "Target.closeTarget", map[string]any{"targetId": s.targetID}, nil)
+		s.conn.close()
+		if err != nil {
+			return &OpError{Op: "Close", ID: s.targetID, Err: err}
+		}
+		return nil
+	}
+
+	s.conn.call(ctx, "", "Browser.close", nil, nil)
+	s.conn.close()
+	select {
+	case <-s.waitDone:
+	case <-ctx.Done():
+		s.cmd.Process.Kill()
+		<-s.waitDone
+	}
+	removeTemp(s.tempDataDir)
+	return nil
+}
+
+// launchArgs builds the browser command line.
+func launchArgs(input LaunchInput, dataDir string) []string {
+	args := []string{
+		"--remote-debugging-port=0",
+		"--user-data-dir=" + dataDir,
+		"--no-first-run",
+		"--no-default-browser-check",
+	}
+	if input.Headless {
+		args = append(args, "--headless=new", "--window-size=1280,800")
+	}
+	args = append(args, input.Args...)
+	return append(args, "about:blank")
+}
+
+// chromeCandidates lists where Chrome and Chromium are usually installed.
+func chromeCandidates(goos string) []string {
+	switch goos {
+	case "darwin":
+		return []string{
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"/Applications/Chromium.app/Contents/MacOS/Chromium",
+			"/Applications/Google Chrome Canary.app/Contents/MacOS/Google Chrome Canary",
+		}
+	case "windows":
+		var out []string
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)", "LocalAppData"} {
+			if dir := os.Getenv(env); dir != "" {
+				out = append(out, filepath.Join(dir, `Google\Chrome\Application\chrome.exe`))
+			}
+		}
+		return append(out, "chrome.exe")
+	default:
+		return []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser"}
+	}
+}
+
+func findChrome(goos string) (string, error) {
+	for _, c := range chromeCandidates(goos) {
+		if p, err := exec.LookPath(c); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no Chrome or Chromium installation found; set ExecPath", ErrBrowserNotFound)
+}
+
+// readDevToolsURL scans the browser's stderr for the DevTools WebSocket
+// address, then keeps draining stderr so the browser never blocks on it. If
+// the browser exits first, the error includes its last stderr lines.
+func readDevToolsURL(ctx context.Context, stderr io.Reader, exited <-chan struct{}) (string, error) {
+	const prefix = "DevTools listening on "
+	found := make(chan string, 1)
+	scanned := make(chan []string, 1)
+	go func() {
+		sc := bufio.NewScanner(stderr)
+		sent := false
+		var tail []string
+		for sc.Scan() {
+			line := sc.Text()
+			if sent {
+				continue
+			}
+			if strings.HasPrefix(line, prefix) {
+				found <- strings.TrimSpace(strings.TrimPrefix(line, prefix))
+				sent = true
+				continue
+			}
+			if tail = append(tail, line); len(tail) > 5 {
+				tail = tail[1:]
+			}
+		}
+		scanned <- tail
+	}()
+	exitErr := func() error {
+		var tail []string
+		select {
+		case tail = <-scanned:
+		case <-time.After(time.Second):
+		}
+		msg := "browser exited before DevTools was ready"
+		if len(tail) > 0 {
+			msg += ": " + strings.Join(tail, "\n")
+		}
+		return fmt.Errorf("%w: %s", ErrSessionClosed, msg)
+	}
+	select {
+	case u := <-found:
+		return u, nil
+	case <-exited:
+		select {
+		case u := <-found:
+			return u, nil
+		default:
+		}
+		return "", exitErr()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// browserWebSocketURL asks a DevTools HTTP endpoint for its browser-level
+// WebSocket address.
+func browserWebSocketURL(ctx context.Context, endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/json/version", nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: /json/version returned %s", ErrProtocol, resp.Status)
+	}
+	var version struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return "", fmt.Errorf("%w: decode /json/version: %v", ErrProtocol, err)
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("%w: /json/version has no webSocketDebuggerUrl", ErrProtocol)
+	}
+	return version.WebSocketDebuggerURL, nil
+}
+
+func removeTemp(dir string) {
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,660 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// launchLive starts a headless browser for live tests, which run only when
+// CUH_BROWSER_LIVE=1. CUH_CHROME_PATH overrides the browser binary.
+func launchLive(t *testing.T) *Session {
+	t.Helper()
+	if os.Getenv("CUH_BROWSER_LIVE") != "1" {
+		t.Skip("set CUH_BROWSER_LIVE=1 to run browser live tests")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	s, err := Launch(ctx, LaunchInput{ExecPath: os.Getenv("CUH_CHROME_PATH"), Headless: true})
+	be.Err(t, err, nil)
+	t.Cleanup(func() { be.Err(t, s.Close(), nil) })
+	return s
+}
+
+// servePages serves path -> HTML for live tests.
+func servePages(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func liveCtx(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestLiveSessionLifecycle(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Live</title><body style="height:3000px"><h1>Hello</h1></body>`,
+	})
+
+	be.Err(t, s.WaitLoad(ctx), nil)
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	png, err := s.Screenshot(ctx, ScreenshotInput{})
+	be.Err(t, err, nil)
+	be.True(t, strings.HasPrefix(string(png), "\x89PNG"))
+	full, err := s.Screenshot(ctx, ScreenshotInput{FullPage: true, Format: ScreenshotJPEG, Quality: 50})
+	be.Err(t, err, nil)
+	be.True(t, strings.HasPrefix(string(full), "\xff\xd8"))
+}
+
+func TestLiveFindGetAct(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Form</title>
+<label for=email>Email</label><input id=email>
+<select id=plan><option value=f>Free</option><option value=p>Pro</option></select>
+<button id=go onclick="document.getElementById('out').textContent = email.value + '/' + plan.value">Sign in</button>
+<button disabled>Disabled</button>
+<p id=out></p>
+<div style="display:none">Sign in hidden</div>`,
+	})
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	email, err := s.Find(ctx, FindInput{Role: "textbox", Name: "email"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(email), 1)
+	plan, err := s.Find(ctx, FindInput{Selector: "#plan"})
+	be.Err(t, err, nil)
+	button, err := s.Find(ctx, FindInput{Text: "sign in"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(button), 1)
+	disabled, err := s.Find(ctx, FindInput{Role: "button", Name: "Disabled"})
+	be.Err(t, err, nil)
+
+	els, err := s.Get(ctx, GetInput{Refs: append(button, "e999")})
+	be.Err(t, err, nil)
+	be.Equal(t, els[0].Tag, "button")
+	be.Equal(t, els[0].Attributes["id"], "go")
+	be.True(t, els[0].Box.Width > 0)
+	be.Err(t, els[1].Err, ErrStaleRef)
+
+	results, err := s.Act(ctx, ActInput{DryRun: true, Ops: []ElementOp{{Kind: OpClick, Ref: disabled[0]}}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, ErrNotInteractable)
+
+	results, err = s.Act(ctx, ActInput{Ops: []ElementOp{
+		{Kind: OpType, Ref: email[0], Text: "me@example.com"},
+		{Kind: OpSelect, Ref: plan[0], Value: "Pro"},
+		{Kind: OpClick, Ref: button[0]},
+	}})
+	be.Err(t, err, nil)
+	for _, r := range results {
+		be.Err(t, r.Err, nil)
+	}
+	out, err := s.Find(ctx, FindInput{Selector: "#out"})
+	be.Err(t, err, nil)
+	els, err = s.Get(ctx, GetInput{Refs: out})
+	be.Err(t, err, nil)
+	be.Equal(t, els[0].Text, "me@example.com/p")
+}
+
+func TestLiveStaleRefAfterNavigate(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	// Both pages register their first element the same way, so only the
+	// per-document nonce tells the refs apart.
+	srv := servePages(t, map[string]string{
+		"/a": `<title>A</title><button id=first>A</button>`,
+		"/b": `<title>B</title><button id=second>B</button>`,
+	})
+	be.Err(t, s.Navigate(ctx, srv.URL+"/a"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+	old, err := s.Find(ctx, FindInput{Role: "button"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(old), 1)
+
+	be.Err(t, s.Navigate(ctx, srv.URL+"/b"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+	fresh, err := s.Find(ctx, FindInput{Role: "button"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(fresh), 1)
+	be.True(t, fresh[0] != old[0])
+
+	els, err := s.Get(ctx, GetInput{Refs: []ElementRef{old[0], fresh[0]}})
+	be.Err(t, err, nil)
+	be.Err(t, els[0].Err, ErrStaleRef)
+	be.Err(t, els[1].Err, nil)
+	be.Equal(t, els[1].Attributes["id"], "second")
+
+	results, err := s.Act(ctx, ActInput{Ops: []ElementOp{{Kind: OpClick, Ref: old[0]}}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, ErrStaleRef)
+	err = s.Eval(ctx, EvalInput{Script: "el => el.id", Args: []any{old[0]}}, nil)
+	be.Err(t, err, ErrStaleRef)
+}
+
+func TestLiveFormOps(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Login</title>
+<form action=/welcome>
+<input name=user placeholder=User>
+<input name=q class=extra><input name=r class=extra>
+<label><input type=checkbox name=remember value=yes> Remember me</label>
+<label><input type=checkbox name=news value=yes checked> News</label>
+<button>Sign in</button>
+</form>`,
+		"/welcome": `<title>Welcome</title><p id=hi>Welcome</p>`,
+	})
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	results, err := s.Act(ctx, ActInput{DryRun: true, ContinueOnError: true, Ops: []ElementOp{
+		{Kind: OpType, Selector: ".extra", Text: "x"},
+		{Kind: OpClick, Selector: "#nope"},
+		{Kind: OpUncheck, Selector: "input[name=user]"},
+	}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, ErrAmbiguous)
+	be.Err(t, results[1].Err, ErrNotFound)
+	be.Err(t, results[2].Err, ErrInvalidArgument)
+
+	results, err = s.Act(ctx, ActInput{Ops: []ElementOp{
+		{Kind: OpType, Selector: "input[name=user]", Text: "ada"},
+		{Kind: OpCheck, Selector: "input[name=remember]"},
+		{Kind: OpCheck, Selector: "input[name=remember]"},
+		{Kind: OpUncheck, Selector: "input[name=news]"},
+		{Kind: OpSubmit, Selector: "input[name=user]"},
+	}})
+	be.Err(t, err, nil)
+	for _, r := range results {
+		be.Err(t, r.Err, nil)
+		be.True(t, r.Ref != "")
+	}
+
+	// Submit settles after the navigation, so the next page is ready.
+	var loc string
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "location.pathname + location.search"}, &loc), nil)
+	be.Equal(t, loc, "/welcome?user=ada&q=&r=&remember=yes")
+	hi, err := s.Find(ctx, FindInput{Selector: "#hi"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(hi), 1)
+}
+
+func TestLiveWait(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(300 * time.Millisecond)
+			w.Write([]byte("done"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<title>Wait</title><ul id=list></ul><p id=spinner>Loading</p>
+<script>
+fetch("/slow").then(r => r.text()).then(t => {
+  window.fetched = t;
+  for (let i = 0; i < 3; i++) list.append(Object.assign(document.createElement("li"), {textContent: "item " + i}));
+  spinner.remove();
+  history.pushState(null, "", "/items?n=3");
+});
+</script>`))
+	}))
+	t.Cleanup(srv.Close)
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	spinner, err := s.Find(ctx, FindInput{Selector: "#spinner"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(spinner), 1)
+
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitNetworkIdle, IdleTime: 200 * time.Millisecond})
+	be.Err(t, err, nil)
+	var fetched string
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "window.fetched"}, &fetched), nil)
+	be.Equal(t, fetched, "done")
+
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitHidden, Ref: spinner[0]})
+	be.Err(t, err, nil)
+	res, err := s.Wait(ctx, WaitInput{Kind: WaitCount, Selector: "#list li", Count: 3})
+	be.Err(t, err, nil)
+	be.Equal(t, res.Count, 3)
+	res, err = s.Wait(ctx, WaitInput{Kind: WaitURL, URL: `/items\?n=3$`})
+	be.Err(t, err, nil)
+	be.Equal(t, res.URL, srv.URL+"/items?n=3")
+	res, err = s.Wait(ctx, WaitInput{Kind: WaitScript, Script: "(n) => document.querySelectorAll('li').length === n && 'ok'", Args: []any{3}})
+	be.Err(t, err, nil)
+	be.Equal(t, res.Value, any("ok"))
+
+	short, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	_, err = s.Wait(short, WaitInput{Kind: WaitVisible, Selector: "#never"})
+	be.Err(t, err, context.DeadlineExceeded)
+}
+
+func TestLiveDownload(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	body := strings.Repeat("date,amount\n2026-01-01,42\n", 1000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/statement.csv" {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="statement.csv"`)
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<title>Bank</title><a id=dl href="/statement.csv">Download statement</a>`))
+	}))
+	t.Cleanup(srv.Close)
+	dir := t.TempDir()
+	sum := sha256.Sum256([]byte(body))
+
+	d, err := s.Download(ctx, DownloadInput{URL: srv.URL + "/statement.csv", Dir: dir})
+	be.Err(t, err, nil)
+	be.Equal(t, d.Path, filepath.Join(dir, "statement.csv"))
+	be.Equal(t, d.Size, int64(len(body)))
+	be.Equal(t, d.SHA256, hex.EncodeToString(sum[:]))
+
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+	d, err = s.Download(ctx, DownloadInput{Selector: "#dl", Dir: dir})
+	be.Err(t, err, nil)
+	be.Equal(t, d.Path, filepath.Join(dir, "statement (1).csv"))
+	data, err := os.ReadFile(d.Path)
+	be.Err(t, err, nil)
+	be.Equal(t, string(data), body)
+
+	_, err = s.Download(ctx, DownloadInput{URL: srv.URL + "/", Dir: dir})
+	be.Err(t, err, ErrNavigation)
+}
+
+func TestLiveCookiesAndStorage(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "server", Value: "s1", Path: "/", HttpOnly: true})
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<title>Store</title><script>localStorage.setItem("seen", "yes")</script>`))
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := s.LocalStorage(ctx, LocalStorageInput{})
+	be.Err(t, err, ErrUnsupported)
+
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	cookies, err := s.Cookies(ctx, CookiesInput{URLs: []string{srv.URL + "/"}})
+	be.Err(t, err, nil)
+	be.Equal(t, len(cookies), 1)
+	be.Equal(t, cookies[0].Name, "server")
+	be.True(t, cookies[0].HTTPOnly)
+
+	results, err := s.SetCookies(ctx, SetCookiesInput{Cookies: []Cookie{
+		{Name: "injected", Value: "v1", Domain: "127.0.0.1", Expires: time.Now().Add(time.Hour)},
+	}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+	var docCookie string
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "document.cookie"}, &docCookie), nil)
+	be.Equal(t, docCookie, "injected=v1")
+
+	removed, err := s.ClearCookies(ctx, ClearCookiesInput{Domain: "127.0.0.1"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(removed), 2)
+	cookies, err = s.Cookies(ctx, CookiesInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(cookies), 0)
+
+	items, err := s.LocalStorage(ctx, LocalStorageInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, items, StorageItems{Origin: srv.URL, Items: map[string]string{"seen": "yes"}})
+	_, err = s.SetLocalStorage(ctx, SetLocalStorageInput{Items: map[string]string{"token": "abc"}})
+	be.Err(t, err, nil)
+	var token string
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "localStorage.getItem('token')"}, &token), nil)
+	be.Equal(t, token, "abc")
+	removed2, err := s.ClearLocalStorage(ctx, ClearLocalStorageInput{All: true})
+	be.Err(t, err, nil)
+	be.Equal(t, len(removed2.Items), 2)
+	items, err = s.LocalStorage(ctx, LocalStorageInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(items.Items), 0)
+}
+
+func TestLiveExtractReadable(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	para := strings.Repeat("The committee met on Tuesday, and after a long debate, it voted to approve the plan. ", 4)
+	srv := servePages(t, map[string]string{
+		"/post": `<html lang="en"><head><title>Council approves plan | Daily Example</title>
+<meta property="og:site_name" content="Daily Example">
+<meta name="author" content="Ada Writer">
+<meta property="article:published_time" content="2026-03-04T09:30:00Z">
+<meta name="description" content="The plan passed.">
+</head><body>
+<nav class="site-nav"><a href="/">Home</a> <a href="/news">News</a></nav>
+<div class="sidebar"><p>Subscribe to our newsletter for daily updates, offers, and more news.</p></div>
+<div class="story-body"><h1>Council approves plan</h1><p>` + para + `</p><p>` + para + `</p>
+<div class="share-tools"><p>Share this story on social media, with friends, and colleagues.</p></div></div>
+<footer><p>Copyright Daily Example, all rights reserved, since 1900.</p></footer>
+</body></html>`,
+	})
+
+	a, err := s.ExtractReadable(ctx, ExtractReadableInput{URL: srv.URL + "/post"})
+	be.Err(t, err, nil)
+	be.Equal(t, a.URL, srv.URL+"/post")
+	be.Equal(t, a.Title, "Council approves plan")
+	be.Equal(t, a.Byline, "Ada Writer")
+	be.Equal(t, a.SiteName, "Daily Example")
+	be.Equal(t, a.PublishedTime, time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC))
+	be.Equal(t, a.Excerpt, "The plan passed.")
+	be.Equal(t, a.Lang, "en")
+	be.True(t, strings.HasPrefix(a.Text, "Council approves plan\n\nThe committee met"))
+	be.True(t, !strings.Contains(a.Text, "Subscribe"))
+	be.True(t, !strings.Contains(a.Text, "Share this story"))
+	be.True(t, !strings.Contains(a.Text, "Copyright"))
+	be.True(t, a.WordCount > 100)
+}
+
+func TestLiveEval(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Eval</title><ul><li>a</li><li>b</li><li>c</li></ul>`,
+	})
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	var title string
+	be.Err(t, s.Eval(ctx, EvalInput{Script: "document.title"}, &title), nil)
+	be.Equal(t, title, "Eval")
+
+	list, err := s.Find(ctx, FindInput{Selector: "ul"})
+	be.Err(t, err, nil)
+	var items []string
+	err = s.Eval(ctx, EvalInput{
+		Script: "async (ul, prefix) => { await new Promise(r => setTimeout(r, 10)); return [...ul.children].map(li => prefix + li.textContent) } // trailing comment",
+		Args:   []any{list[0], "item-"},
+	}, &items)
+	be.Err(t, err, nil)
+	be.Equal(t, items, []string{"item-a", "item-b", "item-c"})
+
+	err = s.Eval(ctx, EvalInput{Script: "() => { throw new Error('boom') }"}, nil)
+	be.Err(t, err, ErrScript)
+	err = s.Eval(ctx, EvalInput{Script: "(("}, nil)
+	be.Err(t, err, ErrScript)
+	err = s.Eval(ctx, EvalInput{Script: "el => el.id", Args: []any{ElementRef("e999")}}, nil)
+	be.Err(t, err, ErrStaleRef)
+}
+
+func TestLiveProfiles(t *testing.T) {
+	if os.Getenv("CUH_BROWSER_LIVE") != "1" {
+		t.Skip("set CUH_BROWSER_LIVE=1 to run browser live tests")
+	}
+	ctx := liveCtx(t)
+	dir := t.TempDir()
+	launch := func(profile string) (*Session, error) {
+		return Launch(ctx, LaunchInput{ExecPath: os.Getenv("CUH_CHROME_PATH"), Headless: true, Profile: profile, ProfilesDir: dir})
+	}
+	expires := time.Now().Add(time.Hour)
+
+	s, err := launch("work")
+	be.Err(t, err, nil)
+	_, err = s.SetCookies(ctx, SetCookiesInput{Cookies: []Cookie{{Name: "sid", Value: "w1", Domain: "example.com", Expires: expires}}})
+	be.Err(t, err, nil)
+
+	// The profile is locked while open; other profiles run alongside it.
+	_, err = launch("work")
+	be.True(t, errors.Is(err, ErrProfileInUse))
+	other, err := launch("personal")
+	be.Err(t, err, nil)
+	cookies, err := other.Cookies(ctx, CookiesInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(cookies), 0)
+	be.Err(t, other.Close(), nil)
+	be.Err(t, s.Close(), nil)
+
+	profiles, err := ListProfiles(ctx, ListProfilesInput{Dir: dir})
+	be.Err(t, err, nil)
+	be.Equal(t, len(profiles), 2)
+	be.True(t, !profiles[1].InUse)
+
+	s, err = launch("work")
+	be.Err(t, err, nil)
+	t.Cleanup(func() { s.Close() })
+	cookies, err = s.Cookies(ctx, CookiesInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(cookies), 1)
+	be.Equal(t, cookies[0].Value, "w1")
+}
+
+func TestLivePrintToPDF(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Order 42</title><h1>Thank you</h1><p>Order 42 is confirmed.</p>`,
+	})
+	path := filepath.Join(t.TempDir(), "order.pdf")
+	pdf, err := s.PrintToPDF(ctx, PDFInput{
+		URL:            srv.URL + "/",
+		Path:           path,
+		Paper:          PaperA4,
+		HeaderTemplate: `<div style="font-size:8px"><span class="title"></span></div>`,
+		FooterTemplate: `<div style="font-size:8px"><span class="pageNumber"></span>/<span class="totalPages"></span></div>`,
+	})
+	be.Err(t, err, nil)
+	data, err := os.ReadFile(path)
+	be.Err(t, err, nil)
+	be.Equal(t, pdf.Size, len(data))
+	be.True(t, strings.HasPrefix(string(data), "%PDF-"))
+
+	pdf, err = s.PrintToPDF(ctx, PDFInput{Landscape: true, Background: true})
+	be.Err(t, err, nil)
+	be.True(t, strings.HasPrefix(string(pdf.Data), "%PDF-"))
+
+	_, err = s.PrintToPDF(ctx, PDFInput{PageRanges: "5-9"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestLiveSnapshot(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := servePages(t, map[string]string{
+		"/": `<title>Sign in</title>
+<nav><a href="/help">Help</a></nav>
+<main>
+  <h1>Sign in</h1>
+  <div class="wrap"><p>Use your <b>work</b> account.</p></div>
+  <form>
+    <label>Email <input type="email" required value="ada@example.com"></label>
+    <label>Password <input type="password" value="secret"></label>
+    <label><input type="checkbox" checked> Remember me</label>
+    <select aria-label="Region"><option>EU</option><option selected>US</option></select>
+    <button type="submit" disabled>Continue</button>
+  </form>
+  <div style="display:none"><button>Hidden</button></div>
+  <script>var x = 1;</script>
+</main>`,
+	})
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	snap, err := s.Snapshot(ctx, SnapshotInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, snap.Title, "Sign in")
+	out := snap.String()
+	for _, want := range []string{
+		`- navigation [`,
+		`  - link "Help" [`,
+		`- main [`,
+		`  - heading "Sign in" level=1 [`,
+		`  - text "Use your work account."`,
+		`  - form [`,
+		`    - textbox "Email" value="ada@example.com" [`,
+		`    - checkbox "Remember me" [`,
+		`    - combobox "Region" value="US" [`,
+		`    - button "Continue" [`,
+	} {
+		be.True(t, strings.Contains(out, want))
+	}
+	for _, unwanted := range []string{"secret", "Hidden", "var x", `text "Email"`} {
+		be.True(t, !strings.Contains(out, unwanted))
+	}
+	be.True(t, strings.Contains(out, "(checked)"))
+	be.True(t, strings.Contains(out, "(disabled)"))
+	be.True(t, strings.Contains(out, "(required)"))
+
+	// Refs from the snapshot drive Act.
+	form, err := s.Snapshot(ctx, SnapshotInput{Selector: "form", InteractiveOnly: true})
+	be.Err(t, err, nil)
+	var fields []string
+	var remember ElementRef
+	for _, n := range form.Nodes {
+		fields = append(fields, n.Role)
+		if n.Role == "checkbox" {
+			remember = n.Ref
+		}
+	}
+	be.Equal(t, fields, []string{"textbox", "textbox", "checkbox", "combobox", "button"})
+	results, err := s.Act(ctx, ActInput{Ops: []ElementOp{{Kind: OpUncheck, Ref: remember}}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+
+	capped, err := s.Snapshot(ctx, SnapshotInput{MaxNodes: 3})
+	be.Err(t, err, nil)
+	be.True(t, capped.Truncated)
+}
+
+func TestLiveCapture(t *testing.T) {
+	s := launchLive(t)
+	ctx := liveCtx(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<title>Orders</title><script>
+fetch("/api/orders?page=1").then(r => r.json()).then(o => document.title = o.count + " orders");
+fetch("/api/track", {method: "POST", body: "x=1"});
+</script>`))
+		case "/api/orders":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"count":3}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := s.StartCapture(ctx, CaptureInput{Bodies: true})
+	be.Err(t, err, nil)
+	be.Err(t, s.Navigate(ctx, srv.URL+"/"), nil)
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitNetworkIdle, IdleTime: 200 * time.Millisecond})
+	be.Err(t, err, nil)
+	be.Err(t, c.Stop(ctx), nil)
+
+	byPath := map[string]NetworkEntry{}
+	for _, e := range c.Entries() {
+		u, err := url.Parse(e.URL)
+		be.Err(t, err, nil)
+		byPath[u.Path] = e
+	}
+	be.Equal(t, byPath["/"].Status, 200)
+	be.Equal(t, byPath["/"].ResourceType, "Document")
+	orders := byPath["/api/orders"]
+	be.Equal(t, orders.Status, 200)
+	be.Equal(t, orders.Body, `{"count":3}`)
+	be.Equal(t, orders.MIMEType, "application/json")
+	be.True(t, orders.Done && orders.Timings != nil)
+	track := byPath["/api/track"]
+	be.Equal(t, track.Method, "POST")
+	be.Equal(t, track.RequestBody, "x=1")
+	be.Equal(t, track.Status, 404)
+
+	data, err := c.HAR()
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(data), `"text": "{\"count\":3}"`))
+}
+
+// TestLiveWebDriver drives a page through a WebDriver server. It runs only
+// when CUH_WEBDRIVER_LIVE=1; CUH_WEBDRIVER names the driver (default
+// chromedriver) and CUH_CHROME_PATH the browser binary.
+func TestLiveWebDriver(t *testing.T) {
+	if os.Getenv("CUH_WEBDRIVER_LIVE") != "1" {
+		t.Skip("set CUH_WEBDRIVER_LIVE=1 to run WebDriver live tests")
+	}
+	ctx := liveCtx(t)
+	s, err := OpenWebDriver(ctx, WebDriverInput{
+		Driver:   os.Getenv("CUH_WEBDRIVER"),
+		ExecPath: os.Getenv("CUH_CHROME_PATH"),
+		Headless: true,
+	})
+	be.Err(t, err, nil)
+	t.Cleanup(func() { be.Err(t, s.Close(), nil) })
+	srv := servePages(t, map[string]string{
+		"/": `<title>Search</title>
+<form action="/results"><label>Query <input name="q"></label>
+<label><input type="checkbox" name="exact"> Exact</label>
+<button>Search</button></form>`,
+		"/results": `<title>Results</title><h1>Results</h1>`,
+	})
+
+	var d Driver = s
+	be.Err(t, d.Navigate(ctx, srv.URL+"/"), nil)
+	be.Err(t, d.WaitLoad(ctx), nil)
+	refs, err := d.Find(ctx, FindInput{Role: "textbox", Name: "Query"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(refs), 1)
+	results, err := d.Act(ctx, ActInput{Ops: []ElementOp{
+		{Kind: OpType, Ref: refs[0], Text: "go"},
+		{Kind: OpCheck, Selector: "input[name=exact]"},
+		{Kind: OpClick, Selector: "button"},
+	}})
+	be.Err(t, err, nil)
+	for _, r := range results {
+		be.Err(t, r.Err, nil)
+	}
+	res, err := d.Wait(ctx, WaitInput{Kind: WaitURL, URL: `/results\?q=go&exact=on$`})
+	be.Err(t, err, nil)
+	be.True(t, strings.HasSuffix(res.URL, "exact=on"))
+	snap, err := d.Snapshot(ctx, SnapshotInput{})
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(snap.String(), `- heading "Results" level=1 [`))
+
+	_, err = d.SetLocalStorage(ctx, SetLocalStorageInput{Items: map[string]string{"k": "v"}})
+	be.Err(t, err, nil)
+	img, err := d.Screenshot(ctx, ScreenshotInput{})
+	be.Err(t, err, nil)
+	be.True(t, len(img) > 0)
+}
@@ -0,0 +1,170 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func attachFake(t *testing.T, f *fakeCDP) *Session {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s, err := Attach(ctx, AttachInput{Endpoint: f.srv.URL})
+	be.Err(t, err, nil)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestAttachNavigateScreenshotClose(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Page.captureScreenshot", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"data": base64.StdEncoding.EncodeToString([]byte("PNG"))}, nil
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+	be.Equal(t, s.TargetID(), "T1")
+
+	be.Err(t, s.Navigate(ctx, "https://example.com"), nil)
+	wctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	be.Err(t, s.WaitLoad(wctx), nil)
+
+	nav, ok := f.lastCall("Page.navigate")
+	be.True(t, ok)
+	be.Equal(t, nav.SessionID, "S1")
+	be.Equal(t, string(nav.Params), `{"url":"https://example.com"}`)
+
+	img, err := s.Screenshot(ctx, ScreenshotInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, string(img), "PNG")
+
+	be.Err(t, s.Close(), nil)
+	be.Err(t, s.Close(), nil)
+	closeCall, ok := f.lastCall("Target.closeTarget")
+	be.True(t, ok)
+	be.Equal(t, string(closeCall.Params), `{"targetId":"T1"}`)
+	be.Equal(t, f.methods(), []string{
+		"Target.createTarget", "Target.attachToTarget", "Page.enable", "Network.enable",
+		"Page.navigate", "Page.captureScreenshot", "Target.closeTarget",
+	})
+}
+
+func TestAttachWebSocketEndpoint(t *testing.T) {
+	f := newFakeCDP(t)
+	s, err := Attach(context.Background(), AttachInput{Endpoint: f.wsURL()})
+	be.Err(t, err, nil)
+	be.Err(t, s.Close(), nil)
+
+	_, err = Attach(context.Background(), AttachInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestScreenshotFullPage(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Page.getLayoutMetrics", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"cssContentSize": map[string]any{"width": 800, "height": 3000}}, nil
+	})
+	f.handle("Page.captureScreenshot", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"data": ""}, nil
+	})
+	s := attachFake(t, f)
+
+	_, err := s.Screenshot(context.Background(), ScreenshotInput{Format: ScreenshotJPEG, Quality: 80, FullPage: true})
+	be.Err(t, err, nil)
+	call, _ := f.lastCall("Page.captureScreenshot")
+	var params map[string]any
+	be.Err(t, json.Unmarshal(call.Params, &params), nil)
+	be.Equal(t, params["format"], any("jpeg"))
+	be.Equal(t, params["quality"], any(80.0))
+	be.Equal(t, params["captureBeyondViewport"], any(true))
+	clip := params["clip"].(map[string]any)
+	be.Equal(t, clip["height"], any(3000.0))
+
+	_, err = s.Screenshot(context.Background(), ScreenshotInput{Format: "gif"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.Screenshot(context.Background(), ScreenshotInput{Quality: 101})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestNavigateErrors(t *testing.T) {
+	f := newFakeCDP(t)
+	f.handle("Page.navigate", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"frameId": "F1", "errorText": "net::ERR_NAME_NOT_RESOLVED"}, nil
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	err := s.Navigate(ctx, "https://nowhere.invalid")
+	be.True(t, errors.Is(err, ErrNavigation))
+	err = s.Navigate(ctx, "nowhere")
+	be.True(t, errors.Is(err, ErrInvalidURL))
+
+	// The fake has no handler for this method, so it answers with a
+	// protocol error.
+	_, err = s.Screenshot(ctx, ScreenshotInput{})
+	be.True(t, errors.Is(err, ErrProtocol))
+}
+
+func TestWaitLoad(t *testing.T) {
+	f := newFakeCDP(t)
+	state := "loading"
+	f.handle("Runtime.evaluate", func(cdpMessage) (any, *cdpError) {
+		v := state
+		state = "complete"
+		return map[string]any{"result": map[string]any{"type": "string", "value": v}}, nil
+	})
+	f.handle("Page.navigate", func(cdpMessage) (any, *cdpError) {
+		return map[string]any{"frameId": "F1"}, nil
+	})
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Before any navigation WaitLoad polls document.readyState.
+	be.Err(t, s.WaitLoad(ctx), nil)
+
+	// After a navigation it waits for the load event.
+	be.Err(t, s.Navigate(ctx, "https://example.com"), nil)
+	short, cancelShort := context.WithTimeout(ctx, 150*time.Millisecond)
+	defer cancelShort()
+	be.True(t, errors.Is(s.WaitLoad(short), context.DeadlineExceeded))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		f.emit("S1", "Page.loadEventFired", map[string]any{})
+	}()
+	be.Err(t, s.WaitLoad(ctx), nil)
+}
+
+func TestSessionClosedOnDisconnect(t *testing.T) {
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	f.disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := s.WaitLoad(ctx)
+	be.True(t, errors.Is(err, ErrSessionClosed))
+	err = s.Navigate(ctx, "https://example.com")
+	be.True(t, errors.Is(err, ErrSessionClosed))
+}
+
+func TestLaunchArgs(t *testing.T) {
+	got := launchArgs(LaunchInput{Headless: true, Args: []string{"--mute-audio"}}, "/tmp/p")
+	be.Equal(t, got, []string{
+		"--remote-debugging-port=0", "--user-data-dir=/tmp/p", "--no-first-run",
+		"--no-default-browser-check", "--headless=new", "--window-size=1280,800",
+		"--mute-audio", "about:blank",
+	})
+}
+
+func TestLaunchBrowserNotFound(t *testing.T) {
+	_, err := Launch(context.Background(), LaunchInput{ExecPath: "/nonexistent/cuh-chrome"})
+	be.True(t, errors.Is(err, ErrBrowserNotFound))
+}
@@ -0,0 +1,149 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultSnapshotNodes caps a snapshot when SnapshotInput.MaxNodes is zero.
+const defaultSnapshotNodes = 1000
+
+// SnapshotInput configures [Session.Snapshot].
+type SnapshotInput struct {
+	// Ref or Selector limits the snapshot to one element's subtree. A
+	// Selector must match exactly one visible element. Empty outlines the
+	// whole page.
+	Ref      ElementRef `json:"ref,omitempty"`
+	Selector string     `json:"selector,omitempty"`
+	// InteractiveOnly keeps only controls: links, buttons, fields, and other
+	// elements that accept input, without the text around them.
+	InteractiveOnly bool `json:"interactive_only,omitempty"`
+	// MaxDepth limits how deeply nodes nest. Zero means no limit.
+	MaxDepth int `json:"max_depth,omitempty"`
+	// MaxNodes caps the number of nodes. Zero means 1000.
+	MaxNodes int `json:"max_nodes,omitempty"`
+	// MaxText truncates each name, value, and text to this many characters.
+	// Zero means no limit.
+	MaxText int `json:"max_text,omitempty"`
+}
+
+// SnapshotNode is one node of a [Snapshot]. Elements have a ref usable with
+// [Session.Act] and [Session.Get]; runs of text have Role "text", their
+// content in Name, and no ref.
+type SnapshotNode struct {
+	Ref  ElementRef `json:"ref,omitempty"`
+	Role string     `json:"role"`
+	Name string     `json:"name,omitempty"`
+	// Value is the current value of fields and selects. Password values are
+	// never included.
+	Value string `json:"value,omitempty"`
+	// Level is the heading level.
+	Level int `json:"level,omitempty"`
+	// States lists the set states among checked, disabled, expanded,
+	// collapsed, selected, required, readonly, and focused.
+	States   []string       `json:"states,omitempty"`
+	Box      Rect           `json:"box,omitzero"`
+	Children []SnapshotNode `json:"children,omitempty"`
+}
+
+// Snapshot is a compact outline of the rendered page, as returned by
+// [Session.Snapshot].
+type Snapshot struct {
+	URL   string         `json:"url"`
+	Title string         `json:"title"`
+	Nodes []SnapshotNode `json:"nodes"`
+	// Truncated is true when MaxNodes cut the outline short.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Snapshot returns an outline of the visible page built from the
+// accessibility roles and names that [Session.Find] matches on. Elements
+// with a role become nodes carrying a ref, name, value, states, and box;
+// wrappers without one are flattened away, and hidden content is left out.
+// Links, buttons, headings, and fields are leaves named by their text.
+//
+// The outline is far smaller than the page's HTML, and [Snapshot.String]
+// renders it as indented text for a model to read. Every ref in it can be
+// passed straight to [Session.Act].
+func (s *Session) Snapshot(ctx context.Context, input SnapshotInput) (Snapshot, error) {
+	return snapshot(ctx, s, input)
+}
+
+func snapshot(ctx context.Context, s page, input SnapshotInput) (Snapshot, error) {
+	id := ElementOp{Ref: input.Ref, Selector: input.Selector}.id()
+	if input.Ref != "" && input.Selector != "" {
+		return Snapshot{}, newInvalidArg("Snapshot", id, "ref and selector are mutually exclusive")
+	}
+	if input.MaxDepth < 0 || input.MaxNodes < 0 || input.MaxText < 0 {
+		return Snapshot{}, newInvalidArg("Snapshot", id, "maxDepth, maxNodes, and maxText must not be negative")
+	}
+	if input.MaxNodes == 0 {
+		input.MaxNodes = defaultSnapshotNodes
+	}
+	q := map[string]any{
+		"ref":         input.Ref,
+		"selector":    input.Selector,
+		"interactive": input.InteractiveOnly,
+		"max_depth":   input.MaxDepth,
+		"max_nodes":   input.MaxNodes,
+		"max_text":    input.MaxText,
+	}
+	var snap Snapshot
+	if err := callPage(ctx, s, "snapshot", []any{q}, &snap); err != nil {
+		return Snapshot{}, &OpError{Op: "Snapshot", ID: id, Err: err}
+	}
+	if snap.Nodes == nil {
+		snap.Nodes = []SnapshotNode{}
+	}
+	return snap, nil
+}
+
+// String renders the snapshot as an indented outline, one node per line,
+// with the box as x,y and width x height in whole CSS pixels:
+//
+//	# Sign in — https://example.com/login
+//	- heading "Sign in" level=1 [e1] @40,20 400x32
+//	- form [e2] @40,60 400x200
+//	  - textbox "Email" value="ada@example.com" [e3] @40,60 400x24 (required)
+//	  - button "Continue" [e4] @40,100 90x32
+//	- text "No account? Register below."
+func (s Snapshot) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — %s\n", s.Title, s.URL)
+	for _, n := range s.Nodes {
+		writeNode(&b, n, 0)
+	}
+	if s.Truncated {
+		b.WriteString("… (truncated)\n")
+	}
+	return b.String()
+}
+
+func writeNode(b *strings.Builder, n SnapshotNode, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString("- ")
+	b.WriteString(n.Role)
+	if n.Name != "" {
+		fmt.Fprintf(b, " %q", n.Name)
+	}
+	if n.Value != "" {
+		fmt.Fprintf(b, " value=%q", n.Value)
+	}
+	if n.Level > 0 {
+		fmt.Fprintf(b, " level=%d", n.Level)
+	}
+	if n.Ref != "" {
+		fmt.Fprintf(b, " [%s]", n.Ref)
+	}
+	if n.Box != (Rect{}) {
+		fmt.Fprintf(b, " @%.0f,%.0f %.0fx%.0f", n.Box.X, n.Box.Y, n.Box.Width, n.Box.Height)
+	}
+	if len(n.States) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(n.States, ", "))
+	}
+	b.WriteByte('\n')
+	for _, c := range n.Children {
+		writeNode(b, c, depth+1)
+	}
+}
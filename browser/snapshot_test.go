@@ -0,0 +1,73 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/nalgeon/be"
+)
+
+func TestSnapshot(t *testing.T) {
+	f := newFakeCDP(t)
+	var gotQuery map[string]any
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "snapshot")
+		be.Err(t, json.Unmarshal(args[0], &gotQuery), nil)
+		return map[string]any{"value": map[string]any{
+			"url":   "https://example.com/login",
+			"title": "Sign in",
+			"nodes": []any{
+				map[string]any{"ref": "e1", "role": "heading", "name": "Sign in", "level": 1, "box": map[string]any{"x": 40, "y": 20, "width": 400, "height": 32}},
+				map[string]any{"ref": "e2", "role": "form", "box": map[string]any{"x": 40, "y": 60, "width": 400, "height": 200.4}, "children": []any{
+					map[string]any{"ref": "e3", "role": "textbox", "name": "Email", "value": "ada@example.com", "states": []string{"required"}, "box": map[string]any{"x": 40, "y": 60, "width": 400, "height": 24}},
+					map[string]any{"ref": "e4", "role": "checkbox", "name": "Remember \"me\"", "states": []string{"checked", "disabled"}, "box": map[string]any{"x": 40.6, "y": 100, "width": 16, "height": 16}},
+				}},
+				map[string]any{"role": "text", "name": "No account? Register below."},
+			},
+			"truncated": true,
+		}}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	snap, err := s.Snapshot(ctx, SnapshotInput{Selector: "main", InteractiveOnly: true, MaxText: 80})
+	be.Err(t, err, nil)
+	be.Equal(t, gotQuery, map[string]any{
+		"ref": "", "selector": "main", "interactive": true,
+		"max_depth": 0.0, "max_nodes": 1000.0, "max_text": 80.0,
+	})
+	be.Equal(t, snap.Nodes[1].Children[0], SnapshotNode{
+		Ref: "e3", Role: "textbox", Name: "Email", Value: "ada@example.com",
+		States: []string{"required"}, Box: Rect{X: 40, Y: 60, Width: 400, Height: 24},
+	})
+	be.Equal(t, snap.String(), `# Sign in — https://example.com/login
+- heading "Sign in" level=1 [e1] @40,20 400x32
+- form [e2] @40,60 400x200
+  - textbox "Email" value="ada@example.com" [e3] @40,60 400x24 (required)
+  - checkbox "Remember \"me\"" [e4] @41,100 16x16 (checked, disabled)
+- text "No account? Register below."
+… (truncated)
+`)
+
+	data, err := json.Marshal(snap.Nodes[2])
+	be.Err(t, err, nil)
+	be.Equal(t, string(data), `{"role":"text","name":"No account? Register below."}`)
+}
+
+func TestSnapshotInvalid(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(string, []json.RawMessage) any {
+		return map[string]any{"error": map[string]any{"code": "not_found", "message": "no visible element matches"}}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	_, err := s.Snapshot(ctx, SnapshotInput{Selector: "#missing"})
+	be.True(t, errors.Is(err, ErrNotFound))
+	_, err = s.Snapshot(ctx, SnapshotInput{Ref: "e1", Selector: "main"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.Snapshot(ctx, SnapshotInput{MaxNodes: -1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
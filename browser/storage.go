@@ -0,0 +1,409 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Cookie is a browser cookie.
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Domain is the cookie's domain. A leading dot, as in ".example.com",
+	// marks a cookie shared with subdomains.
+	Domain string `json:"domain"`
+	// Path defaults to "/" when setting.
+	Path string `json:"path,omitempty"`
+	// Expires is when the cookie lapses. Zero means a session cookie, which
+	// lasts until the browser exits.
+	Expires  time.Time `json:"expires,omitzero"`
+	HTTPOnly bool      `json:"http_only,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	// SameSite is "Strict", "Lax", "None", or empty for the browser default.
+	SameSite string `json:"same_site,omitempty"`
+}
+
+// CookiesInput selects cookies for [Session.Cookies].
+type CookiesInput struct {
+	// URLs limits the result to cookies the browser would send to any of
+	// these URLs. Empty returns every cookie in the session's profile.
+	URLs []string `json:"urls,omitempty"`
+	// Name, if set, keeps only cookies with this name.
+	Name string `json:"name,omitempty"`
+}
+
+// SetCookiesInput lists cookies for [Session.SetCookies].
+type SetCookiesInput struct {
+	Cookies []Cookie `json:"cookies"`
+	// DryRun validates the cookies without setting them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// CookieResult is the outcome of setting one cookie.
+type CookieResult struct {
+	Cookie  Cookie `json:"cookie"`
+	Applied bool   `json:"applied"`
+	Err     error  `json:"-"`
+}
+
+// ClearCookiesInput selects the cookies [Session.ClearCookies] deletes.
+// Domain and Name narrow the selection; All must be set to delete every
+// cookie in the profile.
+type ClearCookiesInput struct {
+	// Domain matches cookies for this host and its subdomains, for example
+	// "example.com" matches "example.com" and ".www.example.com".
+	Domain string `json:"domain,omitempty"`
+	Name   string `json:"name,omitempty"`
+	All    bool   `json:"all,omitempty"`
+	// DryRun reports the cookies that would be deleted without deleting
+	// them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// StorageItems is localStorage content for one origin.
+type StorageItems struct {
+	// Origin is the page origin the items belong to, such as
+	// "https://example.com".
+	Origin string            `json:"origin"`
+	Items  map[string]string `json:"items"`
+}
+
+// LocalStorageInput selects items for [Session.LocalStorage].
+type LocalStorageInput struct {
+	// Keys limits the result to these keys. Empty returns every item.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// SetLocalStorageInput lists items for [Session.SetLocalStorage].
+type SetLocalStorageInput struct {
+	Items map[string]string `json:"items"`
+	// DryRun checks that the page has localStorage without writing.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ClearLocalStorageInput selects the items [Session.ClearLocalStorage]
+// removes: the listed Keys, or every item when All is set.
+type ClearLocalStorageInput struct {
+	Keys []string `json:"keys,omitempty"`
+	All  bool     `json:"all,omitempty"`
+	// DryRun reports the items that would be removed without removing them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// cdpCookie is a cookie as the Network and Storage domains encode it.
+type cdpCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires,omitempty"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	Session  bool    `json:"session,omitempty"`
+	SameSite string  `json:"sameSite,omitempty"`
+}
+
+func (c cdpCookie) cookie() Cookie {
+	out := Cookie{
+		Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path,
+		HTTPOnly: c.HTTPOnly, Secure: c.Secure, SameSite: c.SameSite,
+	}
+	if !c.Session && c.Expires > 0 {
+		sec, frac := math.Modf(c.Expires)
+		out.Expires = time.Unix(int64(sec), int64(frac*1e9))
+	}
+	return out
+}
+
+// Cookies returns the cookies in the session's browser profile, in the
+// browser's order, filtered by input. HttpOnly cookies are included.
+func (s *Session) Cookies(ctx context.Context, input CookiesInput) ([]Cookie, error) {
+	urls := make([]string, len(input.URLs))
+	for i, u := range input.URLs {
+		var err error
+		if urls[i], err = validateURL("Cookies", u); err != nil {
+			return nil, err
+		}
+	}
+	var raw []cdpCookie
+	var err error
+	if len(urls) == 0 {
+		raw, err = s.allCookies(ctx)
+	} else {
+		var res struct {
+			Cookies []cdpCookie `json:"cookies"`
+		}
+		err = s.call(ctx, "Network.getCookies", map[string]any{"urls": urls}, &res)
+		raw = res.Cookies
+	}
+	if err != nil {
+		return nil, &OpError{Op: "Cookies", Err: err}
+	}
+	var out []Cookie
+	for _, c := range raw {
+		if input.Name == "" || c.Name == input.Name {
+			out = append(out, c.cookie())
+		}
+	}
+	return out, nil
+}
+
+// allCookies returns every cookie in the browser context of the session.
+func (s *Session) allCookies(ctx context.Context) ([]cdpCookie, error) {
+	var res struct {
+		Cookies []cdpCookie `json:"cookies"`
+	}
+	if err := s.conn.call(ctx, "", "Storage.getCookies", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Cookies, nil
+}
+
+// SetCookies creates or replaces cookies in the session's browser profile
+// and reports one [CookieResult] per cookie. Each cookie needs a Name and a
+// Domain, and an Expires, if set, in the future; use [Session.ClearCookies]
+// to delete. Every write is read back, and a cookie the browser did not store
+// (for example a Secure or SameSite=None cookie rejected by policy) fails
+// with [ErrVerificationFailed].
+//
+// Per-cookie failures are reported in CookieResult.Err. The returned error is
+// non-nil only for invalid input or when the cookie jar cannot be read.
+func (s *Session) SetCookies(ctx context.Context, input SetCookiesInput) ([]CookieResult, error) {
+	results, err := cookieResults(input)
+	if err != nil || input.DryRun {
+		return results, err
+	}
+	for i, r := range results {
+		c := r.Cookie
+		params := map[string]any{
+			"name": c.Name, "value": c.Value, "domain": c.Domain, "path": c.Path,
+			"secure": c.Secure, "httpOnly": c.HTTPOnly,
+		}
+		if c.SameSite != "" {
+			params["sameSite"] = c.SameSite
+		}
+		if !c.Expires.IsZero() {
+			params["expires"] = float64(c.Expires.UnixNano()) / 1e9
+		}
+		if err := s.call(ctx, "Network.setCookie", params, nil); err != nil {
+			results[i].Err = &OpError{Op: "SetCookies", ID: c.Name, Err: err}
+			continue
+		}
+		results[i].Applied = true
+	}
+	jar, err := s.allCookies(ctx)
+	if err != nil {
+		return results, &OpError{Op: "SetCookies", Err: err}
+	}
+	for i, r := range results {
+		if !r.Applied || hasCookie(jar, r.Cookie) {
+			continue
+		}
+		results[i].Applied = false
+		results[i].Err = &OpError{Op: "SetCookies", ID: r.Cookie.Name, Err: fmt.Errorf("%w: cookie was not stored", ErrVerificationFailed)}
+	}
+	return results, nil
+}
+
+// cookieResults validates input and returns a result per cookie, with
+// Path defaulted, for SetCookies to fill in.
+func cookieResults(input SetCookiesInput) ([]CookieResult, error) {
+	if len(input.Cookies) == 0 {
+		return nil, newInvalidArg("SetCookies", "", "at least one cookie is required")
+	}
+	now := time.Now()
+	results := make([]CookieResult, len(input.Cookies))
+	for i, c := range input.Cookies {
+		id := fmt.Sprintf("cookie %d", i)
+		switch {
+		case strings.TrimSpace(c.Name) == "":
+			return nil, newInvalidArg("SetCookies", id, "name is required")
+		case strings.TrimSpace(c.Domain) == "":
+			return nil, newInvalidArg("SetCookies", id, "domain is required")
+		case !c.Expires.IsZero() && !c.Expires.After(now):
+			return nil, newInvalidArg("SetCookies", id, "expires is in the past; use ClearCookies to delete")
+		}
+		switch c.SameSite {
+		case "", "Strict", "Lax", "None":
+		default:
+			return nil, newInvalidArg("SetCookies", id, fmt.Sprintf("unsupported sameSite %q", c.SameSite))
+		}
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		results[i].Cookie = c
+	}
+	return results, nil
+}
+
+// hasCookie reports whether jar holds c with its value. The browser may
+// store a host cookie's domain with or without a leading dot.
+func hasCookie(jar []cdpCookie, c Cookie) bool {
+	for _, j := range jar {
+		if j.Name == c.Name && j.Path == c.Path && j.Value == c.Value &&
+			strings.TrimPrefix(j.Domain, ".") == strings.TrimPrefix(c.Domain, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearCookies deletes the cookies input selects from the session's browser
+// profile and returns them. It reads the jar back and fails with
+// [ErrVerificationFailed] if any selected cookie remains.
+func (s *Session) ClearCookies(ctx context.Context, input ClearCookiesInput) ([]Cookie, error) {
+	id, match, err := cookieSelector(input)
+	if err != nil {
+		return nil, err
+	}
+	jar, err := s.allCookies(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+	}
+	var selected []Cookie
+	for _, c := range jar {
+		if match(c) {
+			selected = append(selected, c.cookie())
+		}
+	}
+	if input.DryRun || len(selected) == 0 {
+		return selected, nil
+	}
+	for _, c := range selected {
+		params := map[string]any{"name": c.Name, "domain": c.Domain, "path": c.Path}
+		if err := s.call(ctx, "Network.deleteCookies", params, nil); err != nil {
+			return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+		}
+	}
+	jar, err = s.allCookies(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+	}
+	for _, c := range jar {
+		if match(c) {
+			return nil, &OpError{Op: "ClearCookies", ID: id, Err: fmt.Errorf("%w: cookie %s for %s remains", ErrVerificationFailed, c.Name, c.Domain)}
+		}
+	}
+	return selected, nil
+}
+
+// cookieSelector validates input and returns an id for errors and a match
+// for the cookies ClearCookies deletes.
+func cookieSelector(input ClearCookiesInput) (string, func(cdpCookie) bool, error) {
+	domain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(input.Domain)), ".")
+	if domain == "" && input.Name == "" && !input.All {
+		return "", nil, newInvalidArg("ClearCookies", "", "domain, name, or all is required")
+	}
+	if input.All && (domain != "" || input.Name != "") {
+		return "", nil, newInvalidArg("ClearCookies", "", "all cannot be combined with domain or name")
+	}
+	id := domain
+	if input.Name != "" {
+		id = strings.TrimPrefix(domain+" "+input.Name, " ")
+	}
+	match := func(c cdpCookie) bool {
+		if input.Name != "" && c.Name != input.Name {
+			return false
+		}
+		d := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		return domain == "" || d == domain || strings.HasSuffix(d, "."+domain)
+	}
+	return id, match, nil
+}
+
+// LocalStorage returns localStorage items for the origin of the session's
+// current page. Storage belongs to the origin, so navigate to a page of the
+// site first; pages without an origin, such as about:blank, fail with
+// [ErrUnsupported].
+func (s *Session) LocalStorage(ctx context.Context, input LocalStorageInput) (StorageItems, error) {
+	return localStorage(ctx, s, input)
+}
+
+func localStorage(ctx context.Context, s page, input LocalStorageInput) (StorageItems, error) {
+	keys := input.Keys
+	if keys == nil {
+		keys = []string{}
+	}
+	var out StorageItems
+	if err := callPage(ctx, s, "storage", []any{"get", keys}, &out); err != nil {
+		return StorageItems{}, &OpError{Op: "LocalStorage", Err: err}
+	}
+	return out, nil
+}
+
+// SetLocalStorage writes items to localStorage for the origin of the
+// session's current page and returns the stored items. It reads them back
+// and fails with [ErrVerificationFailed] if any value did not persist.
+func (s *Session) SetLocalStorage(ctx context.Context, input SetLocalStorageInput) (StorageItems, error) {
+	return setLocalStorage(ctx, s, input)
+}
+
+func setLocalStorage(ctx context.Context, s page, input SetLocalStorageInput) (StorageItems, error) {
+	if len(input.Items) == 0 {
+		return StorageItems{}, newInvalidArg("SetLocalStorage", "", "at least one item is required")
+	}
+	args := []any{"set", input.Items}
+	if input.DryRun {
+		// Reading checks the page has storage without writing to it.
+		keys := make([]string, 0, len(input.Items))
+		for k := range input.Items {
+			keys = append(keys, k)
+		}
+		args = []any{"get", keys}
+	}
+	var out StorageItems
+	if err := callPage(ctx, s, "storage", args, &out); err != nil {
+		return StorageItems{}, &OpError{Op: "SetLocalStorage", Err: err}
+	}
+	if input.DryRun {
+		return StorageItems{Origin: out.Origin, Items: input.Items}, nil
+	}
+	for k, v := range input.Items {
+		if got, ok := out.Items[k]; !ok || got != v {
+			return out, &OpError{Op: "SetLocalStorage", ID: k, Err: fmt.Errorf("%w: item was not stored", ErrVerificationFailed)}
+		}
+	}
+	return out, nil
+}
+
+// ClearLocalStorage removes the items input selects from localStorage for
+// the origin of the session's current page and returns them as they were.
+// It fails with [ErrVerificationFailed] if any remain.
+func (s *Session) ClearLocalStorage(ctx context.Context, input ClearLocalStorageInput) (StorageItems, error) {
+	return clearLocalStorage(ctx, s, input)
+}
+
+func clearLocalStorage(ctx context.Context, s page, input ClearLocalStorageInput) (StorageItems, error) {
+	if len(input.Keys) == 0 && !input.All {
+		return StorageItems{}, newInvalidArg("ClearLocalStorage", "", "keys or all is required")
+	}
+	if len(input.Keys) > 0 && input.All {
+		return StorageItems{}, newInvalidArg("ClearLocalStorage", "", "all cannot be combined with keys")
+	}
+	keys := input.Keys
+	if keys == nil {
+		keys = []string{}
+	}
+	var before StorageItems
+	if err := callPage(ctx, s, "storage", []any{"get", keys}, &before); err != nil {
+		return StorageItems{}, &OpError{Op: "ClearLocalStorage", Err: err}
+	}
+	if input.DryRun || len(before.Items) == 0 {
+		return before, nil
+	}
+	removeKeys := make([]string, 0, len(before.Items))
+	for k := range before.Items {
+		removeKeys = append(removeKeys, k)
+	}
+	var after StorageItems
+	if err := callPage(ctx, s, "storage", []any{"remove", removeKeys}, &after); err != nil {
+		return StorageItems{}, &OpError{Op: "ClearLocalStorage", Err: err}
+	}
+	if len(after.Items) > 0 {
+		return StorageItems{}, &OpError{Op: "ClearLocalStorage", Err: fmt.Errorf("%w: %d items remain", ErrVerificationFailed, len(after.Items))}
+	}
+	return before, nil
+}
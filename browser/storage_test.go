@@ -0,0 +1,252 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// fakeCookieJar serves the cookie commands on f from an in-memory jar. The
+// browser silently refuses cookies named "rejected".
+func fakeCookieJar(t *testing.T, f *fakeCDP, jar []cdpCookie) *[]cdpCookie {
+	var mu sync.Mutex
+	f.handle("Storage.getCookies", func(cdpMessage) (any, *cdpError) {
+		mu.Lock()
+		defer mu.Unlock()
+		return map[string]any{"cookies": jar}, nil
+	})
+	f.handle("Network.getCookies", func(msg cdpMessage) (any, *cdpError) {
+		var p struct {
+			URLs []string `json:"urls"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &p), nil)
+		mu.Lock()
+		defer mu.Unlock()
+		var out []cdpCookie
+		for _, c := range jar {
+			for _, u := range p.URLs {
+				if strings.Contains(u, strings.TrimPrefix(c.Domain, ".")) {
+					out = append(out, c)
+					break
+				}
+			}
+		}
+		return map[string]any{"cookies": out}, nil
+	})
+	f.handle("Network.setCookie", func(msg cdpMessage) (any, *cdpError) {
+		var c cdpCookie
+		be.Err(t, json.Unmarshal(msg.Params, &c), nil)
+		if c.Name == "rejected" {
+			return map[string]any{"success": false}, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		jar = append(jar, c)
+		return map[string]any{"success": true}, nil
+	})
+	f.handle("Network.deleteCookies", func(msg cdpMessage) (any, *cdpError) {
+		var c cdpCookie
+		be.Err(t, json.Unmarshal(msg.Params, &c), nil)
+		mu.Lock()
+		defer mu.Unlock()
+		var kept []cdpCookie
+		for _, j := range jar {
+			if j.Name != c.Name || j.Domain != c.Domain || j.Path != c.Path {
+				kept = append(kept, j)
+			}
+		}
+		jar = kept
+		return struct{}{}, nil
+	})
+	return &jar
+}
+
+func TestCookies(t *testing.T) {
+	f := newFakeCDP(t)
+	fakeCookieJar(t, f, []cdpCookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: 1893456000.5, HTTPOnly: true, Secure: true, SameSite: "Lax"},
+		{Name: "theme", Value: "dark", Domain: "www.example.com", Path: "/", Session: true, Expires: -1},
+		{Name: "sid", Value: "zzz", Domain: "other.test", Path: "/"},
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	all, err := s.Cookies(ctx, CookiesInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(all), 3)
+	be.Equal(t, all[0], Cookie{
+		Name: "sid", Value: "abc", Domain: ".example.com", Path: "/",
+		Expires: time.Unix(1893456000, 5e8), HTTPOnly: true, Secure: true, SameSite: "Lax",
+	})
+	be.True(t, all[1].Expires.IsZero())
+
+	got, err := s.Cookies(ctx, CookiesInput{URLs: []string{"https://www.example.com/"}, Name: "sid"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(got), 1)
+	be.Equal(t, got[0].Value, "abc")
+
+	_, err = s.Cookies(ctx, CookiesInput{URLs: []string{"example.com"}})
+	be.True(t, errors.Is(err, ErrInvalidURL))
+}
+
+func TestSetCookies(t *testing.T) {
+	f := newFakeCDP(t)
+	jar := fakeCookieJar(t, f, nil)
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	results, err := s.SetCookies(ctx, SetCookiesInput{Cookies: []Cookie{
+		{Name: "sid", Value: "abc", Domain: "example.com", Expires: expires, Secure: true, SameSite: "Strict"},
+		{Name: "rejected", Value: "x", Domain: "example.com"},
+	}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+	be.True(t, results[0].Applied)
+	be.Equal(t, results[0].Cookie.Path, "/")
+	be.True(t, errors.Is(results[1].Err, ErrVerificationFailed))
+	be.True(t, !results[1].Applied)
+	be.Equal(t, len(*jar), 1)
+	set, _ := f.lastCall("Network.setCookie")
+	be.True(t, strings.Contains(string(set.Params), `"name":"rejected"`))
+
+	data, err := json.Marshal(results[1])
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(data), `"code":"verification_failed"`))
+
+	results, err = s.SetCookies(ctx, SetCookiesInput{DryRun: true, Cookies: []Cookie{{Name: "a", Domain: "example.com"}}})
+	be.Err(t, err, nil)
+	be.True(t, !results[0].Applied)
+	be.Equal(t, len(*jar), 1)
+
+	invalid := []Cookie{
+		{Domain: "example.com"},
+		{Name: "a"},
+		{Name: "a", Domain: "example.com", Expires: time.Now().Add(-time.Hour)},
+		{Name: "a", Domain: "example.com", SameSite: "lax"},
+	}
+	for _, c := range invalid {
+		_, err := s.SetCookies(ctx, SetCookiesInput{Cookies: []Cookie{c}})
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+	_, err = s.SetCookies(ctx, SetCookiesInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestClearCookies(t *testing.T) {
+	f := newFakeCDP(t)
+	jar := fakeCookieJar(t, f, []cdpCookie{
+		{Name: "sid", Value: "1", Domain: ".example.com", Path: "/"},
+		{Name: "pref", Value: "2", Domain: "shop.example.com", Path: "/cart"},
+		{Name: "sid", Value: "3", Domain: "notexample.com", Path: "/"},
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	preview, err := s.ClearCookies(ctx, ClearCookiesInput{Domain: "example.com", DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, len(preview), 2)
+	be.Equal(t, len(*jar), 3)
+
+	removed, err := s.ClearCookies(ctx, ClearCookiesInput{Domain: "example.com"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(removed), 2)
+	be.Equal(t, *jar, []cdpCookie{{Name: "sid", Value: "3", Domain: "notexample.com", Path: "/"}})
+
+	// A cookie the browser will not delete fails verification.
+	f.handle("Network.deleteCookies", func(cdpMessage) (any, *cdpError) { return struct{}{}, nil })
+	_, err = s.ClearCookies(ctx, ClearCookiesInput{All: true})
+	be.True(t, errors.Is(err, ErrVerificationFailed))
+
+	_, err = s.ClearCookies(ctx, ClearCookiesInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.ClearCookies(ctx, ClearCookiesInput{All: true, Name: "sid"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestLocalStorage(t *testing.T) {
+	f := newFakeCDP(t)
+	store := map[string]string{"token": "t1", "theme": "dark"}
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "storage")
+		var op string
+		be.Err(t, json.Unmarshal(args[0], &op), nil)
+		var keys []string
+		if op == "set" {
+			var items map[string]string
+			be.Err(t, json.Unmarshal(args[1], &items), nil)
+			for k, v := range items {
+				if k != "quota" {
+					store[k] = v
+				}
+				keys = append(keys, k)
+			}
+		} else if string(args[1]) != "[]" {
+			be.Err(t, json.Unmarshal(args[1], &keys), nil)
+		} else {
+			for k := range store {
+				keys = append(keys, k)
+			}
+		}
+		if op == "remove" {
+			for _, k := range keys {
+				delete(store, k)
+			}
+		}
+		items := map[string]string{}
+		for _, k := range keys {
+			if v, ok := store[k]; ok {
+				items[k] = v
+			}
+		}
+		return map[string]any{"value": map[string]any{"origin": "https://example.com", "items": items}}
+	})
+	s := attachFake(t, f)
+	ctx := context.Background()
+
+	got, err := s.LocalStorage(ctx, LocalStorageInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, got, StorageItems{Origin: "https://example.com", Items: map[string]string{"token": "t1", "theme": "dark"}})
+	got, err = s.LocalStorage(ctx, LocalStorageInput{Keys: []string{"token", "missing"}})
+	be.Err(t, err, nil)
+	be.Equal(t, got.Items, map[string]string{"token": "t1"})
+
+	got, err = s.SetLocalStorage(ctx, SetLocalStorageInput{Items: map[string]string{"token": "t2"}})
+	be.Err(t, err, nil)
+	be.Equal(t, got.Items, map[string]string{"token": "t2"})
+	_, err = s.SetLocalStorage(ctx, SetLocalStorageInput{Items: map[string]string{"quota": "x"}})
+	be.True(t, errors.Is(err, ErrVerificationFailed))
+	got, err = s.SetLocalStorage(ctx, SetLocalStorageInput{Items: map[string]string{"token": "t3"}, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, got.Origin, "https://example.com")
+	be.Equal(t, store["token"], "t2")
+
+	removed, err := s.ClearLocalStorage(ctx, ClearLocalStorageInput{Keys: []string{"theme"}})
+	be.Err(t, err, nil)
+	be.Equal(t, removed.Items, map[string]string{"theme": "dark"})
+	removed, err = s.ClearLocalStorage(ctx, ClearLocalStorageInput{All: true})
+	be.Err(t, err, nil)
+	be.Equal(t, removed.Items, map[string]string{"token": "t2"})
+	be.Equal(t, len(store), 0)
+
+	_, err = s.ClearLocalStorage(ctx, ClearLocalStorageInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = s.SetLocalStorage(ctx, SetLocalStorageInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestLocalStorageUnsupported(t *testing.T) {
+	f := newFakeCDP(t)
+	handlePage(t, f, func(string, []json.RawMessage) any {
+		return map[string]any{"error": map[string]any{"code": "unsupported", "message": "about:blank"}}
+	})
+	s := attachFake(t, f)
+	_, err := s.LocalStorage(context.Background(), LocalStorageInput{})
+	be.True(t, errors.Is(err, ErrUnsupported))
+}
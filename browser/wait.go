@@ -0,0 +1,288 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WaitKind names a condition awaited by [Session.Wait].
+type WaitKind string
+
+const (
+	// WaitVisible waits until Ref is visible, or until at least one element
+	// matching Selector is.
+	WaitVisible WaitKind = "visible"
+	// WaitHidden waits until Ref is hidden or removed, or until no element
+	// matching Selector is visible.
+	WaitHidden WaitKind = "hidden"
+	// WaitCount waits until the number of visible elements matching Selector
+	// equals Count, or is at least Count when AtLeast is set.
+	WaitCount WaitKind = "count"
+	// WaitURL waits until the page URL matches the regular expression URL.
+	WaitURL WaitKind = "url"
+	// WaitNetworkIdle waits until no more than MaxInflight requests have
+	// been in flight for IdleTime.
+	WaitNetworkIdle WaitKind = "network_idle"
+	// WaitScript waits until Script, evaluated as by [Session.Eval], returns
+	// a truthy value.
+	WaitScript WaitKind = "script"
+)
+
+// defaultIdleTime is how long the network must be quiet for
+// WaitNetworkIdle when IdleTime is zero.
+const defaultIdleTime = 500 * time.Millisecond
+
+// waitPoll is how often page conditions are re-checked.
+const waitPoll = 100 * time.Millisecond
+
+// WaitInput is one condition for [Session.Wait]. Fields other than Kind apply
+// only to the kinds noted.
+type WaitInput struct {
+	Kind WaitKind `json:"kind"`
+	// Ref or Selector names the element for WaitVisible and WaitHidden; set
+	// exactly one. WaitCount takes a Selector.
+	Ref      ElementRef `json:"ref,omitempty"`
+	Selector string     `json:"selector,omitempty"`
+	// Count is the number of matches WaitCount waits for. Zero waits until
+	// none are visible.
+	Count int `json:"count,omitempty"`
+	// AtLeast makes WaitCount accept Count or more matches.
+	AtLeast bool `json:"at_least,omitempty"`
+	// URL is a regular expression, in Go syntax, matched against the full
+	// page URL by WaitURL. For example `/orders/\d+$`.
+	URL string `json:"url,omitempty"`
+	// Script and Args are the predicate for WaitScript.
+	Script string `json:"script,omitempty"`
+	Args   []any  `json:"args,omitempty"`
+	// IdleTime is how long the network must stay quiet for WaitNetworkIdle.
+	// Zero means 500ms.
+	IdleTime time.Duration `json:"idle_time,omitempty"`
+	// MaxInflight is the number of open requests WaitNetworkIdle tolerates,
+	// for pages that keep long-polling connections open.
+	MaxInflight int `json:"max_inflight,omitempty"`
+}
+
+// WaitResult describes the page state that satisfied a wait.
+type WaitResult struct {
+	// Waited is how long the condition took to hold.
+	Waited time.Duration `json:"waited"`
+	// Count is the number of visible matches for a Selector condition.
+	Count int `json:"count,omitempty"`
+	// URL is the page URL for WaitURL.
+	URL string `json:"url,omitempty"`
+	// Value is the truthy result of a WaitScript predicate.
+	Value any `json:"value,omitempty"`
+}
+
+// Wait blocks until the condition in input holds and reports the state that
+// satisfied it. Bound the wait with a ctx deadline: when ctx ends first the
+// error wraps ctx.Err() and describes the last state seen.
+//
+// Page conditions are polled, so they hold across navigations; a poll that
+// lands mid-navigation is retried. WaitVisible on a ref that has been removed
+// fails at once with [ErrStaleRef], and a WaitScript predicate that throws
+// fails with [ErrScript].
+func (s *Session) Wait(ctx context.Context, input WaitInput) (WaitResult, error) {
+	return wait(ctx, s, input)
+}
+
+func wait(ctx context.Context, s page, input WaitInput) (WaitResult, error) {
+	id := string(input.Kind)
+	cond, err := waitCondition(s, input)
+	if err != nil {
+		return WaitResult{}, newInvalidArg("Wait", id, err.Error())
+	}
+	start := time.Now()
+	if input.Kind == WaitNetworkIdle {
+		if err := s.waitNetworkIdle(ctx, input); err != nil {
+			return WaitResult{}, &OpError{Op: "Wait", ID: id, Err: err}
+		}
+		return WaitResult{Waited: time.Since(start)}, nil
+	}
+	last := "not checked yet"
+	for {
+		res, ok, err := cond(ctx)
+		switch {
+		case err == nil && ok:
+			res.Waited = time.Since(start)
+			return res, nil
+		case err == nil:
+			last = res.describe(input)
+		case errors.Is(err, ErrProtocol):
+			// The page was between documents; try again.
+			last = err.Error()
+		case ctx.Err() != nil:
+		default:
+			return WaitResult{}, &OpError{Op: "Wait", ID: id, Err: err}
+		}
+		select {
+		case <-ctx.Done():
+			return WaitResult{}, &OpError{Op: "Wait", ID: id, Err: fmt.Errorf("%w (last state: %s)", ctx.Err(), last)}
+		case <-s.done():
+			return WaitResult{}, &OpError{Op: "Wait", ID: id, Err: s.closedError()}
+		case <-time.After(waitPoll):
+		}
+	}
+}
+
+// waitCondition validates input and returns a check of the page condition.
+func waitCondition(s page, input WaitInput) (func(context.Context) (WaitResult, bool, error), error) {
+	switch input.Kind {
+	case WaitVisible, WaitHidden:
+		if (input.Ref == "") == (strings.TrimSpace(input.Selector) == "") {
+			return nil, fmt.Errorf("%s needs a ref or a selector", input.Kind)
+		}
+		return func(ctx context.Context) (WaitResult, bool, error) {
+			p, err := probe(ctx, s, input.Ref, input.Selector)
+			if err != nil {
+				return WaitResult{}, false, err
+			}
+			if input.Ref != "" {
+				if input.Kind == WaitVisible && !p.Attached {
+					return WaitResult{}, false, fmt.Errorf("%w: element %s is no longer in the page", ErrStaleRef, input.Ref)
+				}
+				return WaitResult{}, p.Visible == (input.Kind == WaitVisible), nil
+			}
+			return WaitResult{Count: p.Count}, (p.Count > 0) == (input.Kind == WaitVisible), nil
+		}, nil
+	case WaitCount:
+		if strings.TrimSpace(input.Selector) == "" {
+			return nil, fmt.Errorf("count needs a selector")
+		}
+		if input.Count < 0 {
+			return nil, fmt.Errorf("count must not be negative")
+		}
+		return func(ctx context.Context) (WaitResult, bool, error) {
+			p, err := probe(ctx, s, "", input.Selector)
+			if err != nil {
+				return WaitResult{}, false, err
+			}
+			ok := p.Count == input.Count || (input.AtLeast && p.Count > input.Count)
+			return WaitResult{Count: p.Count}, ok, nil
+		}, nil
+	case WaitURL:
+		if input.URL == "" {
+			return nil, fmt.Errorf("url needs a pattern")
+		}
+		re, err := regexp.Compile(input.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url pattern: %v", err)
+		}
+		return func(ctx context.Context) (WaitResult, bool, error) {
+			var href string
+			if err := s.evaluate(ctx, "location.href", &href); err != nil {
+				return WaitResult{}, false, err
+			}
+			return WaitResult{URL: href}, re.MatchString(href), nil
+		}, nil
+	case WaitNetworkIdle:
+		if input.IdleTime < 0 || input.MaxInflight < 0 {
+			return nil, fmt.Errorf("idle time and max inflight must not be negative")
+		}
+		return nil, nil
+	case WaitScript:
+		if strings.TrimSpace(input.Script) == "" {
+			return nil, fmt.Errorf("script needs a script")
+		}
+		expr, err := evalExpr(input.Script, input.Args)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (WaitResult, bool, error) {
+			var v any
+			if err := evaluatePage(ctx, s, expr, &v); err != nil {
+				return WaitResult{}, false, err
+			}
+			return WaitResult{Value: v}, truthy(v), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported wait kind %q", input.Kind)
+	}
+}
+
+// describe summarises an unsatisfied poll for timeout errors.
+func (r WaitResult) describe(input WaitInput) string {
+	switch input.Kind {
+	case WaitVisible, WaitHidden:
+		if input.Ref != "" {
+			return fmt.Sprintf("element %s is not %s", input.Ref, input.Kind)
+		}
+		return fmt.Sprintf("%d visible matches", r.Count)
+	case WaitCount:
+		return fmt.Sprintf("%d visible matches", r.Count)
+	case WaitURL:
+		return "url is " + r.URL
+	default:
+		v, _ := json.Marshal(r.Value)
+		return "script returned " + string(v)
+	}
+}
+
+// pageProbe is page.js's view of a ref or selector.
+type pageProbe struct {
+	Attached bool `json:"attached"`
+	Visible  bool `json:"visible"`
+	Count    int  `json:"count"`
+}
+
+func probe(ctx context.Context, s page, ref ElementRef, selector string) (pageProbe, error) {
+	var p pageProbe
+	q := map[string]string{"ref": string(ref), "selector": selector}
+	err := callPage(ctx, s, "probe", []any{q}, &p)
+	return p, err
+}
+
+// waitNetworkIdle waits until at most input.MaxInflight requests have been
+// open for the idle time, using the request tracking started with the
+// session.
+func (s *Session) waitNetworkIdle(ctx context.Context, input WaitInput) error {
+	idle := input.IdleTime
+	if idle == 0 {
+		idle = defaultIdleTime
+	}
+	for {
+		s.mu.Lock()
+		open, quiet, ch := len(s.inflight), time.Since(s.netActive), s.changed
+		s.mu.Unlock()
+		wake := time.Duration(0)
+		if open <= input.MaxInflight {
+			if quiet >= idle {
+				return nil
+			}
+			wake = idle - quiet
+		}
+		var timer <-chan time.Time
+		if wake > 0 {
+			timer = time.After(wake)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last state: %d requests in flight)", ctx.Err(), open)
+		case <-s.conn.done:
+			return s.conn.closedError()
+		case <-ch:
+		case <-timer:
+		}
+	}
+}
+
+// truthy applies JavaScript truthiness to a decoded JSON value.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
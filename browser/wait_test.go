@@ -0,0 +1,146 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+func TestWaitSelector(t *testing.T) {
+	f := newFakeCDP(t)
+	polls := 0
+	handlePage(t, f, func(method string, args []json.RawMessage) any {
+		be.Equal(t, method, "probe")
+		var q map[string]string
+		be.Err(t, json.Unmarshal(args[0], &q), nil)
+		if q["ref"] == "gone" {
+			return map[string]any{"value": map[string]any{"attached": false}}
+		}
+		polls++
+		return map[string]any{"value": map[string]any{"count": polls - 1}}
+	})
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := s.Wait(ctx, WaitInput{Kind: WaitVisible, Selector: ".result"})
+	be.Err(t, err, nil)
+	be.Equal(t, res.Count, 1)
+	be.Equal(t, polls, 2)
+
+	polls = 0
+	res, err = s.Wait(ctx, WaitInput{Kind: WaitCount, Selector: ".result", Count: 2, AtLeast: true})
+	be.Err(t, err, nil)
+	be.Equal(t, res.Count, 2)
+
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitVisible, Ref: "gone"})
+	be.True(t, errors.Is(err, ErrStaleRef))
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitHidden, Ref: "gone"})
+	be.Err(t, err, nil)
+
+	// A deadline reports the last state seen.
+	polls = 5
+	short, cancelShort := context.WithTimeout(ctx, 250*time.Millisecond)
+	defer cancelShort()
+	_, err = s.Wait(short, WaitInput{Kind: WaitHidden, Selector: ".result"})
+	be.True(t, errors.Is(err, context.DeadlineExceeded))
+	be.True(t, strings.Contains(err.Error(), "visible matches"))
+	be.Equal(t, ErrorCode(err), "timeout")
+}
+
+func TestWaitURLAndScript(t *testing.T) {
+	f := newFakeCDP(t)
+	evals := 0
+	f.handle("Runtime.evaluate", func(msg cdpMessage) (any, *cdpError) {
+		var params struct {
+			Expression string `json:"expression"`
+		}
+		be.Err(t, json.Unmarshal(msg.Params, &params), nil)
+		evals++
+		if evals == 1 {
+			// The first poll lands while the page is between documents.
+			return nil, &cdpError{Code: -32000, Message: "Execution context was destroyed."}
+		}
+		if params.Expression == "location.href" {
+			url := "https://shop.example/cart"
+			if evals > 2 {
+				url = "https://shop.example/orders/42"
+			}
+			return map[string]any{"result": map[string]any{"type": "string", "value": url}}, nil
+		}
+		var value any = 0
+		if evals > 2 {
+			value = "ready"
+		}
+		return map[string]any{"result": map[string]any{"type": "object", "value": map[string]any{"value": value}}}, nil
+	})
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := s.Wait(ctx, WaitInput{Kind: WaitURL, URL: `/orders/\d+$`})
+	be.Err(t, err, nil)
+	be.Equal(t, res.URL, "https://shop.example/orders/42")
+
+	evals = 0
+	res, err = s.Wait(ctx, WaitInput{Kind: WaitScript, Script: "() => window.state"})
+	be.Err(t, err, nil)
+	be.Equal(t, res.Value, any("ready"))
+}
+
+func TestWaitNetworkIdle(t *testing.T) {
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	f.emit("S1", "Network.requestWillBeSent", map[string]any{"requestId": "r1"})
+	f.emit("S1", "Network.requestWillBeSent", map[string]any{"requestId": "r2"})
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		f.emit("S1", "Network.loadingFinished", map[string]any{"requestId": "r1"})
+		f.emit("S1", "Network.loadingFailed", map[string]any{"requestId": "r2"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	_, err := s.Wait(ctx, WaitInput{Kind: WaitNetworkIdle, IdleTime: 100 * time.Millisecond})
+	be.Err(t, err, nil)
+	be.True(t, time.Since(start) >= 150*time.Millisecond)
+
+	// A long-lived request is tolerated with MaxInflight.
+	f.emit("S1", "Network.requestWillBeSent", map[string]any{"requestId": "poll"})
+	time.Sleep(20 * time.Millisecond)
+	short, cancelShort := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancelShort()
+	_, err = s.Wait(short, WaitInput{Kind: WaitNetworkIdle, IdleTime: 50 * time.Millisecond})
+	be.True(t, errors.Is(err, context.DeadlineExceeded))
+	be.True(t, strings.Contains(err.Error(), "1 requests in flight"))
+	_, err = s.Wait(ctx, WaitInput{Kind: WaitNetworkIdle, IdleTime: 50 * time.Millisecond, MaxInflight: 1})
+	be.Err(t, err, nil)
+}
+
+func TestWaitInvalid(t *testing.T) {
+	f := newFakeCDP(t)
+	s := attachFake(t, f)
+	invalid := []WaitInput{
+		{},
+		{Kind: "stable"},
+		{Kind: WaitVisible},
+		{Kind: WaitVisible, Ref: "e1", Selector: "a"},
+		{Kind: WaitCount},
+		{Kind: WaitCount, Selector: "a", Count: -1},
+		{Kind: WaitURL},
+		{Kind: WaitURL, URL: "("},
+		{Kind: WaitScript},
+		{Kind: WaitNetworkIdle, IdleTime: -1},
+	}
+	for _, in := range invalid {
+		_, err := s.Wait(context.Background(), in)
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+}
@@ -0,0 +1,762 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebDriverInput configures [OpenWebDriver].
+type WebDriverInput struct {
+	// URL is the address of a running WebDriver server, such as
+	// "http://127.0.0.1:4444" for Selenium or a driver started by hand.
+	// Empty starts Driver.
+	URL string `json:"url,omitempty"`
+	// Driver is the driver executable to start when URL is empty:
+	// "chromedriver", "msedgedriver", "geckodriver", or "safaridriver", or a
+	// path to one. Empty means chromedriver.
+	Driver string `json:"driver,omitempty"`
+	// Browser is the browserName capability: "chrome", "MicrosoftEdge",
+	// "firefox", or "safari". Empty derives it from Driver, or leaves the
+	// choice to the server at URL.
+	Browser string `json:"browser,omitempty"`
+	// ExecPath is the browser binary for Chrome, Edge, and Firefox. Empty
+	// lets the driver find it.
+	ExecPath string `json:"exec_path,omitempty"`
+	// Headless runs the browser without a window. Safari does not support
+	// it.
+	Headless bool `json:"headless,omitempty"`
+	// Args are extra command-line flags passed to Chrome, Edge, or Firefox.
+	Args []string `json:"args,omitempty"`
+	// Capabilities are merged over the capabilities derived from the fields
+	// above, for vendor options or a Selenium grid's platformName.
+	Capabilities map[string]any `json:"capabilities,omitempty"`
+}
+
+// WebDriverSession is one browser window driven through a W3C WebDriver
+// server (chromedriver, msedgedriver, geckodriver, or safaridriver). It
+// implements [Driver] with the same page-level behaviour as [Session], for
+// environments where attaching the DevTools protocol to the browser is not
+// permitted. Create it with [OpenWebDriver] and release it with Close.
+// Methods are safe for concurrent use, though the server runs one command
+// at a time.
+type WebDriverSession struct {
+	base    string
+	id      string
+	browser string
+	client  *http.Client
+
+	// Set when the session started its own driver.
+	cmd      *exec.Cmd
+	waitDone chan struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// OpenWebDriver starts a browser session through a WebDriver server: the
+// one at input.URL, or a driver process it starts and Close stops. The
+// driver must be installed and match the browser's version; a driver that
+// is not found fails with [ErrBrowserNotFound].
+func OpenWebDriver(ctx context.Context, input WebDriverInput) (*WebDriverSession, error) {
+	base := strings.TrimRight(strings.TrimSpace(input.URL), "/")
+	driver := strings.TrimSpace(input.Driver)
+	if base != "" && driver != "" {
+		return nil, newInvalidArg("OpenWebDriver", base, "url and driver are mutually exclusive")
+	}
+	if base == "" && driver == "" {
+		driver = "chromedriver"
+	}
+	browser := strings.TrimSpace(input.Browser)
+	if browser == "" && driver != "" {
+		browser = driverBrowser(driver)
+	}
+	caps, err := webDriverCapabilities(browser, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &WebDriverSession{base: base, client: &http.Client{}, closed: make(chan struct{})}
+	if driver != "" {
+		if err := s.startDriver(ctx, driver); err != nil {
+			return nil, err
+		}
+	}
+	var res struct {
+		SessionID    string `json:"sessionId"`
+		Capabilities struct {
+			BrowserName string `json:"browserName"`
+		} `json:"capabilities"`
+	}
+	body := map[string]any{"capabilities": map[string]any{"alwaysMatch": caps}}
+	if err := s.send(ctx, http.MethodPost, "/session", body, &res); err != nil {
+		s.stopDriver()
+		return nil, &OpError{Op: "OpenWebDriver", ID: s.base, Err: err}
+	}
+	s.id, s.browser = res.SessionID, res.Capabilities.BrowserName
+	return s, nil
+}
+
+// ID is the WebDriver session id.
+func (s *WebDriverSession) ID() string { return s.id }
+
+// BrowserName is the browser the server started, as it reports it, such as
+// "chrome" or "firefox".
+func (s *WebDriverSession) BrowserName() string { return s.browser }
+
+// driverBrowser maps a driver executable to the browser it drives.
+func driverBrowser(driver string) string {
+	name := strings.ToLower(filepath.Base(driver))
+	switch {
+	case strings.Contains(name, "gecko"):
+		return "firefox"
+	case strings.Contains(name, "edge"):
+		return "MicrosoftEdge"
+	case strings.Contains(name, "safari"):
+		return "safari"
+	case strings.Contains(name, "chrome"):
+		return "chrome"
+	}
+	return ""
+}
+
+// webDriverCapabilities builds the alwaysMatch capabilities for browser.
+func webDriverCapabilities(browser string, input WebDriverInput) (map[string]any, error) {
+	caps := map[string]any{}
+	if browser != "" {
+		caps["browserName"] = browser
+	}
+	execPath := strings.TrimSpace(input.ExecPath)
+	args := append([]string(nil), input.Args...)
+	// Each browser takes its flags in a vendor capability.
+	var key, headless string
+	switch strings.ToLower(browser) {
+	case "chrome", "chromium":
+		key, headless = "goog:chromeOptions", "--headless=new"
+	case "microsoftedge", "msedge":
+		key, headless = "ms:edgeOptions", "--headless=new"
+	case "firefox":
+		key, headless = "moz:firefoxOptions", "-headless"
+	default:
+		if input.Headless || execPath != "" || len(args) > 0 {
+			id := browser
+			if id == "" {
+				id = "server default"
+			}
+			return nil, &OpError{Op: "OpenWebDriver", ID: id, Err: fmt.Errorf("%w: headless, execPath, and args need chrome, MicrosoftEdge, or firefox", ErrUnsupported)}
+		}
+	}
+	if input.Headless {
+		args = append([]string{headless}, args...)
+	}
+	options := map[string]any{}
+	if len(args) > 0 {
+		options["args"] = args
+	}
+	if execPath != "" {
+		options["binary"] = execPath
+	}
+	if len(options) > 0 {
+		caps[key] = options
+	}
+	for k, v := range input.Capabilities {
+		caps[k] = v
+	}
+	return caps, nil
+}
+
+// startDriver runs driver on a free local port and waits for it to accept
+// sessions.
+func (s *WebDriverSession) startDriver(ctx context.Context, driver string) error {
+	path, err := exec.LookPath(driver)
+	if err != nil {
+		return &OpError{Op: "OpenWebDriver", ID: driver, Err: fmt.Errorf("%w: %v", ErrBrowserNotFound, err)}
+	}
+	port, err := freePort()
+	if err != nil {
+		return &OpError{Op: "OpenWebDriver", ID: driver, Err: err}
+	}
+	cmd := exec.Command(path, driverArgs(driver, port)...)
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return &OpError{Op: "OpenWebDriver", ID: driver, Err: fmt.Errorf("%w: %v", ErrBrowserNotFound, err)}
+		}
+		return &OpError{Op: "OpenWebDriver", ID: driver, Err: err}
+	}
+	s.cmd, s.waitDone = cmd, make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(s.waitDone)
+	}()
+	s.base = "http://127.0.0.1:" + strconv.Itoa(port)
+	for {
+		var status struct {
+			Ready bool `json:"ready"`
+		}
+		if err := s.send(ctx, http.MethodGet, "/status", nil, &status); err == nil && status.Ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			s.stopDriver()
+			return &OpError{Op: "OpenWebDriver", ID: driver, Err: ctx.Err()}
+		case <-s.waitDone:
+			return &OpError{Op: "OpenWebDriver", ID: driver, Err: fmt.Errorf("%w: driver exited: %v", ErrSessionClosed, cmd.ProcessState)}
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// driverArgs returns the flags that make driver listen on port.
+func driverArgs(driver string, port int) []string {
+	p := strconv.Itoa(port)
+	switch driverBrowser(driver) {
+	case "firefox":
+		return []string{"--port", p}
+	case "safari":
+		return []string{"-p", p}
+	default:
+		return []string{"--port=" + p}
+	}
+}
+
+// freePort returns a TCP port on the loopback interface that is free now.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// stopDriver kills a driver started by startDriver.
+func (s *WebDriverSession) stopDriver() {
+	if s.cmd == nil {
+		return
+	}
+	s.cmd.Process.Kill()
+	<-s.waitDone
+}
+
+// webDriverError is an error response from a WebDriver server.
+type webDriverError struct {
+	Code    string `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *webDriverError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// sentinel maps a WebDriver error code to the package's typed errors.
+func (e *webDriverError) sentinel() error {
+	switch e.Code {
+	case "invalid session id":
+		return ErrSessionClosed
+	case "javascript error":
+		return ErrScript
+	case "invalid argument":
+		return ErrInvalidArgument
+	case "unknown command", "unknown method", "unsupported operation":
+		return ErrUnsupported
+	case "element not interactable", "element click intercepted":
+		return ErrNotInteractable
+	case "no such element", "stale element reference", "detached shadow root":
+		return ErrStaleRef
+	default:
+		return ErrProtocol
+	}
+}
+
+// command sends a command for the session; path is relative to it.
+func (s *WebDriverSession) command(ctx context.Context, method, path string, body, out any) error {
+	select {
+	case <-s.closed:
+		return ErrSessionClosed
+	default:
+	}
+	return s.send(ctx, method, "/session/"+url.PathEscape(s.id)+path, body, out)
+}
+
+// send makes a request to the server and decodes the response's value
+// into out, which may be nil.
+func (s *WebDriverSession) send(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.base+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %v", ErrSessionClosed, err)
+	}
+	defer resp.Body.Close()
+	var res struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("%w: %s %s returned %s: %v", ErrProtocol, method, path, resp.Status, err)
+	}
+	if resp.StatusCode >= 400 {
+		wdErr := &webDriverError{Code: "unknown error", Message: resp.Status}
+		json.Unmarshal(res.Value, wdErr)
+		return fmt.Errorf("%w: %s %s: %w", wdErr.sentinel(), method, path, wdErr)
+	}
+	if out == nil || len(res.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Value, out)
+}
+
+// Navigate loads url in the session's window. WebDriver returns once the
+// page has loaded, so a following [WebDriverSession.WaitLoad] returns at
+// once. A page that fails to load returns [ErrNavigation].
+func (s *WebDriverSession) Navigate(ctx context.Context, url string) error {
+	url, err := validateURL("Navigate", url)
+	if err != nil {
+		return err
+	}
+	if err := s.command(ctx, http.MethodPost, "/url", map[string]any{"url": url}, nil); err != nil {
+		var wdErr *webDriverError
+		if errors.As(err, &wdErr) && !errors.Is(err, ErrSessionClosed) {
+			err = fmt.Errorf("%w: %s", ErrNavigation, wdErr.Message)
+		}
+		return &OpError{Op: "Navigate", ID: url, Err: err}
+	}
+	return nil
+}
+
+// WaitLoad blocks until the current document is complete. Bound the wait
+// with ctx.
+func (s *WebDriverSession) WaitLoad(ctx context.Context) error {
+	for {
+		var state string
+		if err := s.evaluate(ctx, "document.readyState", &state); err != nil && !errors.Is(err, ErrProtocol) {
+			return &OpError{Op: "WaitLoad", Err: err}
+		}
+		if state == "complete" {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return &OpError{Op: "WaitLoad", Err: ctx.Err()}
+		case <-s.closed:
+			return &OpError{Op: "WaitLoad", Err: ErrSessionClosed}
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Screenshot captures the window as PNG, the only format WebDriver
+// produces. FullPage is supported by Firefox only; other browsers fail with
+// [ErrUnsupported].
+func (s *WebDriverSession) Screenshot(ctx context.Context, input ScreenshotInput) ([]byte, error) {
+	format, err := screenshotFormat(input)
+	if err != nil {
+		return nil, err
+	}
+	if format != ScreenshotPNG {
+		return nil, &OpError{Op: "Screenshot", ID: string(format), Err: fmt.Errorf("%w: WebDriver captures PNG only", ErrUnsupported)}
+	}
+	path := "/screenshot"
+	if input.FullPage {
+		if s.browser != "firefox" {
+			return nil, &OpError{Op: "Screenshot", Err: fmt.Errorf("%w: full-page screenshots need firefox over WebDriver", ErrUnsupported)}
+		}
+		path = "/moz/screenshot/full"
+	}
+	var data string
+	if err := s.command(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, &OpError{Op: "Screenshot", Err: err}
+	}
+	img, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, &OpError{Op: "Screenshot", Err: fmt.Errorf("%w: decode image: %v", ErrProtocol, err)}
+	}
+	return img, nil
+}
+
+// Find is [Session.Find] for a WebDriver session.
+func (s *WebDriverSession) Find(ctx context.Context, input FindInput) ([]ElementRef, error) {
+	return find(ctx, s, input)
+}
+
+// Get is [Session.Get] for a WebDriver session.
+func (s *WebDriverSession) Get(ctx context.Context, input GetInput) ([]Element, error) {
+	return get(ctx, s, input)
+}
+
+// Act is [Session.Act] for a WebDriver session. Clicks and typing go
+// through WebDriver actions, so pages see trusted events.
+func (s *WebDriverSession) Act(ctx context.Context, input ActInput) ([]ActResult, error) {
+	return act(ctx, s, input)
+}
+
+// Snapshot is [Session.Snapshot] for a WebDriver session.
+func (s *WebDriverSession) Snapshot(ctx context.Context, input SnapshotInput) (Snapshot, error) {
+	return snapshot(ctx, s, input)
+}
+
+// Wait is [Session.Wait] for a WebDriver session. WaitNetworkIdle needs
+// the DevTools protocol and fails with [ErrUnsupported].
+func (s *WebDriverSession) Wait(ctx context.Context, input WaitInput) (WaitResult, error) {
+	return wait(ctx, s, input)
+}
+
+// Eval is [Session.Eval] for a WebDriver session.
+func (s *WebDriverSession) Eval(ctx context.Context, input EvalInput, out any) error {
+	return eval(ctx, s, input, out)
+}
+
+// ExtractReadable is [Session.ExtractReadable] for a WebDriver session.
+func (s *WebDriverSession) ExtractReadable(ctx context.Context, input ExtractReadableInput) (Article, error) {
+	return extractReadable(ctx, s, input)
+}
+
+// LocalStorage is [Session.LocalStorage] for a WebDriver session.
+func (s *WebDriverSession) LocalStorage(ctx context.Context, input LocalStorageInput) (StorageItems, error) {
+	return localStorage(ctx, s, input)
+}
+
+// SetLocalStorage is [Session.SetLocalStorage] for a WebDriver session.
+func (s *WebDriverSession) SetLocalStorage(ctx context.Context, input SetLocalStorageInput) (StorageItems, error) {
+	return setLocalStorage(ctx, s, input)
+}
+
+// ClearLocalStorage is [Session.ClearLocalStorage] for a WebDriver session.
+func (s *WebDriverSession) ClearLocalStorage(ctx context.Context, input ClearLocalStorageInput) (StorageItems, error) {
+	return clearLocalStorage(ctx, s, input)
+}
+
+// webDriverCookie is a cookie as WebDriver encodes it.
+type webDriverCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Expiry   int64  `json:"expiry,omitempty"`
+	HTTPOnly bool   `json:"httpOnly"`
+	Secure   bool   `json:"secure"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+func (c webDriverCookie) cdp() cdpCookie {
+	return cdpCookie{
+		Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path,
+		Expires: float64(c.Expiry), Session: c.Expiry == 0,
+		HTTPOnly: c.HTTPOnly, Secure: c.Secure, SameSite: c.SameSite,
+	}
+}
+
+// jar returns the cookies visible to the current page.
+func (s *WebDriverSession) jar(ctx context.Context) ([]cdpCookie, error) {
+	var raw []webDriverCookie
+	if err := s.command(ctx, http.MethodGet, "/cookie", nil, &raw); err != nil {
+		return nil, err
+	}
+	out := make([]cdpCookie, len(raw))
+	for i, c := range raw {
+		out[i] = c.cdp()
+	}
+	return out, nil
+}
+
+// Cookies returns the cookies visible to the current page, filtered by
+// input.Name. WebDriver exposes only the current page's cookies, so
+// input.URLs fails with [ErrUnsupported]; navigate to a page of the site
+// instead.
+func (s *WebDriverSession) Cookies(ctx context.Context, input CookiesInput) ([]Cookie, error) {
+	if len(input.URLs) > 0 {
+		return nil, &OpError{Op: "Cookies", Err: fmt.Errorf("%w: WebDriver reads only the current page's cookies", ErrUnsupported)}
+	}
+	jar, err := s.jar(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "Cookies", Err: err}
+	}
+	var out []Cookie
+	for _, c := range jar {
+		if input.Name == "" || c.Name == input.Name {
+			out = append(out, c.cookie())
+		}
+	}
+	return out, nil
+}
+
+// SetCookies is [Session.SetCookies] for a WebDriver session. WebDriver
+// sets cookies only for the current page's site, so a cookie for another
+// domain fails with [ErrInvalidArgument] in its CookieResult.
+func (s *WebDriverSession) SetCookies(ctx context.Context, input SetCookiesInput) ([]CookieResult, error) {
+	results, err := cookieResults(input)
+	if err != nil || input.DryRun {
+		return results, err
+	}
+	for i, r := range results {
+		c := r.Cookie
+		wc := webDriverCookie{
+			Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path,
+			HTTPOnly: c.HTTPOnly, Secure: c.Secure, SameSite: c.SameSite,
+		}
+		if !c.Expires.IsZero() {
+			wc.Expiry = c.Expires.Unix()
+		}
+		if err := s.command(ctx, http.MethodPost, "/cookie", map[string]any{"cookie": wc}, nil); err != nil {
+			var wdErr *webDriverError
+			if errors.As(err, &wdErr) && (wdErr.Code == "invalid cookie domain" || wdErr.Code == "unable to set cookie") {
+				err = fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+			}
+			results[i].Err = &OpError{Op: "SetCookies", ID: c.Name, Err: err}
+			continue
+		}
+		results[i].Applied = true
+	}
+	jar, err := s.jar(ctx)
+	if err != nil {
+		return results, &OpError{Op: "SetCookies", Err: err}
+	}
+	for i, r := range results {
+		if !r.Applied || hasCookie(jar, r.Cookie) {
+			continue
+		}
+		results[i].Applied = false
+		results[i].Err = &OpError{Op: "SetCookies", ID: r.Cookie.Name, Err: fmt.Errorf("%w: cookie was not stored", ErrVerificationFailed)}
+	}
+	return results, nil
+}
+
+// ClearCookies is [Session.ClearCookies] for a WebDriver session, limited
+// to the cookies visible to the current page.
+func (s *WebDriverSession) ClearCookies(ctx context.Context, input ClearCookiesInput) ([]Cookie, error) {
+	id, match, err := cookieSelector(input)
+	if err != nil {
+		return nil, err
+	}
+	jar, err := s.jar(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+	}
+	var selected []Cookie
+	for _, c := range jar {
+		if match(c) {
+			selected = append(selected, c.cookie())
+		}
+	}
+	if input.DryRun || len(selected) == 0 {
+		return selected, nil
+	}
+	for _, c := range selected {
+		if err := s.command(ctx, http.MethodDelete, "/cookie/"+url.PathEscape(c.Name), nil, nil); err != nil {
+			return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+		}
+	}
+	jar, err = s.jar(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "ClearCookies", ID: id, Err: err}
+	}
+	for _, c := range jar {
+		if match(c) {
+			return nil, &OpError{Op: "ClearCookies", ID: id, Err: fmt.Errorf("%w: cookie %s for %s remains", ErrVerificationFailed, c.Name, c.Domain)}
+		}
+	}
+	return selected, nil
+}
+
+// PrintToPDF is [Session.PrintToPDF] for a WebDriver session. WebDriver's
+// print command has no header, footer, or CSS page size options, so
+// HeaderFooter, the templates, and PreferCSSPageSize fail with
+// [ErrUnsupported]. Safari cannot print.
+func (s *WebDriverSession) PrintToPDF(ctx context.Context, input PDFInput) (PDF, error) {
+	id := pdfID(input)
+	params, err := pdfParams(input)
+	if err != nil {
+		return PDF{}, newInvalidArg("PrintToPDF", id, err.Error())
+	}
+	if input.HeaderFooter || input.HeaderTemplate != "" || input.FooterTemplate != "" || input.PreferCSSPageSize {
+		return PDF{}, &OpError{Op: "PrintToPDF", ID: id, Err: fmt.Errorf("%w: headers, footers, and CSS page size over WebDriver", ErrUnsupported)}
+	}
+	path, err := pdfPath(input)
+	if err != nil {
+		return PDF{}, err
+	}
+	if input.URL != "" {
+		if err := s.Navigate(ctx, input.URL); err != nil {
+			return PDF{}, err
+		}
+		if err := s.WaitLoad(ctx); err != nil {
+			return PDF{}, err
+		}
+	}
+	var data string
+	if err := s.command(ctx, http.MethodPost, "/print", printParams(params), &data); err != nil {
+		return PDF{}, &OpError{Op: "PrintToPDF", ID: id, Err: err}
+	}
+	return savePDF(id, path, data)
+}
+
+// printParams converts Page.printToPDF parameters, in inches, to WebDriver
+// print parameters, in centimetres.
+func printParams(p map[string]any) map[string]any {
+	cm := func(key string) float64 {
+		v, _ := p[key].(float64)
+		return math.Round(v*2.54*1000) / 1000
+	}
+	out := map[string]any{
+		"orientation": "portrait",
+		"background":  p["printBackground"],
+		"page":        map[string]any{"width": cm("paperWidth"), "height": cm("paperHeight")},
+	}
+	if p["landscape"] == true {
+		out["orientation"] = "landscape"
+	}
+	if scale, ok := p["scale"]; ok {
+		out["scale"] = scale
+	}
+	if _, ok := p["marginTop"]; ok {
+		out["margin"] = map[string]any{
+			"top": cm("marginTop"), "right": cm("marginRight"),
+			"bottom": cm("marginBottom"), "left": cm("marginLeft"),
+		}
+	}
+	if r, ok := p["pageRanges"].(string); ok {
+		ranges := []string{}
+		for part := range strings.SplitSeq(r, ",") {
+			ranges = append(ranges, strings.TrimSpace(part))
+		}
+		out["pageRanges"] = ranges
+	}
+	return out
+}
+
+// Close ends the WebDriver session, which closes its browser, and stops the
+// driver if OpenWebDriver started it. Close is idempotent.
+func (s *WebDriverSession) Close() error {
+	s.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := s.command(ctx, http.MethodDelete, "", nil, nil)
+		close(s.closed)
+		s.stopDriver()
+		if err != nil && !errors.Is(err, ErrSessionClosed) {
+			s.closeErr = &OpError{Op: "Close", ID: s.id, Err: err}
+		}
+	})
+	return s.closeErr
+}
+
+// evaluate runs expression through Execute Script. The value crosses as a
+// JSON string, so it decodes as it would from [Session.evaluate], without
+// WebDriver's own serialisation of elements and windows.
+func (s *WebDriverSession) evaluate(ctx context.Context, expression string, out any) error {
+	script := "return Promise.resolve((\n" + expression + "\n)).then(v => v === undefined ? null : JSON.stringify(v));"
+	var res *string
+	if err := s.command(ctx, http.MethodPost, "/execute/sync", map[string]any{"script": script, "args": []any{}}, &res); err != nil {
+		return err
+	}
+	if out == nil || res == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(*res), out)
+}
+
+// click sends a left-button press and release at viewport point (x, y).
+func (s *WebDriverSession) click(ctx context.Context, x, y float64) error {
+	pointer := map[string]any{
+		"type":       "pointer",
+		"id":         "cuh-mouse",
+		"parameters": map[string]any{"pointerType": "mouse"},
+		"actions": []any{
+			map[string]any{"type": "pointerMove", "origin": "viewport", "x": int(math.Round(x)), "y": int(math.Round(y)), "duration": 0},
+			map[string]any{"type": "pointerDown", "button": 0},
+			map[string]any{"type": "pointerUp", "button": 0},
+		},
+	}
+	return s.command(ctx, http.MethodPost, "/actions", map[string]any{"actions": []any{pointer}}, nil)
+}
+
+// insertText types text into the focused element, one key per character.
+func (s *WebDriverSession) insertText(ctx context.Context, text string) error {
+	keys := make([]any, 0, 2*len(text))
+	for _, r := range text {
+		// WebDriver names Enter and Tab by private-use code points.
+		key := string(r)
+		switch r {
+		case '\n':
+			key = "\ue007"
+		case '\t':
+			key = "\ue004"
+		}
+		keys = append(keys, map[string]any{"type": "keyDown", "value": key}, map[string]any{"type": "keyUp", "value": key})
+	}
+	keyboard := map[string]any{"type": "key", "id": "cuh-keyboard", "actions": keys}
+	return s.command(ctx, http.MethodPost, "/actions", map[string]any{"actions": []any{keyboard}}, nil)
+}
+
+// settle waits, best effort, for the page to react to an action. WebDriver
+// reports no load events, so it polls the document's ready state before
+// waiting for the DOM to go quiet.
+func (s *WebDriverSession) settle(ctx context.Context, timeout time.Duration) {
+	if timeout < 0 {
+		return
+	}
+	if timeout == 0 {
+		timeout = defaultSettleTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(settleGrace):
+	}
+	for {
+		var state string
+		if err := s.evaluate(ctx, "document.readyState", &state); err == nil && state == "complete" {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closed:
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	callPage(ctx, s, "settle", []any{settleQuiet.Milliseconds(), settleDOMMax.Milliseconds()}, nil)
+}
+
+func (s *WebDriverSession) waitNetworkIdle(context.Context, WaitInput) error {
+	return fmt.Errorf("%w: waiting for network idle needs the DevTools protocol", ErrUnsupported)
+}
+
+func (s *WebDriverSession) done() <-chan struct{} { return s.closed }
+
+func (s *WebDriverSession) closedError() error { return ErrSessionClosed }
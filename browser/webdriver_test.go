@@ -0,0 +1,318 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// wdRequest is a command received by fakeWebDriver.
+type wdRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// wdHandler answers a command with a value or a WebDriver error.
+type wdHandler func(body map[string]any) (any, *webDriverError)
+
+// fakeWebDriver is a W3C WebDriver server that answers commands for
+// session "W1" from handlers keyed by method and path below the session,
+// such as "POST /url".
+type fakeWebDriver struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]wdHandler
+	requests []wdRequest
+}
+
+func newFakeWebDriver(t *testing.T) *fakeWebDriver {
+	f := &fakeWebDriver{handlers: make(map[string]wdHandler)}
+	f.srv = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.srv.Close)
+	f.handle("POST /session", func(map[string]any) (any, *webDriverError) {
+		return map[string]any{"sessionId": "W1", "capabilities": map[string]any{"browserName": "chrome"}}, nil
+	})
+	f.handle("DELETE ", func(map[string]any) (any, *webDriverError) { return nil, nil })
+	return f
+}
+
+func (f *fakeWebDriver) handle(key string, h wdHandler) {
+	f.mu.Lock()
+	f.handlers[key] = h
+	f.mu.Unlock()
+}
+
+// script answers Execute Script, passing each script to fn, whose result
+// is returned JSON-encoded as evaluate expects.
+func (f *fakeWebDriver) script(fn func(script string) any) {
+	f.handle("POST /execute/sync", func(body map[string]any) (any, *webDriverError) {
+		v := fn(body["script"].(string))
+		if err, ok := v.(*webDriverError); ok {
+			return nil, err
+		}
+		data, _ := json.Marshal(v)
+		return string(data), nil
+	})
+}
+
+func (f *fakeWebDriver) last(key string) (wdRequest, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.requests) - 1; i >= 0; i-- {
+		if r := f.requests[i]; r.Method+" "+r.Path == key {
+			return r, true
+		}
+	}
+	return wdRequest{}, false
+}
+
+func (f *fakeWebDriver) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if rest, ok := strings.CutPrefix(path, "/session/W1"); ok {
+		path = rest
+	}
+	req := wdRequest{Method: r.Method, Path: path}
+	json.NewDecoder(r.Body).Decode(&req.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	h := f.handlers[r.Method+" "+path]
+	f.mu.Unlock()
+	var (
+		value any
+		err   *webDriverError
+	)
+	if h == nil {
+		err = &webDriverError{Code: "unknown command", Message: r.Method + " " + path}
+	} else {
+		value, err = h(req.Body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]any{"value": err})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"value": value})
+}
+
+func openFakeWebDriver(t *testing.T, f *fakeWebDriver) *WebDriverSession {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s, err := OpenWebDriver(ctx, WebDriverInput{URL: f.srv.URL})
+	be.Err(t, err, nil)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestOpenWebDriverCapabilities(t *testing.T) {
+	f := newFakeWebDriver(t)
+	ctx := context.Background()
+	s, err := OpenWebDriver(ctx, WebDriverInput{
+		URL: f.srv.URL + "/", Browser: "firefox", Headless: true, Args: []string{"-width=800"},
+		Capabilities: map[string]any{"platformName": "linux"},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, s.ID(), "W1")
+	be.Equal(t, s.BrowserName(), "chrome")
+	req, _ := f.last("POST /session")
+	be.Equal(t, req.Body, map[string]any{"capabilities": map[string]any{"alwaysMatch": map[string]any{
+		"browserName":        "firefox",
+		"platformName":       "linux",
+		"moz:firefoxOptions": map[string]any{"args": []any{"-headless", "-width=800"}},
+	}}})
+
+	caps, err := webDriverCapabilities(driverBrowser("/usr/local/bin/chromedriver"), WebDriverInput{Headless: true, ExecPath: "/opt/chrome"})
+	be.Err(t, err, nil)
+	be.Equal(t, caps, map[string]any{
+		"browserName":        "chrome",
+		"goog:chromeOptions": map[string]any{"args": []string{"--headless=new"}, "binary": "/opt/chrome"},
+	})
+	be.Equal(t, driverArgs("geckodriver", 4444), []string{"--port", "4444"})
+	be.Equal(t, driverArgs("safaridriver", 4444), []string{"-p", "4444"})
+	be.Equal(t, driverArgs("msedgedriver.exe", 4444), []string{"--port=4444"})
+
+	_, err = OpenWebDriver(ctx, WebDriverInput{Driver: "safaridriver", Headless: true})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, err = OpenWebDriver(ctx, WebDriverInput{URL: f.srv.URL, Driver: "chromedriver"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = OpenWebDriver(ctx, WebDriverInput{Driver: "cuh-no-such-chromedriver"})
+	be.True(t, errors.Is(err, ErrBrowserNotFound))
+
+	f.handle("POST /session", func(map[string]any) (any, *webDriverError) {
+		return nil, &webDriverError{Code: "session not created", Message: "Chrome version must be between 120 and 121"}
+	})
+	_, err = OpenWebDriver(ctx, WebDriverInput{URL: f.srv.URL})
+	be.True(t, errors.Is(err, ErrProtocol))
+	be.True(t, strings.Contains(err.Error(), "Chrome version must be"))
+}
+
+func TestWebDriverPage(t *testing.T) {
+	f := newFakeWebDriver(t)
+	f.handle("POST /url", func(body map[string]any) (any, *webDriverError) {
+		if strings.Contains(body["url"].(string), "offline") {
+			return nil, &webDriverError{Code: "unknown error", Message: "net::ERR_NAME_NOT_RESOLVED"}
+		}
+		return nil, nil
+	})
+	f.handle("POST /actions", func(map[string]any) (any, *webDriverError) { return nil, nil })
+	f.script(func(script string) any {
+		switch {
+		case strings.Contains(script, "(\ndocument.readyState\n)"):
+			return "complete"
+		case strings.Contains(script, ")().find("):
+			return map[string]any{"value": []string{"e1"}}
+		case strings.Contains(script, ")().perform("):
+			return map[string]any{"value": map[string]any{"ref": "e1", "click": true, "x": 10.6, "y": 20.2}}
+		case strings.Contains(script, "boom"):
+			return &webDriverError{Code: "javascript error", Message: "Error: boom"}
+		default:
+			return map[string]any{"value": nil}
+		}
+	})
+	s := openFakeWebDriver(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var d Driver = s
+
+	be.Err(t, d.Navigate(ctx, "https://example.com"), nil)
+	be.Err(t, d.WaitLoad(ctx), nil)
+	err := d.Navigate(ctx, "https://offline.example")
+	be.True(t, errors.Is(err, ErrNavigation))
+
+	refs, err := d.Find(ctx, FindInput{Role: "button"})
+	be.Err(t, err, nil)
+	be.Equal(t, refs, []ElementRef{"e1"})
+	req, _ := f.last("POST /execute/sync")
+	be.True(t, strings.HasPrefix(req.Body["script"].(string), "return Promise.resolve((\n("+pageLib))
+	be.Equal(t, req.Body["args"], any([]any{}))
+
+	res, err := d.Act(ctx, ActInput{Ops: []ElementOp{{Kind: OpClick, Ref: "e1"}}, SettleTimeout: -1})
+	be.Err(t, err, nil)
+	be.Err(t, res[0].Err, nil)
+	req, _ = f.last("POST /actions")
+	be.Equal(t, req.Body["actions"], any([]any{map[string]any{
+		"type": "pointer", "id": "cuh-mouse", "parameters": map[string]any{"pointerType": "mouse"},
+		"actions": []any{
+			map[string]any{"type": "pointerMove", "origin": "viewport", "x": 11.0, "y": 20.0, "duration": 0.0},
+			map[string]any{"type": "pointerDown", "button": 0.0},
+			map[string]any{"type": "pointerUp", "button": 0.0},
+		},
+	}}))
+	_, err = d.Act(ctx, ActInput{Ops: []ElementOp{{Kind: OpType, Ref: "e1", Text: "a\n"}}, SettleTimeout: -1})
+	be.Err(t, err, nil)
+	req, _ = f.last("POST /actions")
+	keys := req.Body["actions"].([]any)[0].(map[string]any)["actions"].([]any)
+	be.Equal(t, len(keys), 4)
+	be.Equal(t, keys[2], any(map[string]any{"type": "keyDown", "value": "\ue007"}))
+
+	err = d.Eval(ctx, EvalInput{Script: "() => { throw new Error('boom') }"}, nil)
+	be.True(t, errors.Is(err, ErrScript))
+	_, err = d.Wait(ctx, WaitInput{Kind: WaitNetworkIdle})
+	be.True(t, errors.Is(err, ErrUnsupported))
+
+	be.Err(t, d.Close(), nil)
+	be.Err(t, d.Close(), nil)
+	_, ok := f.last("DELETE ")
+	be.True(t, ok)
+	_, err = d.Find(ctx, FindInput{Selector: "h1"})
+	be.True(t, errors.Is(err, ErrSessionClosed))
+}
+
+func TestWebDriverScreenshotAndPDF(t *testing.T) {
+	f := newFakeWebDriver(t)
+	f.handle("GET /screenshot", func(map[string]any) (any, *webDriverError) {
+		return base64.StdEncoding.EncodeToString([]byte("PNG")), nil
+	})
+	f.handle("POST /print", func(map[string]any) (any, *webDriverError) {
+		return base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")), nil
+	})
+	s := openFakeWebDriver(t, f)
+	ctx := context.Background()
+
+	img, err := s.Screenshot(ctx, ScreenshotInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, string(img), "PNG")
+	_, err = s.Screenshot(ctx, ScreenshotInput{Format: ScreenshotJPEG})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, err = s.Screenshot(ctx, ScreenshotInput{FullPage: true})
+	be.True(t, errors.Is(err, ErrUnsupported))
+
+	pdf, err := s.PrintToPDF(ctx, PDFInput{Paper: PaperA4, Landscape: true, Margins: &PDFMargins{Top: 1}, PageRanges: "1-2, 4"})
+	be.Err(t, err, nil)
+	be.Equal(t, string(pdf.Data), "%PDF-1.4")
+	req, _ := f.last("POST /print")
+	be.Equal(t, req.Body, map[string]any{
+		"orientation": "landscape",
+		"background":  false,
+		"page":        map[string]any{"width": 21.006, "height": 29.693},
+		"margin":      map[string]any{"top": 2.54, "right": 0.0, "bottom": 0.0, "left": 0.0},
+		"pageRanges":  []any{"1-2", "4"},
+	})
+	_, err = s.PrintToPDF(ctx, PDFInput{FooterTemplate: "<span></span>"})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, err = s.PrintToPDF(ctx, PDFInput{Scale: 5})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestWebDriverCookies(t *testing.T) {
+	f := newFakeWebDriver(t)
+	var (
+		mu  sync.Mutex
+		jar = []webDriverCookie{{Name: "sid", Value: "1", Domain: ".example.com", Path: "/", HTTPOnly: true, Expiry: 1900000000}}
+	)
+	f.handle("GET /cookie", func(map[string]any) (any, *webDriverError) {
+		mu.Lock()
+		defer mu.Unlock()
+		return jar, nil
+	})
+	f.handle("POST /cookie", func(body map[string]any) (any, *webDriverError) {
+		c := body["cookie"].(map[string]any)
+		if c["domain"] != "example.com" {
+			return nil, &webDriverError{Code: "invalid cookie domain", Message: "Cookie domain must match the current page"}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		jar = append(jar, webDriverCookie{Name: c["name"].(string), Value: c["value"].(string), Domain: ".example.com", Path: c["path"].(string)})
+		return nil, nil
+	})
+	f.handle("DELETE /cookie/sid", func(map[string]any) (any, *webDriverError) {
+		mu.Lock()
+		defer mu.Unlock()
+		jar = jar[1:]
+		return nil, nil
+	})
+	s := openFakeWebDriver(t, f)
+	ctx := context.Background()
+
+	cookies, err := s.Cookies(ctx, CookiesInput{Name: "sid"})
+	be.Err(t, err, nil)
+	be.Equal(t, cookies, []Cookie{{Name: "sid", Value: "1", Domain: ".example.com", Path: "/", HTTPOnly: true, Expires: time.Unix(1900000000, 0)}})
+	_, err = s.Cookies(ctx, CookiesInput{URLs: []string{"https://example.com"}})
+	be.True(t, errors.Is(err, ErrUnsupported))
+
+	results, err := s.SetCookies(ctx, SetCookiesInput{Cookies: []Cookie{
+		{Name: "theme", Value: "dark", Domain: "example.com"},
+		{Name: "other", Value: "x", Domain: "other.example"},
+	}})
+	be.Err(t, err, nil)
+	be.Err(t, results[0].Err, nil)
+	be.True(t, results[0].Applied)
+	be.True(t, errors.Is(results[1].Err, ErrInvalidArgument))
+
+	cleared, err := s.ClearCookies(ctx, ClearCookiesInput{Name: "sid"})
+	be.Err(t, err, nil)
+	be.Equal(t, len(cleared), 1)
+	_, ok := f.last("DELETE /cookie/sid")
+	be.True(t, ok)
+}
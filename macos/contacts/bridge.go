@@ -10,9 +10,32 @@ package contacts
 */
 import "C"
 import (
+	"context"
+	"runtime"
+	"sync/atomic"
 	"unsafe"
 )
 
+// --- cancellation ---
+
+// watchCancel allocates a C flag that is set once ctx is done, so enumerating
+// bridge calls can stop between contacts. release must be called after the
+// bridge call returns.
+func watchCancel(ctx context.Context) (flag *C.int, release func()) {
+	flag = (*C.int)(C.calloc(1, C.size_t(unsafe.Sizeof(C.int(0)))))
+	fired := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		atomic.StoreInt32((*int32)(unsafe.Pointer(flag)), 1)
+		close(fired)
+	})
+	return flag, func() {
+		if !stop() {
+			<-fired
+		}
+		C.free(unsafe.Pointer(flag))
+	}
+}
+
 // --- cgo string helpers ---
 
 func goString(cs C.BridgeString) string {
@@ -86,7 +109,8 @@ func goContact(cc C.CContact) Contact {
 		for i, p := range phones {
 			c.PhoneNumbers[i] = LabeledValue[string]{
 				Identifier: goString(p.identifier),
-				Label:      goString(p.label),
+				Label:      friendlyLabel(goString(p.label)),
+				RawLabel:   goString(p.label),
 				Value:      goString(p.value),
 			}
 		}
@@ -98,7 +122,8 @@ func goContact(cc C.CContact) Contact {
 		for i, e := range emails {
 			c.EmailAddresses[i] = LabeledValue[string]{
 				Identifier: goString(e.identifier),
-				Label:      goString(e.label),
+				Label:      friendlyLabel(goString(e.label)),
+				RawLabel:   goString(e.label),
 				Value:      goString(e.value),
 			}
 		}
@@ -110,7 +135,8 @@ func goContact(cc C.CContact) Contact {
 		for i, a := range addrs {
 			c.PostalAddresses[i] = LabeledValue[PostalAddress]{
 				Identifier: goString(a.identifier),
-				Label:      goString(a.label),
+				Label:      friendlyLabel(goString(a.label)),
+				RawLabel:   goString(a.label),
 				Value: PostalAddress{
 					Street:         goString(a.value.street),
 					City:           goString(a.value.city),
@@ -129,7 +155,8 @@ func goContact(cc C.CContact) Contact {
 		for i, u := range urls {
 			c.URLAddresses[i] = LabeledValue[string]{
 				Identifier: goString(u.identifier),
-				Label:      goString(u.label),
+				Label:      friendlyLabel(goString(u.label)),
+				RawLabel:   goString(u.label),
 				Value:      goString(u.value),
 			}
 		}
@@ -141,7 +168,8 @@ func goContact(cc C.CContact) Contact {
 		for i, r := range rels {
 			c.ContactRelations[i] = LabeledValue[ContactRelation]{
 				Identifier: goString(r.identifier),
-				Label:      goString(r.label),
+				Label:      friendlyLabel(goString(r.label)),
+				RawLabel:   goString(r.label),
 				Value:      ContactRelation{Name: goString(r.value.name)},
 			}
 		}
@@ -153,7 +181,8 @@ func goContact(cc C.CContact) Contact {
 		for i, p := range profiles {
 			c.SocialProfiles[i] = LabeledValue[SocialProfile]{
 				Identifier: goString(p.identifier),
-				Label:      goString(p.label),
+				Label:      friendlyLabel(goString(p.label)),
+				RawLabel:   goString(p.label),
 				Value: SocialProfile{
 					URLString: goString(p.value.urlString),
 					Username:  goString(p.value.username),
@@ -169,7 +198,8 @@ func goContact(cc C.CContact) Contact {
 		for i, im := range ims {
 			c.InstantMessages[i] = LabeledValue[InstantMessage]{
 				Identifier: goString(im.identifier),
-				Label:      goString(im.label),
+				Label:      friendlyLabel(goString(im.label)),
+				RawLabel:   goString(im.label),
 				Value: InstantMessage{
 					Username: goString(im.value.instantUsername),
 					Service:  goString(im.value.instantService),
@@ -184,7 +214,8 @@ func goContact(cc C.CContact) Contact {
 		for i, d := range dates {
 			c.Dates[i] = LabeledValue[DateComponents]{
 				Identifier: goString(d.identifier),
-				Label:      goString(d.label),
+				Label:      friendlyLabel(goString(d.label)),
+				RawLabel:   goString(d.label),
 				Value: DateComponents{
 					Year:  int(d.value.year),
 					Month: int(d.value.month),
@@ -290,6 +321,22 @@ func getContact(identifier string, unified bool) (Contact, string) {
 	return c, ""
 }
 
+func getMeContact() (Contact, string) {
+	result := C.bridge_get_me_contact()
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	if errStr != "" {
+		C.bridge_free_contact(&result.contact)
+		return Contact{}, errStr
+	}
+
+	c := goContact(result.contact)
+	C.bridge_free_contact(&result.contact)
+	return c, ""
+}
+
 func resolveContactIdentity(identifier string) (ContactIdentity, string) {
 	cid := makeBridgeString(identifier)
 	defer freeBridgeString(cid)
@@ -307,7 +354,7 @@ func resolveContactIdentity(identifier string) (ContactIdentity, string) {
 	return identity, ""
 }
 
-func listContacts(filters []Filter) ([]Contact, string) {
+func listContacts(ctx context.Context, filters []Filter) ([]Contact, string) {
 	var cFilters *C.CFilter
 	var cFilterPtrs []C.CFilter
 
@@ -316,14 +363,16 @@ func listContacts(filters []Filter) ([]Contact, string) {
 		for i, f := range filters {
 			cFilterPtrs[i] = C.CFilter{
 				fieldName: makeBridgeString(string(f.Field)),
-				value:     makeBridgeString(f.Value),
+				value:     makeBridgeString(bridgeFilterValue(f)),
 				op:        C.int(f.Op),
 			}
 		}
 		cFilters = &cFilterPtrs[0]
 	}
 
-	result := C.bridge_list_contacts(cFilters, C.int(len(filters)))
+	cancel, release := watchCancel(ctx)
+	result := C.bridge_list_contacts(cFilters, C.int(len(filters)), cancel)
+	release()
 
 	// Free filter strings
 	for _, cf := range cFilterPtrs {
@@ -373,11 +422,78 @@ func createContact(input CreateContactInput) (string, string) {
 	return id, ""
 }
 
-func updateContact(input Contact) string {
+func createContacts(inputs []CreateContactInput) ([]string, string) {
+	if len(inputs) == 0 {
+		return nil, ""
+	}
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	cContacts := make([]C.CContact, len(inputs))
+	cContainerIDs := make([]C.BridgeString, len(inputs))
+	for i, input := range inputs {
+		cContacts[i] = buildCContact(input)
+		pinCContact(&pinner, &cContacts[i])
+		cContainerIDs[i] = makeBridgeString(input.Contact.ContainerID)
+	}
+	defer func() {
+		for i := range cContacts {
+			freeCContactInput(&cContacts[i])
+			freeBridgeString(cContainerIDs[i])
+		}
+	}()
+
+	result := C.bridge_create_contacts(&cContacts[0], &cContainerIDs[0], C.int(len(inputs)))
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	if errStr != "" {
+		return nil, errStr
+	}
+	ids := goStringSlice(result.identifiers, result.count)
+	C.bridge_free_string_array(result.identifiers, result.count)
+	return ids, ""
+}
+
+func updateContact(input Contact, patch UpdateContactInput) string {
 	cc := buildCContactFromContact(input)
 	defer freeCContactInput(&cc)
+	cp, freePatch := makeCMultiValuePatch(patch)
+	defer freePatch()
+
+	result := C.bridge_update_contact(cc, cp)
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	return errStr
+}
 
-	result := C.bridge_update_contact(cc)
+// updateContacts saves every contact in one request. patches[i] holds the
+// list operations for contacts[i].
+func updateContacts(contacts []Contact, patches []UpdateContactInput) string {
+	if len(contacts) == 0 {
+		return ""
+	}
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	cContacts := make([]C.CContact, len(contacts))
+	cPatches := make([]C.CMultiValuePatch, len(contacts))
+	frees := make([]func(), len(contacts))
+	for i := range contacts {
+		cContacts[i] = buildCContactFromContact(contacts[i])
+		pinCContact(&pinner, &cContacts[i])
+		cPatches[i], frees[i] = makeCMultiValuePatch(patches[i])
+		pinCMultiValuePatch(&pinner, &cPatches[i])
+	}
+	defer func() {
+		for i := range cContacts {
+			freeCContactInput(&cContacts[i])
+			frees[i]()
+		}
+	}()
+
+	result := C.bridge_update_contacts(&cContacts[0], &cPatches[0], C.int(len(contacts)))
 	errStr := goString(result.error)
 	if result.error.str != nil {
 		C.free(unsafe.Pointer(result.error.str))
@@ -385,6 +501,106 @@ func updateContact(input Contact) string {
 	return errStr
 }
 
+// makeCMultiValuePatch converts the email and phone list operations of patch.
+// The returned func frees the C strings.
+func makeCMultiValuePatch(patch UpdateContactInput) (C.CMultiValuePatch, func()) {
+	var cp C.CMultiValuePatch
+	addEmails := makeCLabeledStrings(patch.AddEmailAddresses)
+	removeEmails := makeBridgeStrings(patch.RemoveEmailAddresses)
+	addPhones := makeCLabeledStrings(patch.AddPhoneNumbers)
+	removePhones := makeBridgeStrings(patch.RemovePhoneNumbers)
+	if len(addEmails) > 0 {
+		cp.addEmails = &addEmails[0]
+		cp.addEmailsCount = C.int(len(addEmails))
+	}
+	if len(removeEmails) > 0 {
+		cp.removeEmails = &removeEmails[0]
+		cp.removeEmailsCount = C.int(len(removeEmails))
+	}
+	if len(addPhones) > 0 {
+		cp.addPhones = &addPhones[0]
+		cp.addPhonesCount = C.int(len(addPhones))
+	}
+	if len(removePhones) > 0 {
+		cp.removePhones = &removePhones[0]
+		cp.removePhonesCount = C.int(len(removePhones))
+	}
+	return cp, func() {
+		freeCLabeledStrings(addEmails)
+		freeBridgeStrings(removeEmails)
+		freeCLabeledStrings(addPhones)
+		freeBridgeStrings(removePhones)
+	}
+}
+
+// pinCContact pins the Go-allocated arrays cc points to. cgo only lets Go
+// memory that is passed to C hold pointers to pinned objects, which is the
+// case for the slices of CContact handed to the batch functions.
+func pinCContact(p *runtime.Pinner, cc *C.CContact) {
+	for _, ptr := range []unsafe.Pointer{
+		unsafe.Pointer(cc.linkedIDs),
+		unsafe.Pointer(cc.phoneNumbers),
+		unsafe.Pointer(cc.emailAddresses),
+		unsafe.Pointer(cc.postalAddresses),
+		unsafe.Pointer(cc.urlAddresses),
+		unsafe.Pointer(cc.contactRelations),
+		unsafe.Pointer(cc.socialProfiles),
+		unsafe.Pointer(cc.instantMessages),
+		unsafe.Pointer(cc.dates),
+	} {
+		if ptr != nil {
+			p.Pin(ptr)
+		}
+	}
+}
+
+func pinCMultiValuePatch(p *runtime.Pinner, cp *C.CMultiValuePatch) {
+	for _, ptr := range []unsafe.Pointer{
+		unsafe.Pointer(cp.addEmails),
+		unsafe.Pointer(cp.removeEmails),
+		unsafe.Pointer(cp.addPhones),
+		unsafe.Pointer(cp.removePhones),
+	} {
+		if ptr != nil {
+			p.Pin(ptr)
+		}
+	}
+}
+
+func makeCLabeledStrings(values []LabeledValue[string]) []C.CLabeledString {
+	out := make([]C.CLabeledString, len(values))
+	for i, v := range values {
+		out[i] = C.CLabeledString{
+			identifier: makeBridgeString(v.Identifier),
+			label:      makeBridgeString(systemLabel(v.Label, v.RawLabel)),
+			value:      makeBridgeString(v.Value),
+		}
+	}
+	return out
+}
+
+func freeCLabeledStrings(values []C.CLabeledString) {
+	for _, v := range values {
+		freeBridgeString(v.identifier)
+		freeBridgeString(v.label)
+		freeBridgeString(v.value)
+	}
+}
+
+func makeBridgeStrings(values []string) []C.BridgeString {
+	out := make([]C.BridgeString, len(values))
+	for i, v := range values {
+		out[i] = makeBridgeString(v)
+	}
+	return out
+}
+
+func freeBridgeStrings(values []C.BridgeString) {
+	for _, v := range values {
+		freeBridgeString(v)
+	}
+}
+
 func buildCContactFromContact(input Contact) C.CContact {
 	var cc C.CContact
 	cc.identifier = makeBridgeString(input.Identifier)
@@ -417,7 +633,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, p := range input.PhoneNumbers {
 			phones[i] = C.CLabeledString{
 				identifier: makeBridgeString(p.Identifier),
-				label:      makeBridgeString(p.Label),
+				label:      makeBridgeString(systemLabel(p.Label, p.RawLabel)),
 				value:      makeBridgeString(p.Value),
 			}
 		}
@@ -430,7 +646,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, e := range input.EmailAddresses {
 			emails[i] = C.CLabeledString{
 				identifier: makeBridgeString(e.Identifier),
-				label:      makeBridgeString(e.Label),
+				label:      makeBridgeString(systemLabel(e.Label, e.RawLabel)),
 				value:      makeBridgeString(e.Value),
 			}
 		}
@@ -443,7 +659,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, a := range input.PostalAddresses {
 			addrs[i] = C.CLabeledPostalAddress{
 				identifier: makeBridgeString(a.Identifier),
-				label:      makeBridgeString(a.Label),
+				label:      makeBridgeString(systemLabel(a.Label, a.RawLabel)),
 				value: C.CPostalAddress{
 					street:         makeBridgeString(a.Value.Street),
 					city:           makeBridgeString(a.Value.City),
@@ -463,7 +679,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, u := range input.URLAddresses {
 			urls[i] = C.CLabeledString{
 				identifier: makeBridgeString(u.Identifier),
-				label:      makeBridgeString(u.Label),
+				label:      makeBridgeString(systemLabel(u.Label, u.RawLabel)),
 				value:      makeBridgeString(u.Value),
 			}
 		}
@@ -476,7 +692,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, r := range input.ContactRelations {
 			rels[i] = C.CLabeledContactRelation{
 				identifier: makeBridgeString(r.Identifier),
-				label:      makeBridgeString(r.Label),
+				label:      makeBridgeString(systemLabel(r.Label, r.RawLabel)),
 				value:      C.CContactRelation{name: makeBridgeString(r.Value.Name)},
 			}
 		}
@@ -489,7 +705,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, p := range input.SocialProfiles {
 			profiles[i] = C.CLabeledSocialProfile{
 				identifier: makeBridgeString(p.Identifier),
-				label:      makeBridgeString(p.Label),
+				label:      makeBridgeString(systemLabel(p.Label, p.RawLabel)),
 				value: C.CSocialProfile{
 					urlString: makeBridgeString(p.Value.URLString),
 					username:  makeBridgeString(p.Value.Username),
@@ -506,7 +722,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, im := range input.InstantMessages {
 			ims[i] = C.CLabeledInstantMessage{
 				identifier: makeBridgeString(im.Identifier),
-				label:      makeBridgeString(im.Label),
+				label:      makeBridgeString(systemLabel(im.Label, im.RawLabel)),
 				value: C.CInstantMessage{
 					instantUsername: makeBridgeString(im.Value.Username),
 					instantService:  makeBridgeString(im.Value.Service),
@@ -522,7 +738,7 @@ func buildCContactFromContact(input Contact) C.CContact {
 		for i, d := range input.Dates {
 			dates[i] = C.CLabeledDateComponents{
 				identifier: makeBridgeString(d.Identifier),
-				label:      makeBridgeString(d.Label),
+				label:      makeBridgeString(systemLabel(d.Label, d.RawLabel)),
 				value: C.CDateComponents{
 					year:  C.int(d.Value.Year),
 					month: C.int(d.Value.Month),
@@ -576,7 +792,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, p := range contact.PhoneNumbers {
 			phones[i] = C.CLabeledString{
 				identifier: makeBridgeString(p.Identifier),
-				label:      makeBridgeString(p.Label),
+				label:      makeBridgeString(systemLabel(p.Label, p.RawLabel)),
 				value:      makeBridgeString(p.Value),
 			}
 		}
@@ -589,7 +805,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, e := range contact.EmailAddresses {
 			emails[i] = C.CLabeledString{
 				identifier: makeBridgeString(e.Identifier),
-				label:      makeBridgeString(e.Label),
+				label:      makeBridgeString(systemLabel(e.Label, e.RawLabel)),
 				value:      makeBridgeString(e.Value),
 			}
 		}
@@ -602,7 +818,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, a := range contact.PostalAddresses {
 			addrs[i] = C.CLabeledPostalAddress{
 				identifier: makeBridgeString(a.Identifier),
-				label:      makeBridgeString(a.Label),
+				label:      makeBridgeString(systemLabel(a.Label, a.RawLabel)),
 				value: C.CPostalAddress{
 					street:         makeBridgeString(a.Value.Street),
 					city:           makeBridgeString(a.Value.City),
@@ -622,7 +838,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, u := range contact.URLAddresses {
 			urls[i] = C.CLabeledString{
 				identifier: makeBridgeString(u.Identifier),
-				label:      makeBridgeString(u.Label),
+				label:      makeBridgeString(systemLabel(u.Label, u.RawLabel)),
 				value:      makeBridgeString(u.Value),
 			}
 		}
@@ -635,7 +851,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, r := range contact.ContactRelations {
 			rels[i] = C.CLabeledContactRelation{
 				identifier: makeBridgeString(r.Identifier),
-				label:      makeBridgeString(r.Label),
+				label:      makeBridgeString(systemLabel(r.Label, r.RawLabel)),
 				value:      C.CContactRelation{name: makeBridgeString(r.Value.Name)},
 			}
 		}
@@ -648,7 +864,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, p := range contact.SocialProfiles {
 			profiles[i] = C.CLabeledSocialProfile{
 				identifier: makeBridgeString(p.Identifier),
-				label:      makeBridgeString(p.Label),
+				label:      makeBridgeString(systemLabel(p.Label, p.RawLabel)),
 				value: C.CSocialProfile{
 					urlString: makeBridgeString(p.Value.URLString),
 					username:  makeBridgeString(p.Value.Username),
@@ -665,7 +881,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, im := range contact.InstantMessages {
 			ims[i] = C.CLabeledInstantMessage{
 				identifier: makeBridgeString(im.Identifier),
-				label:      makeBridgeString(im.Label),
+				label:      makeBridgeString(systemLabel(im.Label, im.RawLabel)),
 				value: C.CInstantMessage{
 					instantUsername: makeBridgeString(im.Value.Username),
 					instantService:  makeBridgeString(im.Value.Service),
@@ -681,7 +897,7 @@ func buildCContact(input CreateContactInput) C.CContact {
 		for i, d := range contact.Dates {
 			dates[i] = C.CLabeledDateComponents{
 				identifier: makeBridgeString(d.Identifier),
-				label:      makeBridgeString(d.Label),
+				label:      makeBridgeString(systemLabel(d.Label, d.RawLabel)),
 				value: C.CDateComponents{
 					year:  C.int(d.Value.Year),
 					month: C.int(d.Value.Month),
@@ -982,11 +1198,13 @@ func defaultContainerID() (string, string) {
 	return id, ""
 }
 
-func listContactsInGroup(groupID string) ([]Contact, string) {
+func listContactsInGroup(ctx context.Context, groupID string) ([]Contact, string) {
 	cgid := makeBridgeString(groupID)
 	defer freeBridgeString(cgid)
 
-	result := C.bridge_list_contacts_in_group(cgid)
+	cancel, release := watchCancel(ctx)
+	result := C.bridge_list_contacts_in_group(cgid, cancel)
+	release()
 	errStr := goString(result.error)
 	if result.error.str != nil {
 		C.free(unsafe.Pointer(result.error.str))
@@ -1005,3 +1223,122 @@ func listContactsInGroup(groupID string) ([]Contact, string) {
 	}
 	return contacts, ""
 }
+
+func listGroupMembers(ctx context.Context, groupID string) ([]GroupMember, string) {
+	cgid := makeBridgeString(groupID)
+	defer freeBridgeString(cgid)
+
+	cancel, release := watchCancel(ctx)
+	result := C.bridge_list_group_members(cgid, cancel)
+	release()
+	defer C.bridge_free_group_members(&result)
+	if errStr := goString(result.error); errStr != "" {
+		return nil, errStr
+	}
+
+	ids := goStringSlice(result.ids, result.count)
+	names := goStringSlice(result.names, result.count)
+	members := make([]GroupMember, len(ids))
+	for i := range ids {
+		members[i] = GroupMember{Identifier: ids[i], Name: names[i]}
+	}
+	return members, ""
+}
+
+func contactStats(ctx context.Context) (ContactStats, map[string]int, map[string]int, string) {
+	cancel, release := watchCancel(ctx)
+	result := C.bridge_contact_stats(cancel)
+	release()
+	defer C.bridge_free_contact_stats(&result)
+	if errStr := goString(result.error); errStr != "" {
+		return ContactStats{}, nil, nil, errStr
+	}
+
+	stats := ContactStats{
+		Total:        int(result.total),
+		WithEmail:    int(result.withEmail),
+		WithoutEmail: int(result.total - result.withEmail),
+		WithPhone:    int(result.withPhone),
+		WithoutPhone: int(result.total - result.withPhone),
+		WithNeither:  int(result.total - result.withEmailOrPhone),
+	}
+	byContainer := goCountMap(result.containerIDs, result.containerCounts, result.containerCount)
+	byGroup := goCountMap(result.groupIDs, result.groupCounts, result.groupCount)
+	return stats, byContainer, byGroup, ""
+}
+
+func goCountMap(ids *C.BridgeString, counts *C.int, count C.int) map[string]int {
+	out := make(map[string]int, int(count))
+	if ids == nil || counts == nil || count <= 0 {
+		return out
+	}
+	keys := goStringSlice(ids, count)
+	for i, n := range unsafe.Slice(counts, int(count)) {
+		out[keys[i]] = int(n)
+	}
+	return out
+}
+
+func goStringSlice(values *C.BridgeString, count C.int) []string {
+	if values == nil || count <= 0 {
+		return nil
+	}
+	out := make([]string, int(count))
+	for i, v := range unsafe.Slice(values, int(count)) {
+		out[i] = goString(v)
+	}
+	return out
+}
+
+func getContactNote(identifier string) (string, string) {
+	cid := makeBridgeString(identifier)
+	defer freeBridgeString(cid)
+
+	result := C.bridge_get_contact_note(cid)
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	note := goString(result.note)
+	if result.note.str != nil {
+		C.free(unsafe.Pointer(result.note.str))
+	}
+	if errStr != "" {
+		return "", errStr
+	}
+	return note, ""
+}
+
+func currentHistoryToken() (string, string) {
+	result := C.bridge_current_history_token()
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	token := goString(result.token)
+	if result.token.str != nil {
+		C.free(unsafe.Pointer(result.token.str))
+	}
+	if errStr != "" {
+		return "", errStr
+	}
+	return token, ""
+}
+
+func fetchChangeHistory(token string) (ContactChanges, string) {
+	ctoken := makeBridgeString(token)
+	defer freeBridgeString(ctoken)
+
+	result := C.bridge_fetch_change_history(ctoken)
+	defer C.bridge_free_change_history(&result)
+	if errStr := goString(result.error); errStr != "" {
+		return ContactChanges{}, errStr
+	}
+	return ContactChanges{
+		AddedIDs:       goStringSlice(result.addedIDs, result.addedCount),
+		UpdatedIDs:     goStringSlice(result.updatedIDs, result.updatedCount),
+		DeletedIDs:     goStringSlice(result.deletedIDs, result.deletedCount),
+		DropEverything: result.dropEverything != 0,
+		Token:          goString(result.token),
+	}, ""
+}
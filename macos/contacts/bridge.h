@@ -220,14 +220,87 @@ typedef struct {
     BridgeString error;
 } CDefaultContainerResult;
 
+typedef struct {
+    BridgeString *identifiers;
+    int           count;
+    BridgeString  error;
+} CBatchCreateResult;
+
+// CMultiValuePatch carries list operations applied to the contact's current
+// emails and phones at save time. Removals are applied before additions.
+typedef struct {
+    CLabeledString *addEmails;
+    int             addEmailsCount;
+    BridgeString   *removeEmails;
+    int             removeEmailsCount;
+    CLabeledString *addPhones;
+    int             addPhonesCount;
+    BridgeString   *removePhones;
+    int             removePhonesCount;
+} CMultiValuePatch;
+
+typedef struct {
+    BridgeString *addedIDs;
+    int           addedCount;
+    BridgeString *updatedIDs;
+    int           updatedCount;
+    BridgeString *deletedIDs;
+    int           deletedCount;
+    int           dropEverything;
+    BridgeString  token;  // base64-encoded history token after the changes
+    BridgeString  error;
+} CChangeHistoryResult;
+
+typedef struct {
+    BridgeString *ids;
+    BridgeString *names;  // formatted full names, parallel to ids
+    int           count;
+    BridgeString  error;
+} CGroupMembersResult;
+
+typedef struct {
+    int           total;             // non-unified contacts
+    int           withEmail;
+    int           withPhone;
+    int           withEmailOrPhone;
+    BridgeString *containerIDs;
+    int          *containerCounts;   // parallel to containerIDs
+    int           containerCount;
+    BridgeString *groupIDs;
+    int          *groupCounts;       // parallel to groupIDs
+    int           groupCount;
+    BridgeString  error;
+} CContactStatsResult;
+
+typedef struct {
+    BridgeString token;
+    BridgeString error;
+} CHistoryTokenResult;
+
+typedef struct {
+    BridgeString note;
+    BridgeString error;
+} CNoteResult;
+
 // --- Bridge functions ---
+// Enumerating functions accept an optional cancel flag. When the flag becomes
+// non-zero, enumeration stops and the result error is "operation cancelled".
 int              bridge_check_authorization(void);
 CAuthResult      bridge_request_access(void);
 CContactResult   bridge_get_contact(BridgeString identifier, int unifyResults);
 CContactIdentityResult bridge_resolve_contact_identity(BridgeString identifier);
-CContactListResult bridge_list_contacts(CFilter *filters, int filterCount);
+CContactResult   bridge_get_me_contact(void);
+CContactListResult bridge_list_contacts(CFilter *filters, int filterCount, int *cancel);
+// bridge_stream_contacts passes each matching contact to the Go callback
+// goStreamContact(handle, contact) as it is enumerated, stopping when the
+// callback returns 0. The contact is freed after the callback returns.
+CSimpleResult    bridge_stream_contacts(CFilter *filters, int filterCount, int *cancel, uintptr_t handle);
 CCreateResult    bridge_create_contact(CContact input, BridgeString containerID);
-CSimpleResult    bridge_update_contact(CContact input);
+CBatchCreateResult bridge_create_contacts(CContact *inputs, BridgeString *containerIDs, int count);
+CSimpleResult    bridge_update_contact(CContact input, CMultiValuePatch patch);
+// bridge_update_contacts applies every update in one CNSaveRequest. Like
+// bridge_create_contacts it is atomic: on failure nothing is saved.
+CSimpleResult    bridge_update_contacts(CContact *inputs, CMultiValuePatch *patches, int count);
 CSimpleResult    bridge_delete_contact(BridgeString identifier);
 CGroupListResult bridge_list_groups(BridgeString containerID, int includeHierarchy);
 CCreateResult    bridge_create_group(BridgeString name, BridgeString containerID, BridgeString parentGroupID);
@@ -238,7 +311,12 @@ CSimpleResult    bridge_remove_contact_from_group(BridgeString contactID, Bridge
 CContainerResult bridge_get_container(BridgeString identifier);
 CContainerListResult bridge_list_containers(void);
 CDefaultContainerResult bridge_default_container_id(void);
-CContactListResult bridge_list_contacts_in_group(BridgeString groupID);
+CContactListResult bridge_list_contacts_in_group(BridgeString groupID, int *cancel);
+CGroupMembersResult bridge_list_group_members(BridgeString groupID, int *cancel);
+CContactStatsResult bridge_contact_stats(int *cancel);
+CNoteResult      bridge_get_contact_note(BridgeString identifier);
+CHistoryTokenResult bridge_current_history_token(void);
+CChangeHistoryResult bridge_fetch_change_history(BridgeString token);
 
 // --- Memory management ---
 void bridge_free_contact(CContact *contact);
@@ -246,5 +324,9 @@ void bridge_free_contact_identity(CContactIdentity *identity);
 void bridge_free_contact_list(CContact *contacts, int count);
 void bridge_free_group_list(CGroup *groups, int count);
 void bridge_free_container_list(CContainer *containers, int count);
+void bridge_free_change_history(CChangeHistoryResult *result);
+void bridge_free_group_members(CGroupMembersResult *result);
+void bridge_free_contact_stats(CContactStatsResult *result);
+void bridge_free_string_array(BridgeString *values, int count);
 
 #endif /* CONTACTS_BRIDGE_H */
\ No newline at end of file
@@ -1,8 +1,10 @@
 #import <Contacts/Contacts.h>
 #import <Foundation/Foundation.h>
+#import <objc/runtime.h>
 #include <stdlib.h>
 #include <string.h>
 #include "bridge.h"
+#include "_cgo_export.h"
 
 // --- Helpers ---
 
@@ -43,6 +45,37 @@ static BridgeString cstring_from_error(NSError *error) {
     return cstring_from_nsstring([error localizedDescription]);
 }
 
+// kNoteEntitlementErrorCode is the Cocoa error reported when a save touches
+// the note field without the com.apple.developer.contacts.notes entitlement.
+static const NSInteger kNoteEntitlementErrorCode = 134092;
+
+// is_note_entitlement_error reports whether error was caused by touching the
+// note key without the entitlement: Cocoa error 134092 on save, or a Contacts
+// error naming the note key path on fetch.
+static BOOL is_note_entitlement_error(NSError *error) {
+    if (error == nil) {
+        return NO;
+    }
+    if ([error.domain isEqualToString:NSCocoaErrorDomain] && error.code == kNoteEntitlementErrorCode) {
+        return YES;
+    }
+    if ([error.domain isEqualToString:CNErrorDomain]) {
+        NSArray *keyPaths = error.userInfo[CNErrorUserInfoKeyPathsKey];
+        return [keyPaths isKindOfClass:[NSArray class]] && [keyPaths containsObject:CNContactNoteKey];
+    }
+    return NO;
+}
+
+// cstring_from_save_error tags note entitlement failures, on save or on a
+// fetch that includes the note, so the Go layer can fall back to writing the
+// note through AppleScript, or report ErrNoteEntitlementMissing.
+static BridgeString cstring_from_save_error(NSError *error) {
+    if (is_note_entitlement_error(error)) {
+        return cstring_from_nsstring([NSString stringWithFormat:@"note entitlement error 134092: %@", error.localizedDescription]);
+    }
+    return cstring_from_error(error);
+}
+
 static void free_cstring(BridgeString *cs) {
     if (cs->str != NULL) {
         free((void *)cs->str);
@@ -56,7 +89,7 @@ static void free_cstring(BridgeString *cs) {
 static CLabeledString convert_labeled_string(CNLabeledValue<NSString *> *lv) {
     CLabeledString cls;
     cls.identifier = cstring_from_nsstring(lv.identifier);
-    cls.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    cls.label = cstring_from_nsstring(lv.label);
     cls.value = cstring_from_nsstring(lv.value);
     return cls;
 }
@@ -64,7 +97,7 @@ static CLabeledString convert_labeled_string(CNLabeledValue<NSString *> *lv) {
 static CLabeledPostalAddress convert_labeled_postal(CNLabeledValue<CNPostalAddress *> *lv) {
     CLabeledPostalAddress cla;
     cla.identifier = cstring_from_nsstring(lv.identifier);
-    cla.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    cla.label = cstring_from_nsstring(lv.label);
     CNPostalAddress *addr = lv.value;
     cla.value.street = cstring_from_nsstring(addr.street);
     cla.value.city = cstring_from_nsstring(addr.city);
@@ -78,7 +111,7 @@ static CLabeledPostalAddress convert_labeled_postal(CNLabeledValue<CNPostalAddre
 static CLabeledContactRelation convert_labeled_relation(CNLabeledValue<CNContactRelation *> *lv) {
     CLabeledContactRelation clr;
     clr.identifier = cstring_from_nsstring(lv.identifier);
-    clr.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    clr.label = cstring_from_nsstring(lv.label);
     clr.value.name = cstring_from_nsstring(lv.value.name);
     return clr;
 }
@@ -86,7 +119,7 @@ static CLabeledContactRelation convert_labeled_relation(CNLabeledValue<CNContact
 static CLabeledSocialProfile convert_labeled_social(CNLabeledValue<CNSocialProfile *> *lv) {
     CLabeledSocialProfile cls;
     cls.identifier = cstring_from_nsstring(lv.identifier);
-    cls.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    cls.label = cstring_from_nsstring(lv.label);
     CNSocialProfile *sp = lv.value;
     cls.value.urlString = cstring_from_nsstring(sp.urlString);
     cls.value.username = cstring_from_nsstring(sp.username);
@@ -97,7 +130,7 @@ static CLabeledSocialProfile convert_labeled_social(CNLabeledValue<CNSocialProfi
 static CLabeledInstantMessage convert_labeled_im(CNLabeledValue<CNInstantMessageAddress *> *lv) {
     CLabeledInstantMessage cli;
     cli.identifier = cstring_from_nsstring(lv.identifier);
-    cli.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    cli.label = cstring_from_nsstring(lv.label);
     CNInstantMessageAddress *im = lv.value;
     cli.value.instantUsername = cstring_from_nsstring(im.username);
     cli.value.instantService = cstring_from_nsstring(im.service);
@@ -115,11 +148,40 @@ static CDateComponents convert_date_components(NSDateComponents *dc) {
 static CLabeledDateComponents convert_labeled_date(CNLabeledValue<NSDateComponents *> *lv) {
     CLabeledDateComponents cld;
     cld.identifier = cstring_from_nsstring(lv.identifier);
-    cld.label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+    cld.label = cstring_from_nsstring(lv.label);
     cld.value = convert_date_components(lv.value);
     return cld;
 }
 
+// relation_label_from_string maps friendly relation names to the system
+// CNLabelContactRelation* constants so Contacts.app shows them as built-in
+// relationships. Unknown labels are stored as custom labels.
+static NSString *relation_label_from_string(NSString *label) {
+    if (label.length == 0) {
+        return nil;
+    }
+    NSDictionary<NSString *, NSString *> *known = @{
+        @"spouse": CNLabelContactRelationSpouse,
+        @"wife": CNLabelContactRelationWife,
+        @"husband": CNLabelContactRelationHusband,
+        @"partner": CNLabelContactRelationPartner,
+        @"assistant": CNLabelContactRelationAssistant,
+        @"manager": CNLabelContactRelationManager,
+        @"father": CNLabelContactRelationFather,
+        @"mother": CNLabelContactRelationMother,
+        @"parent": CNLabelContactRelationParent,
+        @"brother": CNLabelContactRelationBrother,
+        @"sister": CNLabelContactRelationSister,
+        @"sibling": CNLabelContactRelationSibling,
+        @"child": CNLabelContactRelationChild,
+        @"son": CNLabelContactRelationSon,
+        @"daughter": CNLabelContactRelationDaughter,
+        @"friend": CNLabelContactRelationFriend,
+    };
+    NSString *mapped = known[label.lowercaseString];
+    return mapped != nil ? mapped : label;
+}
+
 static NSArray<id<CNKeyDescriptor>> *allContactKeys(void) {
     return @[
         CNContactIdentifierKey,
@@ -152,6 +214,19 @@ static NSArray<id<CNKeyDescriptor>> *allContactKeys(void) {
     ];
 }
 
+// listContactKeys returns the keys a listing fetches. A "text" filter searches
+// notes, so it adds CNContactNoteKey; without the notes entitlement the fetch
+// then fails with a note entitlement error instead of silently skipping notes.
+static NSArray<id<CNKeyDescriptor>> *listContactKeys(CFilter *filters, int filterCount) {
+    NSArray<id<CNKeyDescriptor>> *keys = allContactKeys();
+    for (int i = 0; i < filterCount; i++) {
+        if ([nsstring_from_cstring(filters[i].fieldName) isEqualToString:@"text"]) {
+            return [keys arrayByAddingObject:CNContactNoteKey];
+        }
+    }
+    return keys;
+}
+
 static BOOL parse_bool_filter_value(NSString *value, BOOL *parsed) {
     if (value == nil || parsed == NULL) {
         return NO;
@@ -247,6 +322,12 @@ static NSArray<NSString *> *container_ids_for_contact_ids(CNContactStore *store,
     return containerIDs;
 }
 
+static BOOL bridge_cancelled(int *cancel) {
+    return cancel != NULL && __atomic_load_n(cancel, __ATOMIC_RELAXED) != 0;
+}
+
+static NSString *const kBridgeCancelledError = @"operation cancelled";
+
 static CNContact *fetch_contact_by_identifier(CNContactStore *store, NSString *identifier, NSArray<id<CNKeyDescriptor>> *keysToFetch, BOOL unifyResults, NSError **error) {
     if (identifier == nil || identifier.length == 0) {
         return nil;
@@ -348,7 +429,7 @@ static CContact convert_contact(CNContactStore *store, CNContact *contact, NSErr
             for (int i = 0; i < cc.phoneNumbersCount; i++) {
                 CNLabeledValue<CNPhoneNumber *> *lv = phones[i];
                 cc.phoneNumbers[i].identifier = cstring_from_nsstring(lv.identifier);
-                cc.phoneNumbers[i].label = cstring_from_nsstring(lv.label ? [CNLabeledValue localizedStringForLabel:lv.label] : nil);
+                cc.phoneNumbers[i].label = cstring_from_nsstring(lv.label);
                 cc.phoneNumbers[i].value = cstring_from_nsstring(lv.value.stringValue);
             }
         }
@@ -580,7 +661,7 @@ static void apply_input_to_mutable(CNMutableContact *mc, CContact input) {
             NSString *label = nsstring_from_cstring(input.contactRelations[i].label);
             NSString *name = nsstring_from_cstring(input.contactRelations[i].value.name);
             CNContactRelation *rel = [CNContactRelation contactRelationWithName:name];
-            [rels addObject:[CNLabeledValue labeledValueWithLabel:(label.length > 0 ? label : nil) value:rel]];
+            [rels addObject:[CNLabeledValue labeledValueWithLabel:relation_label_from_string(label) value:rel]];
         }
         mc.contactRelations = rels;
     }
@@ -741,7 +822,7 @@ static void apply_full_input_to_mutable(CNMutableContact *mc, CContact input) {
             NSString *label = nsstring_from_cstring(input.contactRelations[i].label);
             NSString *name = nsstring_from_cstring(input.contactRelations[i].value.name);
             CNContactRelation *rel = [CNContactRelation contactRelationWithName:name];
-            [rels addObject:[CNLabeledValue labeledValueWithLabel:(label.length > 0 ? label : nil) value:rel]];
+            [rels addObject:[CNLabeledValue labeledValueWithLabel:relation_label_from_string(label) value:rel]];
         }
         mc.contactRelations = rels;
     } else {
@@ -816,6 +897,124 @@ static BOOL string_matches_filter(NSString *fieldValue, NSString *filterValue, i
     }
 }
 
+// Phone matching calls back into Go (phone.go) so that filters, patches, and
+// the Go API share one implementation of NormalizePhoneNumber and
+// PhoneNumbersMatch.
+static BOOL phone_numbers_match(NSString *a, NSString *b) {
+    return goPhoneNumbersMatch((char *)(a ?: @"").UTF8String, (char *)(b ?: @"").UTF8String) == 1;
+}
+
+// phone_matches_filter compares digits rather than formatted strings. Filter
+// values without any digits fall back to plain string matching.
+static BOOL phone_matches_filter(NSString *fieldValue, NSString *filterValue, int op) {
+    int match = goPhoneMatchesFilter((char *)(fieldValue ?: @"").UTF8String, (char *)(filterValue ?: @"").UTF8String, op);
+    if (match < 0) {
+        return string_matches_filter(fieldValue, filterValue, op);
+    }
+    return match == 1;
+}
+
+// contact_text_values collects the searchable text of a contact for the
+// "text" filters. The note is included when it was fetched, which only the
+// "text" filter does.
+static NSArray<NSString *> *contact_text_values(CNContact *contact) {
+    NSMutableArray<NSString *> *values = [NSMutableArray array];
+    void (^add)(NSString *) = ^(NSString *v) {
+        if (v.length > 0) [values addObject:v];
+    };
+    add(contact.namePrefix);
+    add(contact.givenName);
+    add(contact.middleName);
+    add(contact.familyName);
+    add(contact.nameSuffix);
+    add(contact.nickname);
+    add(contact.previousFamilyName);
+    add(contact.phoneticGivenName);
+    add(contact.phoneticMiddleName);
+    add(contact.phoneticFamilyName);
+    add(contact.organizationName);
+    add(contact.departmentName);
+    add(contact.jobTitle);
+    if ([contact isKeyAvailable:CNContactNoteKey]) {
+        add(contact.note);
+    }
+    if (contact.givenName.length > 0 && contact.familyName.length > 0) {
+        add([NSString stringWithFormat:@"%@ %@", contact.givenName, contact.familyName]);
+    }
+    for (CNLabeledValue<NSString *> *lv in contact.emailAddresses) {
+        add(lv.value);
+    }
+    return values;
+}
+
+static BOOL text_token_matches(CNContact *contact, NSArray<NSString *> *values, NSString *token) {
+    for (NSString *v in values) {
+        if (string_matches_filter(v, token, 1)) return YES;
+    }
+    for (CNLabeledValue<CNPhoneNumber *> *lv in contact.phoneNumbers) {
+        if (phone_matches_filter(lv.value.stringValue, token, 1)) return YES;
+    }
+    return NO;
+}
+
+// text_matches_filter implements the "text" pseudo-field. Contains requires
+// every whitespace-separated token to appear in some field; equals requires a
+// single field (or "given family") to equal the whole value.
+static BOOL text_matches_filter(CNContact *contact, NSString *filterValue, int op) {
+    NSArray<NSString *> *values = contact_text_values(contact);
+    if (op == 0) {
+        for (NSString *v in values) {
+            if (string_matches_filter(v, filterValue, 0)) return YES;
+        }
+        for (CNLabeledValue<CNPhoneNumber *> *lv in contact.phoneNumbers) {
+            if (phone_matches_filter(lv.value.stringValue, filterValue, 0)) return YES;
+        }
+        return NO;
+    }
+    NSArray<NSString *> *tokens = [filterValue componentsSeparatedByCharactersInSet:[NSCharacterSet whitespaceCharacterSet]];
+    BOOL all = YES;
+    for (NSString *token in tokens) {
+        if (token.length == 0) continue;
+        if (!text_token_matches(contact, values, token)) {
+            all = NO;
+            break;
+        }
+    }
+    return op == 2 ? !all : all;
+}
+
+static char kGroupMemberCacheKey;
+
+// group_member_ids returns the identifiers of the group's direct members. The
+// result is cached on the store, which lives for one listing call, so a
+// groupID filter costs one group fetch rather than one per contact.
+static NSSet<NSString *> *group_member_ids(CNContactStore *store, NSString *groupID, NSError **error) {
+    NSMutableDictionary<NSString *, NSSet<NSString *> *> *cache = objc_getAssociatedObject(store, &kGroupMemberCacheKey);
+    if (cache == nil) {
+        cache = [NSMutableDictionary dictionary];
+        objc_setAssociatedObject(store, &kGroupMemberCacheKey, cache, OBJC_ASSOCIATION_RETAIN_NONATOMIC);
+    }
+    NSSet<NSString *> *cached = cache[groupID];
+    if (cached != nil) {
+        return cached;
+    }
+
+    CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:@[CNContactIdentifierKey]];
+    request.unifyResults = NO;
+    request.predicate = [CNContact predicateForContactsInGroupWithIdentifier:groupID];
+    NSMutableSet<NSString *> *ids = [NSMutableSet set];
+    BOOL success = [store enumerateContactsWithFetchRequest:request error:error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+        if (contact.identifier != nil) {
+            [ids addObject:contact.identifier];
+        }
+    }];
+    if (!success) {
+        return nil;
+    }
+    cache[groupID] = ids;
+    return ids;
+}
+
 static BOOL contact_matches_filter(CNContactStore *store, CNContact *contact, CFilter filter, BOOL unifyResults, NSError **error) {
     NSString *fieldName = nsstring_from_cstring(filter.fieldName);
     NSString *filterValue = nsstring_from_cstring(filter.value);
@@ -860,6 +1059,47 @@ static BOOL contact_matches_filter(CNContactStore *store, CNContact *contact, CF
         return [containerID isEqualToString:filterValue];
     }
 
+    if ([fieldName isEqualToString:@"groupID"]) {
+        if (op != 0 && op != 2) {
+            return NO;
+        }
+        // Several group identifiers are newline-separated; membership in any
+        // of them counts.
+        NSMutableArray<NSSet<NSString *> *> *memberSets = [NSMutableArray array];
+        for (NSString *groupID in [filterValue componentsSeparatedByString:@"\n"]) {
+            NSSet<NSString *> *members = group_member_ids(store, groupID, error);
+            if (members == nil || (error != NULL && *error != nil)) {
+                return NO;
+            }
+            [memberSets addObject:members];
+        }
+        NSArray<NSString *> *candidateIDs = @[contact.identifier];
+        if (unifyResults) {
+            NSArray<NSString *> *linkedIDs = linked_contact_identifiers(store, contact.identifier, error);
+            if (error != NULL && *error != nil) {
+                return NO;
+            }
+            candidateIDs = [candidateIDs arrayByAddingObjectsFromArray:linkedIDs];
+        }
+        BOOL member = NO;
+        for (NSSet<NSString *> *members in memberSets) {
+            for (NSString *candidateID in candidateIDs) {
+                if ([members containsObject:candidateID]) {
+                    member = YES;
+                    break;
+                }
+            }
+            if (member) {
+                break;
+            }
+        }
+        return op == 0 ? member : !member;
+    }
+
+    if ([fieldName isEqualToString:@"text"] || [fieldName isEqualToString:@"textExcludingNotes"]) {
+        return text_matches_filter(contact, filterValue, op);
+    }
+
     // Single-value string fields
     if ([fieldName isEqualToString:@"givenName"] && [contact isKeyAvailable:CNContactGivenNameKey]) {
         return string_matches_filter(contact.givenName, filterValue, op);
@@ -888,6 +1128,18 @@ static BOOL contact_matches_filter(CNContactStore *store, CNContact *contact, CF
     if ([fieldName isEqualToString:@"nameSuffix"] && [contact isKeyAvailable:CNContactNameSuffixKey]) {
         return string_matches_filter(contact.nameSuffix, filterValue, op);
     }
+    if ([fieldName isEqualToString:@"previousFamilyName"] && [contact isKeyAvailable:CNContactPreviousFamilyNameKey]) {
+        return string_matches_filter(contact.previousFamilyName, filterValue, op);
+    }
+    if ([fieldName isEqualToString:@"phoneticGivenName"] && [contact isKeyAvailable:CNContactPhoneticGivenNameKey]) {
+        return string_matches_filter(contact.phoneticGivenName, filterValue, op);
+    }
+    if ([fieldName isEqualToString:@"phoneticMiddleName"] && [contact isKeyAvailable:CNContactPhoneticMiddleNameKey]) {
+        return string_matches_filter(contact.phoneticMiddleName, filterValue, op);
+    }
+    if ([fieldName isEqualToString:@"phoneticFamilyName"] && [contact isKeyAvailable:CNContactPhoneticFamilyNameKey]) {
+        return string_matches_filter(contact.phoneticFamilyName, filterValue, op);
+    }
     if ([fieldName isEqualToString:@"note"] && [contact isKeyAvailable:CNContactNoteKey]) {
         return string_matches_filter(contact.note, filterValue, op);
     }
@@ -908,11 +1160,11 @@ static BOOL contact_matches_filter(CNContactStore *store, CNContact *contact, CF
     }
     if ([fieldName isEqualToString:@"phoneNumbers"] && [contact isKeyAvailable:CNContactPhoneNumbersKey]) {
         for (CNLabeledValue<CNPhoneNumber *> *lv in contact.phoneNumbers) {
-            if (string_matches_filter(lv.value.stringValue, filterValue, op)) return YES;
+            if (phone_matches_filter(lv.value.stringValue, filterValue, op)) return YES;
         }
         if (op == 2) {
             for (CNLabeledValue<CNPhoneNumber *> *lv in contact.phoneNumbers) {
-                if (!string_matches_filter(lv.value.stringValue, filterValue, op)) return NO;
+                if (!phone_matches_filter(lv.value.stringValue, filterValue, op)) return NO;
             }
             return YES;
         }
@@ -996,6 +1248,33 @@ CContactResult bridge_get_contact(BridgeString identifier, int unifyResults) {
     return result;
 }
 
+CContactResult bridge_get_me_contact(void) {
+    CContactResult result;
+    memset(&result, 0, sizeof(CContactResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        NSError *error = nil;
+        CNContact *me = [store unifiedMeContactWithKeysToFetch:allContactKeys() error:&error];
+        if (me == nil) {
+            if (error == nil || ([error.domain isEqualToString:CNErrorDomain] && error.code == CNErrorCodeRecordDoesNotExist)) {
+                result.error = cstring_from_nsstring(@"me contact not found");
+            } else {
+                result.error = cstring_from_error(error);
+            }
+            return result;
+        }
+        result.contact = convert_contact(store, me, &error, YES);
+        if (error != nil) {
+            result.error = cstring_from_error(error);
+            bridge_free_contact(&result.contact);
+            memset(&result.contact, 0, sizeof(CContact));
+            return result;
+        }
+    }
+    return result;
+}
+
 CContactIdentityResult bridge_resolve_contact_identity(BridgeString identifier) {
     CContactIdentityResult result;
     memset(&result, 0, sizeof(CContactIdentityResult));
@@ -1059,7 +1338,33 @@ CContactIdentityResult bridge_resolve_contact_identity(BridgeString identifier)
     return result;
 }
 
-CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
+// unify_results_from_filters reads the "unified" pseudo-filter, which selects
+// unified projections (default) or constituent records. It returns an error
+// message for malformed or conflicting unified filters.
+static NSString *unify_results_from_filters(CFilter *filters, int filterCount, BOOL *unifyResults) {
+    BOOL sawUnifiedFilter = NO;
+    for (int i = 0; i < filterCount; i++) {
+        NSString *fieldName = nsstring_from_cstring(filters[i].fieldName);
+        if (![fieldName isEqualToString:@"unified"]) {
+            continue;
+        }
+        if (filters[i].op != 0) {
+            return @"unified filter only supports equals";
+        }
+        BOOL parsed = YES;
+        if (!parse_bool_filter_value(nsstring_from_cstring(filters[i].value), &parsed)) {
+            return @"unified filter requires bool value";
+        }
+        if (sawUnifiedFilter && parsed != *unifyResults) {
+            return @"conflicting unified filters";
+        }
+        sawUnifiedFilter = YES;
+        *unifyResults = parsed;
+    }
+    return nil;
+}
+
+CContactListResult bridge_list_contacts(CFilter *filters, int filterCount, int *cancel) {
     CContactListResult result;
     memset(&result, 0, sizeof(CContactListResult));
 
@@ -1067,30 +1372,13 @@ CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
         CNContactStore *store = [[CNContactStore alloc] init];
 
         BOOL unifyResults = YES;
-        BOOL sawUnifiedFilter = NO;
-        for (int i = 0; i < filterCount; i++) {
-            NSString *fieldName = nsstring_from_cstring(filters[i].fieldName);
-            if (![fieldName isEqualToString:@"unified"]) {
-                continue;
-            }
-            if (filters[i].op != 0) {
-                result.error = cstring_from_nsstring(@"unified filter only supports equals");
-                return result;
-            }
-            BOOL parsed = YES;
-            if (!parse_bool_filter_value(nsstring_from_cstring(filters[i].value), &parsed)) {
-                result.error = cstring_from_nsstring(@"unified filter requires bool value");
-                return result;
-            }
-            if (sawUnifiedFilter && parsed != unifyResults) {
-                result.error = cstring_from_nsstring(@"conflicting unified filters");
-                return result;
-            }
-            sawUnifiedFilter = YES;
-            unifyResults = parsed;
+        NSString *unifiedError = unify_results_from_filters(filters, filterCount, &unifyResults);
+        if (unifiedError != nil) {
+            result.error = cstring_from_nsstring(unifiedError);
+            return result;
         }
 
-        CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:allContactKeys()];
+        CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:listContactKeys(filters, filterCount)];
         request.sortOrder = CNContactSortOrderGivenName;
         request.unifyResults = unifyResults;
 
@@ -1098,7 +1386,13 @@ CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
         NSError *error = nil;
         __block NSError *filterError = nil;
 
+        __block BOOL cancelled = NO;
         BOOL success = [store enumerateContactsWithFetchRequest:request error:&error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+            if (bridge_cancelled(cancel)) {
+                cancelled = YES;
+                *stop = YES;
+                return;
+            }
             if (filterCount == 0 || contact_matches_all_filters(store, contact, filters, filterCount, unifyResults, &filterError)) {
                 if (filterError == nil) {
                     [matched addObject:contact];
@@ -1109,12 +1403,16 @@ CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
             }
         }];
 
+        if (cancelled) {
+            result.error = cstring_from_nsstring(kBridgeCancelledError);
+            return result;
+        }
         if (filterError != nil) {
             result.error = cstring_from_error(filterError);
             return result;
         }
         if (!success || error != nil) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
 
@@ -1122,6 +1420,13 @@ CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
         if (result.count > 0) {
             result.contacts = (CContact *)malloc(sizeof(CContact) * result.count);
             for (int i = 0; i < result.count; i++) {
+                if (bridge_cancelled(cancel)) {
+                    result.error = cstring_from_nsstring(kBridgeCancelledError);
+                    bridge_free_contact_list(result.contacts, i);
+                    result.contacts = NULL;
+                    result.count = 0;
+                    return result;
+                }
                 result.contacts[i] = convert_contact(store, matched[i], &error, unifyResults);
                 if (error != nil) {
                     result.error = cstring_from_error(error);
@@ -1139,6 +1444,74 @@ CContactListResult bridge_list_contacts(CFilter *filters, int filterCount) {
     return result;
 }
 
+CSimpleResult bridge_stream_contacts(CFilter *filters, int filterCount, int *cancel, uintptr_t handle) {
+    CSimpleResult result;
+    memset(&result, 0, sizeof(CSimpleResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+
+        BOOL unifyResults = YES;
+        NSString *unifiedError = unify_results_from_filters(filters, filterCount, &unifyResults);
+        if (unifiedError != nil) {
+            result.error = cstring_from_nsstring(unifiedError);
+            return result;
+        }
+
+        CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:listContactKeys(filters, filterCount)];
+        request.sortOrder = CNContactSortOrderGivenName;
+        request.unifyResults = unifyResults;
+
+        NSError *error = nil;
+        __block NSError *blockError = nil;
+        __block BOOL cancelled = NO;
+        BOOL success = [store enumerateContactsWithFetchRequest:request error:&error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+            @autoreleasepool {
+                if (bridge_cancelled(cancel)) {
+                    cancelled = YES;
+                    *stop = YES;
+                    return;
+                }
+                if (filterCount > 0 && !contact_matches_all_filters(store, contact, filters, filterCount, unifyResults, &blockError)) {
+                    if (blockError != nil) *stop = YES;
+                    return;
+                }
+                if (blockError != nil) {
+                    *stop = YES;
+                    return;
+                }
+                NSError *convertError = nil;
+                CContact cc = convert_contact(store, contact, &convertError, unifyResults);
+                if (convertError != nil) {
+                    bridge_free_contact(&cc);
+                    blockError = convertError;
+                    *stop = YES;
+                    return;
+                }
+                int keepGoing = goStreamContact(handle, &cc);
+                bridge_free_contact(&cc);
+                if (!keepGoing) {
+                    *stop = YES;
+                }
+            }
+        }];
+
+        if (cancelled) {
+            result.error = cstring_from_nsstring(kBridgeCancelledError);
+            return result;
+        }
+        if (blockError != nil) {
+            result.error = cstring_from_error(blockError);
+            return result;
+        }
+        if (!success || error != nil) {
+            result.error = cstring_from_save_error(error);
+            return result;
+        }
+    }
+    return result;
+}
+
 CCreateResult bridge_create_contact(CContact input, BridgeString containerID) {
     CCreateResult result;
     memset(&result, 0, sizeof(CCreateResult));
@@ -1154,7 +1527,7 @@ CCreateResult bridge_create_contact(CContact input, BridgeString containerID) {
 
         NSError *error = nil;
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
         result.identifier = cstring_from_nsstring(mc.identifier);
@@ -1162,37 +1535,184 @@ CCreateResult bridge_create_contact(CContact input, BridgeString containerID) {
     return result;
 }
 
-CSimpleResult bridge_update_contact(CContact input) {
+// bridge_create_contacts saves all inputs in one CNSaveRequest. The request is
+// atomic: on failure nothing is saved and only the error is returned.
+CBatchCreateResult bridge_create_contacts(CContact *inputs, BridgeString *containerIDs, int count) {
+    CBatchCreateResult result;
+    memset(&result, 0, sizeof(CBatchCreateResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        CNSaveRequest *saveRequest = [[CNSaveRequest alloc] init];
+        NSMutableArray<CNMutableContact *> *created = [NSMutableArray arrayWithCapacity:count];
+        for (int i = 0; i < count; i++) {
+            CNMutableContact *mc = [[CNMutableContact alloc] init];
+            apply_input_to_mutable(mc, inputs[i]);
+            NSString *cid = nsstring_from_cstring(containerIDs[i]);
+            [saveRequest addContact:mc toContainerWithIdentifier:(cid.length > 0 ? cid : nil)];
+            [created addObject:mc];
+        }
+
+        NSError *error = nil;
+        if (![store executeSaveRequest:saveRequest error:&error]) {
+            result.error = cstring_from_save_error(error);
+            return result;
+        }
+
+        result.count = count;
+        if (count > 0) {
+            result.identifiers = (BridgeString *)malloc(sizeof(BridgeString) * count);
+            for (int i = 0; i < count; i++) {
+                result.identifiers[i] = cstring_from_nsstring(created[i].identifier);
+            }
+        }
+    }
+    return result;
+}
+
+static NSString *phone_label_from_string(NSString *label) {
+    if ([label isEqualToString:@"home"] || [label isEqualToString:@"Home"]) return CNLabelHome;
+    if ([label isEqualToString:@"work"] || [label isEqualToString:@"Work"]) return CNLabelWork;
+    if ([label isEqualToString:@"mobile"] || [label isEqualToString:@"Mobile"]) return CNLabelPhoneNumberMobile;
+    if ([label isEqualToString:@"main"] || [label isEqualToString:@"Main"]) return CNLabelPhoneNumberMain;
+    if ([label isEqualToString:@"iPhone"]) return CNLabelPhoneNumberiPhone;
+    return label.length > 0 ? label : nil;
+}
+
+static NSString *email_label_from_string(NSString *label) {
+    if ([label isEqualToString:@"home"] || [label isEqualToString:@"Home"]) return CNLabelHome;
+    if ([label isEqualToString:@"work"] || [label isEqualToString:@"Work"]) return CNLabelWork;
+    return label.length > 0 ? label : nil;
+}
+
+static BOOL email_values_match(NSString *a, NSString *b) {
+    NSCharacterSet *ws = [NSCharacterSet whitespaceAndNewlineCharacterSet];
+    return [[a stringByTrimmingCharactersInSet:ws] caseInsensitiveCompare:[b stringByTrimmingCharactersInSet:ws]] == NSOrderedSame;
+}
+
+// apply_multi_value_patch rebuilds emails and phones from the freshly fetched
+// contact so list operations never clobber values written since the caller
+// last read the contact.
+static void apply_multi_value_patch(CNMutableContact *mc, CNContact *current, CMultiValuePatch patch) {
+    if (patch.addEmailsCount > 0 || patch.removeEmailsCount > 0) {
+        NSMutableArray<CNLabeledValue<NSString *> *> *emails = [NSMutableArray array];
+        for (CNLabeledValue<NSString *> *lv in current.emailAddresses) {
+            BOOL removed = NO;
+            for (int i = 0; i < patch.removeEmailsCount && !removed; i++) {
+                removed = email_values_match(lv.value, nsstring_from_cstring(patch.removeEmails[i]));
+            }
+            if (!removed) [emails addObject:lv];
+        }
+        for (int i = 0; i < patch.addEmailsCount; i++) {
+            NSString *value = nsstring_from_cstring(patch.addEmails[i].value);
+            BOOL exists = NO;
+            for (CNLabeledValue<NSString *> *lv in emails) {
+                if (email_values_match(lv.value, value)) {
+                    exists = YES;
+                    break;
+                }
+            }
+            if (exists) continue;
+            NSString *label = email_label_from_string(nsstring_from_cstring(patch.addEmails[i].label));
+            [emails addObject:[CNLabeledValue labeledValueWithLabel:label value:value]];
+        }
+        mc.emailAddresses = emails;
+    }
+
+    if (patch.addPhonesCount > 0 || patch.removePhonesCount > 0) {
+        NSMutableArray<CNLabeledValue<CNPhoneNumber *> *> *phones = [NSMutableArray array];
+        for (CNLabeledValue<CNPhoneNumber *> *lv in current.phoneNumbers) {
+            BOOL removed = NO;
+            for (int i = 0; i < patch.removePhonesCount && !removed; i++) {
+                removed = phone_numbers_match(lv.value.stringValue, nsstring_from_cstring(patch.removePhones[i]));
+            }
+            if (!removed) [phones addObject:lv];
+        }
+        for (int i = 0; i < patch.addPhonesCount; i++) {
+            NSString *value = nsstring_from_cstring(patch.addPhones[i].value);
+            BOOL exists = NO;
+            for (CNLabeledValue<CNPhoneNumber *> *lv in phones) {
+                if (phone_numbers_match(lv.value.stringValue, value)) {
+                    exists = YES;
+                    break;
+                }
+            }
+            if (exists) continue;
+            NSString *label = phone_label_from_string(nsstring_from_cstring(patch.addPhones[i].label));
+            [phones addObject:[CNLabeledValue labeledValueWithLabel:label value:[CNPhoneNumber phoneNumberWithStringValue:value]]];
+        }
+        mc.phoneNumbers = phones;
+    }
+}
+
+// updated_mutable_contact fetches the stored contact named by input and returns
+// a mutable copy with input and patch applied. On failure it returns nil and
+// sets *errorOut.
+static CNMutableContact *updated_mutable_contact(CNContactStore *store, CContact input, CMultiValuePatch patch, BridgeString *errorOut) {
+    NSString *ident = nsstring_from_cstring(input.identifier);
+    if (ident.length == 0) {
+        *errorOut = cstring_from_nsstring(@"identifier is required");
+        return nil;
+    }
+
+    NSError *error = nil;
+    CNContact *contact = fetch_contact_by_identifier(store, ident, allContactKeys(), NO, &error);
+    if (error != nil) {
+        *errorOut = cstring_from_error(error);
+        return nil;
+    }
+    if (contact == nil) {
+        *errorOut = cstring_from_nsstring([NSString stringWithFormat:@"contact %@ not found", ident]);
+        return nil;
+    }
+
+    CNMutableContact *mc = [contact mutableCopy];
+    apply_full_input_to_mutable(mc, input);
+    apply_multi_value_patch(mc, contact, patch);
+    return mc;
+}
+
+CSimpleResult bridge_update_contact(CContact input, CMultiValuePatch patch) {
     CSimpleResult result;
     memset(&result, 0, sizeof(CSimpleResult));
 
     @autoreleasepool {
         CNContactStore *store = [[CNContactStore alloc] init];
-        NSString *ident = nsstring_from_cstring(input.identifier);
-        if (ident.length == 0) {
-            result.error = cstring_from_nsstring(@"identifier is required");
+        CNMutableContact *mc = updated_mutable_contact(store, input, patch, &result.error);
+        if (mc == nil) {
             return result;
         }
 
+        CNSaveRequest *saveRequest = [[CNSaveRequest alloc] init];
+        [saveRequest updateContact:mc];
+
         NSError *error = nil;
-        CNContact *contact = fetch_contact_by_identifier(store, ident, allContactKeys(), NO, &error);
-        if (error != nil) {
-            result.error = cstring_from_error(error);
-            return result;
-        }
-        if (contact == nil) {
-            result.error = cstring_from_nsstring([NSString stringWithFormat:@"contact %@ not found", ident]);
+        if (![store executeSaveRequest:saveRequest error:&error]) {
+            result.error = cstring_from_save_error(error);
             return result;
         }
+    }
+    return result;
+}
 
-        CNMutableContact *mc = [contact mutableCopy];
-        apply_full_input_to_mutable(mc, input);
+CSimpleResult bridge_update_contacts(CContact *inputs, CMultiValuePatch *patches, int count) {
+    CSimpleResult result;
+    memset(&result, 0, sizeof(CSimpleResult));
 
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
         CNSaveRequest *saveRequest = [[CNSaveRequest alloc] init];
-        [saveRequest updateContact:mc];
+        for (int i = 0; i < count; i++) {
+            CNMutableContact *mc = updated_mutable_contact(store, inputs[i], patches[i], &result.error);
+            if (mc == nil) {
+                return result;
+            }
+            [saveRequest updateContact:mc];
+        }
 
+        NSError *error = nil;
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1225,7 +1745,7 @@ CSimpleResult bridge_delete_contact(BridgeString identifier) {
         [saveRequest deleteContact:mc];
 
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1356,7 +1876,7 @@ CCreateResult bridge_create_group(BridgeString name, BridgeString containerID, B
 
         NSError *error = nil;
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
         result.identifier = cstring_from_nsstring(mg.identifier);
@@ -1458,7 +1978,7 @@ CSimpleResult bridge_update_group(BridgeString identifier, BridgeString name, in
         }
 
         if (hasMutations && ![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1497,7 +2017,7 @@ CSimpleResult bridge_delete_group(BridgeString identifier) {
         [saveRequest deleteGroup:mg];
 
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1563,7 +2083,7 @@ CSimpleResult bridge_add_contact_to_group(BridgeString contactID, BridgeString g
         [saveRequest addMember:contact toGroup:targetGroup];
 
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1629,7 +2149,7 @@ CSimpleResult bridge_remove_contact_from_group(BridgeString contactID, BridgeStr
         [saveRequest removeMember:contact fromGroup:targetGroup];
 
         if (![store executeSaveRequest:saveRequest error:&error]) {
-            result.error = cstring_from_error(error);
+            result.error = cstring_from_save_error(error);
             return result;
         }
     }
@@ -1707,7 +2227,7 @@ CDefaultContainerResult bridge_default_container_id(void) {
     return result;
 }
 
-CContactListResult bridge_list_contacts_in_group(BridgeString groupID) {
+CContactListResult bridge_list_contacts_in_group(BridgeString groupID, int *cancel) {
     CContactListResult result;
     memset(&result, 0, sizeof(CContactListResult));
 
@@ -1722,9 +2242,19 @@ CContactListResult bridge_list_contacts_in_group(BridgeString groupID) {
         request.predicate = [CNContact predicateForContactsInGroupWithIdentifier:gid];
 
         NSMutableArray<CNContact *> *contacts = [NSMutableArray array];
+        __block BOOL cancelled = NO;
         BOOL success = [store enumerateContactsWithFetchRequest:request error:&error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+            if (bridge_cancelled(cancel)) {
+                cancelled = YES;
+                *stop = YES;
+                return;
+            }
             [contacts addObject:contact];
         }];
+        if (cancelled) {
+            result.error = cstring_from_nsstring(kBridgeCancelledError);
+            return result;
+        }
         if (!success || error != nil) {
             result.error = cstring_from_error(error);
             return result;
@@ -1734,6 +2264,13 @@ CContactListResult bridge_list_contacts_in_group(BridgeString groupID) {
         if (result.count > 0) {
             result.contacts = (CContact *)malloc(sizeof(CContact) * result.count);
             for (int i = 0; i < result.count; i++) {
+                if (bridge_cancelled(cancel)) {
+                    result.error = cstring_from_nsstring(kBridgeCancelledError);
+                    bridge_free_contact_list(result.contacts, i);
+                    result.contacts = NULL;
+                    result.count = 0;
+                    return result;
+                }
                 result.contacts[i] = convert_contact(store, contacts[i], &error, NO);
                 if (error != nil) {
                     result.error = cstring_from_error(error);
@@ -1751,6 +2288,308 @@ CContactListResult bridge_list_contacts_in_group(BridgeString groupID) {
     return result;
 }
 
+// bridge_list_group_members fetches only identifiers and name keys, so large
+// groups can be counted and paged without converting full contacts.
+CGroupMembersResult bridge_list_group_members(BridgeString groupID, int *cancel) {
+    CGroupMembersResult result;
+    memset(&result, 0, sizeof(CGroupMembersResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        NSString *gid = nsstring_from_cstring(groupID);
+        NSError *error = nil;
+
+        NSArray *keys = @[
+            CNContactIdentifierKey,
+            [CNContactFormatter descriptorForRequiredKeysForStyle:CNContactFormatterStyleFullName],
+        ];
+        CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:keys];
+        request.unifyResults = NO;
+        request.sortOrder = CNContactSortOrderGivenName;
+        request.predicate = [CNContact predicateForContactsInGroupWithIdentifier:gid];
+
+        NSMutableArray<NSString *> *ids = [NSMutableArray array];
+        NSMutableArray<NSString *> *names = [NSMutableArray array];
+        __block BOOL cancelled = NO;
+        BOOL success = [store enumerateContactsWithFetchRequest:request error:&error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+            if (bridge_cancelled(cancel)) {
+                cancelled = YES;
+                *stop = YES;
+                return;
+            }
+            [ids addObject:contact.identifier ?: @""];
+            NSString *name = [CNContactFormatter stringFromContact:contact style:CNContactFormatterStyleFullName];
+            [names addObject:name ?: @""];
+        }];
+        if (cancelled) {
+            result.error = cstring_from_nsstring(kBridgeCancelledError);
+            return result;
+        }
+        if (!success || error != nil) {
+            result.error = cstring_from_error(error);
+            return result;
+        }
+
+        result.count = (int)ids.count;
+        if (result.count > 0) {
+            result.ids = (BridgeString *)malloc(sizeof(BridgeString) * result.count);
+            result.names = (BridgeString *)malloc(sizeof(BridgeString) * result.count);
+            for (int i = 0; i < result.count; i++) {
+                result.ids[i] = cstring_from_nsstring(ids[i]);
+                result.names[i] = cstring_from_nsstring(names[i]);
+            }
+        }
+    }
+    return result;
+}
+
+// count_contacts enumerates non-unified contacts matching predicate (nil for
+// all) with only the identifier key. It returns -1 on error or cancellation.
+static NSInteger count_contacts(CNContactStore *store, NSPredicate *predicate, int *cancel, BOOL *cancelled, NSError **error) {
+    CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:@[CNContactIdentifierKey]];
+    request.unifyResults = NO;
+    request.predicate = predicate;
+    __block NSInteger count = 0;
+    __block BOOL stopped = NO;
+    BOOL success = [store enumerateContactsWithFetchRequest:request error:error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+        if (bridge_cancelled(cancel)) {
+            stopped = YES;
+            *stop = YES;
+            return;
+        }
+        count++;
+    }];
+    if (stopped) {
+        *cancelled = YES;
+        return -1;
+    }
+    if (!success) {
+        return -1;
+    }
+    return count;
+}
+
+// bridge_contact_stats counts contacts in the store, per container, and per
+// group without converting any contact to C.
+CContactStatsResult bridge_contact_stats(int *cancel) {
+    CContactStatsResult result;
+    memset(&result, 0, sizeof(CContactStatsResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        NSError *error = nil;
+
+        CNContactFetchRequest *request = [[CNContactFetchRequest alloc] initWithKeysToFetch:@[
+            CNContactIdentifierKey,
+            CNContactEmailAddressesKey,
+            CNContactPhoneNumbersKey,
+        ]];
+        request.unifyResults = NO;
+        __block int total = 0, withEmail = 0, withPhone = 0, withEither = 0;
+        __block BOOL cancelled = NO;
+        BOOL success = [store enumerateContactsWithFetchRequest:request error:&error usingBlock:^(CNContact * _Nonnull contact, BOOL * _Nonnull stop) {
+            if (bridge_cancelled(cancel)) {
+                cancelled = YES;
+                *stop = YES;
+                return;
+            }
+            BOOL hasEmail = contact.emailAddresses.count > 0;
+            BOOL hasPhone = contact.phoneNumbers.count > 0;
+            total++;
+            if (hasEmail) withEmail++;
+            if (hasPhone) withPhone++;
+            if (hasEmail || hasPhone) withEither++;
+        }];
+        if (cancelled) {
+            result.error = cstring_from_nsstring(kBridgeCancelledError);
+            return result;
+        }
+        if (!success || error != nil) {
+            result.error = cstring_from_error(error);
+            return result;
+        }
+        result.total = total;
+        result.withEmail = withEmail;
+        result.withPhone = withPhone;
+        result.withEmailOrPhone = withEither;
+
+        NSArray<CNContainer *> *containers = [store containersMatchingPredicate:nil error:&error];
+        if (containers == nil || error != nil) {
+            result.error = cstring_from_error(error);
+            return result;
+        }
+        NSArray<CNGroup *> *groups = [store groupsMatchingPredicate:nil error:&error];
+        if (groups == nil || error != nil) {
+            result.error = cstring_from_error(error);
+            return result;
+        }
+
+        result.containerCount = (int)containers.count;
+        if (result.containerCount > 0) {
+            result.containerIDs = (BridgeString *)calloc(result.containerCount, sizeof(BridgeString));
+            result.containerCounts = (int *)calloc(result.containerCount, sizeof(int));
+        }
+        for (int i = 0; i < result.containerCount; i++) {
+            NSString *cid = containers[i].identifier;
+            NSInteger n = count_contacts(store, [CNContact predicateForContactsInContainerWithIdentifier:cid], cancel, &cancelled, &error);
+            if (n < 0) {
+                result.error = cancelled ? cstring_from_nsstring(kBridgeCancelledError) : cstring_from_error(error);
+                return result;
+            }
+            result.containerIDs[i] = cstring_from_nsstring(cid);
+            result.containerCounts[i] = (int)n;
+        }
+
+        result.groupCount = (int)groups.count;
+        if (result.groupCount > 0) {
+            result.groupIDs = (BridgeString *)calloc(result.groupCount, sizeof(BridgeString));
+            result.groupCounts = (int *)calloc(result.groupCount, sizeof(int));
+        }
+        for (int i = 0; i < result.groupCount; i++) {
+            NSString *gid = groups[i].identifier;
+            NSInteger n = count_contacts(store, [CNContact predicateForContactsInGroupWithIdentifier:gid], cancel, &cancelled, &error);
+            if (n < 0) {
+                result.error = cancelled ? cstring_from_nsstring(kBridgeCancelledError) : cstring_from_error(error);
+                return result;
+            }
+            result.groupIDs[i] = cstring_from_nsstring(gid);
+            result.groupCounts[i] = (int)n;
+        }
+    }
+    return result;
+}
+
+// bridge_get_contact_note fetches only the note of the unified contact. It is
+// kept apart from the regular fetch keys because reading the note requires the
+// notes entitlement.
+CNoteResult bridge_get_contact_note(BridgeString identifier) {
+    CNoteResult result;
+    memset(&result, 0, sizeof(CNoteResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        NSString *ident = nsstring_from_cstring(identifier);
+        NSError *error = nil;
+        CNContact *contact = nil;
+
+        @try {
+            contact = fetch_contact_by_identifier(store, ident, @[CNContactNoteKey], YES, &error);
+        } @catch (NSException *exception) {
+            result.error = cstring_from_nsstring([NSString stringWithFormat:@"note entitlement error 134092: %@", exception.reason ?: exception.name]);
+            return result;
+        }
+        if (error != nil) {
+            result.error = cstring_from_save_error(error);
+            return result;
+        }
+        if (contact == nil) {
+            result.error = cstring_from_nsstring([NSString stringWithFormat:@"contact %@ not found", ident]);
+            return result;
+        }
+        result.note = cstring_from_nsstring(contact.note ?: @"");
+    }
+    return result;
+}
+
+CHistoryTokenResult bridge_current_history_token(void) {
+    CHistoryTokenResult result;
+    memset(&result, 0, sizeof(CHistoryTokenResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        NSData *token = store.currentHistoryToken;
+        if (token == nil) {
+            result.error = cstring_from_nsstring(@"change history unsupported: store has no history token");
+            return result;
+        }
+        result.token = cstring_from_nsstring([token base64EncodedStringWithOptions:0]);
+    }
+    return result;
+}
+
+static BridgeString *bridge_string_array(NSOrderedSet<NSString *> *values, int *count) {
+    *count = (int)values.count;
+    if (values.count == 0) {
+        return NULL;
+    }
+    BridgeString *out = (BridgeString *)malloc(sizeof(BridgeString) * values.count);
+    for (NSUInteger i = 0; i < values.count; i++) {
+        out[i] = cstring_from_nsstring(values[i]);
+    }
+    return out;
+}
+
+CChangeHistoryResult bridge_fetch_change_history(BridgeString token) {
+    CChangeHistoryResult result;
+    memset(&result, 0, sizeof(CChangeHistoryResult));
+
+    @autoreleasepool {
+        CNContactStore *store = [[CNContactStore alloc] init];
+        CNChangeHistoryFetchRequest *request = [[CNChangeHistoryFetchRequest alloc] init];
+        request.shouldUnifyResults = NO;
+        request.includeGroupChanges = NO;
+        request.additionalContactKeyDescriptors = @[];
+
+        NSString *tokenString = nsstring_from_cstring(token);
+        if (tokenString.length > 0) {
+            NSData *tokenData = [[NSData alloc] initWithBase64EncodedString:tokenString options:0];
+            if (tokenData == nil) {
+                result.error = cstring_from_nsstring(@"invalid change token");
+                return result;
+            }
+            request.startingToken = tokenData;
+        }
+
+        NSError *error = nil;
+        CNFetchResult<NSEnumerator<CNChangeHistoryEvent *> *> *fetch = [store enumeratorForChangeHistoryFetchRequest:request error:&error];
+        if (fetch == nil || error != nil) {
+            result.error = cstring_from_error(error);
+            return result;
+        }
+
+        // A contact added and then updated is reported once as added; a
+        // contact deleted after other events is reported only as deleted.
+        NSMutableOrderedSet<NSString *> *added = [NSMutableOrderedSet orderedSet];
+        NSMutableOrderedSet<NSString *> *updated = [NSMutableOrderedSet orderedSet];
+        NSMutableOrderedSet<NSString *> *deleted = [NSMutableOrderedSet orderedSet];
+        for (CNChangeHistoryEvent *event in fetch.value) {
+            if ([event isKindOfClass:[CNChangeHistoryDropEverythingEvent class]]) {
+                result.dropEverything = 1;
+                [added removeAllObjects];
+                [updated removeAllObjects];
+                [deleted removeAllObjects];
+            } else if ([event isKindOfClass:[CNChangeHistoryAddContactEvent class]]) {
+                NSString *ident = ((CNChangeHistoryAddContactEvent *)event).contact.identifier;
+                if (ident.length == 0) continue;
+                [deleted removeObject:ident];
+                [added addObject:ident];
+            } else if ([event isKindOfClass:[CNChangeHistoryUpdateContactEvent class]]) {
+                NSString *ident = ((CNChangeHistoryUpdateContactEvent *)event).contact.identifier;
+                if (ident.length == 0 || [added containsObject:ident]) continue;
+                [updated addObject:ident];
+            } else if ([event isKindOfClass:[CNChangeHistoryDeleteContactEvent class]]) {
+                NSString *ident = ((CNChangeHistoryDeleteContactEvent *)event).contactIdentifier;
+                if (ident.length == 0) continue;
+                BOOL wasAdded = [added containsObject:ident];
+                [added removeObject:ident];
+                [updated removeObject:ident];
+                if (!wasAdded) {
+                    [deleted addObject:ident];
+                }
+            }
+        }
+
+        result.addedIDs = bridge_string_array(added, &result.addedCount);
+        result.updatedIDs = bridge_string_array(updated, &result.updatedCount);
+        result.deletedIDs = bridge_string_array(deleted, &result.deletedCount);
+        NSData *next = fetch.currentHistoryToken ?: store.currentHistoryToken;
+        if (next != nil) {
+            result.token = cstring_from_nsstring([next base64EncodedStringWithOptions:0]);
+        }
+    }
+    return result;
+}
+
 // --- Memory management ---
 
 static void free_labeled_string(CLabeledString *ls) {
@@ -1910,4 +2749,46 @@ void bridge_free_container_list(CContainer *containers, int count) {
         free_cstring(&containers[i].name);
     }
     free(containers);
-}
\ No newline at end of file
+}
+
+void bridge_free_change_history(CChangeHistoryResult *result) {
+    if (result == NULL) return;
+    for (int i = 0; i < result->addedCount; i++) {
+        free_cstring(&result->addedIDs[i]);
+    }
+    if (result->addedIDs) free(result->addedIDs);
+    for (int i = 0; i < result->updatedCount; i++) {
+        free_cstring(&result->updatedIDs[i]);
+    }
+    if (result->updatedIDs) free(result->updatedIDs);
+    for (int i = 0; i < result->deletedCount; i++) {
+        free_cstring(&result->deletedIDs[i]);
+    }
+    if (result->deletedIDs) free(result->deletedIDs);
+    free_cstring(&result->token);
+    free_cstring(&result->error);
+}
+
+void bridge_free_group_members(CGroupMembersResult *result) {
+    if (result == NULL) return;
+    bridge_free_string_array(result->ids, result->count);
+    bridge_free_string_array(result->names, result->count);
+    free_cstring(&result->error);
+}
+
+void bridge_free_contact_stats(CContactStatsResult *result) {
+    if (result == NULL) return;
+    bridge_free_string_array(result->containerIDs, result->containerCount);
+    free(result->containerCounts);
+    bridge_free_string_array(result->groupIDs, result->groupCount);
+    free(result->groupCounts);
+    free_cstring(&result->error);
+}
+
+void bridge_free_string_array(BridgeString *values, int count) {
+    if (values == NULL) return;
+    for (int i = 0; i < count; i++) {
+        free_cstring(&values[i]);
+    }
+    free(values);
+}
@@ -0,0 +1,642 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CardDAVAddressBook is a [RemoteAddressBook] backed by a CardDAV address
+// book collection (RFC 6352), such as those served by iCloud, Google,
+// Fastmail, or Nextcloud.
+//
+// Contacts are exchanged as vCard 3.0. Names, organization, job title, note,
+// birthday, phone numbers, emails, postal addresses, and URLs are read and
+// written; other properties of a remote card, such as its photo, are kept
+// as they are when the card is updated.
+type CardDAVAddressBook struct {
+	// URL is the address book collection, for example
+	// "https://carddav.example.com/addressbooks/me/default/".
+	URL string
+	// Username and Password are sent with HTTP basic authentication when
+	// Username is non-empty. iCloud and Fastmail accept an app-specific
+	// password here.
+	Username string
+	Password string
+	// Client sends the requests. Nil means [http.DefaultClient]. Google's
+	// CardDAV endpoint does not accept basic authentication; leave Username
+	// empty and set Client to one that adds OAuth 2.0 credentials, such as
+	// the client returned by golang.org/x/oauth2's Config.Client with the
+	// https://www.googleapis.com/auth/carddav scope.
+	Client *http.Client
+}
+
+// ListContacts returns every card in the collection. RemoteID is the path of
+// the card's resource.
+func (b *CardDAVAddressBook) ListContacts(ctx context.Context) ([]RemoteContact, error) {
+	const query = `<?xml version="1.0" encoding="utf-8"?>
+<C:addressbook-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:carddav">
+  <D:prop><D:getetag/><C:address-data/></D:prop>
+  <C:filter/>
+</C:addressbook-query>`
+	collection, err := b.resolve("")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.do(ctx, "REPORT", collection, strings.NewReader(query), map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+		"Depth":        "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("carddav: decode REPORT response: %w", err)
+	}
+	var out []RemoteContact
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") || strings.TrimSpace(ps.Prop.AddressData) == "" {
+				continue
+			}
+			c, err := parseVCard(ps.Prop.AddressData)
+			if err != nil {
+				return nil, fmt.Errorf("carddav: %s: %w", r.Href, err)
+			}
+			out = append(out, RemoteContact{RemoteID: r.Href, Contact: c})
+		}
+	}
+	return out, nil
+}
+
+// CreateContact stores c as a new card and returns the card as the server
+// saved it.
+func (b *CardDAVAddressBook) CreateContact(ctx context.Context, c Contact) (RemoteContact, error) {
+	uid, err := newVCardUID()
+	if err != nil {
+		return RemoteContact{}, err
+	}
+	target, err := b.resolve(uid + ".vcf")
+	if err != nil {
+		return RemoteContact{}, err
+	}
+	card := encodeVCard(c, []vcardProperty{{name: "UID", value: uid}})
+	if err := b.put(ctx, target, card, map[string]string{"If-None-Match": "*"}); err != nil {
+		return RemoteContact{}, err
+	}
+	return b.get(ctx, target)
+}
+
+// UpdateContact overwrites the card at rc.RemoteID with rc.Contact and
+// returns the card as the server saved it. Properties this package does not
+// map are carried over from the stored card. A card changed by someone else
+// between the read and the write fails with [ErrConflict].
+func (b *CardDAVAddressBook) UpdateContact(ctx context.Context, rc RemoteContact) (RemoteContact, error) {
+	target, err := b.resolve(rc.RemoteID)
+	if err != nil {
+		return RemoteContact{}, err
+	}
+	resp, err := b.do(ctx, http.MethodGet, target, nil, nil)
+	if err != nil {
+		return RemoteContact{}, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return RemoteContact{}, fmt.Errorf("carddav: read %s: %w", rc.RemoteID, err)
+	}
+	props, err := parseVCardProperties(string(data))
+	if err != nil {
+		return RemoteContact{}, fmt.Errorf("carddav: %s: %w", rc.RemoteID, err)
+	}
+	kept := unmappedVCardProperties(props)
+	headers := map[string]string{}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		headers["If-Match"] = etag
+	}
+	if err := b.put(ctx, target, encodeVCard(rc.Contact, kept), headers); err != nil {
+		return RemoteContact{}, err
+	}
+	return b.get(ctx, target)
+}
+
+func (b *CardDAVAddressBook) get(ctx context.Context, target string) (RemoteContact, error) {
+	resp, err := b.do(ctx, http.MethodGet, target, nil, nil)
+	if err != nil {
+		return RemoteContact{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return RemoteContact{}, fmt.Errorf("carddav: read %s: %w", target, err)
+	}
+	c, err := parseVCard(string(data))
+	if err != nil {
+		return RemoteContact{}, fmt.Errorf("carddav: %s: %w", target, err)
+	}
+	u, _ := url.Parse(target)
+	return RemoteContact{RemoteID: u.EscapedPath(), Contact: c}, nil
+}
+
+func (b *CardDAVAddressBook) put(ctx context.Context, target, card string, headers map[string]string) error {
+	headers["Content-Type"] = "text/vcard; charset=utf-8"
+	resp, err := b.do(ctx, http.MethodPut, target, strings.NewReader(card), headers)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// resolve turns a RemoteID, usually a server-absolute path, into a URL.
+func (b *CardDAVAddressBook) resolve(ref string) (string, error) {
+	base, err := url.Parse(b.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return "", fmt.Errorf("%w: carddav: invalid address book URL %q", ErrInvalidArgument, b.URL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("%w: carddav: invalid remote ID %q", ErrInvalidArgument, ref)
+	}
+	return u.String(), nil
+}
+
+// do sends one request and returns the response when its status is 2xx.
+// 404 maps to [ErrNotFound] and 412 to [ErrConflict].
+func (b *CardDAVAddressBook) do(ctx context.Context, method, target string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("carddav: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if b.Username != "" {
+		req.SetBasicAuth(b.Username, b.Password)
+	}
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("carddav: %s %s: %w", method, target, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	resp.Body.Close()
+	err = fmt.Errorf("carddav: %s %s: %s", method, target, resp.Status)
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		err = fmt.Errorf("%w: %w", ErrNotFound, err)
+	case http.StatusPreconditionFailed:
+		err = fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return nil, err
+}
+
+type davMultistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href     string `xml:"DAV: href"`
+	Propstat []struct {
+		Status string `xml:"DAV: status"`
+		Prop   struct {
+			ETag        string `xml:"DAV: getetag"`
+			AddressData string `xml:"urn:ietf:params:xml:ns:carddav address-data"`
+		} `xml:"DAV: prop"`
+	} `xml:"DAV: propstat"`
+}
+
+func newVCardUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("carddav: generate UID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// vcardProperty is one content line of a vCard. Names and parameter names
+// are uppercased; value is kept raw, still escaped.
+type vcardProperty struct {
+	group  string
+	name   string
+	params map[string][]string
+	value  string
+}
+
+// vcardMapped lists the properties encodeVCard writes. An update replaces
+// them and keeps the rest of the stored card.
+var vcardMapped = map[string]bool{
+	"VERSION": true, "PRODID": true, "FN": true, "N": true, "NICKNAME": true,
+	"ORG": true, "TITLE": true, "NOTE": true, "BDAY": true, "TEL": true,
+	"EMAIL": true, "ADR": true, "URL": true, "X-MAIDENNAME": true,
+	"X-PHONETIC-FIRST-NAME": true, "X-PHONETIC-MIDDLE-NAME": true,
+	"X-PHONETIC-LAST-NAME": true, "X-ABSHOWAS": true, "KIND": true,
+}
+
+// unmappedVCardProperties drops the properties encodeVCard writes, along
+// with the X-ABLabel and other properties grouped with them.
+func unmappedVCardProperties(props []vcardProperty) []vcardProperty {
+	dropped := map[string]bool{}
+	for _, p := range props {
+		if vcardMapped[p.name] && p.group != "" {
+			dropped[p.group] = true
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(props), func(p vcardProperty) bool {
+		return vcardMapped[p.name] || (p.group != "" && dropped[p.group])
+	})
+}
+
+// vcardTypes maps labels to vCard TYPE values where the two differ.
+var vcardTypes = map[string]string{"mobile": "cell", "home fax": "home,fax", "work fax": "work,fax", "other fax": "fax"}
+
+// parseVCardProperties splits a vCard into its unfolded content lines.
+func parseVCardProperties(card string) ([]vcardProperty, error) {
+	card = strings.ReplaceAll(card, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(card, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	props := make([]vcardProperty, 0, len(lines))
+	for _, line := range lines {
+		head, value, ok := cutUnquoted(line, ':')
+		if !ok {
+			return nil, fmt.Errorf("malformed vCard line %q", line)
+		}
+		p := vcardProperty{value: value, params: map[string][]string{}}
+		parts := splitUnquoted(head, ';')
+		p.name = strings.ToUpper(parts[0])
+		if group, name, ok := strings.Cut(p.name, "."); ok {
+			p.group, p.name = group, name
+		}
+		for _, param := range parts[1:] {
+			k, v, ok := strings.Cut(param, "=")
+			if !ok {
+				// vCard 2.1 bare types, e.g. TEL;CELL.
+				k, v = "TYPE", param
+			}
+			k = strings.ToUpper(k)
+			for _, item := range splitUnquoted(v, ',') {
+				p.params[k] = append(p.params[k], strings.Trim(item, `"`))
+			}
+		}
+		props = append(props, p)
+	}
+	if len(props) < 2 || props[0].name != "BEGIN" || props[len(props)-1].name != "END" {
+		return nil, fmt.Errorf("not a vCard")
+	}
+	return props[1 : len(props)-1], nil
+}
+
+// parseVCard reads the fields of c that this package maps.
+func parseVCard(card string) (Contact, error) {
+	props, err := parseVCardProperties(card)
+	if err != nil {
+		return Contact{}, err
+	}
+	// Apple clients label values through a grouped X-ABLabel property, e.g.
+	// "item1.EMAIL:..." with "item1.X-ABLabel:_$!<Home>!$_".
+	groupLabels := map[string]string{}
+	for _, p := range props {
+		if p.name == "X-ABLABEL" && p.group != "" {
+			groupLabels[p.group] = friendlyLabel(vcardUnescape(p.value))
+		}
+	}
+	label := func(p vcardProperty, ignore ...string) string {
+		if l, ok := groupLabels[p.group]; ok && p.group != "" {
+			return l
+		}
+		return vcardLabel(p, ignore...)
+	}
+	var c Contact
+	for _, p := range props {
+		switch p.name {
+		case "N":
+			n := vcardComponents(p.value, 5)
+			c.FamilyName, c.GivenName, c.MiddleName, c.NamePrefix, c.NameSuffix = n[0], n[1], n[2], n[3], n[4]
+		case "NICKNAME":
+			c.Nickname = vcardUnescape(p.value)
+		case "X-MAIDENNAME":
+			c.PreviousFamilyName = vcardUnescape(p.value)
+		case "X-PHONETIC-FIRST-NAME":
+			c.PhoneticGivenName = vcardUnescape(p.value)
+		case "X-PHONETIC-MIDDLE-NAME":
+			c.PhoneticMiddleName = vcardUnescape(p.value)
+		case "X-PHONETIC-LAST-NAME":
+			c.PhoneticFamilyName = vcardUnescape(p.value)
+		case "ORG":
+			org := vcardComponents(p.value, 2)
+			c.OrganizationName, c.DepartmentName = org[0], org[1]
+		case "TITLE":
+			c.JobTitle = vcardUnescape(p.value)
+		case "NOTE":
+			c.Note = vcardUnescape(p.value)
+		case "BDAY":
+			c.Birthday = parseVCardDate(p.value)
+		case "X-ABSHOWAS":
+			if strings.EqualFold(p.value, "COMPANY") {
+				c.ContactType = ContactTypeOrganization
+			}
+		case "KIND":
+			if strings.EqualFold(p.value, "org") {
+				c.ContactType = ContactTypeOrganization
+			}
+		case "TEL":
+			c.PhoneNumbers = append(c.PhoneNumbers, LabeledValue[string]{Label: label(p, "voice", "pref"), Value: strings.TrimPrefix(vcardUnescape(p.value), "tel:")})
+		case "EMAIL":
+			c.EmailAddresses = append(c.EmailAddresses, LabeledValue[string]{Label: label(p, "internet", "pref"), Value: vcardUnescape(p.value)})
+		case "URL":
+			c.URLAddresses = append(c.URLAddresses, LabeledValue[string]{Label: label(p, "pref"), Value: vcardUnescape(p.value)})
+		case "ADR":
+			a := vcardComponents(p.value, 7)
+			c.PostalAddresses = append(c.PostalAddresses, LabeledValue[PostalAddress]{
+				Label: label(p, "pref"),
+				Value: PostalAddress{Street: a[2], City: a[3], State: a[4], PostalCode: a[5], Country: a[6]},
+			})
+		}
+	}
+	return c, nil
+}
+
+// encodeVCard renders c as a vCard 3.0 card, followed by extra properties
+// carried over unchanged.
+func encodeVCard(c Contact, extra []vcardProperty) string {
+	var buf bytes.Buffer
+	line := func(s string) {
+		// Fold at 75 octets without splitting a UTF-8 sequence.
+		for len(s) > 75 {
+			cut := 75
+			for cut > 1 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			buf.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		buf.WriteString(s + "\r\n")
+	}
+	text := func(name, v string) {
+		if strings.TrimSpace(v) != "" {
+			line(name + ":" + vcardEscape(v))
+		}
+	}
+	labeled := func(name, label, value string) {
+		if t := vcardType(label); t != "" {
+			name += ";TYPE=" + t
+		}
+		line(name + ":" + value)
+	}
+
+	line("BEGIN:VCARD")
+	line("VERSION:3.0")
+	line("PRODID:-//cuh//contacts//EN")
+	line("FN:" + vcardEscape(vcardFormattedName(c)))
+	line("N:" + vcardJoin(c.FamilyName, c.GivenName, c.MiddleName, c.NamePrefix, c.NameSuffix))
+	text("NICKNAME", c.Nickname)
+	text("X-MAIDENNAME", c.PreviousFamilyName)
+	text("X-PHONETIC-FIRST-NAME", c.PhoneticGivenName)
+	text("X-PHONETIC-MIDDLE-NAME", c.PhoneticMiddleName)
+	text("X-PHONETIC-LAST-NAME", c.PhoneticFamilyName)
+	if c.OrganizationName != "" || c.DepartmentName != "" {
+		line("ORG:" + vcardJoin(c.OrganizationName, c.DepartmentName))
+	}
+	text("TITLE", c.JobTitle)
+	text("NOTE", c.Note)
+	if c.ContactType == ContactTypeOrganization {
+		line("X-ABShowAs:COMPANY")
+	}
+	if b := c.Birthday; b != nil && b.Month > 0 && b.Day > 0 {
+		if b.Year > 0 {
+			line(fmt.Sprintf("BDAY:%04d-%02d-%02d", b.Year, b.Month, b.Day))
+		} else {
+			line(fmt.Sprintf("BDAY:--%02d-%02d", b.Month, b.Day))
+		}
+	}
+	for _, v := range c.PhoneNumbers {
+		labeled("TEL", v.Label, vcardEscape(v.Value))
+	}
+	for _, v := range c.EmailAddresses {
+		labeled("EMAIL", v.Label, vcardEscape(v.Value))
+	}
+	for _, v := range c.PostalAddresses {
+		a := v.Value
+		labeled("ADR", v.Label, vcardJoin("", "", a.Street, a.City, a.State, a.PostalCode, a.Country))
+	}
+	for _, v := range c.URLAddresses {
+		labeled("URL", v.Label, vcardEscape(v.Value))
+	}
+	for _, p := range extra {
+		name := p.name
+		if p.group != "" {
+			name = p.group + "." + name
+		}
+		keys := make([]string, 0, len(p.params))
+		for k := range p.params {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			vals := make([]string, len(p.params[k]))
+			for i, v := range p.params[k] {
+				if strings.ContainsAny(v, ":;,") {
+					v = `"` + v + `"`
+				}
+				vals[i] = v
+			}
+			name += ";" + k + "=" + strings.Join(vals, ",")
+		}
+		line(name + ":" + p.value)
+	}
+	line("END:VCARD")
+	return buf.String()
+}
+
+// vcardFormattedName is the required FN value: the full name, else the
+// organization, else the first email.
+func vcardFormattedName(c Contact) string {
+	var parts []string
+	for _, p := range []string{c.NamePrefix, c.GivenName, c.MiddleName, c.FamilyName, c.NameSuffix} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	switch {
+	case c.ContactType != ContactTypeOrganization && len(parts) > 0:
+		return strings.Join(parts, " ")
+	case c.OrganizationName != "":
+		return c.OrganizationName
+	case len(parts) > 0:
+		return strings.Join(parts, " ")
+	case len(c.EmailAddresses) > 0:
+		return c.EmailAddresses[0].Value
+	}
+	return ""
+}
+
+// vcardLabel returns the label of p: its first TYPE other than ignore, with
+// vCard names mapped back to Contacts labels.
+func vcardLabel(p vcardProperty, ignore ...string) string {
+	var types []string
+	for _, t := range p.params["TYPE"] {
+		t = strings.ToLower(t)
+		if t != "" && !slices.Contains(ignore, t) {
+			types = append(types, t)
+		}
+	}
+	joined := strings.Join(types, ",")
+	for label, t := range vcardTypes {
+		if t == joined {
+			return label
+		}
+	}
+	if len(types) == 0 {
+		return ""
+	}
+	if label, ok := strings.CutPrefix(types[0], "x-"); ok {
+		return label
+	}
+	return types[0]
+}
+
+// vcardType returns the TYPE parameter value for label, or "" for none.
+func vcardType(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if t, ok := vcardTypes[label]; ok {
+		return t
+	}
+	if label == "" || strings.ContainsAny(label, ":;,\"") {
+		return ""
+	}
+	if strings.ContainsAny(label, " ") {
+		return `"` + label + `"`
+	}
+	return label
+}
+
+func parseVCardDate(v string) *DateComponents {
+	v = strings.ReplaceAll(strings.TrimSpace(v), "-", "")
+	if t, _, ok := strings.Cut(v, "T"); ok {
+		v = t
+	}
+	var d DateComponents
+	var err error
+	switch len(v) {
+	case 8:
+		if d.Year, err = strconv.Atoi(v[:4]); err != nil {
+			return nil
+		}
+		v = v[4:]
+	case 4:
+	default:
+		return nil
+	}
+	if d.Month, err = strconv.Atoi(v[:2]); err != nil {
+		return nil
+	}
+	if d.Day, err = strconv.Atoi(v[2:]); err != nil {
+		return nil
+	}
+	return &d
+}
+
+func vcardEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(v)
+}
+
+func vcardUnescape(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' && i+1 < len(v) {
+			i++
+			if v[i] == 'n' || v[i] == 'N' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(v[i])
+			}
+			continue
+		}
+		b.WriteByte(v[i])
+	}
+	return b.String()
+}
+
+func vcardJoin(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = vcardEscape(p)
+	}
+	return strings.Join(parts, ";")
+}
+
+// vcardComponents splits a structured value into at least n unescaped
+// components.
+func vcardComponents(v string, n int) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case ';':
+			out = append(out, vcardUnescape(v[start:i]))
+			start = i + 1
+		}
+	}
+	out = append(out, vcardUnescape(v[start:]))
+	for len(out) < n {
+		out = append(out, "")
+	}
+	return out
+}
+
+// cutUnquoted is strings.Cut for the first sep outside double quotes.
+func cutUnquoted(s string, sep byte) (before, after string, found bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var out []string
+	for {
+		before, after, found := cutUnquoted(s, sep)
+		out = append(out, before)
+		if !found {
+			return out
+		}
+		s = after
+	}
+}
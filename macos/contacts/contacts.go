@@ -3,11 +3,13 @@
 package contacts
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"iter"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -29,46 +31,78 @@ const (
 // LabeledValue pairs a label (e.g. "home", "work") with a value.
 // The Identifier is assigned by the Contacts framework and is stable across
 // fetches. It is empty for values that have not yet been persisted.
+//
+// Label is normalized: system labels such as "_$!<Work>!$_" read as "work",
+// "home", "mobile", "other", "main", "iphone", "home fax", and so on, and
+// those names are written back as the matching system constants. Custom
+// labels pass through unchanged.
 type LabeledValue[T any] struct {
-	Identifier string
-	Label      string
-	Value      T
+	Identifier string `json:"identifier"`
+	Label      string `json:"label"`
+	// RawLabel is the label exactly as stored, for example "_$!<Work>!$_".
+	// It is set on read and only consulted on write to keep a label that
+	// Label still describes byte-for-byte.
+	RawLabel string `json:"raw_label,omitempty"`
+	Value    T      `json:"value"`
 }
 
 // PostalAddress holds a structured mailing address.
 type PostalAddress struct {
-	Street         string
-	City           string
-	State          string
-	PostalCode     string
-	Country        string
-	ISOCountryCode string
+	Street         string `json:"street"`
+	City           string `json:"city"`
+	State          string `json:"state"`
+	PostalCode     string `json:"postal_code"`
+	Country        string `json:"country"`
+	ISOCountryCode string `json:"iso_country_code"`
 }
 
 // ContactRelation holds a related contact name.
 type ContactRelation struct {
-	Name string
+	Name string `json:"name"`
 }
 
+// Relation labels recognized by the bridge. Writing one of these (any case) as
+// a ContactRelations label stores the matching system relationship label, so
+// Contacts.app displays it as a built-in relationship. Other labels are stored
+// verbatim as custom labels.
+const (
+	RelationLabelSpouse    = "spouse"
+	RelationLabelWife      = "wife"
+	RelationLabelHusband   = "husband"
+	RelationLabelPartner   = "partner"
+	RelationLabelAssistant = "assistant"
+	RelationLabelManager   = "manager"
+	RelationLabelFather    = "father"
+	RelationLabelMother    = "mother"
+	RelationLabelParent    = "parent"
+	RelationLabelBrother   = "brother"
+	RelationLabelSister    = "sister"
+	RelationLabelSibling   = "sibling"
+	RelationLabelChild     = "child"
+	RelationLabelSon       = "son"
+	RelationLabelDaughter  = "daughter"
+	RelationLabelFriend    = "friend"
+)
+
 // SocialProfile holds a social-network profile reference.
 type SocialProfile struct {
-	URLString string
-	Username  string
-	Service   string
+	URLString string `json:"url_string"`
+	Username  string `json:"username"`
+	Service   string `json:"service"`
 }
 
 // InstantMessage holds an instant-messaging handle.
 type InstantMessage struct {
-	Username string
-	Service  string
+	Username string `json:"username"`
+	Service  string `json:"service"`
 }
 
 // DateComponents holds a date without requiring a full time.Time.
 // Month and Day are 1-based. Any field may be zero if not set.
 type DateComponents struct {
-	Year  int
-	Month int
-	Day   int
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
 }
 
 // Contact is the model for a macOS contact.
@@ -82,38 +116,41 @@ type DateComponents struct {
 // mechanism of the Contacts.framework. ContainerID identifies the owning
 // container/account when available. Unset multi-value fields are nil (not empty
 // slices).
+//
+// ImageData holds the full-size photo as stored (JPEG, PNG, HEIC, or TIFF) and ThumbnailImageData the
+// system-generated thumbnail; both are nil when ImageDataAvailable is false.
 type Contact struct {
-	Identifier         string
-	Unified            bool
-	LinkedIDs          []string
-	ContainerID        string
-	ContactType        ContactType
-	NamePrefix         string
-	GivenName          string
-	MiddleName         string
-	FamilyName         string
-	PreviousFamilyName string
-	NameSuffix         string
-	Nickname           string
-	PhoneticGivenName  string
-	PhoneticMiddleName string
-	PhoneticFamilyName string
-	OrganizationName   string
-	DepartmentName     string
-	JobTitle           string
-	Note               string
-	Birthday           *DateComponents
-	PhoneNumbers       []LabeledValue[string]
-	EmailAddresses     []LabeledValue[string]
-	PostalAddresses    []LabeledValue[PostalAddress]
-	URLAddresses       []LabeledValue[string]
-	ContactRelations   []LabeledValue[ContactRelation]
-	SocialProfiles     []LabeledValue[SocialProfile]
-	InstantMessages    []LabeledValue[InstantMessage]
-	Dates              []LabeledValue[DateComponents]
-	ImageDataAvailable bool
-	ImageData          []byte
-	ThumbnailImageData []byte
+	Identifier         string                          `json:"identifier"`
+	Unified            bool                            `json:"unified"`
+	LinkedIDs          []string                        `json:"linked_ids"`
+	ContainerID        string                          `json:"container_id"`
+	ContactType        ContactType                     `json:"contact_type"`
+	NamePrefix         string                          `json:"name_prefix"`
+	GivenName          string                          `json:"given_name"`
+	MiddleName         string                          `json:"middle_name"`
+	FamilyName         string                          `json:"family_name"`
+	PreviousFamilyName string                          `json:"previous_family_name"`
+	NameSuffix         string                          `json:"name_suffix"`
+	Nickname           string                          `json:"nickname"`
+	PhoneticGivenName  string                          `json:"phonetic_given_name"`
+	PhoneticMiddleName string                          `json:"phonetic_middle_name"`
+	PhoneticFamilyName string                          `json:"phonetic_family_name"`
+	OrganizationName   string                          `json:"organization_name"`
+	DepartmentName     string                          `json:"department_name"`
+	JobTitle           string                          `json:"job_title"`
+	Note               string                          `json:"note"`
+	Birthday           *DateComponents                 `json:"birthday,omitempty"`
+	PhoneNumbers       []LabeledValue[string]          `json:"phone_numbers"`
+	EmailAddresses     []LabeledValue[string]          `json:"email_addresses"`
+	PostalAddresses    []LabeledValue[PostalAddress]   `json:"postal_addresses"`
+	URLAddresses       []LabeledValue[string]          `json:"url_addresses"`
+	ContactRelations   []LabeledValue[ContactRelation] `json:"contact_relations"`
+	SocialProfiles     []LabeledValue[SocialProfile]   `json:"social_profiles"`
+	InstantMessages    []LabeledValue[InstantMessage]  `json:"instant_messages"`
+	Dates              []LabeledValue[DateComponents]  `json:"dates"`
+	ImageDataAvailable bool                            `json:"image_data_available"`
+	ImageData          []byte                          `json:"image_data,omitempty"`
+	ThumbnailImageData []byte                          `json:"thumbnail_image_data,omitempty"`
 }
 
 // CreateContactInput specifies fields for a new contact.
@@ -124,7 +161,11 @@ type Contact struct {
 // container; if empty, the default container is used.
 type CreateContactInput struct {
 	// Contact defines the contact values to persist.
-	Contact Contact
+	Contact Contact `json:"contact"`
+	// DryRun validates field values and the destination container, then
+	// returns the contact that would be created without saving it. The
+	// returned Contact has an empty Identifier.
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // ContactField identifies a contact field that can be filtered.
@@ -149,10 +190,39 @@ const (
 	ContactFieldNamePrefix ContactField = "namePrefix"
 	// ContactFieldNameSuffix matches the nameSuffix field.
 	ContactFieldNameSuffix ContactField = "nameSuffix"
+	// ContactFieldPreviousFamilyName matches the previousFamilyName (maiden
+	// name) field.
+	ContactFieldPreviousFamilyName ContactField = "previousFamilyName"
+	// ContactFieldPhoneticGivenName matches the phoneticGivenName field.
+	ContactFieldPhoneticGivenName ContactField = "phoneticGivenName"
+	// ContactFieldPhoneticMiddleName matches the phoneticMiddleName field.
+	ContactFieldPhoneticMiddleName ContactField = "phoneticMiddleName"
+	// ContactFieldPhoneticFamilyName matches the phoneticFamilyName field.
+	// Sync sources that store names in a native script often carry the
+	// romanized form here.
+	ContactFieldPhoneticFamilyName ContactField = "phoneticFamilyName"
 	// ContactFieldEmailAddresses matches values in emailAddresses.
 	ContactFieldEmailAddresses ContactField = "emailAddresses"
-	// ContactFieldPhoneNumbers matches values in phoneNumbers.
+	// ContactFieldPhoneNumbers matches values in phoneNumbers by digits, so
+	// formatting is ignored. FilterEquals uses [PhoneNumbersMatch] (country
+	// code aware); FilterContains and FilterNotContains compare the digits of
+	// the filter value against the digits of each stored number.
 	ContactFieldPhoneNumbers ContactField = "phoneNumbers"
+	// ContactFieldText searches across name fields (including "given family"),
+	// nickname, organization, department, job title, emails, phone numbers,
+	// and notes. With FilterContains every whitespace-separated word must
+	// appear in some field, so "marek acme" matches Marek at Acme Corp.
+	// FilterEquals matches when any single field equals the value;
+	// FilterNotContains is the negation of FilterContains.
+	//
+	// Searching notes needs the notes entitlement (see the package docs).
+	// Without it the listing fails with [ErrNoteEntitlementMissing] rather
+	// than quietly skipping notes; use ContactFieldTextExcludingNotes then.
+	// Contacts listed with this filter carry their Note.
+	ContactFieldText ContactField = "text"
+	// ContactFieldTextExcludingNotes is ContactFieldText without notes. It
+	// works without the notes entitlement.
+	ContactFieldTextExcludingNotes ContactField = "textExcludingNotes"
 	// ContactFieldUnified matches whether listing returns unified projections.
 	// Value must be parseable as bool and operator must be FilterEquals.
 	ContactFieldUnified ContactField = "unified"
@@ -160,6 +230,16 @@ const (
 	// For unified listings, this matches if any linked constituent is in the
 	// provided container.
 	ContactFieldContainerID ContactField = "containerID"
+	// ContactFieldGroupID matches direct members of the group with this
+	// identifier. FilterEquals keeps members and FilterNotContains keeps
+	// non-members. For unified listings, a contact is a member if any linked
+	// constituent is.
+	ContactFieldGroupID ContactField = "groupID"
+	// ContactFieldGroupName is ContactFieldGroupID addressed by group name.
+	// Each name is resolved with [FindGroupByName], scoped to the
+	// ContactFieldContainerID filter when one is present, so an unknown name
+	// fails with [ErrNotFound] and a duplicated one with [ErrAmbiguous].
+	ContactFieldGroupName ContactField = "groupName"
 )
 
 // FilterOp specifies how a filter matches against a field value.
@@ -179,18 +259,29 @@ const (
 
 // Filter specifies a single field-level filter for listing contacts.
 type Filter struct {
-	Field ContactField
-	Value string
-	Op    FilterOp
+	Field ContactField `json:"field"`
+	Value string       `json:"value"`
+	// Values names several groups for ContactFieldGroupID and
+	// ContactFieldGroupName, in place of Value. FilterEquals then keeps
+	// members of any of the groups and FilterNotContains keeps contacts in
+	// none of them. Other fields do not accept Values.
+	Values []string `json:"values,omitempty"`
+	Op     FilterOp `json:"op"`
 }
 
 // ListContactsInput controls contact enumeration.
 //
-// Filters are ANDed together. Offset controls the starting position for
-// pagination (0-based).
+// Filters are ANDed together. Results are ordered by given name, then family
+// name (case-insensitive), then identifier.
+//
+// After is a keyset cursor from [ContactCursor]: only contacts sorting after
+// it are returned, so long paginated sweeps neither skip nor repeat records
+// when contacts are added or removed mid-scan. Offset skips that many further
+// results (0-based) and is kept for simple one-shot paging.
 type ListContactsInput struct {
-	Filters []Filter
-	Offset  int
+	Filters []Filter `json:"filters"`
+	After   string   `json:"after"`
+	Offset  int      `json:"offset"`
 }
 
 // ContactIdentity describes how an input identifier resolves in Contacts.
@@ -200,42 +291,65 @@ type ListContactsInput struct {
 // LinkedIDs are linked constituent record identifiers, and ContainerIDs are the
 // corresponding constituent container identifiers.
 type ContactIdentity struct {
-	InputID      string
-	CanonicalID  string
-	Unified      bool
-	LinkedIDs    []string
-	ContainerIDs []string
+	InputID      string   `json:"input_id"`
+	CanonicalID  string   `json:"canonical_id"`
+	Unified      bool     `json:"unified"`
+	LinkedIDs    []string `json:"linked_ids"`
+	ContainerIDs []string `json:"container_ids"`
 }
 
 // UpdateContactInput specifies mutable fields for updating a contact.
 // Nil pointers mean "leave unchanged".
+//
+// ImageData replaces the contact photo; ClearImageData removes it. The
+// thumbnail is regenerated by Contacts.framework and cannot be set directly.
+//
+// When DryRun is true, the target is resolved and validated exactly as for a
+// real update, and the merged contact that would be saved is returned without
+// writing to the store.
 type UpdateContactInput struct {
-	Identifier         string
-	ContactType        *ContactType
-	NamePrefix         *string
-	GivenName          *string
-	MiddleName         *string
-	FamilyName         *string
-	PreviousFamilyName *string
-	NameSuffix         *string
-	Nickname           *string
-	PhoneticGivenName  *string
-	PhoneticMiddleName *string
-	PhoneticFamilyName *string
-	OrganizationName   *string
-	DepartmentName     *string
-	JobTitle           *string
-	Birthday           *DateComponents
-	ClearBirthday      bool
-	PhoneNumbers       *[]LabeledValue[string]
-	EmailAddresses     *[]LabeledValue[string]
-	PostalAddresses    *[]LabeledValue[PostalAddress]
-	URLAddresses       *[]LabeledValue[string]
-	ContactRelations   *[]LabeledValue[ContactRelation]
-	SocialProfiles     *[]LabeledValue[SocialProfile]
-	InstantMessages    *[]LabeledValue[InstantMessage]
-	Dates              *[]LabeledValue[DateComponents]
-	ImageData          *[]byte
+	Identifier         string                           `json:"identifier"`
+	ContactType        *ContactType                     `json:"contact_type,omitempty"`
+	NamePrefix         *string                          `json:"name_prefix,omitempty"`
+	GivenName          *string                          `json:"given_name,omitempty"`
+	MiddleName         *string                          `json:"middle_name,omitempty"`
+	FamilyName         *string                          `json:"family_name,omitempty"`
+	PreviousFamilyName *string                          `json:"previous_family_name,omitempty"`
+	NameSuffix         *string                          `json:"name_suffix,omitempty"`
+	Nickname           *string                          `json:"nickname,omitempty"`
+	PhoneticGivenName  *string                          `json:"phonetic_given_name,omitempty"`
+	PhoneticMiddleName *string                          `json:"phonetic_middle_name,omitempty"`
+	PhoneticFamilyName *string                          `json:"phonetic_family_name,omitempty"`
+	OrganizationName   *string                          `json:"organization_name,omitempty"`
+	DepartmentName     *string                          `json:"department_name,omitempty"`
+	JobTitle           *string                          `json:"job_title,omitempty"`
+	Birthday           *DateComponents                  `json:"birthday,omitempty"`
+	ClearBirthday      bool                             `json:"clear_birthday,omitempty"`
+	PhoneNumbers       *[]LabeledValue[string]          `json:"phone_numbers,omitempty"`
+	EmailAddresses     *[]LabeledValue[string]          `json:"email_addresses,omitempty"`
+	PostalAddresses    *[]LabeledValue[PostalAddress]   `json:"postal_addresses,omitempty"`
+	URLAddresses       *[]LabeledValue[string]          `json:"url_addresses,omitempty"`
+	ContactRelations   *[]LabeledValue[ContactRelation] `json:"contact_relations,omitempty"`
+	SocialProfiles     *[]LabeledValue[SocialProfile]   `json:"social_profiles,omitempty"`
+	InstantMessages    *[]LabeledValue[InstantMessage]  `json:"instant_messages,omitempty"`
+	Dates              *[]LabeledValue[DateComponents]  `json:"dates,omitempty"`
+	ImageData          *[]byte                          `json:"image_data,omitempty"`
+	ClearImageData     bool                             `json:"clear_image_data,omitempty"`
+	DryRun             bool                             `json:"dry_run,omitempty"`
+
+	// AddEmailAddresses appends emails that are not already present
+	// (case-insensitive), and RemoveEmailAddresses deletes every email
+	// matching one of the given values. AddPhoneNumbers and
+	// RemovePhoneNumbers do the same for phone numbers, matching with
+	// [PhoneNumbersMatch]. Removals apply before additions.
+	//
+	// These list operations are applied by the bridge to the contact as it
+	// exists at save time, so concurrent edits to other values are not lost.
+	// They cannot be combined with a full replacement of the same field.
+	AddEmailAddresses    []LabeledValue[string] `json:"add_email_addresses,omitempty"`
+	RemoveEmailAddresses []string               `json:"remove_email_addresses,omitempty"`
+	AddPhoneNumbers      []LabeledValue[string] `json:"add_phone_numbers,omitempty"`
+	RemovePhoneNumbers   []string               `json:"remove_phone_numbers,omitempty"`
 }
 
 // ---------------------------------------------------------------------
@@ -247,36 +361,86 @@ type UpdateContactInput struct {
 // ParentGroupID is non-empty when this group is a subgroup of another group.
 // SubgroupIDs contains direct children when requested.
 type Group struct {
-	Identifier    string
-	Name          string
-	ContainerID   string
-	ParentGroupID string
-	SubgroupIDs   []string
+	Identifier    string   `json:"identifier"`
+	Name          string   `json:"name"`
+	ContainerID   string   `json:"container_id"`
+	ParentGroupID string   `json:"parent_group_id"`
+	SubgroupIDs   []string `json:"subgroup_ids"`
 }
 
 // CreateGroupInput specifies parameters for creating a new group.
 type CreateGroupInput struct {
-	Name string
+	Name string `json:"name"`
 	// ContainerID is the container to add the group to.
 	// If empty, the default container is used.
-	ContainerID string
+	ContainerID string `json:"container_id"`
 	// ParentGroupID, if non-empty, makes this group a subgroup of the
 	// specified parent group.
-	ParentGroupID string
+	ParentGroupID string `json:"parent_group_id"`
+	// DryRun validates the container and parent group, then returns the group
+	// that would be created without saving it. The returned Group has an
+	// empty Identifier.
+	DryRun bool `json:"dry_run,omitempty"`
+	// OnDuplicateName controls what happens when the container already holds
+	// a group with the same name. The zero value allows duplicates.
+	OnDuplicateName DuplicateGroupNamePolicy `json:"on_duplicate_name,omitempty"`
 }
 
+// DuplicateGroupNamePolicy selects how [CreateGroup] and [UpdateGroup] treat a
+// name already used by another group in the same container. Names are compared
+// case-insensitively after trimming spaces.
+type DuplicateGroupNamePolicy string
+
+const (
+	// DuplicateGroupNameAllow saves the group regardless of existing names.
+	DuplicateGroupNameAllow DuplicateGroupNamePolicy = ""
+	// DuplicateGroupNameFail returns [ErrConflict]; the OpError ID is the
+	// identifier of the existing group.
+	DuplicateGroupNameFail DuplicateGroupNamePolicy = "fail"
+	// DuplicateGroupNameReuse makes [CreateGroup] return the existing group
+	// instead of creating a new one. It is not valid for [UpdateGroup].
+	DuplicateGroupNameReuse DuplicateGroupNamePolicy = "reuse"
+)
+
 // ListGroupsInput controls group enumeration.
 type ListGroupsInput struct {
-	ContainerID      string
-	IncludeHierarchy bool
+	ContainerID      string `json:"container_id"`
+	IncludeHierarchy bool   `json:"include_hierarchy,omitempty"`
 }
 
 // UpdateGroupInput specifies mutable group fields.
 // Nil pointers mean "leave unchanged".
+//
+// When DryRun is true, the target and parent groups are validated and the
+// group as it would look after the update is returned without saving.
 type UpdateGroupInput struct {
-	Identifier    string
-	Name          *string
-	ParentGroupID *string
+	Identifier    string  `json:"identifier"`
+	Name          *string `json:"name,omitempty"`
+	ParentGroupID *string `json:"parent_group_id,omitempty"`
+	DryRun        bool    `json:"dry_run,omitempty"`
+	// OnDuplicateName applies to renames. Only [DuplicateGroupNameAllow] and
+	// [DuplicateGroupNameFail] are accepted.
+	OnDuplicateName DuplicateGroupNamePolicy `json:"on_duplicate_name,omitempty"`
+}
+
+// DeleteGroupInput identifies the group to delete.
+//
+// When DryRun is true, the group is resolved and nothing is deleted.
+type DeleteGroupInput struct {
+	Identifier string `json:"identifier"`
+	DryRun     bool   `json:"dry_run,omitempty"`
+}
+
+// GroupMembershipInput names a contact and the group to add it to or remove
+// it from.
+//
+// When DryRun is true, the contact and group are resolved and their
+// containers checked exactly as for a real change, but membership is left
+// unchanged.
+type GroupMembershipInput struct {
+	ContactID string `json:"contact_id"`
+	GroupID   string `json:"group_id"`
+	DryRun    bool   `json:"dry_run,omitempty"`
 }
 
 // ---------------------------------------------------------------------
@@ -299,9 +463,9 @@ const (
 
 // Container represents a contacts container (account/store).
 type Container struct {
-	Identifier    string
-	Name          string
-	ContainerType ContainerType
+	Identifier    string        `json:"identifier"`
+	Name          string        `json:"name"`
+	ContainerType ContainerType `json:"container_type"`
 }
 
 // AuthorizationStatus reflects the app's authorization to access contacts.
@@ -312,6 +476,10 @@ const (
 	AuthorizationStatusRestricted    AuthorizationStatus = 1
 	AuthorizationStatusDenied        AuthorizationStatus = 2
 	AuthorizationStatusAuthorized    AuthorizationStatus = 3
+	// AuthorizationStatusLimited means the user shared only selected
+	// contacts. Reads and writes succeed but see only those contacts, so an
+	// empty or short result does not mean the address book is empty.
+	AuthorizationStatusLimited AuthorizationStatus = 4
 )
 
 // Typed package-level errors.
@@ -330,8 +498,19 @@ var (
 	ErrUnifiedContactNotMutable = errors.New("contacts: unified contact not mutable")
 	// ErrGroupContainerMismatch indicates contact/group container mismatch.
 	ErrGroupContainerMismatch = errors.New("contacts: group container mismatch")
+	// ErrAmbiguous indicates a lookup matched more than one entity.
+	ErrAmbiguous = errors.New("contacts: ambiguous match")
+	// ErrConflict indicates the mutation would collide with an existing
+	// entity, such as a duplicate group name.
+	ErrConflict = errors.New("contacts: conflict")
+	// ErrNoteEntitlementMissing indicates the note field was read or written
+	// without the com.apple.developer.contacts.notes entitlement.
+	ErrNoteEntitlementMissing = errors.New("contacts: note entitlement missing")
 )
 
+// noteEntitlementRemedy is appended to ErrNoteEntitlementMissing messages.
+const noteEntitlementRemedy = "sign the calling binary with the com.apple.developer.contacts.notes entitlement, or leave the note empty"
+
 // OpError captures operation-level failures with typed causes.
 type OpError struct {
 	Op  string
@@ -362,8 +541,16 @@ func validContactField(field ContactField) bool {
 		ContactFieldNickname,
 		ContactFieldNamePrefix,
 		ContactFieldNameSuffix,
+		ContactFieldPreviousFamilyName,
+		ContactFieldPhoneticGivenName,
+		ContactFieldPhoneticMiddleName,
+		ContactFieldPhoneticFamilyName,
 		ContactFieldEmailAddresses,
 		ContactFieldPhoneNumbers,
+		ContactFieldText,
+		ContactFieldTextExcludingNotes,
+		ContactFieldGroupID,
+		ContactFieldGroupName,
 		ContactFieldUnified,
 		ContactFieldContainerID:
 		return true
@@ -383,6 +570,14 @@ func ValidateFilters(filters []Filter) error {
 		if f.Op < FilterEquals || f.Op > FilterNotContains {
 			return fmt.Errorf("%w: filter[%d] has invalid operator %d", ErrInvalidArgument, i, f.Op)
 		}
+		if len(f.Values) > 0 {
+			if f.Field != ContactFieldGroupID && f.Field != ContactFieldGroupName {
+				return fmt.Errorf("%w: filter[%d] field %q does not accept values", ErrInvalidArgument, i, f.Field)
+			}
+			if strings.TrimSpace(f.Value) != "" {
+				return fmt.Errorf("%w: filter[%d] sets both value and values", ErrInvalidArgument, i)
+			}
+		}
 		switch f.Field {
 		case ContactFieldUnified:
 			if f.Op != FilterEquals {
@@ -401,11 +596,71 @@ func ValidateFilters(filters []Filter) error {
 			if f.Op != FilterEquals {
 				return fmt.Errorf("%w: filter[%d] field %q only supports FilterEquals", ErrInvalidArgument, i, f.Field)
 			}
+		case ContactFieldGroupID, ContactFieldGroupName:
+			if f.Op != FilterEquals && f.Op != FilterNotContains {
+				return fmt.Errorf("%w: filter[%d] field %q only supports FilterEquals and FilterNotContains", ErrInvalidArgument, i, f.Field)
+			}
+			if len(f.Values) == 0 && strings.TrimSpace(f.Value) == "" {
+				return fmt.Errorf("%w: filter[%d] field %q requires a value", ErrInvalidArgument, i, f.Field)
+			}
+			for j, v := range f.Values {
+				if strings.TrimSpace(v) == "" {
+					return fmt.Errorf("%w: filter[%d] values[%d] must not be empty", ErrInvalidArgument, i, j)
+				}
+			}
 		}
 	}
 	return nil
 }
 
+// resolveGroupNameFilters replaces ContactFieldGroupName filters with the
+// equivalent ContactFieldGroupID filters. Filters must already be valid.
+func resolveGroupNameFilters(ctx context.Context, filters []Filter) ([]Filter, error) {
+	if !slices.ContainsFunc(filters, func(f Filter) bool { return f.Field == ContactFieldGroupName }) {
+		return filters, nil
+	}
+	var containerID string
+	for _, f := range filters {
+		if f.Field == ContactFieldContainerID {
+			containerID = strings.TrimSpace(f.Value)
+			break
+		}
+	}
+	out := cloneSlice(filters)
+	for i, f := range out {
+		if f.Field != ContactFieldGroupName {
+			continue
+		}
+		if len(f.Values) == 0 {
+			g, err := FindGroupByName(ctx, f.Value, containerID)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = Filter{Field: ContactFieldGroupID, Op: f.Op, Value: g.Identifier}
+			continue
+		}
+		ids := make([]string, len(f.Values))
+		for j, name := range f.Values {
+			g, err := FindGroupByName(ctx, name, containerID)
+			if err != nil {
+				return nil, err
+			}
+			ids[j] = g.Identifier
+		}
+		out[i] = Filter{Field: ContactFieldGroupID, Op: f.Op, Values: ids}
+	}
+	return out, nil
+}
+
+// bridgeFilterValue is the value of f as the bridge reads it. Several group
+// identifiers are joined with newlines, which identifiers never contain.
+func bridgeFilterValue(f Filter) string {
+	if len(f.Values) > 0 {
+		return strings.Join(f.Values, "\n")
+	}
+	return f.Value
+}
+
 func classifyBridgeError(msg string) error {
 	trimmed := strings.TrimSpace(msg)
 	if trimmed == "" {
@@ -413,6 +668,8 @@ func classifyBridgeError(msg string) error {
 	}
 	lower := strings.ToLower(trimmed)
 	switch {
+	case isNoteEntitlementError(trimmed):
+		return fmt.Errorf("%w: %s; %s", ErrNoteEntitlementMissing, trimmed, noteEntitlementRemedy)
 	case strings.Contains(lower, "not found"), strings.Contains(lower, "does not exist"):
 		return fmt.Errorf("%w: %s", ErrNotFound, trimmed)
 	case strings.Contains(lower, "denied"), strings.Contains(lower, "not authorized"), strings.Contains(lower, "authorization"):
@@ -465,6 +722,8 @@ func (s AuthorizationStatus) String() string {
 		return "denied"
 	case AuthorizationStatusAuthorized:
 		return "authorized"
+	case AuthorizationStatusLimited:
+		return "limited"
 	default:
 		return fmt.Sprintf("unknown(%d)", int(s))
 	}
@@ -531,6 +790,84 @@ func (c Contact) FullName() string {
 	return result
 }
 
+// SameAs reports whether c and other refer to the same person: their
+// identifiers match, or one appears in the other's LinkedIDs. It lets callers
+// compare unified projections with constituent records without another
+// store round-trip.
+func (c Contact) SameAs(other Contact) bool {
+	if c.Identifier == "" || other.Identifier == "" {
+		return false
+	}
+	if c.Identifier == other.Identifier {
+		return true
+	}
+	for _, id := range c.LinkedIDs {
+		if id == other.Identifier || slices.Contains(other.LinkedIDs, id) {
+			return true
+		}
+	}
+	return slices.Contains(other.LinkedIDs, c.Identifier)
+}
+
+// RelatedNames returns the names of ContactRelations whose label matches any of
+// labels, compared case-insensitively. With no labels, all relation names are
+// returned. Resolve a name to a contact with [ListContacts], for example by
+// filtering on [ContactFieldGivenName].
+func (c Contact) RelatedNames(labels ...string) []string {
+	var names []string
+	for _, r := range c.ContactRelations {
+		if strings.TrimSpace(r.Value.Name) == "" {
+			continue
+		}
+		if len(labels) == 0 {
+			names = append(names, r.Value.Name)
+			continue
+		}
+		for _, l := range labels {
+			if strings.EqualFold(r.Label, l) {
+				names = append(names, r.Value.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// PrimaryEmail returns the email address to use when a single one is needed.
+// Contacts has no notion of a primary value, so the first non-empty entry whose
+// label matches one of prefer (case-insensitive, tried in order) wins, falling
+// back to the first non-empty entry. It returns "" when there is none.
+//
+// Contacts returned by [ListContacts] already carry all emails and phones, so
+// list-and-choose flows can call PrimaryEmail and [Contact.PrimaryPhone] on each
+// candidate without a [GetContact] round trip.
+func (c Contact) PrimaryEmail(prefer ...string) string {
+	return primaryValue(c.EmailAddresses, prefer)
+}
+
+// PrimaryPhone returns the phone number to use when a single one is needed,
+// with the same label preference rules as [Contact.PrimaryEmail]. For
+// messaging, prefer mobile numbers: c.PrimaryPhone("mobile", "iPhone").
+func (c Contact) PrimaryPhone(prefer ...string) string {
+	return primaryValue(c.PhoneNumbers, prefer)
+}
+
+func primaryValue(values []LabeledValue[string], prefer []string) string {
+	for _, label := range prefer {
+		for _, lv := range values {
+			if strings.TrimSpace(lv.Value) != "" && strings.EqualFold(lv.Label, label) {
+				return lv.Value
+			}
+		}
+	}
+	for _, lv := range values {
+		if strings.TrimSpace(lv.Value) != "" {
+			return lv.Value
+		}
+	}
+	return ""
+}
+
 func mergeContactPatch(current Contact, input UpdateContactInput) Contact {
 	merged := current
 	if input.ContactType != nil {
@@ -588,6 +925,12 @@ func mergeContactPatch(current Contact, input UpdateContactInput) Contact {
 	if input.EmailAddresses != nil {
 		merged.EmailAddresses = cloneSlice(*input.EmailAddresses)
 	}
+	if len(input.AddPhoneNumbers) > 0 || len(input.RemovePhoneNumbers) > 0 {
+		merged.PhoneNumbers = patchLabeledStrings(merged.PhoneNumbers, input.AddPhoneNumbers, input.RemovePhoneNumbers, PhoneNumbersMatch)
+	}
+	if len(input.AddEmailAddresses) > 0 || len(input.RemoveEmailAddresses) > 0 {
+		merged.EmailAddresses = patchLabeledStrings(merged.EmailAddresses, input.AddEmailAddresses, input.RemoveEmailAddresses, emailAddressesMatch)
+	}
 	if input.PostalAddresses != nil {
 		merged.PostalAddresses = cloneSlice(*input.PostalAddresses)
 	}
@@ -609,6 +952,10 @@ func mergeContactPatch(current Contact, input UpdateContactInput) Contact {
 	if input.ImageData != nil {
 		merged.ImageData = cloneSlice(*input.ImageData)
 	}
+	if input.ClearImageData {
+		merged.ImageData = nil
+		merged.ThumbnailImageData = nil
+	}
 	return merged
 }
 
@@ -637,7 +984,80 @@ func hasUpdateContactChanges(input UpdateContactInput) bool {
 		input.SocialProfiles != nil ||
 		input.InstantMessages != nil ||
 		input.Dates != nil ||
-		input.ImageData != nil
+		input.ImageData != nil ||
+		input.ClearImageData ||
+		len(input.AddEmailAddresses) > 0 ||
+		len(input.RemoveEmailAddresses) > 0 ||
+		len(input.AddPhoneNumbers) > 0 ||
+		len(input.RemovePhoneNumbers) > 0
+}
+
+// patchLabeledStrings removes every value matching an entry of remove, then
+// appends each entry of add that does not match a remaining value.
+func patchLabeledStrings(values, add []LabeledValue[string], remove []string, match func(a, b string) bool) []LabeledValue[string] {
+	out := make([]LabeledValue[string], 0, len(values)+len(add))
+	for _, v := range values {
+		if !slices.ContainsFunc(remove, func(r string) bool { return match(v.Value, r) }) {
+			out = append(out, v)
+		}
+	}
+	for _, a := range add {
+		if !slices.ContainsFunc(out, func(v LabeledValue[string]) bool { return match(v.Value, a.Value) }) {
+			out = append(out, LabeledValue[string]{Label: a.Label, Value: a.Value})
+		}
+	}
+	return out
+}
+
+func emailAddressesMatch(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+func validateListPatch(input UpdateContactInput) error {
+	if input.EmailAddresses != nil && (len(input.AddEmailAddresses) > 0 || len(input.RemoveEmailAddresses) > 0) {
+		return fmt.Errorf("emailAddresses cannot be combined with addEmailAddresses or removeEmailAddresses")
+	}
+	if input.PhoneNumbers != nil && (len(input.AddPhoneNumbers) > 0 || len(input.RemovePhoneNumbers) > 0) {
+		return fmt.Errorf("phoneNumbers cannot be combined with addPhoneNumbers or removePhoneNumbers")
+	}
+	for i, v := range input.RemoveEmailAddresses {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("removeEmailAddresses[%d] is empty", i)
+		}
+	}
+	for i, v := range input.RemovePhoneNumbers {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("removePhoneNumbers[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+func verifyListPatch(updated Contact, input UpdateContactInput) error {
+	hasValue := func(values []LabeledValue[string], want string, match func(a, b string) bool) bool {
+		return slices.ContainsFunc(values, func(v LabeledValue[string]) bool { return match(v.Value, want) })
+	}
+	for _, v := range input.RemoveEmailAddresses {
+		if hasValue(updated.EmailAddresses, v, emailAddressesMatch) && !hasValue(input.AddEmailAddresses, v, emailAddressesMatch) {
+			return fmt.Errorf("email %q was not removed", v)
+		}
+	}
+	for _, v := range input.AddEmailAddresses {
+		if !hasValue(updated.EmailAddresses, v.Value, emailAddressesMatch) {
+			return fmt.Errorf("email %q was not added", v.Value)
+		}
+	}
+	for _, v := range input.RemovePhoneNumbers {
+		if hasValue(updated.PhoneNumbers, v, PhoneNumbersMatch) && !hasValue(input.AddPhoneNumbers, v, PhoneNumbersMatch) {
+			return fmt.Errorf("phone %q was not removed", v)
+		}
+	}
+	for _, v := range input.AddPhoneNumbers {
+		if !hasValue(updated.PhoneNumbers, v.Value, PhoneNumbersMatch) {
+			return fmt.Errorf("phone %q was not added", v.Value)
+		}
+	}
+	return nil
 }
 
 func verifyUpdatedContact(updated Contact, input UpdateContactInput) error {
@@ -697,30 +1117,179 @@ func verifyUpdatedContact(updated Contact, input UpdateContactInput) error {
 	if input.EmailAddresses != nil && len(updated.EmailAddresses) != len(*input.EmailAddresses) {
 		return fmt.Errorf("emailAddresses length mismatch")
 	}
-	if input.PostalAddresses != nil && len(updated.PostalAddresses) != len(*input.PostalAddresses) {
-		return fmt.Errorf("postalAddresses length mismatch")
+	if input.PostalAddresses != nil {
+		if len(updated.PostalAddresses) != len(*input.PostalAddresses) {
+			return fmt.Errorf("postalAddresses length mismatch")
+		}
+		for i, want := range *input.PostalAddresses {
+			if !postalAddressMatches(updated.PostalAddresses[i].Value, want.Value) {
+				return fmt.Errorf("postalAddresses[%d] mismatch", i)
+			}
+		}
 	}
-	if input.URLAddresses != nil && len(updated.URLAddresses) != len(*input.URLAddresses) {
-		return fmt.Errorf("urlAddresses length mismatch")
+	if input.URLAddresses != nil {
+		if len(updated.URLAddresses) != len(*input.URLAddresses) {
+			return fmt.Errorf("urlAddresses length mismatch")
+		}
+		for i, want := range *input.URLAddresses {
+			if strings.TrimSpace(updated.URLAddresses[i].Value) != strings.TrimSpace(want.Value) {
+				return fmt.Errorf("urlAddresses[%d] mismatch", i)
+			}
+		}
 	}
 	if input.ContactRelations != nil && len(updated.ContactRelations) != len(*input.ContactRelations) {
 		return fmt.Errorf("contactRelations length mismatch")
 	}
-	if input.SocialProfiles != nil && len(updated.SocialProfiles) != len(*input.SocialProfiles) {
-		return fmt.Errorf("socialProfiles length mismatch")
+	if input.SocialProfiles != nil {
+		if len(updated.SocialProfiles) != len(*input.SocialProfiles) {
+			return fmt.Errorf("socialProfiles length mismatch")
+		}
+		for i, lv := range *input.SocialProfiles {
+			got, want := updated.SocialProfiles[i].Value, lv.Value
+			if want.Username != "" && !strings.EqualFold(got.Username, want.Username) {
+				return fmt.Errorf("socialProfiles[%d] username mismatch", i)
+			}
+			if want.Service != "" && !strings.EqualFold(got.Service, want.Service) {
+				return fmt.Errorf("socialProfiles[%d] service mismatch", i)
+			}
+		}
 	}
-	if input.InstantMessages != nil && len(updated.InstantMessages) != len(*input.InstantMessages) {
-		return fmt.Errorf("instantMessages length mismatch")
+	if input.InstantMessages != nil {
+		if len(updated.InstantMessages) != len(*input.InstantMessages) {
+			return fmt.Errorf("instantMessages length mismatch")
+		}
+		for i, lv := range *input.InstantMessages {
+			got, want := updated.InstantMessages[i].Value, lv.Value
+			if !strings.EqualFold(got.Username, want.Username) {
+				return fmt.Errorf("instantMessages[%d] username mismatch", i)
+			}
+			if want.Service != "" && !strings.EqualFold(got.Service, want.Service) {
+				return fmt.Errorf("instantMessages[%d] service mismatch", i)
+			}
+		}
 	}
-	if input.Dates != nil && len(updated.Dates) != len(*input.Dates) {
-		return fmt.Errorf("dates length mismatch")
+	if input.Dates != nil {
+		if len(updated.Dates) != len(*input.Dates) {
+			return fmt.Errorf("dates length mismatch")
+		}
+		for i, want := range *input.Dates {
+			if updated.Dates[i].Value != want.Value {
+				return fmt.Errorf("dates[%d] mismatch", i)
+			}
+		}
 	}
 	if input.ImageData != nil && len(updated.ImageData) != len(*input.ImageData) {
 		return fmt.Errorf("imageData length mismatch")
 	}
+	if input.ClearImageData && updated.ImageDataAvailable {
+		return fmt.Errorf("imageData was not cleared")
+	}
+	return nil
+}
+
+// postalAddressMatches reports whether got persisted the caller-provided
+// address lines. ISOCountryCode is ignored because Contacts.framework may derive
+// it from Country.
+func postalAddressMatches(got, want PostalAddress) bool {
+	same := func(a, b string) bool { return strings.TrimSpace(a) == strings.TrimSpace(b) }
+	return same(got.Street, want.Street) &&
+		same(got.City, want.City) &&
+		same(got.State, want.State) &&
+		same(got.PostalCode, want.PostalCode) &&
+		same(got.Country, want.Country)
+}
+
+func validateDateComponents(d DateComponents) error {
+	if d.Year == 0 && d.Month == 0 && d.Day == 0 {
+		return fmt.Errorf("at least one of year, month, or day must be set")
+	}
+	if d.Year < 0 {
+		return fmt.Errorf("year %d must be >= 0", d.Year)
+	}
+	if d.Month < 0 || d.Month > 12 {
+		return fmt.Errorf("month %d must be within 1-12", d.Month)
+	}
+	if d.Day < 0 || d.Day > 31 {
+		return fmt.Errorf("day %d must be within 1-31", d.Day)
+	}
+	return nil
+}
+
+// validateContactValues checks caller-provided field values that
+// Contacts.framework would otherwise accept silently or reject with an opaque
+// store error.
+func validateContactValues(c Contact) error {
+	if c.ContactType != ContactTypePerson && c.ContactType != ContactTypeOrganization {
+		return fmt.Errorf("contactType %d is invalid", int(c.ContactType))
+	}
+	if c.Birthday != nil {
+		if err := validateDateComponents(*c.Birthday); err != nil {
+			return fmt.Errorf("birthday: %v", err)
+		}
+	}
+	for i, d := range c.Dates {
+		if err := validateDateComponents(d.Value); err != nil {
+			return fmt.Errorf("dates[%d]: %v", i, err)
+		}
+	}
+	for i, p := range c.PhoneNumbers {
+		if strings.TrimSpace(p.Value) == "" {
+			return fmt.Errorf("phoneNumbers[%d] value is required", i)
+		}
+	}
+	for i, e := range c.EmailAddresses {
+		if strings.TrimSpace(e.Value) == "" {
+			return fmt.Errorf("emailAddresses[%d] value is required", i)
+		}
+	}
+	for i, u := range c.URLAddresses {
+		if strings.TrimSpace(u.Value) == "" {
+			return fmt.Errorf("urlAddresses[%d] value is required", i)
+		}
+	}
+	for i, r := range c.ContactRelations {
+		if strings.TrimSpace(r.Value.Name) == "" {
+			return fmt.Errorf("contactRelations[%d] name is required", i)
+		}
+	}
+	for i, p := range c.SocialProfiles {
+		if strings.TrimSpace(p.Value.Username) == "" && strings.TrimSpace(p.Value.URLString) == "" {
+			return fmt.Errorf("socialProfiles[%d] requires username or urlString", i)
+		}
+	}
+	for i, im := range c.InstantMessages {
+		if strings.TrimSpace(im.Value.Username) == "" {
+			return fmt.Errorf("instantMessages[%d] username is required", i)
+		}
+	}
+	if len(c.ImageData) > 0 && !isContactImage(c.ImageData) {
+		return errors.New("imageData must be JPEG, PNG, GIF, TIFF, or HEIC/HEIF image bytes")
+	}
 	return nil
 }
 
+// heifBrands are the ISO BMFF major brands of HEIC/HEIF still images, which
+// is how iPhones store photos.
+var heifBrands = []string{"heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1"}
+
+// isContactImage reports whether data starts with the signature of an image
+// format Contacts.framework stores as a contact photo.
+func isContactImage(data []byte) bool {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}),
+		bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")),
+		bytes.HasPrefix(data, []byte("GIF87a")),
+		bytes.HasPrefix(data, []byte("GIF89a")),
+		bytes.HasPrefix(data, []byte("II*\x00")),
+		bytes.HasPrefix(data, []byte("MM\x00*")):
+		return true
+	}
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		return slices.Contains(heifBrands, string(data[8:12]))
+	}
+	return false
+}
+
 func hasUpdateGroupChanges(input UpdateGroupInput) bool {
 	return input.Name != nil || input.ParentGroupID != nil
 }
@@ -750,6 +1319,57 @@ func CheckAuthorization(_ context.Context) AuthorizationStatus {
 	return AuthorizationStatus(checkAuthorizationStatus())
 }
 
+// CanAccess reports whether contacts can be read, with full or limited
+// access.
+func (s AuthorizationStatus) CanAccess() bool {
+	return s == AuthorizationStatusAuthorized || s == AuthorizationStatusLimited
+}
+
+// AuthorizedContactIDs returns the identifiers of the non-unified contacts
+// visible to this process. Under [AuthorizationStatusLimited] these are the
+// contacts the user selected; under full access it is every contact.
+func AuthorizedContactIDs(ctx context.Context) ([]string, error) {
+	status := CheckAuthorization(ctx)
+	if !status.CanAccess() {
+		return nil, &OpError{Op: "AuthorizedContactIDs", Err: fmt.Errorf("%w: authorization status is %s", ErrPermissionDenied, status)}
+	}
+	ids := make([]string, 0)
+	for c, err := range StreamContacts(ctx, []Filter{{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"}}) {
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, c.Identifier)
+	}
+	return ids, nil
+}
+
+// contactsPrivacySettingsURL opens Privacy & Security > Contacts in System
+// Settings.
+const contactsPrivacySettingsURL = "x-apple.systempreferences:com.apple.preference.security?Privacy_Contacts"
+
+// OpenAccessSettings opens the Contacts pane of Privacy & Security in System
+// Settings, where the user can grant full access or change which contacts
+// are shared. It returns once System Settings has been asked to open; it does
+// not wait for the user.
+//
+// Contacts.framework's limited-access picker (ContactAccessPicker) is a
+// SwiftUI view with no AppKit or command-line entry point, so this is the
+// closest a cgo caller can get. Call [CheckAuthorization] afterwards to see
+// the new status.
+func OpenAccessSettings(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	out, err := exec.CommandContext(ctx, "open", contactsPrivacySettingsURL).CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		return &OpError{Op: "OpenAccessSettings", Err: fmt.Errorf("%w: open failed: %s (output: %s)", ErrUnsupported, err, strings.TrimSpace(string(out)))}
+	}
+	return nil
+}
+
 // RequestAuthorization requests access to contacts from the user.
 func RequestAuthorization(ctx context.Context) (AuthorizationStatus, error) {
 	if err := ctx.Err(); err != nil {
@@ -778,6 +1398,59 @@ func GetContact(ctx context.Context, identifier string) (Contact, error) {
 	return c, nil
 }
 
+// GetMeContact returns the user's own card (the "My Card" in Contacts.app) as
+// a unified projection. It fails with [ErrNotFound] when no card is set.
+//
+// To recognize the user in [ListContacts] results, compare with
+// [Contact.SameAs].
+func GetMeContact(ctx context.Context) (Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return Contact{}, err
+	}
+	c, errStr := getMeContact()
+	if errStr != "" {
+		return Contact{}, newBridgeOpError("GetMeContact", "", errStr)
+	}
+	return c, nil
+}
+
+// GetContactNote returns the note of the unified contact with the given
+// identifier. Contact fetches omit the note, so this is the only way to read
+// it. Without the notes entitlement it fails with [ErrNoteEntitlementMissing];
+// callers that can do without the note should treat that as an empty note.
+func GetContactNote(ctx context.Context, identifier string) (string, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return "", newInvalidArg("GetContactNote", "", "identifier is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	note, errStr := getContactNote(identifier)
+	if errStr != "" {
+		return "", newBridgeOpError("GetContactNote", identifier, errStr)
+	}
+	return note, nil
+}
+
+// GetConstituentContact fetches a single constituent record (`Unified=false`)
+// without merging in linked cards. Use it with the LinkedIDs of a unified
+// contact to read one account's copy before targeting it with a mutation.
+func GetConstituentContact(ctx context.Context, identifier string) (Contact, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return Contact{}, newInvalidArg("GetConstituentContact", "", "identifier is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return Contact{}, err
+	}
+	c, errStr := getConstituentContact(identifier)
+	if errStr != "" {
+		return Contact{}, newBridgeOpError("GetConstituentContact", identifier, errStr)
+	}
+	return c, nil
+}
+
 // ResolveContactIdentity resolves identifier semantics without hydrating full
 // contact fields.
 func ResolveContactIdentity(ctx context.Context, identifier string) (ContactIdentity, error) {
@@ -835,23 +1508,48 @@ func ListContacts(ctx context.Context, input ListContactsInput) iter.Seq2[Contac
 			yield(Contact{}, &OpError{Op: "ListContacts", Err: err})
 			return
 		}
+		var after *contactSortKey
+		if input.After != "" {
+			k, err := parseContactCursor(input.After)
+			if err != nil {
+				yield(Contact{}, newInvalidArg("ListContacts", "", err.Error()))
+				return
+			}
+			after = &k
+		}
 		if err := ctx.Err(); err != nil {
 			yield(Contact{}, err)
 			return
 		}
+		filters, err := resolveGroupNameFilters(ctx, input.Filters)
+		if err != nil {
+			yield(Contact{}, err)
+			return
+		}
 
-		contacts, errStr := listContacts(input.Filters)
+		contacts, errStr := listContacts(ctx, filters)
+		if err := ctx.Err(); err != nil {
+			yield(Contact{}, err)
+			return
+		}
 		if errStr != "" {
 			yield(Contact{}, newBridgeOpError("ListContacts", "", errStr))
 			return
 		}
 
+		slices.SortStableFunc(contacts, func(a, b Contact) int {
+			return compareSortKeys(sortKeyOf(a), sortKeyOf(b))
+		})
+
 		skipped := 0
 		for _, c := range contacts {
 			if err := ctx.Err(); err != nil {
 				yield(Contact{}, err)
 				return
 			}
+			if after != nil && compareSortKeys(sortKeyOf(c), *after) <= 0 {
+				continue
+			}
 			if skipped < input.Offset {
 				skipped++
 				continue
@@ -864,10 +1562,49 @@ func ListContacts(ctx context.Context, input ListContactsInput) iter.Seq2[Contac
 }
 
 // CreateContact creates a new contact and returns the created record.
+//
+// With input.DryRun set, nothing is saved and the would-be contact is returned.
 func CreateContact(ctx context.Context, input CreateContactInput) (Contact, error) {
+	if err := validateContactValues(input.Contact); err != nil {
+		return Contact{}, newInvalidArg("CreateContact", "", err.Error())
+	}
 	if err := ctx.Err(); err != nil {
 		return Contact{}, err
 	}
+	if input.DryRun {
+		return previewCreateContact(ctx, input)
+	}
+	identifier, errStr := createContact(input)
+	if errStr != "" && input.Contact.Note != "" && isNoteEntitlementError(errStr) {
+		return createContactWithNoteFallback(ctx, input)
+	}
+	if errStr != "" {
+		return Contact{}, newBridgeOpError("CreateContact", "", errStr)
+	}
+	if identifier == "" {
+		return Contact{}, newVerificationError("CreateContact", "", "bridge returned empty identifier")
+	}
+	created, err := GetContact(ctx, identifier)
+	if err != nil {
+		return Contact{}, err
+	}
+	return created, nil
+}
+
+// isNoteEntitlementError reports whether a bridge call failed because the note
+// field was touched without the notes entitlement. The bridge tags such
+// failures with Cocoa error 134092.
+func isNoteEntitlementError(errStr string) bool {
+	return strings.Contains(errStr, "134092")
+}
+
+// createContactWithNoteFallback creates the contact without its note, then
+// writes and verifies the note through AppleScript. If the note cannot be
+// applied, the partially created contact is deleted so callers never observe
+// a contact missing the requested note.
+func createContactWithNoteFallback(ctx context.Context, input CreateContactInput) (Contact, error) {
+	note := input.Contact.Note
+	input.Contact.Note = ""
 	identifier, errStr := createContact(input)
 	if errStr != "" {
 		return Contact{}, newBridgeOpError("CreateContact", "", errStr)
@@ -875,52 +1612,287 @@ func CreateContact(ctx context.Context, input CreateContactInput) (Contact, erro
 	if identifier == "" {
 		return Contact{}, newVerificationError("CreateContact", "", "bridge returned empty identifier")
 	}
+	saved, err := setContactNoteViaOSAScript(ctx, identifier, note)
+	if err == nil && saved != note {
+		err = fmt.Errorf("%w: note mismatch after osascript write", ErrVerificationFailed)
+	}
+	if err != nil {
+		_ = deleteContact(identifier)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Contact{}, ctxErr
+		}
+		return Contact{}, &OpError{Op: "CreateContact", ID: identifier, Err: err}
+	}
 	created, err := GetContact(ctx, identifier)
 	if err != nil {
 		return Contact{}, err
 	}
+	// Fetches omit the note key, so report the verified value.
+	created.Note = note
 	return created, nil
 }
 
+// setContactNoteViaOSAScript sets a contact's note with the Contacts
+// AppleScript dictionary, which is not subject to the notes entitlement, and
+// returns the note as read back after saving. Values are passed as script
+// arguments so no escaping is needed.
+func setContactNoteViaOSAScript(ctx context.Context, contactID, note string) (string, error) {
+	const script = `on run argv
+	tell application "Contacts"
+		set thePerson to first person whose id is (item 1 of argv)
+		set note of thePerson to (item 2 of argv)
+		save
+		return note of thePerson
+	end tell
+end run`
+
+	cmd := exec.CommandContext(ctx, "osascript", "-e", script, contactID, note)
+	out, err := cmd.Output()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("osascript set note failed: %s (output: %s)", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("osascript set note failed: %s", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// previewCreateContact resolves the destination container and returns the
+// contact CreateContact would persist.
+func previewCreateContact(ctx context.Context, input CreateContactInput) (Contact, error) {
+	preview := input.Contact
+	preview.ContainerID = strings.TrimSpace(preview.ContainerID)
+	if preview.ContainerID == "" {
+		id, err := DefaultContainerID(ctx)
+		if err != nil {
+			return Contact{}, err
+		}
+		preview.ContainerID = id
+	} else if _, err := GetContainer(ctx, preview.ContainerID); err != nil {
+		return Contact{}, err
+	}
+	preview.Identifier = ""
+	preview.Unified = false
+	preview.LinkedIDs = nil
+	preview.ImageDataAvailable = len(preview.ImageData) > 0
+	preview.ThumbnailImageData = nil
+	return preview, nil
+}
+
+// CreateContactResult is the per-item outcome of [CreateContacts].
+type CreateContactResult struct {
+	Input   CreateContactInput `json:"input"`
+	Created Contact            `json:"created"`
+	Err     error              `json:"-"`
+}
+
+// CreateContacts creates many contacts, saving them in a single CNSaveRequest
+// instead of one store round-trip per contact.
+//
+// Results are returned in input order. Inputs that fail validation get an
+// ErrInvalidArgument result and are excluded from the batch; inputs with
+// DryRun set are previewed individually. CNSaveRequest is atomic, so if the
+// batched save fails each remaining input is retried on its own to attribute
+// the failure to the offending items. Every created contact is read back for
+// verification. The returned error is non-nil only when ctx is done before
+// the batch is saved.
+func CreateContacts(ctx context.Context, inputs []CreateContactInput) ([]CreateContactResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	results := make([]CreateContactResult, len(inputs))
+	batch := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		results[i].Input = input
+		if input.DryRun {
+			results[i].Created, results[i].Err = CreateContact(ctx, input)
+			continue
+		}
+		if err := validateContactValues(input.Contact); err != nil {
+			results[i].Err = newInvalidArg("CreateContacts", "", fmt.Sprintf("inputs[%d]: %v", i, err))
+			continue
+		}
+		batch = append(batch, i)
+	}
+	if len(batch) == 0 {
+		return results, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	batchInputs := make([]CreateContactInput, len(batch))
+	for j, i := range batch {
+		batchInputs[j] = inputs[i]
+	}
+	ids, errStr := createContacts(batchInputs)
+	if errStr != "" {
+		for _, i := range batch {
+			results[i].Created, results[i].Err = CreateContact(ctx, inputs[i])
+		}
+		return results, nil
+	}
+
+	for j, i := range batch {
+		if ids[j] == "" {
+			results[i].Err = newVerificationError("CreateContacts", "", "bridge returned empty identifier")
+			continue
+		}
+		results[i].Created, results[i].Err = GetContact(ctx, ids[j])
+	}
+	return results, nil
+}
+
 // UpdateContact updates mutable contact fields and verifies persistence.
 // Unified identifiers are rejected with ErrUnifiedContactNotMutable.
+//
+// With input.DryRun set, nothing is saved and the merged contact is returned.
 func UpdateContact(ctx context.Context, input UpdateContactInput) (Contact, error) {
 	input.Identifier = strings.TrimSpace(input.Identifier)
+	merged, err := prepareContactUpdate(ctx, "UpdateContact", input)
+	if err != nil {
+		return Contact{}, err
+	}
+	if input.DryRun {
+		return merged, nil
+	}
+
+	if errStr := updateContact(merged, input); errStr != "" {
+		return Contact{}, newBridgeOpError("UpdateContact", input.Identifier, errStr)
+	}
+	return verifyContactUpdate("UpdateContact", input)
+}
+
+// prepareContactUpdate validates input, which must have a trimmed
+// Identifier, and returns the stored contact with the patch applied.
+func prepareContactUpdate(ctx context.Context, op string, input UpdateContactInput) (Contact, error) {
 	if input.Identifier == "" {
-		return Contact{}, newInvalidArg("UpdateContact", "", "identifier is required")
+		return Contact{}, newInvalidArg(op, "", "identifier is required")
 	}
 	if !hasUpdateContactChanges(input) {
-		return Contact{}, newInvalidArg("UpdateContact", input.Identifier, "at least one field must be set")
+		return Contact{}, newInvalidArg(op, input.Identifier, "at least one field must be set")
+	}
+	if input.ClearImageData && input.ImageData != nil && len(*input.ImageData) > 0 {
+		return Contact{}, newInvalidArg(op, input.Identifier, "imageData and clearImageData are mutually exclusive")
+	}
+	if err := validateListPatch(input); err != nil {
+		return Contact{}, newInvalidArg(op, input.Identifier, err.Error())
+	}
+	patch := mergeContactPatch(Contact{ContactType: ContactTypePerson}, input)
+	if err := validateContactValues(patch); err != nil {
+		return Contact{}, newInvalidArg(op, input.Identifier, err.Error())
 	}
 	if err := ctx.Err(); err != nil {
 		return Contact{}, err
 	}
-	if _, err := ensureNonUnifiedContactIdentity(ctx, "UpdateContact", input.Identifier); err != nil {
+	if _, err := ensureNonUnifiedContactIdentity(ctx, op, input.Identifier); err != nil {
 		return Contact{}, err
 	}
 
 	current, errStr := getConstituentContact(input.Identifier)
 	if errStr != "" {
-		return Contact{}, newBridgeOpError("UpdateContact", input.Identifier, errStr)
+		return Contact{}, newBridgeOpError(op, input.Identifier, errStr)
 	}
 	merged := mergeContactPatch(current, input)
 	merged.Identifier = input.Identifier
 	merged.Unified = false
 	merged.LinkedIDs = nil
-
-	if errStr := updateContact(merged); errStr != "" {
-		return Contact{}, newBridgeOpError("UpdateContact", input.Identifier, errStr)
+	if input.DryRun {
+		merged.ImageDataAvailable = len(merged.ImageData) > 0
 	}
+	return merged, nil
+}
+
+// verifyContactUpdate reads the contact back after a save and checks that
+// every field in input was persisted.
+func verifyContactUpdate(op string, input UpdateContactInput) (Contact, error) {
 	updated, errStr := getConstituentContact(input.Identifier)
 	if errStr != "" {
-		return Contact{}, newBridgeOpError("UpdateContact", input.Identifier, errStr)
+		return Contact{}, newBridgeOpError(op, input.Identifier, errStr)
 	}
 	if err := verifyUpdatedContact(updated, input); err != nil {
-		return Contact{}, newVerificationError("UpdateContact", input.Identifier, err.Error())
+		return Contact{}, newVerificationError(op, input.Identifier, err.Error())
+	}
+	if err := verifyListPatch(updated, input); err != nil {
+		return Contact{}, newVerificationError(op, input.Identifier, err.Error())
 	}
 	return updated, nil
 }
 
+// UpdateContactResult is the per-item outcome of [UpdateContacts].
+type UpdateContactResult struct {
+	Input   UpdateContactInput `json:"input"`
+	Updated Contact            `json:"updated"`
+	Err     error              `json:"-"`
+}
+
+// UpdateContacts updates many contacts, saving them in a single CNSaveRequest
+// instead of one store round-trip per contact.
+//
+// Results are returned in input order. Each input is validated and merged as
+// in [UpdateContact]; inputs that fail get their error as the result and are
+// excluded from the batch, and inputs with DryRun set are previewed
+// individually. An identifier that repeats an earlier input is saved after the
+// batch, so both patches apply. If the batched save fails each remaining
+// input is retried on its own to attribute the failure. The returned error is
+// non-nil only when ctx is done before the batch is saved.
+func UpdateContacts(ctx context.Context, inputs []UpdateContactInput) ([]UpdateContactResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	results := make([]UpdateContactResult, len(inputs))
+	var (
+		batch   []int
+		merged  []Contact
+		patches []UpdateContactInput
+		later   []int
+	)
+	for i, input := range inputs {
+		input.Identifier = strings.TrimSpace(input.Identifier)
+		results[i].Input = input
+		if input.DryRun {
+			results[i].Updated, results[i].Err = UpdateContact(ctx, input)
+			continue
+		}
+		if slices.ContainsFunc(patches, func(p UpdateContactInput) bool { return p.Identifier == input.Identifier }) {
+			later = append(later, i)
+			continue
+		}
+		c, err := prepareContactUpdate(ctx, "UpdateContacts", input)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			results[i].Err = err
+			continue
+		}
+		batch = append(batch, i)
+		merged = append(merged, c)
+		patches = append(patches, input)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if errStr := updateContacts(merged, patches); errStr != "" {
+		for _, i := range batch {
+			results[i].Updated, results[i].Err = UpdateContact(ctx, results[i].Input)
+		}
+	} else {
+		for j, i := range batch {
+			results[i].Updated, results[i].Err = verifyContactUpdate("UpdateContacts", patches[j])
+		}
+	}
+	for _, i := range later {
+		results[i].Updated, results[i].Err = UpdateContact(ctx, results[i].Input)
+	}
+	return results, nil
+}
+
 // DeleteContact deletes the contact with the given identifier.
 // Unified identifiers are rejected with ErrUnifiedContactNotMutable.
 func DeleteContact(ctx context.Context, identifier string) error {
@@ -1000,14 +1972,106 @@ func ListSubgroups(ctx context.Context, parentGroupID string) ([]Group, error) {
 	return out, nil
 }
 
+// FindGroupByName returns the group named name, compared case-insensitively
+// after trimming spaces. An empty containerID searches every container.
+//
+// It returns [ErrNotFound] when no group matches and [ErrAmbiguous] when more
+// than one does.
+func FindGroupByName(ctx context.Context, name, containerID string) (Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Group{}, newInvalidArg("FindGroupByName", "", "name is required")
+	}
+	groups, err := ListGroups(ctx, ListGroupsInput{ContainerID: containerID, IncludeHierarchy: true})
+	if err != nil {
+		return Group{}, err
+	}
+	matches := groupsNamed(groups, name, "")
+	switch len(matches) {
+	case 0:
+		return Group{}, &OpError{Op: "FindGroupByName", ID: name, Err: fmt.Errorf("%w: group %q not found", ErrNotFound, name)}
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, g := range matches {
+			ids[i] = g.Identifier
+		}
+		return Group{}, &OpError{Op: "FindGroupByName", ID: name, Err: fmt.Errorf("%w: %d groups named %q: %s", ErrAmbiguous, len(matches), name, strings.Join(ids, ", "))}
+	}
+}
+
+// groupsNamed returns the groups whose name matches name, skipping the group
+// identified by exclude.
+func groupsNamed(groups []Group, name, exclude string) []Group {
+	name = strings.TrimSpace(name)
+	var out []Group
+	for _, g := range groups {
+		if g.Identifier != exclude && strings.EqualFold(strings.TrimSpace(g.Name), name) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// duplicateGroup returns the first group in containerID, other than exclude,
+// named name, or false when there is none.
+func duplicateGroup(ctx context.Context, name, containerID, exclude string) (Group, bool, error) {
+	groups, err := ListGroups(ctx, ListGroupsInput{ContainerID: containerID, IncludeHierarchy: true})
+	if err != nil {
+		return Group{}, false, err
+	}
+	matches := groupsNamed(groups, name, exclude)
+	if len(matches) == 0 {
+		return Group{}, false, nil
+	}
+	return matches[0], true, nil
+}
+
+func groupConflictError(op string, existing Group) error {
+	return &OpError{Op: op, ID: existing.Identifier, Err: fmt.Errorf("%w: group %q already exists in container %q", ErrConflict, existing.Name, existing.ContainerID)}
+}
+
 // CreateGroup creates a new group and verifies the resulting state.
+//
+// With input.DryRun set, nothing is saved and the would-be group is returned.
+// input.OnDuplicateName decides whether an existing group with the same name
+// in the target container is an error, is returned instead, or is ignored.
 func CreateGroup(ctx context.Context, input CreateGroupInput) (Group, error) {
 	if strings.TrimSpace(input.Name) == "" {
 		return Group{}, newInvalidArg("CreateGroup", "", "group name is required")
 	}
+	switch input.OnDuplicateName {
+	case DuplicateGroupNameAllow, DuplicateGroupNameFail, DuplicateGroupNameReuse:
+	default:
+		return Group{}, newInvalidArg("CreateGroup", "", fmt.Sprintf("unsupported onDuplicateName %q", input.OnDuplicateName))
+	}
 	if err := ctx.Err(); err != nil {
 		return Group{}, err
 	}
+	if input.OnDuplicateName != DuplicateGroupNameAllow {
+		containerID := strings.TrimSpace(input.ContainerID)
+		if containerID == "" {
+			id, err := DefaultContainerID(ctx)
+			if err != nil {
+				return Group{}, err
+			}
+			containerID = id
+		}
+		existing, ok, err := duplicateGroup(ctx, input.Name, containerID, "")
+		if err != nil {
+			return Group{}, err
+		}
+		if ok {
+			if input.OnDuplicateName == DuplicateGroupNameFail {
+				return Group{}, groupConflictError("CreateGroup", existing)
+			}
+			return existing, nil
+		}
+	}
+	if input.DryRun {
+		return previewCreateGroup(ctx, input)
+	}
 	identifier, errStr := createGroup(input)
 	if errStr != "" {
 		return Group{}, newBridgeOpError("CreateGroup", "", errStr)
@@ -1025,7 +2089,32 @@ func CreateGroup(ctx context.Context, input CreateGroupInput) (Group, error) {
 	return created, nil
 }
 
+func previewCreateGroup(ctx context.Context, input CreateGroupInput) (Group, error) {
+	preview := Group{
+		Name:          input.Name,
+		ContainerID:   strings.TrimSpace(input.ContainerID),
+		ParentGroupID: strings.TrimSpace(input.ParentGroupID),
+	}
+	if preview.ContainerID == "" {
+		id, err := DefaultContainerID(ctx)
+		if err != nil {
+			return Group{}, err
+		}
+		preview.ContainerID = id
+	} else if _, err := GetContainer(ctx, preview.ContainerID); err != nil {
+		return Group{}, err
+	}
+	if preview.ParentGroupID != "" {
+		if _, err := GetGroup(ctx, preview.ParentGroupID); err != nil {
+			return Group{}, err
+		}
+	}
+	return preview, nil
+}
+
 // UpdateGroup updates mutable group fields and verifies persistence.
+//
+// With input.DryRun set, nothing is saved and the updated group is returned.
 func UpdateGroup(ctx context.Context, input UpdateGroupInput) (Group, error) {
 	input.Identifier = strings.TrimSpace(input.Identifier)
 	if input.Identifier == "" {
@@ -1037,9 +2126,30 @@ func UpdateGroup(ctx context.Context, input UpdateGroupInput) (Group, error) {
 	if input.ParentGroupID != nil && *input.ParentGroupID == input.Identifier {
 		return Group{}, newInvalidArg("UpdateGroup", input.Identifier, "parentGroupID cannot equal identifier")
 	}
+	switch input.OnDuplicateName {
+	case DuplicateGroupNameAllow, DuplicateGroupNameFail:
+	default:
+		return Group{}, newInvalidArg("UpdateGroup", input.Identifier, fmt.Sprintf("unsupported onDuplicateName %q", input.OnDuplicateName))
+	}
 	if err := ctx.Err(); err != nil {
 		return Group{}, err
 	}
+	if input.Name != nil && input.OnDuplicateName == DuplicateGroupNameFail {
+		current, err := GetGroup(ctx, input.Identifier)
+		if err != nil {
+			return Group{}, err
+		}
+		existing, ok, err := duplicateGroup(ctx, *input.Name, current.ContainerID, current.Identifier)
+		if err != nil {
+			return Group{}, err
+		}
+		if ok {
+			return Group{}, groupConflictError("UpdateGroup", existing)
+		}
+	}
+	if input.DryRun {
+		return previewUpdateGroup(ctx, input)
+	}
 	if errStr := updateGroup(input.Identifier, input.Name, input.ParentGroupID); errStr != "" {
 		return Group{}, newBridgeOpError("UpdateGroup", input.Identifier, errStr)
 	}
@@ -1053,15 +2163,41 @@ func UpdateGroup(ctx context.Context, input UpdateGroupInput) (Group, error) {
 	return updated, nil
 }
 
+func previewUpdateGroup(ctx context.Context, input UpdateGroupInput) (Group, error) {
+	preview, err := GetGroup(ctx, input.Identifier)
+	if err != nil {
+		return Group{}, err
+	}
+	if input.Name != nil {
+		preview.Name = *input.Name
+	}
+	if input.ParentGroupID != nil {
+		parentID := strings.TrimSpace(*input.ParentGroupID)
+		if parentID != "" {
+			if _, err := GetGroup(ctx, parentID); err != nil {
+				return Group{}, err
+			}
+		}
+		preview.ParentGroupID = parentID
+	}
+	return preview, nil
+}
+
 // DeleteGroup deletes the group with the given identifier.
-func DeleteGroup(ctx context.Context, identifier string) error {
-	identifier = strings.TrimSpace(identifier)
+//
+// With input.DryRun set, the group is resolved and nothing is deleted.
+func DeleteGroup(ctx context.Context, input DeleteGroupInput) error {
+	identifier := strings.TrimSpace(input.Identifier)
 	if identifier == "" {
 		return newInvalidArg("DeleteGroup", "", "identifier is required")
 	}
 	if err := ctx.Err(); err != nil {
 		return err
 	}
+	if input.DryRun {
+		_, err := GetGroup(ctx, identifier)
+		return err
+	}
 	if errStr := deleteGroup(identifier); errStr != "" {
 		return newBridgeOpError("DeleteGroup", identifier, errStr)
 	}
@@ -1078,31 +2214,13 @@ func DeleteGroup(ctx context.Context, identifier string) error {
 // AddContactToGroup adds a contact to a group and verifies membership.
 //
 // Membership is record/container scoped. Unified identifiers are rejected with
-// ErrUnifiedContactNotMutable.
-func AddContactToGroup(ctx context.Context, contactID, groupID string) error {
-	contactID = strings.TrimSpace(contactID)
-	groupID = strings.TrimSpace(groupID)
-	if contactID == "" || groupID == "" {
-		return newInvalidArg("AddContactToGroup", "", "contactID and groupID are required")
-	}
-	if err := ctx.Err(); err != nil {
+// ErrUnifiedContactNotMutable. With input.DryRun set, the contact and group
+// are validated and membership is left unchanged.
+func AddContactToGroup(ctx context.Context, input GroupMembershipInput) error {
+	contactID, groupID, err := resolveGroupMembership(ctx, "AddContactToGroup", input)
+	if err != nil || input.DryRun {
 		return err
 	}
-	identity, err := ensureNonUnifiedContactIdentity(ctx, "AddContactToGroup", contactID)
-	if err != nil {
-		return err
-	}
-	group, err := GetGroup(ctx, groupID)
-	if err != nil {
-		return err
-	}
-	if group.ContainerID != "" && len(identity.ContainerIDs) > 0 && !hasContainerIntersection(identity.ContainerIDs, group.ContainerID) {
-		return &OpError{
-			Op:  "AddContactToGroup",
-			ID:  groupID,
-			Err: fmt.Errorf("%w: contact containers %v do not include group container %q", ErrGroupContainerMismatch, identity.ContainerIDs, group.ContainerID),
-		}
-	}
 	if errStr := addContactToGroup(contactID, groupID); errStr != "" {
 		return newBridgeOpError("AddContactToGroup", groupID, errStr)
 	}
@@ -1119,46 +2237,61 @@ func AddContactToGroup(ctx context.Context, contactID, groupID string) error {
 // RemoveContactFromGroup removes a contact from a group.
 //
 // Membership is record/container scoped. Unified identifiers are rejected with
-// ErrUnifiedContactNotMutable.
+// ErrUnifiedContactNotMutable. With input.DryRun set, the contact and group
+// are validated and membership is left unchanged.
 //
 // This uses osascript (AppleScript) to perform the removal because the
 // Contacts.framework CNSaveRequest removeMember:fromGroup: method has a
 // known bug on macOS 14.6+ / 15.x where the removal silently fails.
-func RemoveContactFromGroup(ctx context.Context, contactID, groupID string) error {
-	contactID = strings.TrimSpace(contactID)
-	groupID = strings.TrimSpace(groupID)
+func RemoveContactFromGroup(ctx context.Context, input GroupMembershipInput) error {
+	contactID, groupID, err := resolveGroupMembership(ctx, "RemoveContactFromGroup", input)
+	if err != nil || input.DryRun {
+		return err
+	}
+	if err := removeContactFromGroupViaOSAScript(ctx, contactID, groupID); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &OpError{Op: "RemoveContactFromGroup", ID: groupID, Err: err}
+	}
+	members, err := ListContactsInGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if containsContactID(members, contactID) {
+		return newVerificationError("RemoveContactFromGroup", groupID, "contact is still a member")
+	}
+	return nil
+}
+
+// resolveGroupMembership validates a membership change: both identifiers
+// resolve, the contact is not a unified projection, and the group lives in
+// one of the contact's containers. It returns the trimmed identifiers.
+func resolveGroupMembership(ctx context.Context, op string, input GroupMembershipInput) (string, string, error) {
+	contactID := strings.TrimSpace(input.ContactID)
+	groupID := strings.TrimSpace(input.GroupID)
 	if contactID == "" || groupID == "" {
-		return newInvalidArg("RemoveContactFromGroup", "", "contactID and groupID are required")
+		return "", "", newInvalidArg(op, "", "contactID and groupID are required")
 	}
 	if err := ctx.Err(); err != nil {
-		return err
+		return "", "", err
 	}
-	identity, err := ensureNonUnifiedContactIdentity(ctx, "RemoveContactFromGroup", contactID)
+	identity, err := ensureNonUnifiedContactIdentity(ctx, op, contactID)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	group, err := GetGroup(ctx, groupID)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 	if group.ContainerID != "" && len(identity.ContainerIDs) > 0 && !hasContainerIntersection(identity.ContainerIDs, group.ContainerID) {
-		return &OpError{
-			Op:  "RemoveContactFromGroup",
+		return "", "", &OpError{
+			Op:  op,
 			ID:  groupID,
 			Err: fmt.Errorf("%w: contact containers %v do not include group container %q", ErrGroupContainerMismatch, identity.ContainerIDs, group.ContainerID),
 		}
 	}
-	if err := removeContactFromGroupViaOSAScript(ctx, contactID, groupID); err != nil {
-		return &OpError{Op: "RemoveContactFromGroup", ID: groupID, Err: err}
-	}
-	members, err := ListContactsInGroup(ctx, groupID)
-	if err != nil {
-		return err
-	}
-	if containsContactID(members, contactID) {
-		return newVerificationError("RemoveContactFromGroup", groupID, "contact is still a member")
-	}
-	return nil
+	return contactID, groupID, nil
 }
 
 // removeContactFromGroupViaOSAScript uses osascript to remove a contact
@@ -1177,6 +2310,9 @@ end tell`, contactID, groupID)
 
 	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
 	out, err := cmd.CombinedOutput()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
 		return fmt.Errorf("osascript remove member failed: %s (output: %s)", err, strings.TrimSpace(string(out)))
 	}
@@ -1226,6 +2362,75 @@ func DefaultContainerID(ctx context.Context) (string, error) {
 	return id, nil
 }
 
+// GroupMember is a lightweight reference to a contact in a group.
+//
+// Name is the formatted full name (or organization name). Fetch the full
+// record with [GetContact] when more fields are needed.
+type GroupMember struct {
+	Identifier string `json:"identifier"`
+	Name       string `json:"name"`
+}
+
+// ListGroupMembersInput controls group membership listing.
+//
+// Offset is the 0-based position of the first member returned. Limit caps the
+// number of members returned; 0 means no limit.
+type ListGroupMembersInput struct {
+	GroupID string `json:"group_id"`
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+}
+
+// GroupMembersPage is one page of group members.
+//
+// Total is the number of members in the group regardless of Offset and Limit,
+// so callers can report counts or compute further pages.
+type GroupMembersPage struct {
+	Members []GroupMember `json:"members"`
+	Total   int           `json:"total"`
+}
+
+// ListGroupMembers returns a page of member references for a group. Only
+// identifiers and name keys are fetched, which makes it much cheaper than
+// [ListContactsInGroup] for counting or paging large distribution groups.
+// Members are ordered by given name, matching [ListContactsInGroup].
+func ListGroupMembers(ctx context.Context, input ListGroupMembersInput) (GroupMembersPage, error) {
+	groupID := strings.TrimSpace(input.GroupID)
+	if groupID == "" {
+		return GroupMembersPage{}, newInvalidArg("ListGroupMembers", "", "groupID is required")
+	}
+	if input.Offset < 0 {
+		return GroupMembersPage{}, newInvalidArg("ListGroupMembers", groupID, "offset must be >= 0")
+	}
+	if input.Limit < 0 {
+		return GroupMembersPage{}, newInvalidArg("ListGroupMembers", groupID, "limit must be >= 0")
+	}
+	if err := ctx.Err(); err != nil {
+		return GroupMembersPage{}, err
+	}
+	members, errStr := listGroupMembers(ctx, groupID)
+	if err := ctx.Err(); err != nil {
+		return GroupMembersPage{}, err
+	}
+	if errStr != "" {
+		return GroupMembersPage{}, newBridgeOpError("ListGroupMembers", groupID, errStr)
+	}
+	return pageGroupMembers(members, input.Offset, input.Limit), nil
+}
+
+func pageGroupMembers(members []GroupMember, offset, limit int) GroupMembersPage {
+	page := GroupMembersPage{Total: len(members)}
+	if offset >= len(members) {
+		return page
+	}
+	end := len(members)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	page.Members = members[offset:end]
+	return page
+}
+
 // ListContactsInGroup returns constituent contacts that are members of the
 // specified group (Unified=false for all returned contacts).
 func ListContactsInGroup(ctx context.Context, groupID string) ([]Contact, error) {
@@ -1236,7 +2441,10 @@ func ListContactsInGroup(ctx context.Context, groupID string) ([]Contact, error)
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	contacts, errStr := listContactsInGroup(groupID)
+	contacts, errStr := listContactsInGroup(ctx, groupID)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if errStr != "" {
 		return nil, newBridgeOpError("ListContactsInGroup", groupID, errStr)
 	}
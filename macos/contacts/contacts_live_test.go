@@ -3,9 +3,25 @@
 package contacts
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nalgeon/be"
 )
@@ -110,7 +126,7 @@ func cleanupGroup(t *testing.T, ctx context.Context, id string) {
 	if id == "" {
 		return
 	}
-	err := DeleteGroup(ctx, id)
+	err := DeleteGroup(ctx, DeleteGroupInput{Identifier: id})
 	if err != nil {
 		t.Logf("cleanup: delete group %s: %v", id, err)
 	}
@@ -137,6 +153,33 @@ func requireAuthorized(t *testing.T) {
 	}
 }
 
+func TestAuthorizationStatusCanAccess(t *testing.T) {
+	be.True(t, AuthorizationStatusAuthorized.CanAccess())
+	be.True(t, AuthorizationStatusLimited.CanAccess())
+	be.True(t, !AuthorizationStatusDenied.CanAccess())
+	be.True(t, !AuthorizationStatusRestricted.CanAccess())
+	be.True(t, !AuthorizationStatusNotDetermined.CanAccess())
+
+	var s AuthorizationStatus
+	be.Err(t, s.UnmarshalText([]byte("limited")), nil)
+	be.Equal(t, s, AuthorizationStatusLimited)
+}
+
+func TestAuthorizedContactIDs(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "Visible"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	ids, err := AuthorizedContactIDs(ctx)
+	be.Err(t, err, nil)
+	be.True(t, containsString(ids, created.Identifier))
+}
+
 // containers -------------------------------------------------------------
 
 func TestListContainers(t *testing.T) {
@@ -261,6 +304,34 @@ func TestCreateGetDeleteContact(t *testing.T) {
 	be.Err(t, err)
 }
 
+func TestCreateContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	inputs := []CreateContactInput{
+		{Contact: Contact{GivenName: testPrefix + "BatchA"}},
+		{Contact: Contact{GivenName: testPrefix + "BatchBad", EmailAddresses: []LabeledValue[string]{{Label: "work"}}}},
+		{Contact: Contact{GivenName: testPrefix + "BatchB"}},
+		{Contact: Contact{GivenName: testPrefix + "BatchDry"}, DryRun: true},
+	}
+	results, err := CreateContacts(ctx, inputs)
+	be.Err(t, err, nil)
+	be.Equal(t, len(results), 4)
+	for _, r := range results {
+		if r.Err == nil && r.Created.Identifier != "" {
+			defer cleanupContact(t, ctx, r.Created.Identifier)
+		}
+	}
+
+	be.Err(t, results[0].Err, nil)
+	be.Equal(t, results[0].Created.GivenName, testPrefix+"BatchA")
+	be.True(t, errors.Is(results[1].Err, ErrInvalidArgument))
+	be.Err(t, results[2].Err, nil)
+	be.Equal(t, results[2].Created.GivenName, testPrefix+"BatchB")
+	be.Err(t, results[3].Err, nil)
+	be.Equal(t, results[3].Created.Identifier, "")
+}
+
 func TestCreateContactOrganization(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
@@ -315,6 +386,374 @@ func TestUpdateContact(t *testing.T) {
 	be.Equal(t, len(updated.PhoneNumbers), 1)
 }
 
+func TestUpdateContactListOperations(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "ListOps",
+			EmailAddresses: []LabeledValue[string]{{Label: "home", Value: "keep@example.com"}, {Label: "work", Value: "old@example.com"}},
+			PhoneNumbers:   []LabeledValue[string]{{Label: "mobile", Value: "+1 555 010 4000"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:           created.Identifier,
+		AddEmailAddresses:    []LabeledValue[string]{{Label: "work", Value: "new@example.com"}, {Label: "home", Value: "KEEP@example.com"}},
+		RemoveEmailAddresses: []string{"OLD@example.com"},
+		AddPhoneNumbers:      []LabeledValue[string]{{Label: "work", Value: "555-010-4001"}},
+		RemovePhoneNumbers:   []string{"(555) 010-4000"},
+	})
+	be.Err(t, err, nil)
+
+	var emails, phones []string
+	for _, e := range updated.EmailAddresses {
+		emails = append(emails, e.Value)
+	}
+	for _, p := range updated.PhoneNumbers {
+		phones = append(phones, p.Value)
+	}
+	be.Equal(t, emails, []string{"keep@example.com", "new@example.com"})
+	be.Equal(t, phones, []string{"555-010-4001"})
+}
+
+func TestUpsertContact(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	email := "cuhtest.upsert@example.com"
+	first, err := UpsertContact(ctx, UpsertContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "Upsert",
+			EmailAddresses: []LabeledValue[string]{{Label: "work", Value: email}},
+		},
+		MatchOn: []UpsertMatchKey{UpsertMatchEmail},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, first.Contact.Identifier)
+	be.True(t, first.Created)
+
+	second, err := UpsertContact(ctx, UpsertContactInput{
+		Contact: Contact{
+			JobTitle:       "Buyer",
+			EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "CUHTest.Upsert@example.com"}},
+			PhoneNumbers:   []LabeledValue[string]{{Label: "mobile", Value: "+1 555 010 5000"}},
+		},
+		MatchOn: []UpsertMatchKey{UpsertMatchEmail},
+	})
+	be.Err(t, err, nil)
+	be.True(t, !second.Created)
+	be.Equal(t, second.Contact.Identifier, first.Contact.Identifier)
+	be.Equal(t, second.Contact.GivenName, testPrefix+"Upsert")
+	be.Equal(t, second.Contact.JobTitle, "Buyer")
+	be.Equal(t, len(second.Contact.EmailAddresses), 1)
+	be.Equal(t, len(second.Contact.PhoneNumbers), 1)
+
+	dup, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:    testPrefix + "UpsertDup",
+			PhoneNumbers: []LabeledValue[string]{{Label: "home", Value: "555-010-5000"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, dup.Identifier)
+
+	_, err = UpsertContact(ctx, UpsertContactInput{
+		Contact: Contact{PhoneNumbers: []LabeledValue[string]{{Value: "(555) 010-5000"}}},
+		MatchOn: []UpsertMatchKey{UpsertMatchPhone},
+	})
+	be.True(t, errors.Is(err, ErrAmbiguous))
+}
+
+func TestUpdateContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	a, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "BatchUpdA"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, a.Identifier)
+	b, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "BatchUpdB"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, b.Identifier)
+
+	results, err := UpdateContacts(ctx, []UpdateContactInput{
+		{Identifier: a.Identifier, JobTitle: ptr("Buyer")},
+		{Identifier: "nonexistent-contact-12345", JobTitle: ptr("Nobody")},
+		{Identifier: b.Identifier, AddEmailAddresses: []LabeledValue[string]{{Label: "work", Value: "cuhtest.batchupd@example.com"}}},
+		{Identifier: a.Identifier, Nickname: ptr("Ace")},
+		{Identifier: b.Identifier, Nickname: ptr("Dry"), DryRun: true},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(results), 5)
+	be.Err(t, results[0].Err, nil)
+	be.Equal(t, results[0].Updated.JobTitle, "Buyer")
+	be.True(t, errors.Is(results[1].Err, ErrNotFound))
+	be.Err(t, results[2].Err, nil)
+	be.Equal(t, len(results[2].Updated.EmailAddresses), 1)
+	be.Err(t, results[3].Err, nil)
+	be.Equal(t, results[3].Updated.JobTitle, "Buyer")
+	be.Equal(t, results[3].Updated.Nickname, "Ace")
+	be.Err(t, results[4].Err, nil)
+	be.Equal(t, results[4].Updated.Nickname, "Dry")
+
+	current, err := GetContact(ctx, b.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, current.Nickname, "")
+}
+
+func TestUpsertContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	existing, err := CreateContact(ctx, CreateContactInput{Contact: Contact{
+		GivenName:      testPrefix + "BatchUpsertOld",
+		EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "cuhtest.batchold@example.com"}},
+	}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, existing.Identifier)
+
+	email := func(v string) []LabeledValue[string] { return []LabeledValue[string]{{Label: "work", Value: v}} }
+	matchOn := []UpsertMatchKey{UpsertMatchEmail}
+	results, err := UpsertContacts(ctx, []UpsertContactInput{
+		{Contact: Contact{GivenName: testPrefix + "BatchUpsertNew", EmailAddresses: email("cuhtest.batchnew@example.com")}, MatchOn: matchOn},
+		{Contact: Contact{JobTitle: "Buyer", EmailAddresses: email("cuhtest.batchold@example.com")}, MatchOn: matchOn},
+		{Contact: Contact{JobTitle: "Seller", EmailAddresses: email("CUHTest.BatchNew@example.com")}, MatchOn: matchOn},
+		{Contact: Contact{GivenName: testPrefix + "BatchUpsertNoKey"}, MatchOn: matchOn},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(results), 4)
+	if results[0].Err == nil {
+		defer cleanupContact(t, ctx, results[0].Contact.Identifier)
+	}
+
+	be.Err(t, results[0].Err, nil)
+	be.True(t, results[0].Created)
+	be.Err(t, results[1].Err, nil)
+	be.True(t, !results[1].Created)
+	be.Equal(t, results[1].Contact.Identifier, existing.Identifier)
+	be.Equal(t, results[1].Contact.JobTitle, "Buyer")
+	// The third input matches the contact created by the first one.
+	be.Err(t, results[2].Err, nil)
+	be.True(t, !results[2].Created)
+	be.Equal(t, results[2].Contact.Identifier, results[0].Contact.Identifier)
+	be.Equal(t, results[2].Contact.JobTitle, "Seller")
+	be.True(t, errors.Is(results[3].Err, ErrInvalidArgument))
+}
+
+func TestExportImportCSV(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	g, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "CSVGroup"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, g.Identifier)
+
+	in := "First Name,Last Name,Emails,Phones,Groups,Notes\n" +
+		testPrefix + "CSV,Import,work:cuhtest.csv@example.com,mobile:555-010-7000," + testPrefix + "CSVGroup,ignored\n" +
+		testPrefix + "CSVBad,Import,,,NoSuchGroup,\n"
+	results, err := ImportCSV(ctx, strings.NewReader(in), ImportCSVInput{ContainerID: g.ContainerID})
+	be.Err(t, err, nil)
+	be.Equal(t, len(results), 2)
+	be.Err(t, results[0].Err, nil)
+	defer cleanupContact(t, ctx, results[0].Contact.Identifier)
+	be.True(t, results[0].Created)
+	be.Equal(t, results[0].Row, 2)
+	be.True(t, errors.Is(results[1].Err, ErrNotFound))
+
+	var out bytes.Buffer
+	err = ExportCSV(ctx, &out, ExportCSVInput{
+		Filters: []Filter{{Field: ContactFieldGivenName, Op: FilterEquals, Value: testPrefix + "CSV"}},
+	})
+	be.Err(t, err, nil)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	be.Equal(t, len(lines), 2)
+	be.True(t, strings.Contains(lines[1], "cuhtest.csv@example.com"))
+	be.True(t, strings.Contains(lines[1], testPrefix+"CSVGroup"))
+}
+
+func TestCreateContactWithNote(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	// Depending on the store, the note is saved directly or through the
+	// AppleScript fallback; either way the create must succeed.
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "Note", Note: "met at the conference\nprefers email"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+	be.Equal(t, created.GivenName, testPrefix+"Note")
+
+	note, err := GetContactNote(ctx, created.Identifier)
+	if errors.Is(err, ErrNoteEntitlementMissing) {
+		be.Equal(t, ErrorCode(err), "note_entitlement_missing")
+		be.True(t, strings.Contains(err.Error(), "com.apple.developer.contacts.notes"))
+		return
+	}
+	be.Err(t, err, nil)
+	be.Equal(t, note, "met at the conference\nprefers email")
+}
+
+func TestUpdateContactPostalAddresses(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName: testPrefix + "Postal",
+			PostalAddresses: []LabeledValue[PostalAddress]{
+				{Label: "home", Value: PostalAddress{Street: "1 Old Rd", City: "Oldtown", PostalCode: "11111", Country: "Testland"}},
+			},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	addresses := []LabeledValue[PostalAddress]{
+		{Label: "home", Value: PostalAddress{Street: "2 New Ave\nApt 3", City: "Newtown", State: "NT", PostalCode: "22222", Country: "Testland"}},
+		{Label: "work", Value: PostalAddress{Street: "9 Office Pkwy", City: "Worktown", PostalCode: "33333", Country: "Testland"}},
+	}
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:      created.Identifier,
+		PostalAddresses: &addresses,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(updated.PostalAddresses), 2)
+	be.Equal(t, updated.PostalAddresses[0].Value.Street, "2 New Ave\nApt 3")
+	be.Equal(t, updated.PostalAddresses[0].Value.State, "NT")
+	be.Equal(t, updated.PostalAddresses[1].Value.City, "Worktown")
+	be.Equal(t, updated.PostalAddresses[1].Label, "work")
+	be.Equal(t, updated.PostalAddresses[1].RawLabel, "_$!<Work>!$_")
+
+	empty := []LabeledValue[PostalAddress]{}
+	updated, err = UpdateContact(ctx, UpdateContactInput{
+		Identifier:      created.Identifier,
+		PostalAddresses: &empty,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(updated.PostalAddresses), 0)
+}
+
+func TestUpdateContactBirthdayAndDates(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "Dates"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	dates := []LabeledValue[DateComponents]{
+		{Label: "anniversary", Value: DateComponents{Year: 2015, Month: 9, Day: 12}},
+		{Label: "other", Value: DateComponents{Month: 1, Day: 2}},
+	}
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier: created.Identifier,
+		Birthday:   &DateComponents{Month: 3, Day: 4},
+		Dates:      &dates,
+	})
+	be.Err(t, err, nil)
+	be.True(t, updated.Birthday != nil)
+	be.Equal(t, *updated.Birthday, DateComponents{Month: 3, Day: 4})
+	be.Equal(t, len(updated.Dates), 2)
+	be.Equal(t, updated.Dates[0].Value, DateComponents{Year: 2015, Month: 9, Day: 12})
+
+	updated, err = UpdateContact(ctx, UpdateContactInput{
+		Identifier:    created.Identifier,
+		ClearBirthday: true,
+	})
+	be.Err(t, err, nil)
+	be.True(t, updated.Birthday == nil)
+	be.Equal(t, len(updated.Dates), 2)
+}
+
+func TestContactOnlinePresence(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName: testPrefix + "Online",
+			URLAddresses: []LabeledValue[string]{
+				{Label: "homepage", Value: "https://online.example.com"},
+			},
+			SocialProfiles: []LabeledValue[SocialProfile]{
+				{Value: SocialProfile{Service: "LinkedIn", Username: "cuh-test", URLString: "https://www.linkedin.com/in/cuh-test"}},
+			},
+			InstantMessages: []LabeledValue[InstantMessage]{
+				{Label: "work", Value: InstantMessage{Service: "Jabber", Username: "cuh@jabber.example.com"}},
+			},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	fetched, err := GetContact(ctx, created.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, len(fetched.URLAddresses), 1)
+	be.Equal(t, fetched.URLAddresses[0].Value, "https://online.example.com")
+	be.Equal(t, len(fetched.SocialProfiles), 1)
+	be.Equal(t, fetched.SocialProfiles[0].Value.Username, "cuh-test")
+	be.Equal(t, len(fetched.InstantMessages), 1)
+	be.Equal(t, fetched.InstantMessages[0].Value.Username, "cuh@jabber.example.com")
+
+	urls := []LabeledValue[string]{
+		{Label: "homepage", Value: "https://online.example.com"},
+		{Label: "work", Value: "https://work.example.com"},
+	}
+	profiles := []LabeledValue[SocialProfile]{
+		{Value: SocialProfile{Service: "Twitter", Username: "cuhtest"}},
+	}
+	ims := []LabeledValue[InstantMessage]{}
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:      created.Identifier,
+		URLAddresses:    &urls,
+		SocialProfiles:  &profiles,
+		InstantMessages: &ims,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(updated.URLAddresses), 2)
+	be.Equal(t, updated.SocialProfiles[0].Value.Username, "cuhtest")
+	be.Equal(t, len(updated.InstantMessages), 0)
+}
+
+func TestContactRelations(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName: testPrefix + "Relations",
+			ContactRelations: []LabeledValue[ContactRelation]{
+				{Label: "Spouse", Value: ContactRelation{Name: "Alex Example"}},
+				{Label: "mentor", Value: ContactRelation{Name: "Robin Example"}},
+			},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	fetched, err := GetContact(ctx, created.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, fetched.RelatedNames(RelationLabelSpouse), []string{"Alex Example"})
+	be.Equal(t, fetched.RelatedNames("mentor"), []string{"Robin Example"})
+
+	relations := []LabeledValue[ContactRelation]{
+		{Label: RelationLabelAssistant, Value: ContactRelation{Name: "Jordan Example"}},
+	}
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:       created.Identifier,
+		ContactRelations: &relations,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, updated.RelatedNames(RelationLabelAssistant), []string{"Jordan Example"})
+	be.Equal(t, len(updated.RelatedNames(RelationLabelSpouse)), 0)
+}
+
 // ListContacts -----------------------------------------------------------
 
 func TestListContacts(t *testing.T) {
@@ -390,6 +829,50 @@ func TestListContacts(t *testing.T) {
 		be.Equal(t, count, 1)
 	})
 
+	t.Run("stream", func(t *testing.T) {
+		filters := []Filter{
+			{Field: ContactFieldFamilyName, Value: testPrefix + "ListTest", Op: FilterEquals},
+		}
+		count := 0
+		for c, err := range StreamContacts(ctx, filters) {
+			be.Err(t, err, nil)
+			be.Equal(t, c.FamilyName, testPrefix+"ListTest")
+			count++
+		}
+		be.Equal(t, count, 2)
+
+		count = 0
+		for _, err := range StreamContacts(ctx, filters) {
+			be.Err(t, err, nil)
+			count++
+			break
+		}
+		be.Equal(t, count, 1)
+	})
+
+	t.Run("after cursor", func(t *testing.T) {
+		in := ListContactsInput{
+			Filters: []Filter{
+				{Field: ContactFieldFamilyName, Value: testPrefix + "ListTest", Op: FilterEquals},
+			},
+		}
+		var first []Contact
+		for c, err := range ListContacts(ctx, in) {
+			be.Err(t, err, nil)
+			first = append(first, c)
+		}
+		be.Equal(t, len(first), 2)
+
+		in.After = ContactCursor(first[0])
+		var rest []Contact
+		for c, err := range ListContacts(ctx, in) {
+			be.Err(t, err, nil)
+			rest = append(rest, c)
+		}
+		be.Equal(t, len(rest), 1)
+		be.Equal(t, rest[0].Identifier, first[1].Identifier)
+	})
+
 	t.Run("context cancellation", func(t *testing.T) {
 		ctx2, cancel := context.WithCancel(ctx)
 		cancel() // cancel immediately
@@ -432,56 +915,281 @@ func TestListContactsFilterContains(t *testing.T) {
 	be.Equal(t, count, 1)
 }
 
-func TestListContactsUnifiedFilter(t *testing.T) {
+func TestListContactsFieldFilters(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
 
-	for c, err := range ListContacts(ctx, ListContactsInput{
-		Filters: []Filter{{Field: ContactFieldUnified, Value: "true", Op: FilterEquals}},
-	}) {
-		be.Err(t, err, nil)
-		be.True(t, c.Unified)
-		break
-	}
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "Fields",
+			JobTitle:       "Principal Widget Wrangler",
+			Nickname:       "Wrangles",
+			EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "fields@cuhtest.example.com"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
 
-	for c, err := range ListContacts(ctx, ListContactsInput{
-		Filters: []Filter{{Field: ContactFieldUnified, Value: "false", Op: FilterEquals}},
-	}) {
-		be.Err(t, err, nil)
-		be.True(t, !c.Unified)
-		break
+	found := func(filters ...Filter) bool {
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: filters}) {
+			be.Err(t, err, nil)
+			if c.Identifier == created.Identifier {
+				return true
+			}
+		}
+		return false
 	}
+	be.True(t, found(Filter{Field: ContactFieldEmailAddresses, Op: FilterContains, Value: "@CUHTEST.example.com"}))
+	be.True(t, found(Filter{Field: ContactFieldJobTitle, Op: FilterContains, Value: "widget"}))
+	be.True(t, found(Filter{Field: ContactFieldNickname, Op: FilterEquals, Value: "wrangles"}))
+	be.True(t, found(
+		Filter{Field: ContactFieldJobTitle, Op: FilterContains, Value: "wrangler"},
+		Filter{Field: ContactFieldEmailAddresses, Op: FilterNotContains, Value: "@other.example.com"},
+	))
+	be.True(t, !found(
+		Filter{Field: ContactFieldJobTitle, Op: FilterContains, Value: "wrangler"},
+		Filter{Field: ContactFieldNickname, Op: FilterNotContains, Value: "wrangle"},
+	))
 }
 
-func TestListContactsContainerIDFilter(t *testing.T) {
+func TestListContactsTextFilter(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
 
-	defaultContainerID, err := DefaultContainerID(ctx)
-	be.Err(t, err, nil)
-
 	created, err := CreateContact(ctx, CreateContactInput{
 		Contact: Contact{
-			ContainerID: defaultContainerID,
-			GivenName:   testPrefix + "Container",
-			FamilyName:  testPrefix + "Filter",
+			GivenName:        testPrefix + "Marek",
+			FamilyName:       "Textsearch",
+			OrganizationName: "Conference Widgets",
+			PhoneNumbers:     []LabeledValue[string]{{Label: "mobile", Value: "+1 555 010 3131"}},
+			Note:             "met at the zeppelinfest booth",
 		},
 	})
 	be.Err(t, err, nil)
 	defer cleanupContact(t, ctx, created.Identifier)
 
-	foundCreated := false
-	for c, err := range ListContacts(ctx, ListContactsInput{
-		Filters: []Filter{
-			{Field: ContactFieldUnified, Value: "false", Op: FilterEquals},
-			{Field: ContactFieldContainerID, Value: defaultContainerID, Op: FilterEquals},
-		},
-	}) {
-		be.Err(t, err, nil)
-		be.Equal(t, c.ContainerID, defaultContainerID)
-		if c.Identifier == created.Identifier {
-			foundCreated = true
-		}
+	list := func(field ContactField, op FilterOp, value string) (bool, error) {
+		for c, err := range ListContacts(ctx, ListContactsInput{
+			Filters: []Filter{{Field: field, Op: op, Value: value}},
+		}) {
+			if err != nil {
+				return false, err
+			}
+			if c.Identifier == created.Identifier {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	// A word that appears only in the note matches through the note, or the
+	// listing says notes cannot be searched; it never just comes back empty.
+	field := ContactFieldText
+	inNote, err := list(ContactFieldText, FilterContains, "zeppelinfest")
+	if errors.Is(err, ErrNoteEntitlementMissing) {
+		field = ContactFieldTextExcludingNotes
+	} else {
+		be.Err(t, err, nil)
+		be.True(t, inNote)
+	}
+	inNote, err = list(ContactFieldTextExcludingNotes, FilterContains, "zeppelinfest")
+	be.Err(t, err, nil)
+	be.True(t, !inNote)
+
+	found := func(op FilterOp, value string) bool {
+		ok, err := list(field, op, value)
+		be.Err(t, err, nil)
+		return ok
+	}
+	be.True(t, found(FilterContains, "marek conference"))
+	be.True(t, found(FilterContains, "textsearch 0103131"))
+	be.True(t, found(FilterEquals, "conference widgets"))
+	be.True(t, !found(FilterContains, "marek nowhere"))
+	be.True(t, found(FilterNotContains, "marek nowhere"))
+}
+
+func TestListContactsPhoneticFilter(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			NamePrefix:         "Dr.",
+			GivenName:          testPrefix + "健",
+			FamilyName:         "山田",
+			NameSuffix:         "Jr.",
+			PhoneticGivenName:  "Ken",
+			PhoneticFamilyName: "Yamada",
+			PreviousFamilyName: "Suzuki",
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+	be.Equal(t, created.PhoneticGivenName, "Ken")
+	be.Equal(t, created.PhoneticFamilyName, "Yamada")
+	be.Equal(t, created.NamePrefix, "Dr.")
+	be.Equal(t, created.NameSuffix, "Jr.")
+
+	found := func(f Filter) bool {
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: []Filter{f}}) {
+			be.Err(t, err, nil)
+			if c.Identifier == created.Identifier {
+				return true
+			}
+		}
+		return false
+	}
+	be.True(t, found(Filter{Field: ContactFieldPhoneticFamilyName, Op: FilterEquals, Value: "yamada"}))
+	be.True(t, found(Filter{Field: ContactFieldPhoneticGivenName, Op: FilterContains, Value: "ke"}))
+	be.True(t, found(Filter{Field: ContactFieldPreviousFamilyName, Op: FilterEquals, Value: "Suzuki"}))
+	be.True(t, found(Filter{Field: ContactFieldText, Op: FilterContains, Value: "ken yamada"}))
+	be.True(t, !found(Filter{Field: ContactFieldPhoneticFamilyName, Op: FilterEquals, Value: "Tanaka"}))
+
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:        created.Identifier,
+		PhoneticGivenName: ptr("Kenji"),
+		NameSuffix:        ptr(""),
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, updated.PhoneticGivenName, "Kenji")
+	be.Equal(t, updated.NameSuffix, "")
+}
+
+func TestListContactsGroupFilter(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	group, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "FilterGroup"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, group.Identifier)
+
+	member, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "GroupMember", JobTitle: "CUH Filter Engineer"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, member.Identifier)
+	outsider, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "GroupOutsider", JobTitle: "CUH Filter Engineer"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, outsider.Identifier)
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: member.Identifier, GroupID: group.Identifier}), nil)
+
+	list := func(f Filter) []string {
+		var ids []string
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: []Filter{
+			{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"},
+			{Field: ContactFieldJobTitle, Op: FilterEquals, Value: "CUH Filter Engineer"},
+			f,
+		}}) {
+			be.Err(t, err, nil)
+			ids = append(ids, c.Identifier)
+		}
+		return ids
+	}
+	be.Equal(t, list(Filter{Field: ContactFieldGroupID, Op: FilterEquals, Value: group.Identifier}), []string{member.Identifier})
+	be.Equal(t, list(Filter{Field: ContactFieldGroupName, Op: FilterEquals, Value: group.Name}), []string{member.Identifier})
+	be.Equal(t, list(Filter{Field: ContactFieldGroupName, Op: FilterNotContains, Value: group.Name}), []string{outsider.Identifier})
+
+	other, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "FilterGroupOther"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, other.Identifier)
+	otherMember, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "GroupOtherMember", JobTitle: "CUH Filter Engineer"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, otherMember.Identifier)
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: otherMember.Identifier, GroupID: other.Identifier}), nil)
+
+	anyOf := list(Filter{Field: ContactFieldGroupName, Op: FilterEquals, Values: []string{group.Name, other.Name}})
+	be.Equal(t, len(anyOf), 2)
+	be.True(t, slices.Contains(anyOf, member.Identifier))
+	be.True(t, slices.Contains(anyOf, otherMember.Identifier))
+	be.Equal(t, list(Filter{Field: ContactFieldGroupID, Op: FilterNotContains, Values: []string{group.Identifier, other.Identifier}}), []string{outsider.Identifier})
+
+	for _, err := range ListContacts(ctx, ListContactsInput{Filters: []Filter{
+		{Field: ContactFieldGroupName, Op: FilterEquals, Value: testPrefix + "NoSuchGroup"},
+	}}) {
+		be.True(t, errors.Is(err, ErrNotFound))
+	}
+	for _, err := range ListContacts(ctx, ListContactsInput{Filters: []Filter{
+		{Field: ContactFieldGroupName, Op: FilterEquals, Values: []string{group.Name, testPrefix + "NoSuchGroup"}},
+	}}) {
+		be.True(t, errors.Is(err, ErrNotFound))
+	}
+}
+
+func TestListContactsPhoneFilter(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:    testPrefix + "Phone",
+			PhoneNumbers: []LabeledValue[string]{{Label: "mobile", Value: "(555) 010-4477"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	found := func(f Filter) bool {
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: []Filter{f}}) {
+			be.Err(t, err, nil)
+			if c.Identifier == created.Identifier {
+				return true
+			}
+		}
+		return false
+	}
+	be.True(t, found(Filter{Field: ContactFieldPhoneNumbers, Op: FilterEquals, Value: "+1 555 010 4477"}))
+	be.True(t, found(Filter{Field: ContactFieldPhoneNumbers, Op: FilterContains, Value: "010-44"}))
+	be.True(t, !found(Filter{Field: ContactFieldPhoneNumbers, Op: FilterEquals, Value: "+1 555 010 4478"}))
+}
+
+func TestListContactsUnifiedFilter(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	for c, err := range ListContacts(ctx, ListContactsInput{
+		Filters: []Filter{{Field: ContactFieldUnified, Value: "true", Op: FilterEquals}},
+	}) {
+		be.Err(t, err, nil)
+		be.True(t, c.Unified)
+		break
+	}
+
+	for c, err := range ListContacts(ctx, ListContactsInput{
+		Filters: []Filter{{Field: ContactFieldUnified, Value: "false", Op: FilterEquals}},
+	}) {
+		be.Err(t, err, nil)
+		be.True(t, !c.Unified)
+		break
+	}
+}
+
+func TestListContactsContainerIDFilter(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	defaultContainerID, err := DefaultContainerID(ctx)
+	be.Err(t, err, nil)
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			ContainerID: defaultContainerID,
+			GivenName:   testPrefix + "Container",
+			FamilyName:  testPrefix + "Filter",
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	foundCreated := false
+	for c, err := range ListContacts(ctx, ListContactsInput{
+		Filters: []Filter{
+			{Field: ContactFieldUnified, Value: "false", Op: FilterEquals},
+			{Field: ContactFieldContainerID, Value: defaultContainerID, Op: FilterEquals},
+		},
+	}) {
+		be.Err(t, err, nil)
+		be.Equal(t, c.ContainerID, defaultContainerID)
+		if c.Identifier == created.Identifier {
+			foundCreated = true
+		}
 	}
 	be.True(t, foundCreated)
 }
@@ -536,11 +1244,11 @@ func TestGroupMembershipRejectsUnifiedID(t *testing.T) {
 	be.Err(t, err, nil)
 	defer cleanupGroup(t, ctx, g.Identifier)
 
-	err = AddContactToGroup(ctx, unifiedID, g.Identifier)
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: unifiedID, GroupID: g.Identifier})
 	be.Err(t, err)
 	be.True(t, errors.Is(err, ErrUnifiedContactNotMutable))
 
-	err = RemoveContactFromGroup(ctx, unifiedID, g.Identifier)
+	err = RemoveContactFromGroup(ctx, GroupMembershipInput{ContactID: unifiedID, GroupID: g.Identifier})
 	be.Err(t, err)
 	be.True(t, errors.Is(err, ErrUnifiedContactNotMutable))
 }
@@ -554,7 +1262,7 @@ func TestAddContactToGroupContainerMismatchError(t *testing.T) {
 		t.Skip("could not find cross-container contact/group pair")
 	}
 
-	err := AddContactToGroup(ctx, contactID, groupID)
+	err := AddContactToGroup(ctx, GroupMembershipInput{ContactID: contactID, GroupID: groupID})
 	if err == nil {
 		t.Skip("cross-container add unexpectedly succeeded in this environment")
 	}
@@ -598,7 +1306,7 @@ func TestCreateGetDeleteGroup(t *testing.T) {
 	be.True(t, found)
 
 	// Delete
-	err = DeleteGroup(ctx, g.Identifier)
+	err = DeleteGroup(ctx, DeleteGroupInput{Identifier: g.Identifier})
 	be.Err(t, err, nil)
 
 	// Verify deleted
@@ -656,7 +1364,7 @@ func TestAddRemoveContactGroup(t *testing.T) {
 	defer cleanupGroup(t, ctx, g.Identifier)
 
 	// Add contact to group
-	err = AddContactToGroup(ctx, c.Identifier, g.Identifier)
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: g.Identifier})
 	be.Err(t, err, nil)
 
 	// Verify membership via ListContactsInGroup
@@ -673,7 +1381,7 @@ func TestAddRemoveContactGroup(t *testing.T) {
 	be.True(t, found)
 
 	// Remove contact from group (uses osascript workaround).
-	err = RemoveContactFromGroup(ctx, c.Identifier, g.Identifier)
+	err = RemoveContactFromGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: g.Identifier})
 	be.Err(t, err, nil)
 
 	// Verify removed
@@ -689,6 +1397,44 @@ func TestAddRemoveContactGroup(t *testing.T) {
 	be.True(t, !found)
 }
 
+func TestListGroupMembers(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	g, err := CreateGroup(ctx, CreateGroupInput{
+		Name: testPrefix + "MembersPageGroup",
+	})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, g.Identifier)
+
+	ids := make(map[string]bool)
+	for _, name := range []string{"Alpha", "Bravo", "Charlie"} {
+		c, err := CreateContact(ctx, CreateContactInput{
+			Contact: Contact{GivenName: testPrefix + name, FamilyName: "Members"},
+		})
+		be.Err(t, err, nil)
+		defer cleanupContact(t, ctx, c.Identifier)
+		be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: g.Identifier}), nil)
+		ids[c.Identifier] = true
+	}
+
+	page, err := ListGroupMembers(ctx, ListGroupMembersInput{GroupID: g.Identifier, Limit: 2})
+	be.Err(t, err, nil)
+	be.Equal(t, page.Total, 3)
+	be.Equal(t, len(page.Members), 2)
+
+	rest, err := ListGroupMembers(ctx, ListGroupMembersInput{GroupID: g.Identifier, Offset: 2})
+	be.Err(t, err, nil)
+	be.Equal(t, len(rest.Members), 1)
+
+	for _, m := range append(page.Members, rest.Members...) {
+		be.True(t, ids[m.Identifier])
+		be.True(t, m.Name != "")
+		delete(ids, m.Identifier)
+	}
+	be.Equal(t, len(ids), 0)
+}
+
 func TestDeleteGroupWithContacts(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
@@ -709,11 +1455,11 @@ func TestDeleteGroupWithContacts(t *testing.T) {
 	})
 	be.Err(t, err, nil)
 
-	err = AddContactToGroup(ctx, c.Identifier, g.Identifier)
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: g.Identifier})
 	be.Err(t, err, nil)
 
 	// Delete group — contact should survive
-	err = DeleteGroup(ctx, g.Identifier)
+	err = DeleteGroup(ctx, DeleteGroupInput{Identifier: g.Identifier})
 	be.Err(t, err, nil)
 
 	// Contact still exists
@@ -757,6 +1503,179 @@ func TestCreateSubgroup(t *testing.T) {
 	be.True(t, found)
 }
 
+func TestGroupDuplicateNames(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	name := testPrefix + "DupGroup"
+	first, err := CreateGroup(ctx, CreateGroupInput{Name: name})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, first.Identifier)
+
+	found, err := FindGroupByName(ctx, strings.ToLower(name), first.ContainerID)
+	be.Err(t, err, nil)
+	be.Equal(t, found.Identifier, first.Identifier)
+
+	_, err = CreateGroup(ctx, CreateGroupInput{Name: name, OnDuplicateName: DuplicateGroupNameFail})
+	be.True(t, errors.Is(err, ErrConflict))
+	var opErr *OpError
+	be.True(t, errors.As(err, &opErr))
+	be.Equal(t, opErr.ID, first.Identifier)
+
+	reused, err := CreateGroup(ctx, CreateGroupInput{Name: name, OnDuplicateName: DuplicateGroupNameReuse})
+	be.Err(t, err, nil)
+	be.Equal(t, reused.Identifier, first.Identifier)
+
+	other, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "DupGroupOther"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, other.Identifier)
+	_, err = UpdateGroup(ctx, UpdateGroupInput{Identifier: other.Identifier, Name: &name, OnDuplicateName: DuplicateGroupNameFail})
+	be.True(t, errors.Is(err, ErrConflict))
+	// Renaming a group to its own name is not a conflict.
+	_, err = UpdateGroup(ctx, UpdateGroupInput{Identifier: first.Identifier, Name: &name, OnDuplicateName: DuplicateGroupNameFail})
+	be.Err(t, err, nil)
+
+	// Without a policy duplicates are still allowed, and lookups by name
+	// become ambiguous.
+	dup, err := CreateGroup(ctx, CreateGroupInput{Name: name})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, dup.Identifier)
+	_, err = FindGroupByName(ctx, name, first.ContainerID)
+	be.True(t, errors.Is(err, ErrAmbiguous))
+}
+
+func TestStats(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	token, err := CurrentChangeToken(ctx)
+	be.Err(t, err, nil)
+
+	group, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "StatsGroup"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, group.Identifier)
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "Stats",
+			EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "stats@example.com"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: created.Identifier, GroupID: group.Identifier}), nil)
+
+	stats, err := Stats(ctx, StatsInput{ChangesSince: token})
+	be.Err(t, err, nil)
+	be.True(t, stats.Total >= 1)
+	be.Equal(t, stats.WithEmail+stats.WithoutEmail, stats.Total)
+	be.Equal(t, stats.WithPhone+stats.WithoutPhone, stats.Total)
+	be.True(t, stats.WithNeither <= stats.WithoutEmail)
+
+	sum := 0
+	for _, c := range stats.Containers {
+		sum += c.Total
+	}
+	be.Equal(t, sum, stats.Total)
+
+	i := slices.IndexFunc(stats.Groups, func(g GroupStats) bool { return g.GroupID == group.Identifier })
+	be.True(t, i >= 0)
+	be.Equal(t, stats.Groups[i].Total, 1)
+
+	be.True(t, stats.Recent != nil)
+	be.True(t, stats.Recent.Added >= 1)
+	be.True(t, stats.Recent.Token != "")
+}
+
+func TestDeleteContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	group, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "DeleteGuard"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, group.Identifier)
+
+	var ids []string
+	for _, name := range []string{"DeleteA", "DeleteB"} {
+		c, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + name}})
+		be.Err(t, err, nil)
+		defer cleanupContact(t, ctx, c.Identifier)
+		ids = append(ids, c.Identifier)
+	}
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: ids[0], GroupID: group.Identifier}), nil)
+
+	preview, err := DeleteContacts(ctx, DeleteContactsInput{Identifiers: ids, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, len(preview.Deletions), 2)
+	be.Equal(t, preview.Deletions[0].Contact.GivenName, testPrefix+"DeleteA")
+	be.Equal(t, preview.Deletions[0].GroupIDs, []string{group.Identifier})
+	be.Equal(t, len(preview.Deletions[1].GroupIDs), 0)
+	be.Equal(t, len(preview.DeletedIDs), 0)
+	_, err = GetConstituentContact(ctx, ids[0])
+	be.Err(t, err, nil)
+
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: ids, MaxDeletes: 1, ConfirmToken: preview.ConfirmToken})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	// An edit after the preview invalidates its token.
+	_, err = UpdateContact(ctx, UpdateContactInput{Identifier: ids[1], Nickname: ptr("Edited")})
+	be.Err(t, err, nil)
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: ids, ConfirmToken: preview.ConfirmToken})
+	be.True(t, errors.Is(err, ErrConflict))
+	_, err = GetConstituentContact(ctx, ids[0])
+	be.Err(t, err, nil)
+
+	preview, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: ids, DryRun: true})
+	be.Err(t, err, nil)
+	res, err := DeleteContacts(ctx, DeleteContactsInput{Identifiers: ids, ConfirmToken: preview.ConfirmToken})
+	be.Err(t, err, nil)
+	be.Equal(t, res.DeletedIDs, ids)
+	for _, id := range ids {
+		_, err = GetConstituentContact(ctx, id)
+		be.True(t, errors.Is(err, ErrNotFound))
+	}
+}
+
+func TestSyncContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	containerID, err := DefaultContainerID(ctx)
+	be.Err(t, err, nil)
+
+	remote := &memRemote{contacts: []RemoteContact{{
+		RemoteID: "people/remote",
+		Contact: Contact{
+			GivenName:      testPrefix + "SyncRemote",
+			EmailAddresses: []LabeledValue[string]{{Label: "home", Value: "cuh-sync-remote@example.com"}},
+		},
+	}}}
+
+	// SyncToLocal only writes to the macOS store, so unrelated local
+	// contacts are read but never changed.
+	find := func(changes []SyncChange, id string) SyncChange {
+		i := slices.IndexFunc(changes, func(ch SyncChange) bool { return ch.SourceID == id })
+		be.True(t, i >= 0)
+		return changes[i]
+	}
+
+	preview, err := SyncContacts(ctx, SyncContactsInput{Remote: remote, Direction: SyncToLocal, MatchOn: []UpsertMatchKey{UpsertMatchEmail}, ContainerID: containerID, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, find(preview, "people/remote").Action, SyncActionCreate)
+
+	changes, err := SyncContacts(ctx, SyncContactsInput{Remote: remote, Direction: SyncToLocal, MatchOn: []UpsertMatchKey{UpsertMatchEmail}, ContainerID: containerID})
+	be.Err(t, err, nil)
+	created := find(changes, "people/remote")
+	be.Err(t, created.Err, nil)
+	be.True(t, created.TargetID != "")
+	defer cleanupContact(t, ctx, created.TargetID)
+	be.Equal(t, created.Contact.GivenName, testPrefix+"SyncRemote")
+
+	// A second run finds the copy and has nothing to do.
+	changes, err = SyncContacts(ctx, SyncContactsInput{Remote: remote, Direction: SyncToLocal, MatchOn: []UpsertMatchKey{UpsertMatchEmail}, ContainerID: containerID})
+	be.Err(t, err, nil)
+	be.Equal(t, find(changes, "people/remote").Action, SyncActionNone)
+}
+
 func TestUpdateGroup(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
@@ -787,61 +1706,437 @@ func TestUpdateGroup(t *testing.T) {
 	be.Equal(t, updated.ParentGroupID, parentB.Identifier)
 }
 
-// edge cases / error handling --------------------------------------------
-
-func TestGetContactNotFound(t *testing.T) {
-	requireAuthorized(t)
-	ctx := context.Background()
+// photos -----------------------------------------------------------------
 
-	_, err := GetContact(ctx, "nonexistent-identifier-12345")
-	be.Err(t, err)
-	be.True(t, errors.Is(err, ErrNotFound))
-	t.Logf("expected error: %v", err)
+func testPNG(t *testing.T, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := range 64 {
+		for y := range 64 {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	be.Err(t, png.Encode(&buf, img), nil)
+	return buf.Bytes()
 }
 
-func TestResolveContactIdentity(t *testing.T) {
+func TestContactPhoto(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
 
 	created, err := CreateContact(ctx, CreateContactInput{
-		Contact: Contact{GivenName: testPrefix + "Identity", FamilyName: testPrefix + "Probe"},
+		Contact: Contact{
+			GivenName: testPrefix + "Photo",
+			ImageData: testPNG(t, color.RGBA{R: 255, A: 255}),
+		},
 	})
 	be.Err(t, err, nil)
 	defer cleanupContact(t, ctx, created.Identifier)
 
-	identity, err := ResolveContactIdentity(ctx, created.Identifier)
+	got, err := GetContact(ctx, created.Identifier)
 	be.Err(t, err, nil)
-	be.Equal(t, identity.InputID, created.Identifier)
-	be.True(t, identity.CanonicalID != "")
-	be.True(t, !identity.Unified)
-	be.True(t, len(identity.LinkedIDs) >= 1)
-}
+	be.True(t, got.ImageDataAvailable)
+	be.True(t, len(got.ImageData) > 0)
+	be.True(t, len(got.ThumbnailImageData) > 0)
 
-func TestGetGroupNotFound(t *testing.T) {
-	requireAuthorized(t)
-	ctx := context.Background()
+	replacement := testPNG(t, color.RGBA{B: 255, A: 255})
+	updated, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier: created.Identifier,
+		ImageData:  &replacement,
+	})
+	be.Err(t, err, nil)
+	be.True(t, updated.ImageDataAvailable)
 
-	_, err := GetGroup(ctx, "nonexistent-group-12345")
-	be.Err(t, err)
-	be.True(t, errors.Is(err, ErrNotFound))
-	t.Logf("expected error: %v", err)
+	cleared, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:     created.Identifier,
+		ClearImageData: true,
+	})
+	be.Err(t, err, nil)
+	be.True(t, !cleared.ImageDataAvailable)
+	be.Equal(t, len(cleared.ImageData), 0)
 }
 
-func TestDeleteContactNotFound(t *testing.T) {
-	requireAuthorized(t)
+func TestContactPhotoValidation(t *testing.T) {
 	ctx := context.Background()
 
-	err := DeleteContact(ctx, "nonexistent-identifier-12345")
-	be.Err(t, err)
-	be.True(t, errors.Is(err, ErrNotFound))
-	t.Logf("expected error: %v", err)
-}
+	_, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "BadPhoto", ImageData: []byte("not an image")},
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	photo := testPNG(t, color.White)
+	_, err = UpdateContact(ctx, UpdateContactInput{
+		Identifier:     "any",
+		ImageData:      &photo,
+		ClearImageData: true,
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestIsContactImage(t *testing.T) {
+	heic := append([]byte{0, 0, 0, 0x18}, "ftypheic\x00\x00\x00\x00mif1heic"...)
+	avif := append([]byte{0, 0, 0, 0x18}, "ftypavis"...)
+	mp4 := append([]byte{0, 0, 0, 0x18}, "ftypisom"...)
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{testPNG(t, color.White), true},
+		{[]byte{0xFF, 0xD8, 0xFF, 0xE0}, true},
+		{[]byte("GIF89a\x01\x00"), true},
+		{[]byte("II*\x00\x08\x00\x00\x00"), true},
+		{[]byte("MM\x00*\x00\x00\x00\x08"), true},
+		{heic, true},
+		{avif, false},
+		{mp4, false},
+		{[]byte("not an image"), false},
+	}
+	for _, tt := range tests {
+		be.Equal(t, isContactImage(tt.data), tt.want)
+	}
+}
+
+// duplicates -------------------------------------------------------------
+
+func TestMergeContacts(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	email := "merge.cuhtest@example.com"
+	survivor, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "Merge",
+			EmailAddresses: []LabeledValue[string]{{Label: "work", Value: email}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, survivor.Identifier)
+
+	dup, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{
+			GivenName:      testPrefix + "Merge",
+			FamilyName:     testPrefix + "Dup",
+			EmailAddresses: []LabeledValue[string]{{Label: "home", Value: email}},
+			PhoneNumbers:   []LabeledValue[string]{{Label: "mobile", Value: "+15550109999"}},
+		},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, dup.Identifier)
+
+	group, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "MergeGroup", ContainerID: dup.ContainerID})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, group.Identifier)
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: dup.Identifier, GroupID: group.Identifier}), nil)
+
+	sets, err := FindDuplicateContacts(ctx, FindDuplicateContactsInput{
+		Filters: []Filter{{Field: ContactFieldGivenName, Op: FilterEquals, Value: testPrefix + "Merge"}},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, len(sets), 1)
+	be.Equal(t, len(sets[0].Contacts), 2)
+
+	preview, err := MergeContacts(ctx, MergeContactsInput{
+		SurvivorID:   survivor.Identifier,
+		DuplicateIDs: []string{dup.Identifier},
+		DryRun:       true,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, preview.Survivor.FamilyName, testPrefix+"Dup")
+	be.Equal(t, preview.Duplicates[0].GroupIDs, []string{group.Identifier})
+	be.True(t, !preview.Duplicates[0].Deleted)
+
+	result, err := MergeContacts(ctx, MergeContactsInput{
+		SurvivorID:   survivor.Identifier,
+		DuplicateIDs: []string{dup.Identifier},
+	})
+	be.Err(t, err, nil)
+	be.Err(t, result.Duplicates[0].Err, nil)
+	be.True(t, result.Duplicates[0].Deleted)
+	be.Equal(t, len(result.Survivor.EmailAddresses), 1)
+	be.Equal(t, len(result.Survivor.PhoneNumbers), 1)
+
+	members, err := ListContactsInGroup(ctx, group.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, len(members), 1)
+	be.Equal(t, members[0].Identifier, survivor.Identifier)
+}
+
+func TestMergeContactsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := MergeContacts(ctx, MergeContactsInput{DuplicateIDs: []string{"x"}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = MergeContacts(ctx, MergeContactsInput{SurvivorID: "x"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = MergeContacts(ctx, MergeContactsInput{SurvivorID: "x", DuplicateIDs: []string{"x"}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+// change history ---------------------------------------------------------
+
+func TestListContactChanges(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	token, err := CurrentChangeToken(ctx)
+	be.Err(t, err, nil)
+	be.True(t, token != "")
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "History"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	changes, err := ListContactChanges(ctx, ListContactChangesInput{Token: token})
+	be.Err(t, err, nil)
+	be.True(t, !changes.DropEverything)
+	be.True(t, containsString(changes.AddedIDs, created.Identifier))
+	be.True(t, changes.Token != "")
+
+	be.Err(t, DeleteContact(ctx, created.Identifier), nil)
+	changes, err = ListContactChanges(ctx, ListContactChangesInput{Token: changes.Token})
+	be.Err(t, err, nil)
+	be.True(t, containsString(changes.DeletedIDs, created.Identifier))
+	be.True(t, !containsString(changes.AddedIDs, created.Identifier))
+
+	_, err = ListContactChanges(ctx, ListContactChangesInput{Token: "%%%not-base64"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+// DryRun -----------------------------------------------------------------
+
+func TestDryRunContact(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	given := testPrefix + "DryRunCreate"
+	preview, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: given, FamilyName: testPrefix + "DryRun"},
+		DryRun:  true,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, preview.Identifier, "")
+	be.Equal(t, preview.GivenName, given)
+	be.True(t, preview.ContainerID != "")
+
+	for c, err := range ListContacts(ctx, ListContactsInput{
+		Filters: []Filter{{Field: ContactFieldGivenName, Op: FilterEquals, Value: given}},
+	}) {
+		be.Err(t, err, nil)
+		t.Fatalf("dry-run create persisted contact %s", c.Identifier)
+	}
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "DryRunUpdate", Nickname: "Before"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	merged, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier: created.Identifier,
+		Nickname:   ptr("After"),
+		DryRun:     true,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, merged.Nickname, "After")
+	be.Equal(t, merged.GivenName, created.GivenName)
+
+	current, err := GetContact(ctx, created.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, current.Nickname, "Before")
+
+	_, err = UpdateContact(ctx, UpdateContactInput{
+		Identifier: "nonexistent-identifier-12345",
+		Nickname:   ptr("After"),
+		DryRun:     true,
+	})
+	be.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestDryRunGroup(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	parent, err := CreateGroup(ctx, CreateGroupInput{Name: testPrefix + "DryRunParent"})
+	be.Err(t, err, nil)
+	defer cleanupGroup(t, ctx, parent.Identifier)
+
+	preview, err := CreateGroup(ctx, CreateGroupInput{
+		Name:          testPrefix + "DryRunChild",
+		ParentGroupID: parent.Identifier,
+		DryRun:        true,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, preview.Identifier, "")
+	be.Equal(t, preview.ParentGroupID, parent.Identifier)
+
+	subgroups, err := ListSubgroups(ctx, parent.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, len(subgroups), 0)
+
+	_, err = CreateGroup(ctx, CreateGroupInput{
+		Name:          testPrefix + "DryRunOrphan",
+		ParentGroupID: "nonexistent-group-12345",
+		DryRun:        true,
+	})
+	be.True(t, errors.Is(err, ErrNotFound))
+
+	newName := testPrefix + "DryRunRenamed"
+	renamed, err := UpdateGroup(ctx, UpdateGroupInput{
+		Identifier: parent.Identifier,
+		Name:       &newName,
+		DryRun:     true,
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, renamed.Name, newName)
+
+	current, err := GetGroup(ctx, parent.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, current.Name, parent.Name)
+
+	c, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "DryRunMember"}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, c.Identifier)
+
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: parent.Identifier, DryRun: true})
+	be.Err(t, err, nil)
+	members, err := ListContactsInGroup(ctx, parent.Identifier)
+	be.Err(t, err, nil)
+	be.True(t, !containsContactID(members, c.Identifier))
+
+	be.Err(t, AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: parent.Identifier}), nil)
+	err = RemoveContactFromGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: parent.Identifier, DryRun: true})
+	be.Err(t, err, nil)
+	members, err = ListContactsInGroup(ctx, parent.Identifier)
+	be.Err(t, err, nil)
+	be.True(t, containsContactID(members, c.Identifier))
+
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: "nonexistent-group-12345", DryRun: true})
+	be.True(t, errors.Is(err, ErrNotFound))
+
+	be.Err(t, DeleteGroup(ctx, DeleteGroupInput{Identifier: parent.Identifier, DryRun: true}), nil)
+	_, err = GetGroup(ctx, parent.Identifier)
+	be.Err(t, err, nil)
+	err = DeleteGroup(ctx, DeleteGroupInput{Identifier: "nonexistent-group-12345", DryRun: true})
+	be.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestContactValueValidation(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "BadDate", Birthday: &DateComponents{Month: 13, Day: 1}},
+		DryRun:  true,
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "BadEmail", EmailAddresses: []LabeledValue[string]{{Label: "work"}}},
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	phones := []LabeledValue[string]{{Label: "mobile", Value: " "}}
+	_, err = UpdateContact(ctx, UpdateContactInput{Identifier: "any", PhoneNumbers: &phones})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+// edge cases / error handling --------------------------------------------
+
+func TestGetContactNotFound(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	_, err := GetContact(ctx, "nonexistent-identifier-12345")
+	be.Err(t, err)
+	be.True(t, errors.Is(err, ErrNotFound))
+	t.Logf("expected error: %v", err)
+}
+
+func TestResolveContactIdentity(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "Identity", FamilyName: testPrefix + "Probe"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	identity, err := ResolveContactIdentity(ctx, created.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, identity.InputID, created.Identifier)
+	be.True(t, identity.CanonicalID != "")
+	be.True(t, !identity.Unified)
+	be.True(t, len(identity.LinkedIDs) >= 1)
+}
+
+func TestGetConstituentContact(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	created, err := CreateContact(ctx, CreateContactInput{
+		Contact: Contact{GivenName: testPrefix + "Constituent"},
+	})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, created.Identifier)
+
+	c, err := GetConstituentContact(ctx, created.Identifier)
+	be.Err(t, err, nil)
+	be.Equal(t, c.Identifier, created.Identifier)
+	be.True(t, !c.Unified)
+	be.Equal(t, len(c.LinkedIDs), 0)
+	be.Equal(t, c.GivenName, testPrefix+"Constituent")
+
+	_, err = GetConstituentContact(ctx, "nonexistent-identifier-12345")
+	be.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestGetMeContact(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	me, err := GetMeContact(ctx)
+	if errors.Is(err, ErrNotFound) {
+		t.Skip("no me card configured")
+	}
+	be.Err(t, err, nil)
+	be.True(t, me.Identifier != "")
+	be.True(t, me.Unified)
+
+	fetched, err := GetContact(ctx, me.Identifier)
+	be.Err(t, err, nil)
+	be.True(t, fetched.SameAs(me))
+}
+
+func TestGetGroupNotFound(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	_, err := GetGroup(ctx, "nonexistent-group-12345")
+	be.Err(t, err)
+	be.True(t, errors.Is(err, ErrNotFound))
+	t.Logf("expected error: %v", err)
+}
+
+func TestDeleteContactNotFound(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	err := DeleteContact(ctx, "nonexistent-identifier-12345")
+	be.Err(t, err)
+	be.True(t, errors.Is(err, ErrNotFound))
+	t.Logf("expected error: %v", err)
+}
 
 func TestDeleteGroupNotFound(t *testing.T) {
 	requireAuthorized(t)
 	ctx := context.Background()
 
-	err := DeleteGroup(ctx, "nonexistent-group-12345")
+	err := DeleteGroup(ctx, DeleteGroupInput{Identifier: "nonexistent-group-12345"})
 	be.Err(t, err)
 	be.True(t, errors.Is(err, ErrNotFound))
 	t.Logf("expected error: %v", err)
@@ -853,22 +2148,25 @@ func TestEmptyIdentifier(t *testing.T) {
 	_, err := GetContact(ctx, "")
 	be.Err(t, err)
 
+	_, err = GetConstituentContact(ctx, "")
+	be.Err(t, err)
+
 	err = DeleteContact(ctx, "")
 	be.Err(t, err)
 
 	_, err = GetGroup(ctx, "")
 	be.Err(t, err)
 
-	err = DeleteGroup(ctx, "")
+	err = DeleteGroup(ctx, DeleteGroupInput{Identifier: ""})
 	be.Err(t, err)
 
-	err = AddContactToGroup(ctx, "", "group")
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: "", GroupID: "group"})
 	be.Err(t, err)
 
-	err = AddContactToGroup(ctx, "contact", "")
+	err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: "contact", GroupID: ""})
 	be.Err(t, err)
 
-	err = RemoveContactFromGroup(ctx, "", "group")
+	err = RemoveContactFromGroup(ctx, GroupMembershipInput{ContactID: "", GroupID: "group"})
 	be.Err(t, err)
 
 	_, err = GetContainer(ctx, "")
@@ -878,6 +2176,27 @@ func TestEmptyIdentifier(t *testing.T) {
 	be.Err(t, err)
 }
 
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, err := range ListContacts(ctx, ListContactsInput{}) {
+		be.True(t, errors.Is(err, context.Canceled))
+	}
+
+	_, err := ListContactsInGroup(ctx, "group")
+	be.True(t, errors.Is(err, context.Canceled))
+
+	_, err = GetContact(ctx, "contact")
+	be.True(t, errors.Is(err, context.Canceled))
+
+	_, err = CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "Cancelled"}})
+	be.True(t, errors.Is(err, context.Canceled))
+
+	err = RemoveContactFromGroup(ctx, GroupMembershipInput{ContactID: "contact", GroupID: "group"})
+	be.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestCreateGroupEmptyName(t *testing.T) {
 	ctx := context.Background()
 	_, err := CreateGroup(ctx, CreateGroupInput{Name: ""})
@@ -926,7 +2245,7 @@ func TestMultipleContactsInGroup(t *testing.T) {
 		contactIDs = append(contactIDs, c.Identifier)
 		defer cleanupContact(t, ctx, c.Identifier)
 
-		err = AddContactToGroup(ctx, c.Identifier, g.Identifier)
+		err = AddContactToGroup(ctx, GroupMembershipInput{ContactID: c.Identifier, GroupID: g.Identifier})
 		be.Err(t, err, nil)
 	}
 
@@ -953,6 +2272,25 @@ func TestValidateFilters(t *testing.T) {
 	err = ValidateFilters([]Filter{{Field: ContactFieldContainerID, Value: "container", Op: FilterContains}})
 	be.Err(t, err)
 	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	err = ValidateFilters([]Filter{{Field: ContactFieldGroupName, Value: "Vendors", Op: FilterContains}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ValidateFilters([]Filter{{Field: ContactFieldGroupID, Value: " ", Op: FilterEquals}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ValidateFilters([]Filter{
+		{Field: ContactFieldGroupID, Value: "g1", Op: FilterEquals},
+		{Field: ContactFieldGroupName, Value: "Vendors", Op: FilterNotContains},
+	})
+	be.Err(t, err, nil)
+
+	err = ValidateFilters([]Filter{{Field: ContactFieldGivenName, Values: []string{"a", "b"}, Op: FilterEquals}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ValidateFilters([]Filter{{Field: ContactFieldGroupName, Value: "Vendors", Values: []string{"Press"}, Op: FilterEquals}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ValidateFilters([]Filter{{Field: ContactFieldGroupName, Values: []string{"Vendors", ""}, Op: FilterEquals}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ValidateFilters([]Filter{{Field: ContactFieldGroupName, Values: []string{"Vendors", "Press"}, Op: FilterNotContains}})
+	be.Err(t, err, nil)
 }
 
 func TestStringMethods(t *testing.T) {
@@ -966,6 +2304,7 @@ func TestStringMethods(t *testing.T) {
 	be.Equal(t, AuthorizationStatusDenied.String(), "denied")
 	be.Equal(t, AuthorizationStatusRestricted.String(), "restricted")
 	be.Equal(t, AuthorizationStatusNotDetermined.String(), "not_determined")
+	be.Equal(t, AuthorizationStatusLimited.String(), "limited")
 }
 
 // FullName edge cases -----------------------------------------------------
@@ -992,3 +2331,942 @@ func TestFullNameWithPrefix(t *testing.T) {
 	}
 	be.Equal(t, c.FullName(), "Dr. Jane Smith PhD")
 }
+
+func TestRelatedNames(t *testing.T) {
+	c := Contact{
+		ContactRelations: []LabeledValue[ContactRelation]{
+			{Label: "spouse", Value: ContactRelation{Name: "Alex"}},
+			{Label: "Manager", Value: ContactRelation{Name: "Sam"}},
+			{Label: "friend", Value: ContactRelation{Name: " "}},
+		},
+	}
+	be.Equal(t, c.RelatedNames(RelationLabelSpouse), []string{"Alex"})
+	be.Equal(t, c.RelatedNames(RelationLabelManager, RelationLabelAssistant), []string{"Sam"})
+	be.Equal(t, c.RelatedNames(), []string{"Alex", "Sam"})
+	be.Equal(t, len(c.RelatedNames(RelationLabelFriend)), 0)
+}
+
+func TestPrimaryEmailAndPhone(t *testing.T) {
+	c := Contact{
+		EmailAddresses: []LabeledValue[string]{
+			{Label: "home", Value: " "},
+			{Label: "work", Value: "w@example.com"},
+			{Label: "home", Value: "h@example.com"},
+		},
+		PhoneNumbers: []LabeledValue[string]{
+			{Label: "work", Value: "555-0100"},
+			{Label: "mobile", Value: "555-0101"},
+		},
+	}
+	be.Equal(t, c.PrimaryEmail(), "w@example.com")
+	be.Equal(t, c.PrimaryEmail("Home"), "h@example.com")
+	be.Equal(t, c.PrimaryEmail("other"), "w@example.com")
+	be.Equal(t, c.PrimaryPhone(), "555-0100")
+	be.Equal(t, c.PrimaryPhone("iPhone", "mobile"), "555-0101")
+	be.Equal(t, Contact{}.PrimaryEmail(), "")
+	be.Equal(t, Contact{}.PrimaryPhone("mobile"), "")
+}
+
+func TestPageGroupMembers(t *testing.T) {
+	members := []GroupMember{{Identifier: "a"}, {Identifier: "b"}, {Identifier: "c"}}
+
+	page := pageGroupMembers(members, 0, 0)
+	be.Equal(t, page.Total, 3)
+	be.Equal(t, len(page.Members), 3)
+
+	page = pageGroupMembers(members, 1, 1)
+	be.Equal(t, page.Total, 3)
+	be.Equal(t, page.Members, []GroupMember{{Identifier: "b"}})
+
+	page = pageGroupMembers(members, 2, 5)
+	be.Equal(t, page.Members, []GroupMember{{Identifier: "c"}})
+
+	page = pageGroupMembers(members, 3, 0)
+	be.Equal(t, page.Total, 3)
+	be.Equal(t, len(page.Members), 0)
+}
+
+func TestListGroupMembersInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	_, err := ListGroupMembers(ctx, ListGroupMembersInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = ListGroupMembers(ctx, ListGroupMembersInput{GroupID: "g", Offset: -1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = ListGroupMembers(ctx, ListGroupMembersInput{GroupID: "g", Limit: -1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestQueryStore(t *testing.T) {
+	store := QueryStore{Path: filepath.Join(t.TempDir(), "nested", "queries.json")}
+
+	queries, err := store.List()
+	be.Err(t, err, nil)
+	be.Equal(t, len(queries), 0)
+
+	vendors := SavedQuery{
+		Name: "Vendors",
+		Filters: []Filter{
+			{Field: ContactFieldOrganizationName, Op: FilterContains, Value: "Supply"},
+			{Field: ContactFieldEmailAddresses, Op: FilterNotContains, Value: "@example.com"},
+		},
+	}
+	be.Err(t, store.Save(vendors), nil)
+	be.Err(t, store.Save(SavedQuery{
+		Name:    "Neighbors",
+		Filters: []Filter{{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"}},
+	}), nil)
+
+	got, err := store.Get("Vendors")
+	be.Err(t, err, nil)
+	be.Equal(t, got, vendors)
+
+	queries, err = store.List()
+	be.Err(t, err, nil)
+	be.Equal(t, len(queries), 2)
+	be.Equal(t, queries[0].Name, "Neighbors")
+
+	// Saving an existing name replaces it.
+	vendors.Filters = vendors.Filters[:1]
+	be.Err(t, store.Save(vendors), nil)
+	got, err = store.Get("Vendors")
+	be.Err(t, err, nil)
+	be.Equal(t, len(got.Filters), 1)
+
+	be.Err(t, store.Delete("Neighbors"), nil)
+	_, err = store.Get("Neighbors")
+	be.True(t, errors.Is(err, ErrNotFound))
+	be.True(t, errors.Is(store.Delete("Neighbors"), ErrNotFound))
+
+	be.True(t, errors.Is(store.Save(SavedQuery{Name: " "}), ErrInvalidArgument))
+	be.True(t, errors.Is(store.Save(SavedQuery{
+		Name:    "Bad",
+		Filters: []Filter{{Field: "bogus", Op: FilterEquals, Value: "x"}},
+	}), ErrInvalidArgument))
+
+	var runErr error
+	for _, err := range store.Run(context.Background(), "Missing") {
+		runErr = err
+	}
+	be.True(t, errors.Is(runErr, ErrNotFound))
+}
+
+func TestPatchLabeledStrings(t *testing.T) {
+	values := []LabeledValue[string]{
+		{Identifier: "1", Label: "home", Value: "a@example.com"},
+		{Identifier: "2", Label: "work", Value: "b@example.com"},
+	}
+	got := patchLabeledStrings(values,
+		[]LabeledValue[string]{{Label: "work", Value: "C@example.com"}, {Label: "home", Value: "A@EXAMPLE.COM"}},
+		[]string{"b@example.com"},
+		emailAddressesMatch,
+	)
+	be.Equal(t, got, []LabeledValue[string]{
+		{Identifier: "1", Label: "home", Value: "a@example.com"},
+		{Label: "work", Value: "C@example.com"},
+	})
+
+	phones := patchLabeledStrings(
+		[]LabeledValue[string]{{Label: "mobile", Value: "+1 (555) 123-4567"}},
+		[]LabeledValue[string]{{Label: "mobile", Value: "555.123.4567"}},
+		nil,
+		PhoneNumbersMatch,
+	)
+	be.Equal(t, len(phones), 1)
+}
+
+func TestUpdateContactListPatchValidation(t *testing.T) {
+	ctx := context.Background()
+	_, err := UpdateContact(ctx, UpdateContactInput{
+		Identifier:        "id",
+		EmailAddresses:    &[]LabeledValue[string]{},
+		AddEmailAddresses: []LabeledValue[string]{{Value: "a@example.com"}},
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = UpdateContact(ctx, UpdateContactInput{
+		Identifier:         "id",
+		PhoneNumbers:       &[]LabeledValue[string]{},
+		RemovePhoneNumbers: []string{"555"},
+	})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = UpdateContact(ctx, UpdateContactInput{Identifier: "id", RemoveEmailAddresses: []string{" "}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	_, err = UpdateContact(ctx, UpdateContactInput{Identifier: "id", AddPhoneNumbers: []LabeledValue[string]{{Label: "mobile"}}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestUpsertPatch(t *testing.T) {
+	existing := Contact{
+		Identifier:       "id",
+		GivenName:        "Ana",
+		OrganizationName: "Old Co",
+		Birthday:         &DateComponents{Month: 1, Day: 2},
+		EmailAddresses:   []LabeledValue[string]{{Identifier: "e1", Label: "work", Value: "ana@example.com"}},
+	}
+
+	_, changed := upsertPatch(existing, Contact{GivenName: "Ana", EmailAddresses: []LabeledValue[string]{{Value: "ANA@example.com"}}})
+	be.True(t, !changed)
+
+	patch, changed := upsertPatch(existing, Contact{
+		OrganizationName: "New Co",
+		Birthday:         &DateComponents{Month: 3, Day: 4},
+		EmailAddresses:   []LabeledValue[string]{{Label: "home", Value: "ana@home.example"}},
+		PhoneNumbers:     []LabeledValue[string]{{Label: "mobile", Value: "555-0100"}},
+	})
+	be.True(t, changed)
+	be.Equal(t, patch.Identifier, "id")
+	be.True(t, patch.GivenName == nil)
+	be.Equal(t, *patch.OrganizationName, "New Co")
+	be.Equal(t, *patch.Birthday, DateComponents{Month: 3, Day: 4})
+	be.True(t, patch.EmailAddresses == nil)
+	be.True(t, patch.PhoneNumbers == nil)
+	be.Equal(t, patch.AddEmailAddresses, []LabeledValue[string]{{Label: "home", Value: "ana@home.example"}})
+	be.Equal(t, patch.AddPhoneNumbers, []LabeledValue[string]{{Label: "mobile", Value: "555-0100"}})
+}
+
+func TestUpsertContactInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	_, err := UpsertContact(ctx, UpsertContactInput{Contact: Contact{EmailAddresses: []LabeledValue[string]{{Value: "a@example.com"}}}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = UpsertContact(ctx, UpsertContactInput{Contact: Contact{GivenName: "x"}, MatchOn: []UpsertMatchKey{UpsertMatchEmail}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = UpsertContact(ctx, UpsertContactInput{Contact: Contact{GivenName: "x"}, MatchOn: []UpsertMatchKey{"name"}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestCSVRecordRoundTrip(t *testing.T) {
+	c := Contact{
+		Identifier:       "id-1",
+		GivenName:        "Ana",
+		FamilyName:       "Lopez",
+		OrganizationName: "Acme",
+		EmailAddresses:   []LabeledValue[string]{{Label: "work", Value: "ana@acme.example"}, {Value: "ana@home.example"}},
+		PhoneNumbers:     []LabeledValue[string]{{Label: "mobile", Value: "+1 555 010 6000"}},
+	}
+	record := contactCSVRecord(c, []string{"Vendors", "VIP"}, DefaultCSVColumns)
+	be.Equal(t, record, []string{
+		"id-1", "Ana", "", "Lopez", "", "Acme", "", "",
+		"work:ana@acme.example; ana@home.example",
+		"mobile:+1 555 010 6000",
+		"Vendors; VIP",
+	})
+
+	header := make([]string, len(DefaultCSVColumns))
+	for i, col := range DefaultCSVColumns {
+		header[i] = strings.ToUpper(col.Header)
+	}
+	got, groups := csvRecordContact(record, csvHeaderFields(header, DefaultCSVColumns))
+	be.Equal(t, groups, []string{"Vendors", "VIP"})
+	be.Equal(t, got.Identifier, "")
+	be.Equal(t, got.GivenName, "Ana")
+	be.Equal(t, got.FamilyName, "Lopez")
+	be.Equal(t, got.EmailAddresses, c.EmailAddresses)
+	be.Equal(t, got.PhoneNumbers, c.PhoneNumbers)
+
+	phonetic := []CSVField{CSVFieldPhoneticGivenName, CSVFieldPhoneticFamilyName, CSVFieldPreviousFamilyName}
+	kana, _ := csvRecordContact([]string{"Ken", "Yamada", "Suzuki"}, phonetic)
+	be.Equal(t, kana.PhoneticGivenName, "Ken")
+	be.Equal(t, kana.PhoneticFamilyName, "Yamada")
+	be.Equal(t, kana.PreviousFamilyName, "Suzuki")
+
+	org, _ := csvRecordContact([]string{"Widgets Inc", "ignored"}, []CSVField{CSVFieldOrganizationName, ""})
+	be.Equal(t, org.ContactType, ContactTypeOrganization)
+	be.Equal(t, org.OrganizationName, "Widgets Inc")
+}
+
+func TestCSVRecordSeparatorRoundTrip(t *testing.T) {
+	c := Contact{
+		GivenName: "Ana",
+		EmailAddresses: []LabeledValue[string]{
+			{Label: "Work: main", Value: "x@y.z"},
+			{Value: `back\slash@y.z`},
+		},
+		PhoneNumbers: []LabeledValue[string]{
+			{Label: "mobile", Value: "+1 555 123 4567;ext=89"},
+			{Value: "tel:+1 555 010 6000"},
+		},
+	}
+	groups := []string{"Team; A", "Ops:East", `C:\Shared`}
+	fields := []CSVField{CSVFieldGivenName, CSVFieldEmailAddresses, CSVFieldPhoneNumbers, CSVFieldGroups}
+	columns := make([]CSVColumn, len(fields))
+	for i, f := range fields {
+		columns[i] = CSVColumn{Header: string(f), Field: f}
+	}
+
+	record := contactCSVRecord(c, groups, columns)
+	be.Equal(t, record[1], `Work\: main:x@y.z; back\\slash@y.z`)
+	be.Equal(t, record[3], `Team\; A; Ops\:East; C\:\\Shared`)
+
+	got, gotGroups := csvRecordContact(record, fields)
+	be.Equal(t, got.EmailAddresses, c.EmailAddresses)
+	be.Equal(t, got.PhoneNumbers, c.PhoneNumbers)
+	be.Equal(t, gotGroups, groups)
+}
+
+func TestCSVColumnValidation(t *testing.T) {
+	ctx := context.Background()
+	_, err := ImportCSV(ctx, strings.NewReader("a\n"), ImportCSVInput{Columns: []CSVColumn{{Header: "A", Field: "bogus"}}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ExportCSV(ctx, &bytes.Buffer{}, ExportCSVInput{Columns: []CSVColumn{
+		{Header: "Name", Field: CSVFieldGivenName},
+		{Header: "name", Field: CSVFieldFamilyName},
+	}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+
+	results, err := ImportCSV(ctx, strings.NewReader(""), ImportCSVInput{})
+	be.Err(t, err, nil)
+	be.Equal(t, len(results), 0)
+}
+
+func TestIsNoteEntitlementError(t *testing.T) {
+	be.True(t, isNoteEntitlementError("note entitlement error 134092: The operation couldn't be completed."))
+	be.True(t, isNoteEntitlementError("The operation couldn't be completed. (Cocoa error 134092.)"))
+	be.True(t, !isNoteEntitlementError("contact not found"))
+
+	err := newBridgeOpError("GetContactNote", "id-1", "note entitlement error 134092: Unauthorized keys")
+	be.True(t, errors.Is(err, ErrNoteEntitlementMissing))
+	be.True(t, !errors.Is(err, ErrPermissionDenied))
+	be.True(t, strings.Contains(err.Error(), noteEntitlementRemedy))
+
+	_, err = GetContactNote(context.Background(), " ")
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestContactCursor(t *testing.T) {
+	a := Contact{Identifier: "b-id", GivenName: "Ana", FamilyName: "Lopez"}
+	k, err := parseContactCursor(ContactCursor(a))
+	be.Err(t, err, nil)
+	be.Equal(t, k, sortKeyOf(a))
+
+	// Same name sorts by identifier; case is ignored.
+	b := Contact{Identifier: "c-id", GivenName: "ana", FamilyName: "LOPEZ"}
+	be.True(t, compareSortKeys(sortKeyOf(a), sortKeyOf(b)) < 0)
+	be.True(t, compareSortKeys(sortKeyOf(Contact{GivenName: "Bo"}), sortKeyOf(a)) > 0)
+
+	_, err = parseContactCursor("not base64!")
+	be.True(t, err != nil)
+	_, err = parseContactCursor(ContactCursor(Contact{GivenName: "x"}))
+	be.True(t, err != nil)
+
+	for _, err := range ListContacts(context.Background(), ListContactsInput{After: "%%%"}) {
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+	}
+}
+
+func TestStreamContactsInvalidFilters(t *testing.T) {
+	count := 0
+	for _, err := range StreamContacts(context.Background(), []Filter{{Field: "bogus", Op: FilterEquals}}) {
+		be.True(t, errors.Is(err, ErrInvalidArgument))
+		count++
+	}
+	be.Equal(t, count, 1)
+}
+
+func TestJSONEncoding(t *testing.T) {
+	data, err := json.Marshal(Contact{
+		Identifier:     "id-1",
+		ContactType:    ContactTypeOrganization,
+		GivenName:      "Ana",
+		EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "ana@example.com"}},
+	})
+	be.Err(t, err, nil)
+	var fields map[string]any
+	be.Err(t, json.Unmarshal(data, &fields), nil)
+	be.Equal(t, fields["identifier"], "id-1")
+	be.Equal(t, fields["contact_type"], "organization")
+	be.Equal(t, fields["given_name"], "Ana")
+	_, hasBirthday := fields["birthday"]
+	be.True(t, !hasBirthday)
+
+	var f Filter
+	be.Err(t, json.Unmarshal([]byte(`{"field":"emailAddresses","op":"not_contains","value":"@x"}`), &f), nil)
+	be.Equal(t, f, Filter{Field: ContactFieldEmailAddresses, Op: FilterNotContains, Value: "@x"})
+	be.True(t, errors.Is(json.Unmarshal([]byte(`{"op":"like"}`), &f), ErrInvalidArgument))
+	_, err = json.Marshal(Filter{Op: FilterOp(9)})
+	be.True(t, err != nil)
+
+	data, err = json.Marshal(CreateContactResult{
+		Err: newInvalidArg("CreateContact", "", "givenName is required"),
+	})
+	be.Err(t, err, nil)
+	var result struct {
+		Error errorJSON `json:"error"`
+	}
+	be.Err(t, json.Unmarshal(data, &result), nil)
+	be.Equal(t, result.Error.Op, "CreateContact")
+	be.Equal(t, result.Error.Code, "invalid_argument")
+
+	data, err = json.Marshal(UpsertContactResult{Err: &OpError{Op: "UpsertContacts", Err: ErrAmbiguous}})
+	be.Err(t, err, nil)
+	be.Err(t, json.Unmarshal(data, &result), nil)
+	be.Equal(t, result.Error.Code, "ambiguous")
+
+	data, err = json.Marshal(ImportCSVResult{Row: 2})
+	be.Err(t, err, nil)
+	be.True(t, !strings.Contains(string(data), `"error"`))
+}
+
+func TestErrorCode(t *testing.T) {
+	be.Equal(t, ErrorCode(nil), "")
+	be.Equal(t, ErrorCode(&OpError{Op: "GetContact", Err: ErrNotFound}), "not_found")
+	be.Equal(t, ErrorCode(&OpError{Op: "UpsertContact", Err: ErrAmbiguous}), "ambiguous")
+	be.Equal(t, ErrorCode(&OpError{Op: "CreateGroup", Err: ErrConflict}), "conflict")
+	be.Equal(t, ErrorCode(errors.New("boom")), "internal")
+}
+
+func TestGroupsNamed(t *testing.T) {
+	groups := []Group{
+		{Identifier: "g1", Name: "Vendors"},
+		{Identifier: "g2", Name: " vendors "},
+		{Identifier: "g3", Name: "Vendors Old"},
+	}
+	ids := func(gs []Group) []string {
+		var out []string
+		for _, g := range gs {
+			out = append(out, g.Identifier)
+		}
+		return out
+	}
+	be.Equal(t, ids(groupsNamed(groups, "VENDORS", "")), []string{"g1", "g2"})
+	be.Equal(t, ids(groupsNamed(groups, "vendors", "g1")), []string{"g2"})
+	be.Equal(t, len(groupsNamed(groups, "Clients", "")), 0)
+}
+
+func TestGroupDuplicatePolicyValidation(t *testing.T) {
+	ctx := context.Background()
+	_, err := CreateGroup(ctx, CreateGroupInput{Name: "x", OnDuplicateName: "merge"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	name := "y"
+	_, err = UpdateGroup(ctx, UpdateGroupInput{Identifier: "g", Name: &name, OnDuplicateName: DuplicateGroupNameReuse})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = FindGroupByName(ctx, " ", "")
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestDeleteConfirmToken(t *testing.T) {
+	now := time.Now()
+	ids := []string{"id-1", "id-2"}
+	deletions := []ContactDeletion{
+		{Contact: Contact{Identifier: "id-1", GivenName: "Ana"}, GroupIDs: []string{"g1"}},
+		{Contact: Contact{Identifier: "id-2", GivenName: "Ben"}, GroupIDs: []string{}},
+	}
+	token, err := deleteConfirmToken(now.Unix(), ids, deletions)
+	be.Err(t, err, nil)
+
+	issued, err := checkDeleteConfirmToken(token, []string{"id-2", "id-1"}, now)
+	be.Err(t, err, nil)
+	be.Equal(t, issued, now.Unix())
+	same, err := deleteConfirmToken(issued, []string{"id-2", "id-1"}, []ContactDeletion{deletions[1], deletions[0]})
+	be.Err(t, err, nil)
+	be.Equal(t, same, token)
+
+	changed := slices.Clone(deletions)
+	changed[0].GroupIDs = nil
+	other, err := deleteConfirmToken(issued, ids, changed)
+	be.Err(t, err, nil)
+	be.True(t, other != token)
+
+	_, err = checkDeleteConfirmToken(token, []string{"id-1"}, now)
+	be.Err(t, err)
+	_, err = checkDeleteConfirmToken(token, ids, now.Add(DeleteConfirmTokenTTL+time.Minute))
+	be.Err(t, err)
+	_, err = checkDeleteConfirmToken("", ids, now)
+	be.Err(t, err)
+	// A token cannot be derived from the identifiers alone.
+	sum := sha256.Sum256([]byte("id-1\nid-2"))
+	_, err = checkDeleteConfirmToken(hex.EncodeToString(sum[:16]), ids, now)
+	be.Err(t, err)
+}
+
+func TestDeleteContactsGuardrails(t *testing.T) {
+	ctx := context.Background()
+	_, err := DeleteContacts(ctx, DeleteContactsInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: []string{"a", " "}, DryRun: true})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: []string{"a", "b", "c"}, MaxDeletes: 2, DryRun: true})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: []string{"a"}, MaxDeletes: -1, DryRun: true})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: []string{"a"}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	token, err := deleteConfirmToken(time.Now().Unix(), []string{"b"}, nil)
+	be.Err(t, err, nil)
+	_, err = DeleteContacts(ctx, DeleteContactsInput{Identifiers: []string{"a"}, ConfirmToken: token})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestLabelNormalization(t *testing.T) {
+	be.Equal(t, friendlyLabel("_$!<Work>!$_"), "work")
+	be.Equal(t, friendlyLabel("_$!<Mobile>!$_"), "mobile")
+	be.Equal(t, friendlyLabel("_$!<HomeFAX>!$_"), "home fax")
+	be.Equal(t, friendlyLabel("iPhone"), "iphone")
+	be.Equal(t, friendlyLabel("_$!<Spouse>!$_"), "spouse")
+	be.Equal(t, friendlyLabel("Gym"), "Gym")
+	be.Equal(t, friendlyLabel(""), "")
+
+	be.Equal(t, systemLabel("work", ""), "_$!<Work>!$_")
+	be.Equal(t, systemLabel(" Mobile ", ""), "_$!<Mobile>!$_")
+	be.Equal(t, systemLabel("iPhone", ""), "iPhone")
+	be.Equal(t, systemLabel("Gym", ""), "Gym")
+	be.Equal(t, systemLabel("", ""), "")
+	// An unchanged label keeps its stored form, even without a mapping.
+	be.Equal(t, systemLabel("spouse", "_$!<Spouse>!$_"), "_$!<Spouse>!$_")
+	// A changed label is mapped afresh.
+	be.Equal(t, systemLabel("home", "_$!<Work>!$_"), "_$!<Home>!$_")
+}
+
+// memRemote is an in-memory RemoteAddressBook.
+type memRemote struct {
+	contacts []RemoteContact
+	nextID   int
+}
+
+func (m *memRemote) ListContacts(context.Context) ([]RemoteContact, error) {
+	return slices.Clone(m.contacts), nil
+}
+
+func (m *memRemote) CreateContact(_ context.Context, c Contact) (RemoteContact, error) {
+	m.nextID++
+	rc := RemoteContact{RemoteID: fmt.Sprintf("people/%d", m.nextID), Contact: c}
+	m.contacts = append(m.contacts, rc)
+	return rc, nil
+}
+
+func (m *memRemote) UpdateContact(_ context.Context, rc RemoteContact) (RemoteContact, error) {
+	i := slices.IndexFunc(m.contacts, func(x RemoteContact) bool { return x.RemoteID == rc.RemoteID })
+	if i < 0 {
+		return RemoteContact{}, ErrNotFound
+	}
+	m.contacts[i] = rc
+	return rc, nil
+}
+
+func TestPlanSync(t *testing.T) {
+	email := func(v string) []LabeledValue[string] { return []LabeledValue[string]{{Label: "work", Value: v}} }
+	source := []syncRecord{
+		{id: "l1", contact: Contact{GivenName: "Ana", EmailAddresses: email("ana@example.com"), JobTitle: "CTO"}},
+		{id: "l2", contact: Contact{GivenName: "Ben", EmailAddresses: email("ben@example.com")}},
+		{id: "l3", contact: Contact{GivenName: "Cy", EmailAddresses: email("cy@example.com")}},
+		{id: "l4", contact: Contact{GivenName: "Dee"}},
+		{id: "l5", contact: Contact{GivenName: "Eve", EmailAddresses: email("eve@example.com")}},
+	}
+	target := []syncRecord{
+		{id: "r1", contact: Contact{GivenName: "Ana", EmailAddresses: email("ANA@example.com")}},
+		{id: "r2", contact: Contact{GivenName: "Ben", EmailAddresses: email("ben@example.com")}},
+		{id: "r5", contact: Contact{EmailAddresses: email("eve@example.com")}},
+		{id: "r6", contact: Contact{EmailAddresses: email("eve@example.com")}},
+	}
+	changes, _ := planSync(source, target, []UpsertMatchKey{UpsertMatchEmail})
+	be.Equal(t, len(changes), 5)
+
+	be.Equal(t, changes[0].Action, SyncActionUpdate)
+	be.Equal(t, changes[0].TargetID, "r1")
+	be.Equal(t, changes[0].Contact.JobTitle, "CTO")
+	be.Equal(t, len(changes[0].Contact.EmailAddresses), 1)
+
+	be.Equal(t, changes[1].Action, SyncActionNone)
+	be.Equal(t, changes[2].Action, SyncActionCreate)
+	be.Equal(t, changes[2].TargetID, "")
+	be.Equal(t, changes[3].Action, SyncActionSkip)
+	be.True(t, errors.Is(changes[4].Err, ErrAmbiguous))
+}
+
+func TestPlanSyncSharedSourceKey(t *testing.T) {
+	email := func(v ...string) []LabeledValue[string] {
+		out := make([]LabeledValue[string], len(v))
+		for i, e := range v {
+			out[i] = LabeledValue[string]{Label: "work", Value: e}
+		}
+		return out
+	}
+	phone := func(v string) []LabeledValue[string] { return []LabeledValue[string]{{Label: "mobile", Value: v}} }
+	source := []syncRecord{
+		{id: "l1", contact: Contact{GivenName: "Ana", EmailAddresses: email("ana@example.com")}},
+		{id: "l2", contact: Contact{GivenName: "Ana", JobTitle: "CTO", EmailAddresses: email("ANA@example.com", "ana@work.example")}},
+		{id: "l3", contact: Contact{GivenName: "Ana", EmailAddresses: email("ana@work.example")}},
+		{id: "l4", contact: Contact{GivenName: "Bo", PhoneNumbers: phone("+1 555 010 7788")}},
+		{id: "l5", contact: Contact{GivenName: "Cy", EmailAddresses: email("cy@example.com")}},
+		{id: "l6", contact: Contact{GivenName: "Bo", EmailAddresses: email("cy@example.com"), PhoneNumbers: phone("555-010-7788")}},
+	}
+	changes, creators := planSync(source, nil, []UpsertMatchKey{UpsertMatchEmail, UpsertMatchPhone})
+	be.Equal(t, len(changes), 6)
+
+	be.Equal(t, changes[0].Action, SyncActionCreate)
+	be.Equal(t, creators[0], -1)
+	// l2 shares ana@example.com with the planned create of l1, so it
+	// becomes an update of that record instead of a second copy.
+	be.Equal(t, changes[1].Action, SyncActionUpdate)
+	be.Err(t, changes[1].Err, nil)
+	be.Equal(t, changes[1].TargetID, "")
+	be.Equal(t, creators[1], 0)
+	be.Equal(t, changes[1].Contact.JobTitle, "CTO")
+	be.Equal(t, len(changes[1].Contact.EmailAddresses), 2)
+	// l3 matches the record as l2 leaves it and adds nothing.
+	be.Equal(t, changes[2].Action, SyncActionNone)
+
+	be.Equal(t, changes[3].Action, SyncActionCreate)
+	be.Equal(t, changes[4].Action, SyncActionCreate)
+	// l6 matches both planned creates, by phone and by email.
+	be.True(t, errors.Is(changes[5].Err, ErrAmbiguous))
+}
+
+func TestSyncContactsToRemoteSharedSourceKey(t *testing.T) {
+	requireAuthorized(t)
+	ctx := context.Background()
+
+	shared := "cuh-sync-shared@example.com"
+	first, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "SyncShared", EmailAddresses: []LabeledValue[string]{{Label: "home", Value: shared}}}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, first.Identifier)
+	second, err := CreateContact(ctx, CreateContactInput{Contact: Contact{GivenName: testPrefix + "SyncShared", JobTitle: "CUH Sync", EmailAddresses: []LabeledValue[string]{{Label: "work", Value: shared}}}})
+	be.Err(t, err, nil)
+	defer cleanupContact(t, ctx, second.Identifier)
+
+	remote := &memRemote{}
+	_, err = SyncContacts(ctx, SyncContactsInput{Remote: remote, Direction: SyncToRemote, MatchOn: []UpsertMatchKey{UpsertMatchEmail}})
+	be.Err(t, err, nil)
+	var copies []RemoteContact
+	for _, rc := range remote.contacts {
+		if slices.ContainsFunc(rc.Contact.EmailAddresses, func(e LabeledValue[string]) bool { return e.Value == shared }) {
+			copies = append(copies, rc)
+		}
+	}
+	be.Equal(t, len(copies), 1)
+	be.Equal(t, copies[0].Contact.JobTitle, "CUH Sync")
+}
+
+// fakeCardDAV is a minimal CardDAV collection at /book/ serving
+// addressbook-query, GET, and conditional PUT.
+type fakeCardDAV struct {
+	cards map[string]string
+	etags map[string]int
+}
+
+func newFakeCardDAV(t *testing.T) (*fakeCardDAV, *httptest.Server) {
+	f := &fakeCardDAV{cards: map[string]string{}, etags: map[string]int{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "me" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		etag := func(path string) string { return fmt.Sprintf(`"%d"`, f.etags[path]) }
+		switch r.Method {
+		case "REPORT":
+			// RFC 6352 section 8.6 requires the filter element; servers
+			// such as Google reject a query without it.
+			var query struct {
+				XMLName xml.Name  `xml:"urn:ietf:params:xml:ns:carddav addressbook-query"`
+				Filter  *struct{} `xml:"urn:ietf:params:xml:ns:carddav filter"`
+			}
+			if r.URL.Path != "/book/" || r.Header.Get("Depth") != "1" ||
+				xml.NewDecoder(r.Body).Decode(&query) != nil || query.Filter == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:card="urn:ietf:params:xml:ns:carddav">`)
+			paths := make([]string, 0, len(f.cards))
+			for path := range f.cards {
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			for _, path := range paths {
+				var data strings.Builder
+				_ = xml.EscapeText(&data, []byte(f.cards[path]))
+				fmt.Fprintf(w, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag><card:address-data>%s</card:address-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, path, etag(path), data.String())
+			}
+			fmt.Fprint(w, `</d:multistatus>`)
+		case http.MethodGet:
+			card, ok := f.cards[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", etag(r.URL.Path))
+			fmt.Fprint(w, card)
+		case http.MethodPut:
+			_, exists := f.cards[r.URL.Path]
+			if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag(r.URL.Path)) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			f.cards[r.URL.Path] = string(body)
+			f.etags[r.URL.Path]++
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func TestCardDAVAddressBook(t *testing.T) {
+	ctx := context.Background()
+	f, srv := newFakeCardDAV(t)
+	f.cards["/book/ana.vcf"] = strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"UID:ana",
+		"FN:Ana Lima",
+		"N:Lima;Ana;;;",
+		"item1.EMAIL;type=INTERNET:ana@example.com",
+		"item1.X-ABLabel:_$!<Home>!$_",
+		"TEL;TYPE=CELL;TYPE=VOICE:+1 555 010 1234",
+		"PHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQ",
+		"X-CUSTOM:keep me",
+		"END:VCARD",
+	}, "\r\n")
+	book := &CardDAVAddressBook{URL: srv.URL + "/book", Username: "me", Password: "secret"}
+
+	listed, err := book.ListContacts(ctx)
+	be.Err(t, err, nil)
+	be.Equal(t, len(listed), 1)
+	be.Equal(t, listed[0].RemoteID, "/book/ana.vcf")
+	be.Equal(t, listed[0].Contact.GivenName, "Ana")
+	be.Equal(t, listed[0].Contact.EmailAddresses, []LabeledValue[string]{{Label: "home", Value: "ana@example.com"}})
+	be.Equal(t, listed[0].Contact.PhoneNumbers, []LabeledValue[string]{{Label: "mobile", Value: "+1 555 010 1234"}})
+
+	created, err := book.CreateContact(ctx, Contact{
+		GivenName:      "Ben",
+		FamilyName:     "Ode",
+		JobTitle:       "CTO",
+		EmailAddresses: []LabeledValue[string]{{Label: "work", Value: "ben@example.com"}},
+	})
+	be.Err(t, err, nil)
+	be.True(t, strings.HasPrefix(created.RemoteID, "/book/"))
+	be.Equal(t, created.Contact.JobTitle, "CTO")
+	be.True(t, strings.Contains(f.cards[created.RemoteID], "FN:Ben Ode\r\n"))
+	be.True(t, strings.Contains(f.cards[created.RemoteID], "UID:"))
+
+	ana := listed[0]
+	ana.Contact.JobTitle = "Engineer"
+	ana.Contact.EmailAddresses = []LabeledValue[string]{{Label: "work", Value: "ana@work.example"}}
+	updated, err := book.UpdateContact(ctx, ana)
+	be.Err(t, err, nil)
+	be.Equal(t, updated.RemoteID, "/book/ana.vcf")
+	be.Equal(t, updated.Contact.JobTitle, "Engineer")
+	be.Equal(t, updated.Contact.EmailAddresses, []LabeledValue[string]{{Label: "work", Value: "ana@work.example"}})
+	card := f.cards["/book/ana.vcf"]
+	// Unmapped properties survive; the replaced email takes its label along.
+	be.True(t, strings.Contains(card, "PHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQ"))
+	be.True(t, strings.Contains(card, "X-CUSTOM:keep me"))
+	be.True(t, strings.Contains(card, "UID:ana"))
+	be.True(t, !strings.Contains(card, "X-ABLabel") && !strings.Contains(card, "X-ABLABEL"))
+
+	_, err = book.UpdateContact(ctx, RemoteContact{RemoteID: "/book/missing.vcf", Contact: Contact{GivenName: "X"}})
+	be.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = (&CardDAVAddressBook{URL: srv.URL + "/book/", Username: "me", Password: "wrong"}).ListContacts(ctx)
+	be.Err(t, err)
+	_, err = (&CardDAVAddressBook{URL: "not a url"}).ListContacts(ctx)
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestVCardRoundTrip(t *testing.T) {
+	in := Contact{
+		ContactType:        ContactTypeOrganization,
+		NamePrefix:         "Dr.",
+		GivenName:          "José",
+		MiddleName:         "K",
+		FamilyName:         "Núñez; Jr",
+		NameSuffix:         "PhD",
+		Nickname:           "Pepe",
+		PreviousFamilyName: "Ruiz",
+		PhoneticGivenName:  "Hosay",
+		OrganizationName:   "Acme, Inc.",
+		DepartmentName:     "R&D",
+		JobTitle:           "Lead",
+		Note:               strings.Repeat("Línea larga con acentos. ", 8) + "\nSecond line\\path",
+		Birthday:           &DateComponents{Month: 2, Day: 29},
+		PhoneNumbers: []LabeledValue[string]{
+			{Label: "mobile", Value: "+1 555 010 1234"},
+			{Label: "work fax", Value: "+1 555 010 9999"},
+		},
+		EmailAddresses:  []LabeledValue[string]{{Label: "home", Value: "jose@example.com"}},
+		PostalAddresses: []LabeledValue[PostalAddress]{{Label: "work", Value: PostalAddress{Street: "1 Main St\nSuite 2", City: "Springfield", State: "IL", PostalCode: "62701", Country: "USA"}}},
+		URLAddresses:    []LabeledValue[string]{{Label: "homepage", Value: "https://example.com/a,b"}},
+	}
+	card := encodeVCard(in, nil)
+	for _, line := range strings.Split(card, "\r\n") {
+		be.True(t, len(line) <= 75)
+	}
+	out, err := parseVCard(card)
+	be.Err(t, err, nil)
+	be.Equal(t, out, in)
+
+	d, err := parseVCard("BEGIN:VCARD\nVERSION:2.1\nN:Doe;Jo\nTEL;HOME:555\nBDAY:19800102\nEND:VCARD\n")
+	be.Err(t, err, nil)
+	be.Equal(t, d.FamilyName, "Doe")
+	be.Equal(t, d.PhoneNumbers, []LabeledValue[string]{{Label: "home", Value: "555"}})
+	be.Equal(t, *d.Birthday, DateComponents{Year: 1980, Month: 1, Day: 2})
+
+	_, err = parseVCard("N:Doe")
+	be.Err(t, err)
+}
+
+func TestSyncContactsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	_, err := SyncContacts(ctx, SyncContactsInput{Direction: SyncToRemote, MatchOn: []UpsertMatchKey{UpsertMatchEmail}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = SyncContacts(ctx, SyncContactsInput{Remote: &memRemote{}, Direction: "both", MatchOn: []UpsertMatchKey{UpsertMatchEmail}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = SyncContacts(ctx, SyncContactsInput{Remote: &memRemote{}, Direction: SyncToLocal})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"+1 (555) 123-4567", "+15551234567"},
+		{"555.123.4567", "5551234567"},
+		{"555-123-4567 x89", "5551234567"},
+		{"555-123-4567 ext. 89", "5551234567"},
+		{"0044 20 7946 0000", "+442079460000"},
+		{"tel:+44-20-7946-0000", "+442079460000"},
+		{"+1 555-123-4567;ext=89", "+15551234567"},
+		{"555-123-4567 #89", "5551234567"},
+		{"1-800-FLOWERS", "18003569377"},
+		{"1-800-XBOX", "18009269"},
+		{"1-800-EXPRESS", "18003977377"},
+		{"", ""},
+		{"n/a", ""},
+	}
+	for _, tc := range cases {
+		be.Equal(t, NormalizePhoneNumber(tc.in), tc.want)
+	}
+}
+
+func TestPhoneNumbersMatch(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"+15551234567", "(555) 123-4567", true},
+		{"1 555 123 4567", "+1-555-123-4567", true},
+		{"+44 20 7946 0000", "020 7946 0000", true},
+		{"+15551234567", "+15551234568", false},
+		{"+15551234567", "+445551234567", false},
+		{"12345", "9912345", false},
+		{"12345", "12345", true},
+		{"555-1234", "212-555-1234", false},
+		{"555-1234", "415-555-1234", false},
+		{"555-1234", "+1 212 555 1234", false},
+		{"5551234567", "15551234567", false},
+		{"+15551234567", "+5551234567", false},
+		{"1-800-FLOWERS", "+1 800 356 9377", true},
+		{"1-800-FLOWERS", "1800", false},
+		{"", "", false},
+	}
+	for _, tc := range cases {
+		be.Equal(t, PhoneNumbersMatch(tc.a, tc.b), tc.want)
+	}
+}
+
+// TestPhoneMatchesFilter covers the Go side of the bridge's phone filter
+// callback.
+func TestPhoneMatchesFilter(t *testing.T) {
+	cases := []struct {
+		stored, query string
+		op            FilterOp
+		match, ok     bool
+	}{
+		{"(555) 010-4477", "+1 555 010 4477", FilterEquals, true, true},
+		{"(555) 010-4477", "+1 555 010 4478", FilterEquals, false, true},
+		{"555-1234", "212-555-1234", FilterEquals, false, true},
+		{"(555) 010-4477", "010-44", FilterContains, true, true},
+		{"+1 555 010 4477 x89", "89", FilterContains, false, true},
+		{"1-800-FLOWERS", "3569377", FilterContains, true, true},
+		{"(555) 010-4477", "999", FilterNotContains, true, true},
+		{"(555) 010-4477", "0104", FilterNotContains, false, true},
+		{"(555) 010-4477", "mobile", FilterContains, false, false},
+	}
+	for _, tc := range cases {
+		match, ok := phoneMatchesFilter(tc.stored, tc.query, tc.op)
+		be.Equal(t, ok, tc.ok)
+		be.Equal(t, match, tc.match)
+	}
+}
+
+func TestFindDuplicateSets(t *testing.T) {
+	candidates := []Contact{
+		{Identifier: "a", ContainerID: "c1", GivenName: "Ann", FamilyName: "Lee", EmailAddresses: []LabeledValue[string]{{Value: "Ann@Example.com"}}},
+		{Identifier: "b", ContainerID: "c1", GivenName: "Annie", EmailAddresses: []LabeledValue[string]{{Value: " ann@example.com"}}},
+		{Identifier: "c", ContainerID: "c1", PhoneNumbers: []LabeledValue[string]{{Value: "+1 (555) 010-2000"}}},
+		{Identifier: "d", ContainerID: "c1", PhoneNumbers: []LabeledValue[string]{{Value: "555-010-2000"}}},
+		{Identifier: "e", ContainerID: "c2", PhoneNumbers: []LabeledValue[string]{{Value: "5550102000"}}},
+		{Identifier: "f", ContainerID: "c1", GivenName: "ann", FamilyName: "LEE."},
+		{Identifier: "g", ContainerID: "c1", GivenName: "Bob"},
+	}
+
+	sets := findDuplicateSets(candidates, false)
+	be.Equal(t, len(sets), 2)
+	be.Equal(t, len(sets[0].Contacts), 2)
+	be.Equal(t, sets[0].Contacts[0].Identifier, "a")
+	be.Equal(t, sets[0].Contacts[1].Identifier, "b")
+	be.Equal(t, sets[0].Reasons, []DuplicateReason{DuplicateReasonEmail})
+	be.Equal(t, sets[1].Contacts[0].Identifier, "c")
+	be.Equal(t, sets[1].Contacts[1].Identifier, "d")
+	be.Equal(t, sets[1].Reasons, []DuplicateReason{DuplicateReasonPhone})
+
+	sets = findDuplicateSets(candidates, true)
+	be.Equal(t, len(sets), 2)
+	be.Equal(t, len(sets[0].Contacts), 3)
+	be.Equal(t, sets[0].Reasons, []DuplicateReason{DuplicateReasonEmail, DuplicateReasonName})
+}
+
+func TestMergeContactValues(t *testing.T) {
+	survivor := Contact{
+		Identifier:     "s",
+		GivenName:      "Ann",
+		EmailAddresses: []LabeledValue[string]{{Identifier: "e1", Label: "work", Value: "ann@example.com"}},
+		PhoneNumbers:   []LabeledValue[string]{{Identifier: "p1", Value: "+15550102000"}},
+	}
+	dup := Contact{
+		Identifier:     "d",
+		GivenName:      "Annie",
+		FamilyName:     "Lee",
+		JobTitle:       "Engineer",
+		Birthday:       &DateComponents{Month: 2, Day: 3},
+		EmailAddresses: []LabeledValue[string]{{Identifier: "e2", Value: "ANN@example.com"}, {Identifier: "e3", Label: "home", Value: "ann@home.example.com"}},
+		PhoneNumbers:   []LabeledValue[string]{{Identifier: "p2", Value: "(555) 010-2000"}},
+	}
+
+	merged := mergeContactValues(survivor, dup)
+	be.Equal(t, merged.GivenName, "Ann")
+	be.Equal(t, merged.FamilyName, "Lee")
+	be.Equal(t, merged.JobTitle, "Engineer")
+	be.Equal(t, *merged.Birthday, DateComponents{Month: 2, Day: 3})
+	be.Equal(t, merged.EmailAddresses, []LabeledValue[string]{
+		{Identifier: "e1", Label: "work", Value: "ann@example.com"},
+		{Label: "home", Value: "ann@home.example.com"},
+	})
+	be.Equal(t, len(merged.PhoneNumbers), 1)
+
+	patch, changed := contactPatch(survivor, merged)
+	be.True(t, changed)
+	be.Equal(t, patch.Identifier, "s")
+	be.True(t, patch.GivenName == nil)
+	be.Equal(t, *patch.FamilyName, "Lee")
+	be.True(t, patch.EmailAddresses != nil)
+	be.True(t, patch.PhoneNumbers == nil)
+
+	_, changed = contactPatch(survivor, survivor)
+	be.True(t, !changed)
+}
+
+func TestContactSameAs(t *testing.T) {
+	unified := Contact{Identifier: "u", Unified: true, LinkedIDs: []string{"a", "b"}}
+	be.True(t, unified.SameAs(Contact{Identifier: "u"}))
+	be.True(t, unified.SameAs(Contact{Identifier: "b"}))
+	be.True(t, Contact{Identifier: "a"}.SameAs(unified))
+	be.True(t, unified.SameAs(Contact{Identifier: "x", LinkedIDs: []string{"b", "c"}}))
+	be.True(t, !unified.SameAs(Contact{Identifier: "c"}))
+	be.True(t, !Contact{}.SameAs(Contact{}))
+}
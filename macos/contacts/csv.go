@@ -0,0 +1,534 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// CSVField identifies the contact value held by a CSV column.
+type CSVField string
+
+const (
+	CSVFieldIdentifier         CSVField = "identifier"
+	CSVFieldNamePrefix         CSVField = "namePrefix"
+	CSVFieldGivenName          CSVField = "givenName"
+	CSVFieldMiddleName         CSVField = "middleName"
+	CSVFieldFamilyName         CSVField = "familyName"
+	CSVFieldNameSuffix         CSVField = "nameSuffix"
+	CSVFieldNickname           CSVField = "nickname"
+	CSVFieldPreviousFamilyName CSVField = "previousFamilyName"
+	CSVFieldPhoneticGivenName  CSVField = "phoneticGivenName"
+	CSVFieldPhoneticMiddleName CSVField = "phoneticMiddleName"
+	CSVFieldPhoneticFamilyName CSVField = "phoneticFamilyName"
+	CSVFieldOrganizationName   CSVField = "organizationName"
+	CSVFieldDepartmentName     CSVField = "departmentName"
+	CSVFieldJobTitle           CSVField = "jobTitle"
+	// CSVFieldEmailAddresses and CSVFieldPhoneNumbers hold every value of the
+	// field separated by ";". Each entry is "label:value" or just "value".
+	// A backslash escapes a literal "\", ";", or ":" in a label or value, so
+	// "Work\: main:x@example.com" is the value x@example.com labeled
+	// "Work: main".
+	CSVFieldEmailAddresses CSVField = "emailAddresses"
+	CSVFieldPhoneNumbers   CSVField = "phoneNumbers"
+	// CSVFieldGroups holds group names separated by ";", escaped like the
+	// labeled fields.
+	CSVFieldGroups CSVField = "groups"
+)
+
+// CSVColumn maps a CSV header to a contact field.
+type CSVColumn struct {
+	Header string   `json:"header"`
+	Field  CSVField `json:"field"`
+}
+
+// DefaultCSVColumns is the column mapping used when none is given.
+var DefaultCSVColumns = []CSVColumn{
+	{Header: "Identifier", Field: CSVFieldIdentifier},
+	{Header: "First Name", Field: CSVFieldGivenName},
+	{Header: "Middle Name", Field: CSVFieldMiddleName},
+	{Header: "Last Name", Field: CSVFieldFamilyName},
+	{Header: "Nickname", Field: CSVFieldNickname},
+	{Header: "Organization", Field: CSVFieldOrganizationName},
+	{Header: "Department", Field: CSVFieldDepartmentName},
+	{Header: "Job Title", Field: CSVFieldJobTitle},
+	{Header: "Emails", Field: CSVFieldEmailAddresses},
+	{Header: "Phones", Field: CSVFieldPhoneNumbers},
+	{Header: "Groups", Field: CSVFieldGroups},
+}
+
+// ExportCSVInput controls [ExportCSV].
+type ExportCSVInput struct {
+	// Filters select the contacts to export, as in [ListContactsInput].
+	Filters []Filter `json:"filters"`
+	// Columns defines the header row and column order. Empty means
+	// [DefaultCSVColumns].
+	Columns []CSVColumn `json:"columns"`
+}
+
+// ImportCSVInput controls [ImportCSV].
+type ImportCSVInput struct {
+	// Columns maps headers to fields. Headers are matched case-insensitively;
+	// CSV columns without a mapping are ignored. Empty means
+	// [DefaultCSVColumns]. The identifier column is never imported.
+	Columns []CSVColumn `json:"columns"`
+	// ContainerID is the destination container for new contacts and the
+	// scope for group names. Empty means the default container.
+	ContainerID string `json:"container_id"`
+	// MatchOn, when set, upserts each row with [UpsertContacts] instead of
+	// always creating a new contact.
+	MatchOn []UpsertMatchKey `json:"match_on"`
+	// DryRun validates every row without saving anything.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ImportCSVResult is the outcome of one CSV data row.
+type ImportCSVResult struct {
+	// Row is the 1-based line number of the record, counting the header.
+	Row     int     `json:"row"`
+	Contact Contact `json:"contact"`
+	Created bool    `json:"created"`
+	Err     error   `json:"-"`
+}
+
+// ExportCSV writes the contacts matching input.Filters to w, one row per
+// contact. Multi-value columns join values with ";". Group names are resolved
+// from memberships of each contact's record and its linked records.
+func ExportCSV(ctx context.Context, w io.Writer, input ExportCSVInput) error {
+	columns := input.Columns
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	if err := validateCSVColumns(columns); err != nil {
+		return newInvalidArg("ExportCSV", "", err.Error())
+	}
+
+	var groupsByContact map[string][]string
+	if slices.ContainsFunc(columns, func(c CSVColumn) bool { return c.Field == CSVFieldGroups }) {
+		var err error
+		if groupsByContact, err = groupNamesByContact(ctx); err != nil {
+			return err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return &OpError{Op: "ExportCSV", Err: err}
+	}
+	for c, err := range ListContacts(ctx, ListContactsInput{Filters: input.Filters}) {
+		if err != nil {
+			return err
+		}
+		var groups []string
+		for _, id := range append([]string{c.Identifier}, c.LinkedIDs...) {
+			for _, name := range groupsByContact[id] {
+				if !slices.Contains(groups, name) {
+					groups = append(groups, name)
+				}
+			}
+		}
+		if err := cw.Write(contactCSVRecord(c, groups, columns)); err != nil {
+			return &OpError{Op: "ExportCSV", ID: c.Identifier, Err: err}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return &OpError{Op: "ExportCSV", Err: err}
+	}
+	return nil
+}
+
+// ImportCSV reads a header row followed by one contact per row, and creates
+// (or, with MatchOn, upserts) each contact. Group names in a groups column
+// must name existing groups in the destination container.
+//
+// Rows are saved together through [CreateContacts] or [UpsertContacts], so a
+// large file takes a few save requests rather than one per row. Failures are
+// reported per row in ImportCSVResult.Err so one bad row does not stop the
+// import. The returned error is non-nil only when the CSV itself cannot be
+// read, the column mapping is invalid, or ctx is done.
+func ImportCSV(ctx context.Context, r io.Reader, input ImportCSVInput) ([]ImportCSVResult, error) {
+	columns := input.Columns
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	if err := validateCSVColumns(columns); err != nil {
+		return nil, newInvalidArg("ImportCSV", "", err.Error())
+	}
+
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &OpError{Op: "ImportCSV", Err: err}
+	}
+	fields := csvHeaderFields(header, columns)
+
+	var groupsByName map[string]string
+	var (
+		results  []ImportCSVResult
+		rows     []int // indexes into results of rows to save
+		contacts []Contact
+		groupIDs [][]string
+	)
+	for row := 2; ; row++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return results, &OpError{Op: "ImportCSV", Err: err}
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		c, groups := csvRecordContact(record, fields)
+		c.ContainerID = input.ContainerID
+		res := ImportCSVResult{Row: row}
+
+		var ids []string
+		if len(groups) > 0 {
+			if groupsByName == nil {
+				if groupsByName, err = groupIDsByName(ctx, input.ContainerID); err != nil {
+					return results, err
+				}
+			}
+			for _, name := range groups {
+				id, ok := groupsByName[strings.ToLower(name)]
+				if !ok {
+					res.Err = &OpError{Op: "ImportCSV", ID: name, Err: fmt.Errorf("%w: group %q not found", ErrNotFound, name)}
+					break
+				}
+				ids = append(ids, id)
+			}
+		}
+		if res.Err == nil {
+			rows = append(rows, len(results))
+			contacts = append(contacts, c)
+			groupIDs = append(groupIDs, ids)
+		}
+		results = append(results, res)
+	}
+
+	if err := importCSVContacts(ctx, contacts, input, rows, results); err != nil {
+		return results, err
+	}
+	if input.DryRun {
+		return results, nil
+	}
+	for j, i := range rows {
+		res := &results[i]
+		if res.Err != nil {
+			continue
+		}
+		for _, gid := range groupIDs[j] {
+			if err := AddContactToGroup(ctx, GroupMembershipInput{ContactID: res.Contact.Identifier, GroupID: gid}); err != nil {
+				res.Err = err
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+// importCSVContacts saves contacts in one batch, creating them or, with
+// input.MatchOn, upserting them, and records each outcome in
+// results[rows[j]].
+func importCSVContacts(ctx context.Context, contacts []Contact, input ImportCSVInput, rows []int, results []ImportCSVResult) error {
+	if len(input.MatchOn) > 0 {
+		inputs := make([]UpsertContactInput, len(contacts))
+		for j, c := range contacts {
+			inputs[j] = UpsertContactInput{Contact: c, MatchOn: input.MatchOn, DryRun: input.DryRun}
+		}
+		upserted, err := UpsertContacts(ctx, inputs)
+		for j, res := range upserted {
+			if err != nil && res.Err == nil && res.Contact.Identifier == "" {
+				// Not reached before ctx was done.
+				res.Err = err
+			}
+			r := &results[rows[j]]
+			r.Contact, r.Created, r.Err = res.Contact, res.Created, res.Err
+		}
+		return err
+	}
+	inputs := make([]CreateContactInput, len(contacts))
+	for j, c := range contacts {
+		inputs[j] = CreateContactInput{Contact: c, DryRun: input.DryRun}
+	}
+	created, err := CreateContacts(ctx, inputs)
+	if err != nil {
+		return err
+	}
+	for j, res := range created {
+		r := &results[rows[j]]
+		r.Contact, r.Created, r.Err = res.Created, res.Err == nil, res.Err
+	}
+	return nil
+}
+
+func validateCSVColumns(columns []CSVColumn) error {
+	seen := make(map[string]bool, len(columns))
+	for i, col := range columns {
+		switch col.Field {
+		case CSVFieldIdentifier, CSVFieldNamePrefix, CSVFieldGivenName, CSVFieldMiddleName,
+			CSVFieldFamilyName, CSVFieldNameSuffix, CSVFieldNickname, CSVFieldPreviousFamilyName,
+			CSVFieldPhoneticGivenName, CSVFieldPhoneticMiddleName, CSVFieldPhoneticFamilyName,
+			CSVFieldOrganizationName, CSVFieldDepartmentName, CSVFieldJobTitle,
+			CSVFieldEmailAddresses, CSVFieldPhoneNumbers,
+			CSVFieldGroups:
+		default:
+			return fmt.Errorf("columns[%d] field %q is unsupported", i, col.Field)
+		}
+		key := strings.ToLower(strings.TrimSpace(col.Header))
+		if key == "" {
+			return fmt.Errorf("columns[%d] header is required", i)
+		}
+		if seen[key] {
+			return fmt.Errorf("columns[%d] header %q is duplicated", i, col.Header)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// csvHeaderFields returns the field of each CSV column, or "" when the header
+// has no mapping.
+func csvHeaderFields(header []string, columns []CSVColumn) []CSVField {
+	fields := make([]CSVField, len(header))
+	for i, h := range header {
+		for _, col := range columns {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(col.Header)) {
+				fields[i] = col.Field
+				break
+			}
+		}
+	}
+	return fields
+}
+
+func contactCSVRecord(c Contact, groups []string, columns []CSVColumn) []string {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		switch col.Field {
+		case CSVFieldIdentifier:
+			record[i] = c.Identifier
+		case CSVFieldNamePrefix:
+			record[i] = c.NamePrefix
+		case CSVFieldGivenName:
+			record[i] = c.GivenName
+		case CSVFieldMiddleName:
+			record[i] = c.MiddleName
+		case CSVFieldFamilyName:
+			record[i] = c.FamilyName
+		case CSVFieldNameSuffix:
+			record[i] = c.NameSuffix
+		case CSVFieldNickname:
+			record[i] = c.Nickname
+		case CSVFieldPreviousFamilyName:
+			record[i] = c.PreviousFamilyName
+		case CSVFieldPhoneticGivenName:
+			record[i] = c.PhoneticGivenName
+		case CSVFieldPhoneticMiddleName:
+			record[i] = c.PhoneticMiddleName
+		case CSVFieldPhoneticFamilyName:
+			record[i] = c.PhoneticFamilyName
+		case CSVFieldOrganizationName:
+			record[i] = c.OrganizationName
+		case CSVFieldDepartmentName:
+			record[i] = c.DepartmentName
+		case CSVFieldJobTitle:
+			record[i] = c.JobTitle
+		case CSVFieldEmailAddresses:
+			record[i] = formatCSVLabeled(c.EmailAddresses)
+		case CSVFieldPhoneNumbers:
+			record[i] = formatCSVLabeled(c.PhoneNumbers)
+		case CSVFieldGroups:
+			escaped := make([]string, len(groups))
+			for j, g := range groups {
+				escaped[j] = escapeCSVItem(g)
+			}
+			record[i] = strings.Join(escaped, "; ")
+		}
+	}
+	return record
+}
+
+func csvRecordContact(record []string, fields []CSVField) (Contact, []string) {
+	var c Contact
+	var groups []string
+	for i, value := range record {
+		if i >= len(fields) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		switch fields[i] {
+		case CSVFieldNamePrefix:
+			c.NamePrefix = value
+		case CSVFieldGivenName:
+			c.GivenName = value
+		case CSVFieldMiddleName:
+			c.MiddleName = value
+		case CSVFieldFamilyName:
+			c.FamilyName = value
+		case CSVFieldNameSuffix:
+			c.NameSuffix = value
+		case CSVFieldNickname:
+			c.Nickname = value
+		case CSVFieldPreviousFamilyName:
+			c.PreviousFamilyName = value
+		case CSVFieldPhoneticGivenName:
+			c.PhoneticGivenName = value
+		case CSVFieldPhoneticMiddleName:
+			c.PhoneticMiddleName = value
+		case CSVFieldPhoneticFamilyName:
+			c.PhoneticFamilyName = value
+		case CSVFieldOrganizationName:
+			c.OrganizationName = value
+		case CSVFieldDepartmentName:
+			c.DepartmentName = value
+		case CSVFieldJobTitle:
+			c.JobTitle = value
+		case CSVFieldEmailAddresses:
+			c.EmailAddresses = parseCSVLabeled(value)
+		case CSVFieldPhoneNumbers:
+			c.PhoneNumbers = parseCSVLabeled(value)
+		case CSVFieldGroups:
+			for _, g := range splitCSVItem(value, ';') {
+				if g = unescapeCSVItem(strings.TrimSpace(g)); g != "" {
+					groups = append(groups, g)
+				}
+			}
+		}
+	}
+	if c.GivenName == "" && c.FamilyName == "" && c.OrganizationName != "" {
+		c.ContactType = ContactTypeOrganization
+	}
+	return c, groups
+}
+
+func formatCSVLabeled(values []LabeledValue[string]) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if v.Label != "" {
+			parts = append(parts, escapeCSVItem(v.Label)+":"+escapeCSVItem(v.Value))
+		} else {
+			parts = append(parts, escapeCSVItem(v.Value))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func parseCSVLabeled(s string) []LabeledValue[string] {
+	var out []LabeledValue[string]
+	for _, part := range splitCSVItem(s, ';') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lv := LabeledValue[string]{Value: unescapeCSVItem(part)}
+		if fields := splitCSVItem(part, ':'); len(fields) > 1 {
+			lv = LabeledValue[string]{
+				Label: unescapeCSVItem(strings.TrimSpace(fields[0])),
+				Value: unescapeCSVItem(strings.TrimSpace(part[len(fields[0])+1:])),
+			}
+		}
+		out = append(out, lv)
+	}
+	return out
+}
+
+// escapeCSVItem backslash-escapes the separators of a multi-value cell.
+func escapeCSVItem(s string) string {
+	if !strings.ContainsAny(s, `\;:`) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\\' || r == ';' || r == ':' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unescapeCSVItem reverses escapeCSVItem. A trailing lone backslash is kept.
+func unescapeCSVItem(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitCSVItem splits s at each sep not escaped by a backslash, leaving the
+// escapes in place.
+func splitCSVItem(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func groupNamesByContact(ctx context.Context) (map[string][]string, error) {
+	groups, err := ListGroups(ctx, ListGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for _, g := range groups {
+		page, err := ListGroupMembers(ctx, ListGroupMembersInput{GroupID: g.Identifier})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page.Members {
+			out[m.Identifier] = append(out[m.Identifier], g.Name)
+		}
+	}
+	return out, nil
+}
+
+func groupIDsByName(ctx context.Context, containerID string) (map[string]string, error) {
+	if strings.TrimSpace(containerID) == "" {
+		id, err := DefaultContainerID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		containerID = id
+	}
+	groups, err := ListGroups(ctx, ListGroupsInput{ContainerID: containerID})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(groups))
+	for _, g := range groups {
+		out[strings.ToLower(g.Name)] = g.Identifier
+	}
+	return out, nil
+}
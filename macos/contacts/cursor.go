@@ -0,0 +1,54 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// contactSortKey is the keyset position of a contact in [ListContacts]
+// results: case-folded given name, then family name, then identifier.
+type contactSortKey struct {
+	given, family, id string
+}
+
+func sortKeyOf(c Contact) contactSortKey {
+	return contactSortKey{
+		given:  strings.ToLower(c.GivenName),
+		family: strings.ToLower(c.FamilyName),
+		id:     c.Identifier,
+	}
+}
+
+func compareSortKeys(a, b contactSortKey) int {
+	if n := strings.Compare(a.given, b.given); n != 0 {
+		return n
+	}
+	if n := strings.Compare(a.family, b.family); n != 0 {
+		return n
+	}
+	return strings.Compare(a.id, b.id)
+}
+
+// ContactCursor returns an opaque keyset cursor positioned at c. Passing it as
+// [ListContactsInput].After resumes listing with the contact that sorts after
+// c, even if contacts were added or removed since c was returned.
+func ContactCursor(c Contact) string {
+	k := sortKeyOf(c)
+	raw := k.given + "\x00" + k.family + "\x00" + k.id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parseContactCursor(cursor string) (contactSortKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return contactSortKey{}, fmt.Errorf("invalid cursor: %v", err)
+	}
+	parts := strings.Split(string(raw), "\x00")
+	if len(parts) != 3 || parts[2] == "" {
+		return contactSortKey{}, fmt.Errorf("invalid cursor")
+	}
+	return contactSortKey{given: parts[0], family: parts[1], id: parts[2]}, nil
+}
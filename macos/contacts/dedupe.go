@@ -0,0 +1,513 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateReason describes why contacts were grouped as likely duplicates.
+type DuplicateReason string
+
+const (
+	// DuplicateReasonEmail means the contacts share an email address
+	// (case-insensitive).
+	DuplicateReasonEmail DuplicateReason = "email"
+	// DuplicateReasonPhone means the contacts share a phone number under
+	// [PhoneNumbersMatch].
+	DuplicateReasonPhone DuplicateReason = "phone"
+	// DuplicateReasonName means the contacts have the same normalized given and
+	// family name (or organization name for organization contacts).
+	DuplicateReasonName DuplicateReason = "name"
+)
+
+// DuplicateSet is a group of constituent contacts that likely describe the same
+// person. Contacts are ordered by identifier; Reasons lists every signal that
+// linked members of the set.
+type DuplicateSet struct {
+	Contacts []Contact         `json:"contacts"`
+	Reasons  []DuplicateReason `json:"reasons"`
+}
+
+// FindDuplicateContactsInput scopes duplicate detection.
+type FindDuplicateContactsInput struct {
+	// Filters narrows the candidate contacts, using the same semantics as
+	// [ListContacts]. Unified filters are rejected because detection always
+	// runs over constituent records.
+	Filters []Filter `json:"filters"`
+	// MatchNames also groups contacts whose normalized names are equal. Name
+	// matching is noisier than email/phone matching, so it is opt-in.
+	MatchNames bool `json:"match_names,omitempty"`
+}
+
+// FindDuplicateContacts reports likely duplicate constituent contacts.
+//
+// Only contacts in the same container are compared: copies of a person in
+// different accounts (for example iCloud and Google) are linked by the system
+// into a unified contact and are not duplicates in the sense of this function.
+// The result is read-only; pass a set to [MergeContacts] to consolidate it.
+func FindDuplicateContacts(ctx context.Context, input FindDuplicateContactsInput) ([]DuplicateSet, error) {
+	for _, f := range input.Filters {
+		if f.Field == ContactFieldUnified {
+			return nil, newInvalidArg("FindDuplicateContacts", "", "unified filters are not supported")
+		}
+	}
+	filters := append(slices.Clone(input.Filters), Filter{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"})
+
+	var candidates []Contact
+	for c, err := range ListContacts(ctx, ListContactsInput{Filters: filters}) {
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return findDuplicateSets(candidates, input.MatchNames), nil
+}
+
+// findDuplicateSets groups contacts with union-find over the email, phone, and
+// optional name keys.
+func findDuplicateSets(candidates []Contact, matchNames bool) []DuplicateSet {
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	type link struct {
+		a      int
+		reason DuplicateReason
+	}
+	var links []link
+	unionBucket := func(idx []int, reason DuplicateReason, match func(a, b Contact) bool) {
+		for x := range idx {
+			for y := x + 1; y < len(idx); y++ {
+				a, b := idx[x], idx[y]
+				if candidates[a].ContainerID != candidates[b].ContainerID {
+					continue
+				}
+				if match != nil && !match(candidates[a], candidates[b]) {
+					continue
+				}
+				if ra, rb := find(a), find(b); ra != rb {
+					parent[rb] = ra
+				}
+				links = append(links, link{a: a, reason: reason})
+			}
+		}
+	}
+
+	byEmail := make(map[string][]int)
+	byPhoneTail := make(map[string][]int)
+	byName := make(map[string][]int)
+	for i, c := range candidates {
+		for _, e := range c.EmailAddresses {
+			if key := emailKey(e.Value); key != "" {
+				byEmail[key] = appendUnique(byEmail[key], i)
+			}
+		}
+		for _, p := range c.PhoneNumbers {
+			key := phoneMatchKey(NormalizePhoneNumber(p.Value))
+			if len(key) > minPhoneSuffixDigits {
+				key = key[len(key)-minPhoneSuffixDigits:]
+			}
+			if key != "" {
+				byPhoneTail[key] = appendUnique(byPhoneTail[key], i)
+			}
+		}
+		if matchNames {
+			if key := nameKey(c); key != "" {
+				byName[key] = append(byName[key], i)
+			}
+		}
+	}
+	for _, idx := range byEmail {
+		unionBucket(idx, DuplicateReasonEmail, nil)
+	}
+	for _, idx := range byPhoneTail {
+		unionBucket(idx, DuplicateReasonPhone, contactsSharePhone)
+	}
+	for _, idx := range byName {
+		unionBucket(idx, DuplicateReasonName, nil)
+	}
+
+	members := make(map[int][]int)
+	for i := range candidates {
+		r := find(i)
+		members[r] = append(members[r], i)
+	}
+	setReasons := make(map[int]map[DuplicateReason]bool)
+	for _, l := range links {
+		r := find(l.a)
+		if setReasons[r] == nil {
+			setReasons[r] = make(map[DuplicateReason]bool)
+		}
+		setReasons[r][l.reason] = true
+	}
+
+	var sets []DuplicateSet
+	for root, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		set := DuplicateSet{Contacts: make([]Contact, 0, len(idx))}
+		for _, i := range idx {
+			set.Contacts = append(set.Contacts, candidates[i])
+		}
+		sort.Slice(set.Contacts, func(a, b int) bool {
+			return set.Contacts[a].Identifier < set.Contacts[b].Identifier
+		})
+		for _, reason := range []DuplicateReason{DuplicateReasonEmail, DuplicateReasonPhone, DuplicateReasonName} {
+			if setReasons[root][reason] {
+				set.Reasons = append(set.Reasons, reason)
+			}
+		}
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(a, b int) bool {
+		return sets[a].Contacts[0].Identifier < sets[b].Contacts[0].Identifier
+	})
+	return sets
+}
+
+func appendUnique(idx []int, i int) []int {
+	if len(idx) > 0 && idx[len(idx)-1] == i {
+		return idx
+	}
+	return append(idx, i)
+}
+
+func contactsSharePhone(a, b Contact) bool {
+	for _, pa := range a.PhoneNumbers {
+		for _, pb := range b.PhoneNumbers {
+			if PhoneNumbersMatch(pa.Value, pb.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// nameKey returns a normalized name used for name-based duplicate matching.
+// Person contacts need both a given and a family name; punctuation and case
+// are ignored.
+func nameKey(c Contact) string {
+	if c.ContactType == ContactTypeOrganization {
+		return normalizeNamePart(c.OrganizationName)
+	}
+	given, family := normalizeNamePart(c.GivenName), normalizeNamePart(c.FamilyName)
+	if given == "" || family == "" {
+		return ""
+	}
+	return given + "|" + family
+}
+
+func normalizeNamePart(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// MergeContactsInput specifies a survivor and the duplicates to fold into it.
+type MergeContactsInput struct {
+	// SurvivorID is the constituent contact that receives merged values.
+	SurvivorID string `json:"survivor_id"`
+	// DuplicateIDs are constituent contacts merged into the survivor and then
+	// deleted. They must be in the survivor's container.
+	DuplicateIDs []string `json:"duplicate_ids"`
+	// DryRun computes the merged survivor and per-duplicate results without
+	// saving, transferring memberships, or deleting anything.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// MergeDuplicateResult reports what happened to one duplicate.
+type MergeDuplicateResult struct {
+	Identifier string `json:"identifier"`
+	// GroupIDs lists groups the duplicate belonged to that the survivor joins
+	// (or would join, for a dry run).
+	GroupIDs []string `json:"group_ids"`
+	// Deleted is true once the duplicate has been removed.
+	Deleted bool  `json:"deleted"`
+	Err     error `json:"-"`
+}
+
+// MergeContactsResult is returned by [MergeContacts].
+type MergeContactsResult struct {
+	// Survivor is the merged survivor as persisted, or as it would be
+	// persisted for a dry run.
+	Survivor   Contact                `json:"survivor"`
+	Duplicates []MergeDuplicateResult `json:"duplicates"`
+}
+
+// MergeContacts consolidates duplicates into a surviving contact.
+//
+// Empty single-value fields on the survivor are filled from duplicates in
+// order; multi-value fields are unioned, skipping values the survivor already
+// has (emails case-insensitively, phones via [PhoneNumbersMatch]). The photo
+// is taken from the first duplicate that has one if the survivor has none.
+// Group memberships of each duplicate are transferred to the survivor before
+// the duplicate is deleted.
+//
+// The returned error covers input validation and survivor failures. Problems
+// with an individual duplicate are reported in its MergeDuplicateResult, and
+// that duplicate is left untouched.
+func MergeContacts(ctx context.Context, input MergeContactsInput) (MergeContactsResult, error) {
+	const op = "MergeContacts"
+	survivorID := strings.TrimSpace(input.SurvivorID)
+	if survivorID == "" {
+		return MergeContactsResult{}, newInvalidArg(op, "", "survivorID is required")
+	}
+	if len(input.DuplicateIDs) == 0 {
+		return MergeContactsResult{}, newInvalidArg(op, survivorID, "at least one duplicate ID is required")
+	}
+	seen := map[string]bool{survivorID: true}
+	for _, id := range input.DuplicateIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			return MergeContactsResult{}, newInvalidArg(op, survivorID, fmt.Sprintf("duplicate ID %q is empty or repeated", id))
+		}
+		seen[id] = true
+	}
+	if err := ctx.Err(); err != nil {
+		return MergeContactsResult{}, err
+	}
+	if _, err := ensureNonUnifiedContactIdentity(ctx, op, survivorID); err != nil {
+		return MergeContactsResult{}, err
+	}
+	survivor, errStr := getConstituentContact(survivorID)
+	if errStr != "" {
+		return MergeContactsResult{}, newBridgeOpError(op, survivorID, errStr)
+	}
+
+	results := make([]MergeDuplicateResult, len(input.DuplicateIDs))
+	duplicates := make([]Contact, len(input.DuplicateIDs))
+	for i, id := range input.DuplicateIDs {
+		id = strings.TrimSpace(id)
+		results[i].Identifier = id
+		if _, err := ensureNonUnifiedContactIdentity(ctx, op, id); err != nil {
+			results[i].Err = err
+			continue
+		}
+		dup, errStr := getConstituentContact(id)
+		if errStr != "" {
+			results[i].Err = newBridgeOpError(op, id, errStr)
+			continue
+		}
+		if dup.ContainerID != survivor.ContainerID {
+			results[i].Err = newInvalidArg(op, id, fmt.Sprintf("duplicate container %q differs from survivor container %q", dup.ContainerID, survivor.ContainerID))
+			continue
+		}
+		duplicates[i] = dup
+	}
+
+	memberships, err := groupIDsByContact(ctx, survivor.ContainerID)
+	if err != nil {
+		return MergeContactsResult{}, err
+	}
+	survivorGroups := memberships[survivorID]
+
+	merged := survivor
+	for i, dup := range duplicates {
+		if results[i].Err != nil {
+			continue
+		}
+		merged = mergeContactValues(merged, dup)
+		for _, gid := range memberships[dup.Identifier] {
+			if !slices.Contains(survivorGroups, gid) {
+				results[i].GroupIDs = append(results[i].GroupIDs, gid)
+			}
+		}
+	}
+
+	if input.DryRun {
+		merged.ImageDataAvailable = len(merged.ImageData) > 0
+		return MergeContactsResult{Survivor: merged, Duplicates: results}, nil
+	}
+
+	updated := survivor
+	if patch, changed := contactPatch(survivor, merged); changed {
+		updated, err = UpdateContact(ctx, patch)
+		if err != nil {
+			return MergeContactsResult{}, err
+		}
+	}
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		for _, gid := range results[i].GroupIDs {
+			if slices.Contains(survivorGroups, gid) {
+				continue
+			}
+			if err := AddContactToGroup(ctx, GroupMembershipInput{ContactID: survivorID, GroupID: gid}); err != nil {
+				results[i].Err = err
+				break
+			}
+			survivorGroups = append(survivorGroups, gid)
+		}
+		if results[i].Err != nil {
+			continue
+		}
+		if err := DeleteContact(ctx, results[i].Identifier); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Deleted = true
+	}
+	return MergeContactsResult{Survivor: updated, Duplicates: results}, nil
+}
+
+// groupIDsByContact maps constituent contact IDs to the groups in containerID
+// that contain them.
+func groupIDsByContact(ctx context.Context, containerID string) (map[string][]string, error) {
+	groups, err := ListGroups(ctx, ListGroupsInput{ContainerID: containerID, IncludeHierarchy: true})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for _, g := range groups {
+		members, err := ListContactsInGroup(ctx, g.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			out[m.Identifier] = append(out[m.Identifier], g.Identifier)
+		}
+	}
+	return out, nil
+}
+
+// mergeContactValues fills empty single-value fields of dst from src and
+// appends src multi-values that dst does not already have.
+func mergeContactValues(dst, src Contact) Contact {
+	fill := func(d *string, s string) {
+		if strings.TrimSpace(*d) == "" {
+			*d = s
+		}
+	}
+	fill(&dst.NamePrefix, src.NamePrefix)
+	fill(&dst.GivenName, src.GivenName)
+	fill(&dst.MiddleName, src.MiddleName)
+	fill(&dst.FamilyName, src.FamilyName)
+	fill(&dst.PreviousFamilyName, src.PreviousFamilyName)
+	fill(&dst.NameSuffix, src.NameSuffix)
+	fill(&dst.Nickname, src.Nickname)
+	fill(&dst.PhoneticGivenName, src.PhoneticGivenName)
+	fill(&dst.PhoneticMiddleName, src.PhoneticMiddleName)
+	fill(&dst.PhoneticFamilyName, src.PhoneticFamilyName)
+	fill(&dst.OrganizationName, src.OrganizationName)
+	fill(&dst.DepartmentName, src.DepartmentName)
+	fill(&dst.JobTitle, src.JobTitle)
+	if dst.Birthday == nil && src.Birthday != nil {
+		b := *src.Birthday
+		dst.Birthday = &b
+	}
+	if len(dst.ImageData) == 0 && len(src.ImageData) > 0 {
+		dst.ImageData = slices.Clone(src.ImageData)
+	}
+
+	dst.EmailAddresses = unionLabeled(dst.EmailAddresses, src.EmailAddresses, func(a, b string) bool {
+		return emailKey(a) == emailKey(b)
+	})
+	dst.PhoneNumbers = unionLabeled(dst.PhoneNumbers, src.PhoneNumbers, PhoneNumbersMatch)
+	dst.URLAddresses = unionLabeled(dst.URLAddresses, src.URLAddresses, func(a, b string) bool {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	})
+	dst.PostalAddresses = unionLabeled(dst.PostalAddresses, src.PostalAddresses, postalAddressMatches)
+	dst.ContactRelations = unionLabeled(dst.ContactRelations, src.ContactRelations, func(a, b ContactRelation) bool {
+		return strings.EqualFold(strings.TrimSpace(a.Name), strings.TrimSpace(b.Name))
+	})
+	dst.SocialProfiles = unionLabeled(dst.SocialProfiles, src.SocialProfiles, func(a, b SocialProfile) bool {
+		return strings.EqualFold(a.Service, b.Service) && strings.EqualFold(a.Username, b.Username)
+	})
+	dst.InstantMessages = unionLabeled(dst.InstantMessages, src.InstantMessages, func(a, b InstantMessage) bool {
+		return strings.EqualFold(a.Service, b.Service) && strings.EqualFold(a.Username, b.Username)
+	})
+	dst.Dates = unionLabeled(dst.Dates, src.Dates, func(a, b DateComponents) bool { return a == b })
+	return dst
+}
+
+// unionLabeled appends values from src that have no equal value in dst.
+// Appended values drop their Identifier so they are saved as new entries.
+func unionLabeled[T any](dst, src []LabeledValue[T], equal func(a, b T) bool) []LabeledValue[T] {
+	out := slices.Clone(dst)
+	for _, s := range src {
+		if slices.ContainsFunc(out, func(d LabeledValue[T]) bool { return equal(d.Value, s.Value) }) {
+			continue
+		}
+		s.Identifier = ""
+		out = append(out, s)
+	}
+	return out
+}
+
+// contactPatch builds an UpdateContactInput that turns current into merged.
+// It reports false when nothing differs.
+func contactPatch(current, merged Contact) (UpdateContactInput, bool) {
+	in := UpdateContactInput{Identifier: current.Identifier}
+	str := func(dst **string, cur, next string) {
+		if cur != next {
+			*dst = &next
+		}
+	}
+	str(&in.NamePrefix, current.NamePrefix, merged.NamePrefix)
+	str(&in.GivenName, current.GivenName, merged.GivenName)
+	str(&in.MiddleName, current.MiddleName, merged.MiddleName)
+	str(&in.FamilyName, current.FamilyName, merged.FamilyName)
+	str(&in.PreviousFamilyName, current.PreviousFamilyName, merged.PreviousFamilyName)
+	str(&in.NameSuffix, current.NameSuffix, merged.NameSuffix)
+	str(&in.Nickname, current.Nickname, merged.Nickname)
+	str(&in.PhoneticGivenName, current.PhoneticGivenName, merged.PhoneticGivenName)
+	str(&in.PhoneticMiddleName, current.PhoneticMiddleName, merged.PhoneticMiddleName)
+	str(&in.PhoneticFamilyName, current.PhoneticFamilyName, merged.PhoneticFamilyName)
+	str(&in.OrganizationName, current.OrganizationName, merged.OrganizationName)
+	str(&in.DepartmentName, current.DepartmentName, merged.DepartmentName)
+	str(&in.JobTitle, current.JobTitle, merged.JobTitle)
+	if current.Birthday == nil && merged.Birthday != nil {
+		in.Birthday = merged.Birthday
+	}
+	if !bytes.Equal(current.ImageData, merged.ImageData) {
+		in.ImageData = &merged.ImageData
+	}
+	if !slices.Equal(current.EmailAddresses, merged.EmailAddresses) {
+		in.EmailAddresses = &merged.EmailAddresses
+	}
+	if !slices.Equal(current.PhoneNumbers, merged.PhoneNumbers) {
+		in.PhoneNumbers = &merged.PhoneNumbers
+	}
+	if !slices.Equal(current.URLAddresses, merged.URLAddresses) {
+		in.URLAddresses = &merged.URLAddresses
+	}
+	if !slices.Equal(current.PostalAddresses, merged.PostalAddresses) {
+		in.PostalAddresses = &merged.PostalAddresses
+	}
+	if !slices.Equal(current.ContactRelations, merged.ContactRelations) {
+		in.ContactRelations = &merged.ContactRelations
+	}
+	if !slices.Equal(current.SocialProfiles, merged.SocialProfiles) {
+		in.SocialProfiles = &merged.SocialProfiles
+	}
+	if !slices.Equal(current.InstantMessages, merged.InstantMessages) {
+		in.InstantMessages = &merged.InstantMessages
+	}
+	if !slices.Equal(current.Dates, merged.Dates) {
+		in.Dates = &merged.Dates
+	}
+	return in, hasUpdateContactChanges(in)
+}
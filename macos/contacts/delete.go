@@ -0,0 +1,238 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDeletes is the [DeleteContactsInput] MaxDeletes used when none is
+// given.
+const DefaultMaxDeletes = 25
+
+// DeleteConfirmTokenTTL is how long the ConfirmToken of a [DeleteContacts] dry
+// run stays valid.
+const DeleteConfirmTokenTTL = 10 * time.Minute
+
+// deleteConfirmKey signs confirmation tokens. It is random per process, so a
+// token cannot be derived without a dry run and is not accepted by another
+// process.
+var deleteConfirmKey = func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}()
+
+// DeleteContactsInput specifies a guarded bulk delete.
+//
+// Deleting is a two-step protocol: call [DeleteContacts] with DryRun set,
+// review the returned deletions, then repeat the call with the same
+// Identifiers and the returned ConfirmToken within [DeleteConfirmTokenTTL].
+type DeleteContactsInput struct {
+	// Identifiers are non-unified contact identifiers. Duplicates are
+	// ignored.
+	Identifiers []string `json:"identifiers"`
+	// MaxDeletes caps how many contacts one call may delete. Zero means
+	// [DefaultMaxDeletes]; a negative value is invalid.
+	MaxDeletes int `json:"max_deletes,omitempty"`
+	// ConfirmToken must be the token returned by a dry run over the same
+	// identifiers, in the same process. It is ignored when DryRun is set.
+	ConfirmToken string `json:"confirm_token,omitempty"`
+	// DryRun validates every identifier and reports what would be removed
+	// without deleting anything.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ContactDeletion describes one contact removed, or to be removed, by
+// [DeleteContacts].
+type ContactDeletion struct {
+	// Contact is the full record as it exists before deletion. Nothing in it
+	// can be recovered through this package afterwards.
+	Contact Contact `json:"contact"`
+	// GroupIDs are the groups the contact is a member of; deleting the
+	// contact also drops these memberships.
+	GroupIDs []string `json:"group_ids"`
+}
+
+// DeleteContactsResult is the outcome of [DeleteContacts].
+type DeleteContactsResult struct {
+	Deletions []ContactDeletion `json:"deletions"`
+	// ConfirmToken is set by dry runs. It binds the identifiers to the
+	// previewed deletions; pass it back in [DeleteContactsInput] to perform
+	// the delete.
+	ConfirmToken string `json:"confirm_token"`
+	// DeletedIDs lists the contacts actually deleted. It is empty for dry
+	// runs, and on error holds the contacts deleted before the failure.
+	DeletedIDs []string `json:"deleted_ids"`
+}
+
+// DeleteContacts deletes several contacts behind guardrails meant for
+// unattended callers: the call fails with [ErrInvalidArgument] when there are
+// more than MaxDeletes identifiers, and a real delete requires the
+// ConfirmToken of a recent dry run over the same identifiers.
+//
+// Every identifier is resolved before anything is deleted, so an unknown or
+// unified identifier fails the whole call. If any contact or its group
+// memberships changed since the dry run, the call fails with [ErrConflict]
+// and nothing is deleted; preview again to get a fresh token. Deletes are
+// then applied one at a time with [DeleteContact].
+func DeleteContacts(ctx context.Context, input DeleteContactsInput) (DeleteContactsResult, error) {
+	ids := make([]string, 0, len(input.Identifiers))
+	for _, id := range input.Identifiers {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return DeleteContactsResult{}, newInvalidArg("DeleteContacts", "", "identifiers must not be empty")
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return DeleteContactsResult{}, newInvalidArg("DeleteContacts", "", "identifiers are required")
+	}
+	if input.MaxDeletes < 0 {
+		return DeleteContactsResult{}, newInvalidArg("DeleteContacts", "", "maxDeletes must not be negative")
+	}
+	maxDeletes := input.MaxDeletes
+	if maxDeletes == 0 {
+		maxDeletes = DefaultMaxDeletes
+	}
+	if len(ids) > maxDeletes {
+		return DeleteContactsResult{}, newInvalidArg("DeleteContacts", "", fmt.Sprintf("%d contacts exceeds maxDeletes %d", len(ids), maxDeletes))
+	}
+	var issued int64
+	if !input.DryRun {
+		var err error
+		if issued, err = checkDeleteConfirmToken(input.ConfirmToken, ids, time.Now()); err != nil {
+			return DeleteContactsResult{}, newInvalidArg("DeleteContacts", "", err.Error())
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return DeleteContactsResult{}, err
+	}
+
+	result := DeleteContactsResult{DeletedIDs: make([]string, 0, len(ids))}
+	for _, id := range ids {
+		if _, err := ensureNonUnifiedContactIdentity(ctx, "DeleteContacts", id); err != nil {
+			return DeleteContactsResult{}, err
+		}
+		c, err := GetConstituentContact(ctx, id)
+		if err != nil {
+			return DeleteContactsResult{}, err
+		}
+		result.Deletions = append(result.Deletions, ContactDeletion{Contact: c, GroupIDs: make([]string, 0)})
+	}
+	if err := fillDeletionGroups(ctx, result.Deletions); err != nil {
+		return DeleteContactsResult{}, err
+	}
+	if input.DryRun {
+		token, err := deleteConfirmToken(time.Now().Unix(), ids, result.Deletions)
+		if err != nil {
+			return DeleteContactsResult{}, &OpError{Op: "DeleteContacts", Err: err}
+		}
+		result.ConfirmToken = token
+		return result, nil
+	}
+	want, err := deleteConfirmToken(issued, ids, result.Deletions)
+	if err != nil {
+		return DeleteContactsResult{}, &OpError{Op: "DeleteContacts", Err: err}
+	}
+	if !hmac.Equal([]byte(want), []byte(strings.TrimSpace(input.ConfirmToken))) {
+		return DeleteContactsResult{}, &OpError{Op: "DeleteContacts", Err: fmt.Errorf("%w: contacts changed since the dry run", ErrConflict)}
+	}
+
+	for _, id := range ids {
+		if err := DeleteContact(ctx, id); err != nil {
+			return result, err
+		}
+		result.DeletedIDs = append(result.DeletedIDs, id)
+	}
+	return result, nil
+}
+
+// deleteConfirmToken signs a dry run issued at the given Unix time. The token
+// is "<issued>.<ids MAC>.<state MAC>": the first MAC covers the identifiers,
+// independent of their order, and the second also covers every previewed
+// contact and its group memberships.
+func deleteConfirmToken(issued int64, ids []string, deletions []ContactDeletion) (string, error) {
+	sorted := slices.Clone(deletions)
+	slices.SortFunc(sorted, func(a, b ContactDeletion) int { return strings.Compare(a.Contact.Identifier, b.Contact.Identifier) })
+	for i := range sorted {
+		sorted[i].GroupIDs = slices.Sorted(slices.Values(sorted[i].GroupIDs))
+	}
+	state, err := json.Marshal(sorted)
+	if err != nil {
+		return "", err
+	}
+	prefix := strconv.FormatInt(issued, 10)
+	idsMAC := deleteConfirmMAC(prefix, sortedIDs(ids))
+	return prefix + "." + idsMAC + "." + deleteConfirmMAC(prefix, idsMAC, string(state)), nil
+}
+
+// checkDeleteConfirmToken verifies that token came from a dry run over ids
+// that is not older than [DeleteConfirmTokenTTL], and returns when it was
+// issued. The previewed state is checked by the caller once it is re-read.
+func checkDeleteConfirmToken(token string, ids []string, now time.Time) (int64, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return 0, errors.New("confirmToken is required and must come from a dry run of these identifiers")
+	}
+	issued, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || !hmac.Equal([]byte(parts[1]), []byte(deleteConfirmMAC(parts[0], sortedIDs(ids)))) {
+		return 0, errors.New("confirmToken does not match a dry run of these identifiers")
+	}
+	if age := now.Sub(time.Unix(issued, 0)); age < 0 || age > DeleteConfirmTokenTTL {
+		return 0, errors.New("confirmToken has expired; run a new dry run")
+	}
+	return issued, nil
+}
+
+func deleteConfirmMAC(parts ...string) string {
+	mac := hmac.New(sha256.New, deleteConfirmKey)
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func sortedIDs(ids []string) string {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	return strings.Join(sorted, "\n")
+}
+
+func fillDeletionGroups(ctx context.Context, deletions []ContactDeletion) error {
+	containers := make([]string, 0)
+	for _, d := range deletions {
+		if !slices.Contains(containers, d.Contact.ContainerID) {
+			containers = append(containers, d.Contact.ContainerID)
+		}
+	}
+	for _, containerID := range containers {
+		groups, err := ListGroups(ctx, ListGroupsInput{ContainerID: containerID})
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			members, errStr := listGroupMembers(ctx, g.Identifier)
+			if errStr != "" {
+				return newBridgeOpError("DeleteContacts", g.Identifier, errStr)
+			}
+			for i := range deletions {
+				if slices.ContainsFunc(members, func(m GroupMember) bool { return m.Identifier == deletions[i].Contact.Identifier }) {
+					deletions[i].GroupIDs = append(deletions[i].GroupIDs, g.Identifier)
+				}
+			}
+		}
+	}
+	return nil
+}
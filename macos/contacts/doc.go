@@ -7,14 +7,27 @@
 //
 // Primitive groups:
 //
-//   - Contacts: [CreateContact], [GetContact], [ListContacts], [UpdateContact],
-//     [DeleteContact], [ResolveContactIdentity].
-//   - Groups: [CreateGroup], [GetGroup], [ListGroups], [ListSubgroups],
-//     [UpdateGroup], [DeleteGroup].
+//   - Contacts: [CreateContact], [CreateContacts], [GetContact],
+//     [GetConstituentContact], [GetMeContact], [GetContactNote], [ListContacts],
+//     [StreamContacts], [UpdateContact], [UpdateContacts],
+//     [UpsertContact], [UpsertContacts], [DeleteContact], [DeleteContacts],
+//     [ResolveContactIdentity].
+//   - Groups: [CreateGroup], [GetGroup], [FindGroupByName], [ListGroups],
+//     [ListSubgroups], [UpdateGroup], [DeleteGroup].
 //   - Membership: [AddContactToGroup], [RemoveContactFromGroup],
-//     [ListContactsInGroup].
+//     [ListContactsInGroup], [ListGroupMembers].
 //   - Containers: [ListContainers], [GetContainer], [DefaultContainerID].
-//   - Authorization: [CheckAuthorization], [RequestAuthorization].
+//   - Cleanup: [FindDuplicateContacts], [MergeContacts], [Stats].
+//   - Change tracking: [CurrentChangeToken], [ListContactChanges].
+//   - Sync: [SyncContacts] merges contacts one way between macOS and a
+//     [RemoteAddressBook] such as a [CardDAVAddressBook].
+//   - Import/export: [ExportCSV], [ImportCSV] with a configurable [CSVColumn]
+//     mapping for spreadsheet review workflows.
+//   - Saved queries: [QueryStore] persists named filter sets ("Vendors",
+//     "Neighbors") and evaluates them with [QueryStore.Run], emulating smart
+//     groups that Contacts.framework does not expose.
+//   - Authorization: [CheckAuthorization], [RequestAuthorization],
+//     [AuthorizedContactIDs], [OpenAccessSettings].
 //
 // Groups and subgroups are represented by the same [Group] type. A subgroup is
 // just a group with ParentGroupID set.
@@ -29,6 +42,12 @@
 //   - constituent record: `Unified=false`
 //   - unified projection: `Unified=true` with `LinkedIDs` listing constituents
 //
+// [GetContact] returns the unified projection, merging every linked card (for
+// example the iCloud and Google copies of one person). [GetConstituentContact]
+// returns a single linked record, which is what mutations operate on. To edit
+// a person across accounts, resolve the LinkedIDs with
+// [ResolveContactIdentity] and target each constituent explicitly.
+//
 // # Identity Semantics
 //
 // Unified reads may return canonical identifiers that differ from input IDs.
@@ -38,9 +57,61 @@
 //
 // # Listing Semantics
 //
-// [ListContacts] supports [ContactFieldUnified] and [ContactFieldContainerID].
-// When listing unified projections, container filtering matches if any linked
-// constituent belongs to the target container.
+// [ListContacts] filters are evaluated inside the bridge while enumerating the
+// store, so only matching contacts are converted and returned. Filters combine
+// with AND semantics. Text fields (names, [ContactFieldOrganizationName],
+// [ContactFieldDepartmentName], [ContactFieldJobTitle],
+// [ContactFieldNickname]) and the multi-value [ContactFieldEmailAddresses] and
+// [ContactFieldPhoneNumbers] support all operators case-insensitively; a
+// multi-value field matches when any value matches (FilterNotContains requires
+// that no value contains the filter value). For example, an email domain
+// clause is {Field: ContactFieldEmailAddresses, Op: FilterContains, Value:
+// "@example.com"}.
+//
+// [ContactFieldText] is a free-text clause across names, organization, job
+// details, emails, phones, and notes, for fuzzy agent queries such as "the
+// person named something like Marek from the conference". Searching notes
+// needs the notes entitlement; without it the listing fails with
+// [ErrNoteEntitlementMissing], and [ContactFieldTextExcludingNotes] searches
+// everything else.
+//
+// Phonetic names ([ContactFieldPhoneticGivenName],
+// [ContactFieldPhoneticMiddleName], [ContactFieldPhoneticFamilyName]) and
+// [ContactFieldPreviousFamilyName] are filterable like other name fields. Some
+// sync sources store a romanized name only in the phonetic fields, so match on
+// them when a lookup by given or family name finds nothing.
+//
+// [ListContacts] materializes and sorts every match before yielding the first
+// one. For 10k+ contact stores, [StreamContacts] yields matches as
+// Contacts.framework enumerates them, trading the stable cursor order for flat
+// memory and low first-result latency.
+//
+// [ContactFieldGroupID] and [ContactFieldGroupName] restrict a listing to the
+// members (FilterEquals) or non-members (FilterNotContains) of a group, so
+// "engineers not yet in Engineering" is one call:
+//
+//	contacts.ListContacts(ctx, contacts.ListContactsInput{Filters: []contacts.Filter{
+//		{Field: contacts.ContactFieldJobTitle, Op: contacts.FilterContains, Value: "Engineer"},
+//		{Field: contacts.ContactFieldGroupName, Op: contacts.FilterNotContains, Value: "Engineering"},
+//	}})
+//
+// Filter.Values takes several groups at once and matches members of any of
+// them, e.g. everyone in Engineering or Design:
+//
+//	{Field: contacts.ContactFieldGroupName, Op: contacts.FilterEquals, Values: []string{"Engineering", "Design"}}
+//
+// Group names are resolved before listing; a name that matches no group or
+// several groups fails with [ErrNotFound] or [ErrAmbiguous].
+//
+// [ListContacts] also supports [ContactFieldUnified] and
+// [ContactFieldContainerID]. When listing unified projections, container
+// filtering matches if any linked constituent belongs to the target container.
+//
+// [ContactFieldPhoneNumbers] filters compare digits rather than formatted
+// strings, and FilterEquals tolerates a missing country code, so a handle such
+// as "+15551234567" finds a contact saved as "(555) 123-4567". Use
+// [NormalizePhoneNumber] and [PhoneNumbersMatch] to apply the same rules in
+// caller code.
 //
 // # Mutation Semantics
 //
@@ -48,11 +119,54 @@
 // Unified identifiers are rejected with typed errors such as
 // [ErrUnifiedContactNotMutable].
 //
+// Multi-value fields in [UpdateContactInput] replace the whole list. For
+// emails and phones, AddEmailAddresses/RemoveEmailAddresses and
+// AddPhoneNumbers/RemovePhoneNumbers instead edit the list as it exists when
+// the bridge saves, avoiding read-modify-write races with other writers.
+//
+// Labels on multi-value fields are normalized in both directions. Reads turn
+// system constants such as "_$!<Work>!$_" into "work", "home", "mobile",
+// "other", and similar names, keeping the stored form in
+// [LabeledValue.RawLabel]; writes map those names back to the constants.
+// Custom labels are stored and returned as given.
+//
 // # Group Semantics
 //
 // Group membership is record/container scoped with no implied linked-set fanout.
 // [ListContactsInGroup] returns non-unified contacts (`Unified=false`) so
-// membership state is deterministic.
+// membership state is deterministic. [ListGroupMembers] returns the same
+// members as lightweight identifier/name references with a total count and
+// Offset/Limit paging, for managing large distribution groups.
+//
+// Contacts.framework allows several groups with the same name in one
+// container, which makes later lookups by name ambiguous. Set
+// OnDuplicateName on [CreateGroupInput] or [UpdateGroupInput] to fail with
+// [ErrConflict] (the [OpError] ID names the existing group) or, when creating,
+// to reuse the existing group. [FindGroupByName] resolves a name to exactly
+// one group or reports [ErrNotFound] / [ErrAmbiguous].
+//
+// # Sync Semantics
+//
+// [SyncContacts] keeps a second address book, such as a Google account's
+// contacts, from drifting out of step. [CardDAVAddressBook] reaches any
+// CardDAV server, Google's included (Google needs an OAuth 2.0 Client rather
+// than Username and Password); other services adapt to
+// [RemoteAddressBook]. Each call is one direction ([SyncToRemote] or
+// [SyncToLocal]) and applies the [UpsertContact] merge rules per record,
+// never deleting. Source records that share an email or phone merge into a
+// single target record. Run with DryRun to preview the per-record
+// [SyncChange] list, then again without it to apply.
+//
+//	remote := &contacts.CardDAVAddressBook{
+//		URL:      "https://carddav.example.com/addressbooks/me/default/",
+//		Username: "me",
+//		Password: appPassword,
+//	}
+//	changes, err := contacts.SyncContacts(ctx, contacts.SyncContactsInput{
+//		Remote:    remote,
+//		Direction: contacts.SyncToRemote,
+//		MatchOn:   []contacts.UpsertMatchKey{contacts.UpsertMatchEmail},
+//	})
 //
 // # Safety Model
 //
@@ -62,6 +176,21 @@
 // [ErrInvalidArgument], [ErrPermissionDenied], [ErrVerificationFailed]) wrapped
 // in [OpError] for operation context.
 //
+// [CreateContactInput], [UpdateContactInput], [CreateGroupInput],
+// [UpdateGroupInput], [DeleteGroupInput], and [GroupMembershipInput] accept
+// DryRun. A dry run performs the same validation and store lookups as a real
+// call (target identity, destination container, parent group, membership
+// container match) and returns the record as it would look after the
+// mutation, without saving anything.
+//
+// [DeleteContacts] adds guardrails for bulk deletes: a per-call cap
+// (MaxDeletes, default [DefaultMaxDeletes]) and a ConfirmToken that must be
+// echoed from a prior dry run over the same identifiers. The dry run reports
+// each full record and its group memberships, which is everything the delete
+// removes irreversibly. The token is signed with a per-process key, expires
+// after [DeleteConfirmTokenTTL], and is bound to the previewed records: if any
+// of them changes before the delete, it fails with [ErrConflict].
+//
 // [RemoveContactFromGroup] uses osascript (AppleScript) as a platform
 // workaround because CNSaveRequest removeMember:fromGroup: can silently fail on
 // macOS 14.6+/15.x.
@@ -78,6 +207,14 @@
 //  5. Verify and synchronize membership with [ListContactsInGroup],
 //     [AddContactToGroup], [RemoveContactFromGroup].
 //
+// [AuthorizationStatusLimited] means the user shared only selected contacts.
+// Every call succeeds but sees only that subset, so a small or empty
+// [ListContacts] result is not evidence that a contact does not exist. Check
+// [AuthorizationStatus.CanAccess] rather than comparing with
+// [AuthorizationStatusAuthorized], report the limited scope to the user, list
+// the visible records with [AuthorizedContactIDs], and use
+// [OpenAccessSettings] to let the user widen access.
+//
 // # Cookbook Recipes
 //
 // The snippets below are recipe-level examples built only from exported
@@ -111,61 +248,61 @@
 //		return out, nil
 //	}
 //
-// 2) Page through contacts using Offset and a caller-defined limit:
+// 2) Page through contacts with a keyset cursor and a caller-defined limit.
+// [ContactCursor] of the last contact resumes the next page without skipping
+// or repeating records when the store changes between pages:
 //
-//	func contactsPageByFamilyName(ctx context.Context, familyName string, offset, limit int) ([]contacts.Contact, error) {
+//	func contactsPageByFamilyName(ctx context.Context, familyName, after string, limit int) (page []contacts.Contact, next string, err error) {
 //		if limit <= 0 {
-//			return []contacts.Contact{}, nil
+//			return []contacts.Contact{}, after, nil
 //		}
 //
 //		in := contacts.ListContactsInput{
 //			Filters: []contacts.Filter{
 //				{Field: contacts.ContactFieldFamilyName, Op: contacts.FilterEquals, Value: familyName},
 //			},
-//			Offset: offset,
+//			After: after,
 //		}
 //
-//		out := make([]contacts.Contact, 0, limit)
+//		page = make([]contacts.Contact, 0, limit)
 //		for c, err := range contacts.ListContacts(ctx, in) {
 //			if err != nil {
-//				return nil, err
+//				return nil, "", err
 //			}
-//			out = append(out, c)
-//			if len(out) == limit {
+//			page = append(page, c)
+//			if len(page) == limit {
 //				break
 //			}
 //		}
-//		return out, nil
+//		if len(page) == 0 {
+//			return page, "", nil
+//		}
+//		return page, contacts.ContactCursor(page[len(page)-1]), nil
 //	}
 //
-// 3) Create multiple contacts in a batch with per-item success/failure:
+// 3) Create multiple contacts in a batch with per-item success/failure.
+// [CreateContacts] saves the batch in one CNSaveRequest and attributes
+// failures to individual inputs. [UpdateContacts] and [UpsertContacts] batch
+// patches the same way, and [ImportCSV] uses them for the whole file:
 //
-//	type BatchCreateResult struct {
-//		Input   contacts.CreateContactInput
-//		Created contacts.Contact
-//		Err     error
-//	}
+//	func importContacts(ctx context.Context, people []contacts.Contact) (created, failed int, err error) {
+//		inputs := make([]contacts.CreateContactInput, len(people))
+//		for i, p := range people {
+//			inputs[i] = contacts.CreateContactInput{Contact: p}
+//		}
 //
-//	func batchCreateContacts(ctx context.Context, inputs []contacts.CreateContactInput) ([]BatchCreateResult, error) {
-//		defaultContainerID, err := contacts.DefaultContainerID(ctx)
+//		results, err := contacts.CreateContacts(ctx, inputs)
 //		if err != nil {
-//			return nil, err
+//			return 0, 0, err
 //		}
-//
-//		results := make([]BatchCreateResult, 0, len(inputs))
-//		for _, in := range inputs {
-//			if in.Contact.ContainerID == "" {
-//				in.Contact.ContainerID = defaultContainerID
+//		for _, r := range results {
+//			if r.Err != nil {
+//				failed++
+//				continue
 //			}
-//
-//			created, err := contacts.CreateContact(ctx, in)
-//			results = append(results, BatchCreateResult{
-//				Input:   in,
-//				Created: created,
-//				Err:     err,
-//			})
+//			created++
 //		}
-//		return results, nil
+//		return created, failed, nil
 //	}
 //
 // 4) Create contacts with an auto-incrementing name suffix:
@@ -204,23 +341,10 @@
 // 5) Ensure a group exists, then sync matching contacts into membership:
 //
 //	func ensureGroupByName(ctx context.Context, containerID, name string) (contacts.Group, error) {
-//		groups, err := contacts.ListGroups(ctx, contacts.ListGroupsInput{
-//			ContainerID:      containerID,
-//			IncludeHierarchy: true,
-//		})
-//		if err != nil {
-//			return contacts.Group{}, err
-//		}
-//
-//		for _, g := range groups {
-//			if g.Name == name {
-//				return g, nil
-//			}
-//		}
-//
 //		return contacts.CreateGroup(ctx, contacts.CreateGroupInput{
-//			Name:        name,
-//			ContainerID: containerID,
+//			Name:            name,
+//			ContainerID:     containerID,
+//			OnDuplicateName: contacts.DuplicateGroupNameReuse,
 //		})
 //	}
 //
@@ -263,7 +387,7 @@
 //				if _, ok := inGroup[targetID]; ok {
 //					continue
 //				}
-//				if err := contacts.AddContactToGroup(ctx, targetID, group.Identifier); err != nil {
+//				if err := contacts.AddContactToGroup(ctx, contacts.GroupMembershipInput{ContactID: targetID, GroupID: group.Identifier}); err != nil {
 //					return err
 //				}
 //				inGroup[targetID] = struct{}{}
@@ -294,6 +418,52 @@
 //		return parent, child, nil
 //	}
 //
+// 7) Find contacts whose birthday falls in a month, and set a birthday without a
+// known year:
+//
+//	func birthdaysInMonth(ctx context.Context, month time.Month) ([]contacts.Contact, error) {
+//		out := make([]contacts.Contact, 0)
+//		for c, err := range contacts.ListContacts(ctx, contacts.ListContactsInput{}) {
+//			if err != nil {
+//				return nil, err
+//			}
+//			if c.Birthday != nil && c.Birthday.Month == int(month) {
+//				out = append(out, c)
+//			}
+//		}
+//		return out, nil
+//	}
+//
+//	func setBirthday(ctx context.Context, contactID string, month time.Month, day int) (contacts.Contact, error) {
+//		return contacts.UpdateContact(ctx, contacts.UpdateContactInput{
+//			Identifier: contactID,
+//			Birthday:   &contacts.DateComponents{Month: int(month), Day: day},
+//		})
+//	}
+//
+// 8) Incrementally sync a local cache using change tokens:
+//
+//	func syncCache(ctx context.Context, cache map[string]contacts.Contact, token string) (string, error) {
+//		changes, err := contacts.ListContactChanges(ctx, contacts.ListContactChangesInput{Token: token})
+//		if err != nil {
+//			return token, err
+//		}
+//		if changes.DropEverything {
+//			clear(cache)
+//		}
+//		for _, id := range changes.DeletedIDs {
+//			delete(cache, id)
+//		}
+//		for _, id := range slices.Concat(changes.AddedIDs, changes.UpdatedIDs) {
+//			c, err := contacts.GetContact(ctx, id)
+//			if err != nil {
+//				return token, err
+//			}
+//			cache[id] = c
+//		}
+//		return changes.Token, nil
+//	}
+//
 // # Error Handling Pattern
 //
 // Use [errors.Is] for coarse-grained typed handling and [errors.As] for
@@ -310,11 +480,23 @@
 //		}
 //	}
 //
+// # JSON Encoding
+//
+// Public inputs and results carry snake_case JSON tags so they can be passed
+// directly as agent tool payloads and logged deterministically. Enumerations
+// such as [ContactType] and [FilterOp] encode as their String names ("person",
+// "not_contains"), and errors, including the per-item Err of batch results,
+// encode as {"op", "id", "code", "message"} with code from [ErrorCode].
+//
 // # Context
 //
-// All functions accept context.Context. Long-running enumerations check for
-// context cancellation between iterations where possible. Individual cgo calls
-// into Contacts.framework are not interruptible.
+// All functions accept context.Context and return ctx.Err() when the context
+// is cancelled or its deadline passes. [ListContacts], [StreamContacts], and
+// [ListContactsInGroup] pass a cancellation flag into the bridge, which stops
+// the Contacts.framework enumeration between contacts, so a deadline bounds
+// fetches over large address books. Single-record cgo calls (get, save) are
+// not interruptible once started. [RemoveContactFromGroup] runs osascript under
+// the context, so a stalled AppleScript invocation is killed on cancellation.
 //
 // # Build Constraints
 //
@@ -329,7 +511,26 @@
 // [Contact] is still settable during create operations (writes do not require the
 // entitlement), but fetched contacts will have an empty Note unless the calling
 // app has the notes entitlement. For this reason, filter fields intentionally
-// do not expose a Note constant.
+// do not expose a Note constant; [ContactFieldText] is the one filter that
+// reads notes, and it fails with [ErrNoteEntitlementMissing] rather than
+// skip them.
+//
+// Some stores reject note writes without the entitlement, failing the whole
+// save with Cocoa error 134092. [CreateContact] then recreates the contact
+// without the note and applies the note through the Contacts AppleScript
+// dictionary (the same osascript workaround [RemoveContactFromGroup] uses),
+// reading it back to verify persistence. If the note cannot be applied the new
+// contact is deleted and the error is returned.
+//
+// Any other operation that hits the missing entitlement fails with
+// [ErrNoteEntitlementMissing] (code "note_entitlement_missing"), whose message
+// says how to fix it. [GetContactNote] reads a note on demand; degrade by
+// treating that error as an empty note:
+//
+//	note, err := contacts.GetContactNote(ctx, id)
+//	if errors.Is(err, contacts.ErrNoteEntitlementMissing) {
+//		note, err = "", nil
+//	}
 //
 // # Testing
 //
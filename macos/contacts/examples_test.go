@@ -13,12 +13,6 @@ import (
 	contacts "github.com/spachava753/cuh/macos/contacts"
 )
 
-type batchCreateResult struct {
-	Input   contacts.CreateContactInput
-	Created contacts.Contact
-	Err     error
-}
-
 func ExampleListContacts_filterAndPostFilter() {
 	ctx := context.Background()
 
@@ -40,6 +34,41 @@ func ExampleListContacts_filterAndPostFilter() {
 	_ = filtered
 }
 
+func ExampleListContacts_cursorPagination() {
+	ctx := context.Background()
+	const pageSize = 25
+	after := ""
+
+	all := make([]contacts.Contact, 0, pageSize)
+	for {
+		in := contacts.ListContactsInput{
+			Filters: []contacts.Filter{
+				{Field: contacts.ContactFieldFamilyName, Op: contacts.FilterEquals, Value: "ExampleFamily"},
+			},
+			After: after,
+		}
+
+		page := make([]contacts.Contact, 0, pageSize)
+		for c, err := range contacts.ListContacts(ctx, in) {
+			if err != nil {
+				return
+			}
+			page = append(page, c)
+			if len(page) == pageSize {
+				break
+			}
+		}
+
+		if len(page) == 0 {
+			break
+		}
+		all = append(all, page...)
+		after = contacts.ContactCursor(page[len(page)-1])
+	}
+
+	_ = all
+}
+
 func ExampleListContacts_offsetPagination() {
 	ctx := context.Background()
 	const pageSize = 25
@@ -120,7 +149,45 @@ func ExampleListContacts_filterByContainer() {
 	}
 }
 
-func ExampleCreateContact_batch() {
+func ExampleListContacts_birthdaysThisMonth() {
+	ctx := context.Background()
+	month := time.Now().Month()
+
+	celebrants := make([]contacts.Contact, 0)
+	for c, err := range contacts.ListContacts(ctx, contacts.ListContactsInput{}) {
+		if err != nil {
+			return
+		}
+		if c.Birthday != nil && c.Birthday.Month == int(month) {
+			celebrants = append(celebrants, c)
+		}
+	}
+	_ = celebrants
+}
+
+func ExampleUpdateContact_addBirthdayAndDates() {
+	ctx := context.Background()
+
+	created, err := contacts.CreateContact(ctx, contacts.CreateContactInput{
+		Contact: contacts.Contact{GivenName: "Maya", FamilyName: "Example"},
+	})
+	if err != nil {
+		return
+	}
+	defer func() { _ = contacts.DeleteContact(ctx, created.Identifier) }()
+
+	// Year is optional: zero means "unknown year".
+	dates := []contacts.LabeledValue[contacts.DateComponents]{
+		{Label: "anniversary", Value: contacts.DateComponents{Year: 2015, Month: 9, Day: 12}},
+	}
+	_, _ = contacts.UpdateContact(ctx, contacts.UpdateContactInput{
+		Identifier: created.Identifier,
+		Birthday:   &contacts.DateComponents{Month: 3, Day: 4},
+		Dates:      &dates,
+	})
+}
+
+func ExampleCreateContacts() {
 	ctx := context.Background()
 
 	defaultContainerID, err := contacts.DefaultContainerID(ctx)
@@ -138,15 +205,15 @@ func ExampleCreateContact_batch() {
 		},
 	}
 
-	results := make([]batchCreateResult, 0, len(inputs))
-	for _, in := range inputs {
-		created, err := contacts.CreateContact(ctx, in)
-		results = append(results, batchCreateResult{Input: in, Created: created, Err: err})
-		if err == nil {
-			_ = contacts.DeleteContact(ctx, created.Identifier)
+	results, err := contacts.CreateContacts(ctx, inputs)
+	if err != nil {
+		return
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			_ = contacts.DeleteContact(ctx, r.Created.Identifier)
 		}
 	}
-	_ = results
 }
 
 func ExampleCreateContact_autoIncrementGivenName() {
@@ -175,7 +242,7 @@ func ExampleAddContactToGroup_syncSelection() {
 	if err != nil {
 		return
 	}
-	defer func() { _ = contacts.DeleteGroup(ctx, group.Identifier) }()
+	defer func() { _ = contacts.DeleteGroup(ctx, contacts.DeleteGroupInput{Identifier: group.Identifier}) }()
 
 	family := "ExampleSync" + suffix
 	seedContacts := []contacts.CreateContactInput{
@@ -235,7 +302,7 @@ func ExampleAddContactToGroup_syncSelection() {
 			if _, ok := inGroup[targetID]; ok {
 				continue
 			}
-			if err := contacts.AddContactToGroup(ctx, targetID, group.Identifier); err != nil {
+			if err := contacts.AddContactToGroup(ctx, contacts.GroupMembershipInput{ContactID: targetID, GroupID: group.Identifier}); err != nil {
 				return
 			}
 			inGroup[targetID] = struct{}{}
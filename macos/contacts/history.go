@@ -0,0 +1,67 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"strings"
+)
+
+// ContactChanges lists constituent contact identifiers changed since a change
+// token, as reported by the Contacts change-history API.
+type ContactChanges struct {
+	// AddedIDs are contacts created since the token. A contact created and
+	// then edited appears only here.
+	AddedIDs []string `json:"added_ids"`
+	// UpdatedIDs are pre-existing contacts that were modified.
+	UpdatedIDs []string `json:"updated_ids"`
+	// DeletedIDs are pre-existing contacts that were removed.
+	DeletedIDs []string `json:"deleted_ids"`
+	// DropEverything is true when the store could not provide a delta (empty
+	// or expired token, or a store reset). Callers must discard cached state;
+	// AddedIDs then lists every contact currently in the store.
+	DropEverything bool `json:"drop_everything"`
+	// Token is the change token to pass to the next [ListContactChanges] call.
+	Token string `json:"token"`
+}
+
+// ListContactChangesInput specifies the starting point for change tracking.
+type ListContactChangesInput struct {
+	// Token is a value previously returned by [CurrentChangeToken] or
+	// [ListContactChanges]. An empty Token returns the full store as a
+	// DropEverything result, which is how a sync client bootstraps.
+	Token string `json:"token"`
+}
+
+// CurrentChangeToken returns an opaque token for the current state of the
+// contact store. Passing it to [ListContactChanges] later returns only what
+// changed after this call.
+func CurrentChangeToken(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	token, errStr := currentHistoryToken()
+	if errStr != "" {
+		return "", newBridgeOpError("CurrentChangeToken", "", errStr)
+	}
+	return token, nil
+}
+
+// ListContactChanges returns contacts added, updated, or deleted since
+// input.Token, along with the token to use next time. Identifiers refer to
+// constituent records (`Unified=false`); use [GetContact] or
+// [ResolveContactIdentity] to hydrate them.
+//
+// Tokens are opaque base64 strings that are safe to persist. A malformed token
+// fails with [ErrInvalidArgument].
+func ListContactChanges(ctx context.Context, input ListContactChangesInput) (ContactChanges, error) {
+	if err := ctx.Err(); err != nil {
+		return ContactChanges{}, err
+	}
+	token := strings.TrimSpace(input.Token)
+	changes, errStr := fetchChangeHistory(token)
+	if errStr != "" {
+		return ContactChanges{}, newBridgeOpError("ListContactChanges", "", errStr)
+	}
+	return changes, nil
+}
@@ -0,0 +1,205 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON encoding
+//
+// Public types carry snake_case JSON tags so they can be used directly as
+// agent tool payloads. Enumerations encode as their String form, and errors
+// encode as {"op", "id", "code", "message"} objects.
+
+// String returns the wire name of the filter operator.
+func (op FilterOp) String() string {
+	switch op {
+	case FilterEquals:
+		return "equals"
+	case FilterContains:
+		return "contains"
+	case FilterNotContains:
+		return "not_contains"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(op))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (op FilterOp) MarshalText() ([]byte, error) {
+	return marshalEnum(op, op >= FilterEquals && op <= FilterNotContains)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (op *FilterOp) UnmarshalText(text []byte) error {
+	return unmarshalEnum(op, text, []FilterOp{FilterEquals, FilterContains, FilterNotContains})
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t ContactType) MarshalText() ([]byte, error) {
+	return marshalEnum(t, t == ContactTypePerson || t == ContactTypeOrganization)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *ContactType) UnmarshalText(text []byte) error {
+	return unmarshalEnum(t, text, []ContactType{ContactTypePerson, ContactTypeOrganization})
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t ContainerType) MarshalText() ([]byte, error) {
+	return marshalEnum(t, t >= ContainerTypeUnassigned && t <= ContainerTypeCardDAV)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *ContainerType) UnmarshalText(text []byte) error {
+	return unmarshalEnum(t, text, []ContainerType{ContainerTypeUnassigned, ContainerTypeLocal, ContainerTypeExchange, ContainerTypeCardDAV})
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuthorizationStatus) MarshalText() ([]byte, error) {
+	return marshalEnum(s, s >= AuthorizationStatusNotDetermined && s <= AuthorizationStatusLimited)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuthorizationStatus) UnmarshalText(text []byte) error {
+	return unmarshalEnum(s, text, []AuthorizationStatus{
+		AuthorizationStatusNotDetermined,
+		AuthorizationStatusRestricted,
+		AuthorizationStatusDenied,
+		AuthorizationStatusAuthorized,
+		AuthorizationStatusLimited,
+	})
+}
+
+func marshalEnum(v fmt.Stringer, known bool) ([]byte, error) {
+	if !known {
+		return nil, fmt.Errorf("%w: cannot marshal %s", ErrInvalidArgument, v)
+	}
+	return []byte(v.String()), nil
+}
+
+func unmarshalEnum[T fmt.Stringer](dst *T, text []byte, values []T) error {
+	for _, v := range values {
+		if v.String() == string(text) {
+			*dst = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown %T %q", ErrInvalidArgument, *dst, text)
+}
+
+// ErrorCode returns a stable snake_case code for err's sentinel cause, such
+// as "not_found" or "invalid_argument", or "internal" for other errors. It
+// returns "" for a nil error.
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrPermissionDenied):
+		return "permission_denied"
+	case errors.Is(err, ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, ErrUnsupported):
+		return "unsupported"
+	case errors.Is(err, ErrVerificationFailed):
+		return "verification_failed"
+	case errors.Is(err, ErrUnifiedContactNotMutable):
+		return "unified_contact_not_mutable"
+	case errors.Is(err, ErrGroupContainerMismatch):
+		return "group_container_mismatch"
+	case errors.Is(err, ErrAmbiguous):
+		return "ambiguous"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrNoteEntitlementMissing):
+		return "note_entitlement_missing"
+	default:
+		return "internal"
+	}
+}
+
+type errorJSON struct {
+	Op      string `json:"op,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	out := &errorJSON{Code: ErrorCode(err), Message: err.Error()}
+	var op *OpError
+	if errors.As(err, &op) {
+		out.Op, out.ID = op.Op, op.ID
+	}
+	return out
+}
+
+// MarshalJSON encodes the error as {"op", "id", "code", "message"}.
+func (e *OpError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newErrorJSON(e))
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r CreateContactResult) MarshalJSON() ([]byte, error) {
+	type plain CreateContactResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r UpdateContactResult) MarshalJSON() ([]byte, error) {
+	type plain UpdateContactResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r UpsertContactResult) MarshalJSON() ([]byte, error) {
+	type plain UpsertContactResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r MergeDuplicateResult) MarshalJSON() ([]byte, error) {
+	type plain MergeDuplicateResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r ImportCSVResult) MarshalJSON() ([]byte, error) {
+	type plain ImportCSVResult
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (r SyncChange) MarshalJSON() ([]byte, error) {
+	type plain SyncChange
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(r), newErrorJSON(r.Err)})
+}
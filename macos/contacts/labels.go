@@ -0,0 +1,63 @@
+//go:build darwin
+
+package contacts
+
+import "strings"
+
+// systemLabels maps friendly label names to the CNLabel* constants stored by
+// Contacts.framework.
+var systemLabels = map[string]string{
+	"home":        "_$!<Home>!$_",
+	"work":        "_$!<Work>!$_",
+	"school":      "_$!<School>!$_",
+	"other":       "_$!<Other>!$_",
+	"mobile":      "_$!<Mobile>!$_",
+	"main":        "_$!<Main>!$_",
+	"iphone":      "iPhone",
+	"apple watch": "_$!<AppleWatch>!$_",
+	"home fax":    "_$!<HomeFAX>!$_",
+	"work fax":    "_$!<WorkFAX>!$_",
+	"other fax":   "_$!<OtherFAX>!$_",
+	"pager":       "_$!<Pager>!$_",
+	"icloud":      "iCloud",
+	"homepage":    "_$!<HomePage>!$_",
+	"anniversary": "_$!<Anniversary>!$_",
+}
+
+// friendlyLabels is the inverse of systemLabels.
+var friendlyLabels = func() map[string]string {
+	out := make(map[string]string, len(systemLabels))
+	for friendly, raw := range systemLabels {
+		out[raw] = friendly
+	}
+	return out
+}()
+
+// friendlyLabel converts a stored label to the name callers use: "work",
+// "home", "mobile", and so on. Other system labels of the form "_$!<Name>!$_"
+// become their lowercased name, and custom labels are returned unchanged.
+func friendlyLabel(raw string) string {
+	if friendly, ok := friendlyLabels[raw]; ok {
+		return friendly
+	}
+	if name, ok := strings.CutPrefix(raw, "_$!<"); ok {
+		if name, ok := strings.CutSuffix(name, ">!$_"); ok {
+			return strings.ToLower(name)
+		}
+	}
+	return raw
+}
+
+// systemLabel converts a label given by a caller to the value to store. When
+// label is still the friendly form of raw, raw is kept so values read and
+// written back do not change. Otherwise friendly names map to their CNLabel*
+// constants, case-insensitively, and anything else is stored as given.
+func systemLabel(label, raw string) string {
+	if raw != "" && friendlyLabel(raw) == label {
+		return raw
+	}
+	if mapped, ok := systemLabels[strings.ToLower(strings.TrimSpace(label))]; ok {
+		return mapped
+	}
+	return label
+}
@@ -0,0 +1,170 @@
+//go:build darwin
+
+package contacts
+
+import "C"
+import "strings"
+
+// minPhoneSuffixDigits is the shortest national number that may match a longer
+// number by suffix. Shorter values only match exactly so that short codes do
+// not collide with arbitrary numbers.
+const minPhoneSuffixDigits = 7
+
+// maxCountryCodeDigits bounds how many leading digits a suffix match may
+// attribute to a country calling code.
+const maxCountryCodeDigits = 3
+
+// phoneKeypad maps the letters a-z to the telephone keys that carry them.
+const phoneKeypad = "22233344455566677778889999"
+
+// NormalizePhoneNumber reduces a phone number to digits, prefixed with "+"
+// when the input is written in international form (a leading "+" or "00").
+// Formatting characters are dropped, letters after the first digit are
+// translated to their keypad digits, and an extension marker ("x", "ext",
+// ";", ",", "#") followed by digits ends the number.
+//
+// NormalizePhoneNumber("+1 (555) 123-4567") returns "+15551234567",
+// NormalizePhoneNumber("555.123.4567 x89") returns "5551234567", and
+// NormalizePhoneNumber("1-800-FLOWERS") returns "18003569377".
+//
+// The bridge calls back into this function when evaluating
+// [ContactFieldPhoneNumbers] filters, so callers can use it to pre-normalize
+// handles (for example iMessage phone handles) before matching.
+func NormalizePhoneNumber(s string) string {
+	var b strings.Builder
+	international := false
+	seenDigit := false
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+			seenDigit = true
+		case r == '+' && !seenDigit:
+			international = true
+		case !seenDigit:
+		case isPhoneExtension(s[i:]):
+			return phoneWithPrefix(b.String(), international)
+		case r >= 'a' && r <= 'z':
+			b.WriteByte(phoneKeypad[r-'a'])
+		case r >= 'A' && r <= 'Z':
+			b.WriteByte(phoneKeypad[r-'A'])
+		}
+	}
+	return phoneWithPrefix(b.String(), international)
+}
+
+// isPhoneExtension reports whether s starts with an extension marker followed
+// by the extension digits, as in "x89", "ext. 89", or ";ext=89". A marker
+// without digits is part of the number, so "1-800-XBOX" keeps its letters.
+func isPhoneExtension(s string) bool {
+	rest := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(rest, "ext"):
+		rest = rest[3:]
+	case strings.ContainsRune("x;,#", rune(rest[0])):
+		rest = rest[1:]
+	default:
+		return false
+	}
+	rest = strings.TrimLeft(rest, " .:=")
+	return rest != "" && rest[0] >= '0' && rest[0] <= '9'
+}
+
+func phoneWithPrefix(digits string, international bool) string {
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		international = true
+	}
+	if digits == "" {
+		return ""
+	}
+	if international {
+		return "+" + digits
+	}
+	return digits
+}
+
+// phoneMatchKey returns the digits of a normalized number used for equality
+// matching. National numbers drop a single leading trunk "0" so that
+// "020 7946 0000" matches "+44 20 7946 0000".
+func phoneMatchKey(n string) string {
+	if strings.HasPrefix(n, "+") {
+		return n[1:]
+	}
+	return strings.TrimPrefix(n, "0")
+}
+
+// PhoneNumbersMatch reports whether a and b refer to the same number using the
+// E.164-aware rules of [FilterEquals] on [ContactFieldPhoneNumbers]: after
+// normalization the digits must be equal, or one number must be international
+// and end with the other, national number (at least 7 digits) with at most a
+// 3-digit country code in front. "+1 555-123-4567", "1 (555) 123 4567", and
+// "(555) 123-4567" all match "+15551234567", but "555-1234" does not match
+// "212-555-1234" because neither carries a country code.
+func PhoneNumbersMatch(a, b string) bool {
+	na, nb := NormalizePhoneNumber(a), NormalizePhoneNumber(b)
+	ka, kb := phoneMatchKey(na), phoneMatchKey(nb)
+	if ka == "" || kb == "" {
+		return false
+	}
+	if ka == kb {
+		return true
+	}
+	if len(ka) < len(kb) {
+		na, nb = nb, na
+		ka, kb = kb, ka
+	}
+	if !strings.HasPrefix(na, "+") || strings.HasPrefix(nb, "+") {
+		return false
+	}
+	if len(kb) < minPhoneSuffixDigits || len(ka)-len(kb) > maxCountryCodeDigits {
+		return false
+	}
+	return strings.HasSuffix(ka, kb)
+}
+
+// phoneMatchesFilter applies a [ContactFieldPhoneNumbers] filter to one stored
+// number. ok is false when query has no digits; callers then match it as
+// plain text.
+func phoneMatchesFilter(stored, query string, op FilterOp) (match, ok bool) {
+	q := strings.TrimPrefix(NormalizePhoneNumber(query), "+")
+	if q == "" {
+		return false, false
+	}
+	s := strings.TrimPrefix(NormalizePhoneNumber(stored), "+")
+	switch op {
+	case FilterEquals:
+		return PhoneNumbersMatch(stored, query), true
+	case FilterContains:
+		return strings.Contains(s, q), true
+	case FilterNotContains:
+		return !strings.Contains(s, q), true
+	}
+	return true, true
+}
+
+// The bridge matches phone numbers through these callbacks so that filters,
+// patches, and Go callers share one implementation.
+
+//export goPhoneNumbersMatch
+func goPhoneNumbersMatch(a, b *C.char) C.int {
+	if PhoneNumbersMatch(C.GoString(a), C.GoString(b)) {
+		return 1
+	}
+	return 0
+}
+
+// goPhoneMatchesFilter returns 1 or 0 for phoneMatchesFilter, or -1 when the
+// query has no digits.
+//
+//export goPhoneMatchesFilter
+func goPhoneMatchesFilter(stored, query *C.char, op C.int) C.int {
+	match, ok := phoneMatchesFilter(C.GoString(stored), C.GoString(query), FilterOp(op))
+	switch {
+	case !ok:
+		return -1
+	case match:
+		return 1
+	}
+	return 0
+}
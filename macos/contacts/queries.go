@@ -0,0 +1,203 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SavedQuery is a named set of contact filters, evaluated on demand with
+// [QueryStore.Run]. Saved queries emulate smart groups, which
+// Contacts.framework does not expose.
+type SavedQuery struct {
+	Name    string   `json:"name"`
+	Filters []Filter `json:"filters"`
+}
+
+// QueryStore persists saved queries as a JSON file.
+//
+// The zero value uses [DefaultQueryStorePath]. A QueryStore does not lock the
+// file; concurrent writers from separate processes may lose updates.
+type QueryStore struct {
+	// Path is the JSON file holding the queries. Empty means
+	// [DefaultQueryStorePath].
+	Path string
+}
+
+// DefaultQueryStorePath returns the default saved-query file,
+// <UserConfigDir>/cuh/contacts/queries.json.
+func DefaultQueryStorePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cuh", "contacts", "queries.json"), nil
+}
+
+type savedQueryFile struct {
+	Queries []SavedQuery `json:"queries"`
+}
+
+func (s QueryStore) path() (string, error) {
+	if strings.TrimSpace(s.Path) != "" {
+		return s.Path, nil
+	}
+	return DefaultQueryStorePath()
+}
+
+// List returns all saved queries sorted by name. A missing file yields no
+// queries.
+func (s QueryStore) List() ([]SavedQuery, error) {
+	path, err := s.path()
+	if err != nil {
+		return nil, &OpError{Op: "QueryStore.List", Err: err}
+	}
+	queries, err := readSavedQueries(path)
+	if err != nil {
+		return nil, &OpError{Op: "QueryStore.List", Err: err}
+	}
+	return queries, nil
+}
+
+// Get returns the saved query with the given name, or [ErrNotFound].
+func (s QueryStore) Get(name string) (SavedQuery, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return SavedQuery{}, newInvalidArg("QueryStore.Get", "", "name is required")
+	}
+	queries, err := s.List()
+	if err != nil {
+		return SavedQuery{}, err
+	}
+	for _, q := range queries {
+		if q.Name == name {
+			return q, nil
+		}
+	}
+	return SavedQuery{}, &OpError{Op: "QueryStore.Get", ID: name, Err: ErrNotFound}
+}
+
+// Save validates q and stores it, replacing any query with the same name.
+func (s QueryStore) Save(q SavedQuery) error {
+	q.Name = strings.TrimSpace(q.Name)
+	if q.Name == "" {
+		return newInvalidArg("QueryStore.Save", "", "name is required")
+	}
+	if err := ValidateFilters(q.Filters); err != nil {
+		return &OpError{Op: "QueryStore.Save", ID: q.Name, Err: err}
+	}
+	path, err := s.path()
+	if err != nil {
+		return &OpError{Op: "QueryStore.Save", ID: q.Name, Err: err}
+	}
+	queries, err := readSavedQueries(path)
+	if err != nil {
+		return &OpError{Op: "QueryStore.Save", ID: q.Name, Err: err}
+	}
+	q.Filters = cloneSlice(q.Filters)
+	i := slices.IndexFunc(queries, func(existing SavedQuery) bool { return existing.Name == q.Name })
+	if i >= 0 {
+		queries[i] = q
+	} else {
+		queries = append(queries, q)
+	}
+	if err := writeSavedQueries(path, queries); err != nil {
+		return &OpError{Op: "QueryStore.Save", ID: q.Name, Err: err}
+	}
+	return nil
+}
+
+// Delete removes the saved query with the given name, or returns
+// [ErrNotFound].
+func (s QueryStore) Delete(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return newInvalidArg("QueryStore.Delete", "", "name is required")
+	}
+	path, err := s.path()
+	if err != nil {
+		return &OpError{Op: "QueryStore.Delete", ID: name, Err: err}
+	}
+	queries, err := readSavedQueries(path)
+	if err != nil {
+		return &OpError{Op: "QueryStore.Delete", ID: name, Err: err}
+	}
+	i := slices.IndexFunc(queries, func(q SavedQuery) bool { return q.Name == name })
+	if i < 0 {
+		return &OpError{Op: "QueryStore.Delete", ID: name, Err: ErrNotFound}
+	}
+	queries = slices.Delete(queries, i, i+1)
+	if err := writeSavedQueries(path, queries); err != nil {
+		return &OpError{Op: "QueryStore.Delete", ID: name, Err: err}
+	}
+	return nil
+}
+
+// Run evaluates the named query with [ListContacts]. Errors loading the query
+// are yielded as the first and only element.
+func (s QueryStore) Run(ctx context.Context, name string) iter.Seq2[Contact, error] {
+	return func(yield func(Contact, error) bool) {
+		q, err := s.Get(name)
+		if err != nil {
+			yield(Contact{}, err)
+			return
+		}
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: q.Filters}) {
+			if !yield(c, err) {
+				return
+			}
+		}
+	}
+}
+
+func readSavedQueries(path string) ([]SavedQuery, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file savedQueryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	queries := file.Queries
+	slices.SortFunc(queries, func(a, b SavedQuery) int { return strings.Compare(a.Name, b.Name) })
+	return queries, nil
+}
+
+func writeSavedQueries(path string, queries []SavedQuery) error {
+	file := savedQueryFile{Queries: slices.Clone(queries)}
+	slices.SortFunc(file.Queries, func(a, b SavedQuery) int { return strings.Compare(a.Name, b.Name) })
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename so a crash never leaves a
+	// truncated store behind.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".queries-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,118 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// ContactStats summarizes address-book health. Counts are over constituent
+// records (`Unified=false`), so a person linked across two accounts counts
+// twice.
+type ContactStats struct {
+	Total        int `json:"total"`
+	WithEmail    int `json:"with_email"`
+	WithoutEmail int `json:"without_email"`
+	WithPhone    int `json:"with_phone"`
+	WithoutPhone int `json:"without_phone"`
+	// WithNeither counts contacts with no email address and no phone number,
+	// which are usually the first candidates for cleanup.
+	WithNeither int `json:"with_neither"`
+	// Containers lists every container, sorted by name.
+	Containers []ContainerStats `json:"containers"`
+	// Groups lists every group, sorted by name.
+	Groups []GroupStats `json:"groups"`
+	// Recent counts changes since StatsInput.ChangesSince. It is nil when no
+	// token was given.
+	Recent *RecentChangeStats `json:"recent,omitempty"`
+}
+
+// ContainerStats is the contact count of one container.
+type ContainerStats struct {
+	ContainerID string `json:"container_id"`
+	Name        string `json:"name"`
+	Total       int    `json:"total"`
+}
+
+// GroupStats is the direct member count of one group.
+type GroupStats struct {
+	GroupID     string `json:"group_id"`
+	Name        string `json:"name"`
+	ContainerID string `json:"container_id"`
+	Total       int    `json:"total"`
+}
+
+// RecentChangeStats counts contacts changed since a change token.
+type RecentChangeStats struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+	// DropEverything is true when the token was expired or invalidated, in
+	// which case Added counts every contact and the other counts are zero.
+	DropEverything bool `json:"drop_everything"`
+	// Token is the change token to pass next time.
+	Token string `json:"token"`
+}
+
+// StatsInput controls [Stats].
+type StatsInput struct {
+	// ChangesSince is a token from [CurrentChangeToken] or
+	// [ListContactChanges]. When set, Stats also fills Recent.
+	// Contacts.framework does not record modification dates, so "recently
+	// modified" is measured from a token the caller saved earlier.
+	ChangesSince string `json:"changes_since,omitempty"`
+}
+
+// Stats counts contacts in total, per container, and per group, along with how
+// many have email addresses and phone numbers. Counting happens inside the
+// bridge, so no contact is converted to Go.
+func Stats(ctx context.Context, input StatsInput) (ContactStats, error) {
+	if err := ctx.Err(); err != nil {
+		return ContactStats{}, err
+	}
+	stats, byContainer, byGroup, errStr := contactStats(ctx)
+	if errStr != "" {
+		return ContactStats{}, newBridgeOpError("Stats", "", errStr)
+	}
+
+	containers, err := ListContainers(ctx)
+	if err != nil {
+		return ContactStats{}, err
+	}
+	stats.Containers = make([]ContainerStats, 0, len(containers))
+	for _, c := range containers {
+		stats.Containers = append(stats.Containers, ContainerStats{ContainerID: c.Identifier, Name: c.Name, Total: byContainer[c.Identifier]})
+	}
+	slices.SortFunc(stats.Containers, func(a, b ContainerStats) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	groups, err := ListGroups(ctx, ListGroupsInput{})
+	if err != nil {
+		return ContactStats{}, err
+	}
+	stats.Groups = make([]GroupStats, 0, len(groups))
+	for _, g := range groups {
+		stats.Groups = append(stats.Groups, GroupStats{GroupID: g.Identifier, Name: g.Name, ContainerID: g.ContainerID, Total: byGroup[g.Identifier]})
+	}
+	slices.SortFunc(stats.Groups, func(a, b GroupStats) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	if token := strings.TrimSpace(input.ChangesSince); token != "" {
+		changes, err := ListContactChanges(ctx, ListContactChangesInput{Token: token})
+		if err != nil {
+			return ContactStats{}, err
+		}
+		stats.Recent = &RecentChangeStats{
+			Added:          len(changes.AddedIDs),
+			Updated:        len(changes.UpdatedIDs),
+			Deleted:        len(changes.DeletedIDs),
+			DropEverything: changes.DropEverything,
+			Token:          changes.Token,
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,121 @@
+//go:build darwin
+
+package contacts
+
+/*
+#include "bridge.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"context"
+	"iter"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// streamState carries the consumer of a streaming enumeration across the
+// bridge callback.
+type streamState struct {
+	yield    func(Contact) bool
+	panicked any
+}
+
+//export goStreamContact
+func goStreamContact(handle C.uintptr_t, cc *C.CContact) (keepGoing C.int) {
+	s := cgo.Handle(handle).Value().(*streamState)
+	// A panic must not unwind through Objective-C frames; stop the
+	// enumeration and re-raise it once the bridge call has returned.
+	defer func() {
+		if r := recover(); r != nil {
+			s.panicked = r
+			keepGoing = 0
+		}
+	}()
+	if s.yield(goContact(*cc)) {
+		return 1
+	}
+	return 0
+}
+
+func streamContacts(ctx context.Context, filters []Filter, yield func(Contact) bool) string {
+	cFilters := make([]C.CFilter, len(filters))
+	for i, f := range filters {
+		cFilters[i] = C.CFilter{
+			fieldName: makeBridgeString(string(f.Field)),
+			value:     makeBridgeString(bridgeFilterValue(f)),
+			op:        C.int(f.Op),
+		}
+	}
+	defer func() {
+		for _, cf := range cFilters {
+			freeBridgeString(cf.fieldName)
+			freeBridgeString(cf.value)
+		}
+	}()
+	var filterPtr *C.CFilter
+	if len(cFilters) > 0 {
+		filterPtr = &cFilters[0]
+	}
+
+	state := &streamState{yield: yield}
+	handle := cgo.NewHandle(state)
+	defer handle.Delete()
+
+	cancel, release := watchCancel(ctx)
+	result := C.bridge_stream_contacts(filterPtr, C.int(len(filters)), cancel, C.uintptr_t(handle))
+	release()
+	if state.panicked != nil {
+		panic(state.panicked)
+	}
+	errStr := goString(result.error)
+	if result.error.str != nil {
+		C.free(unsafe.Pointer(result.error.str))
+	}
+	return errStr
+}
+
+// StreamContacts enumerates contacts matching filters lazily: each contact is
+// converted and yielded while Contacts.framework is still enumerating, so
+// memory stays flat and the first result arrives without waiting for the
+// whole store. Filters have the same semantics as [ListContacts].
+//
+// Unlike [ListContacts], results follow the framework's given-name order and
+// do not support After or Offset. Stopping the loop early ends the
+// enumeration. The loop body runs on the enumerating thread, so it should not
+// block for long.
+func StreamContacts(ctx context.Context, filters []Filter) iter.Seq2[Contact, error] {
+	return func(yield func(Contact, error) bool) {
+		if err := ValidateFilters(filters); err != nil {
+			yield(Contact{}, &OpError{Op: "StreamContacts", Err: err})
+			return
+		}
+		if err := ctx.Err(); err != nil {
+			yield(Contact{}, err)
+			return
+		}
+		resolved, err := resolveGroupNameFilters(ctx, filters)
+		if err != nil {
+			yield(Contact{}, err)
+			return
+		}
+		stopped := false
+		errStr := streamContacts(ctx, resolved, func(c Contact) bool {
+			if !yield(c, nil) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+		if err := ctx.Err(); err != nil {
+			yield(Contact{}, err)
+			return
+		}
+		if errStr != "" {
+			yield(Contact{}, newBridgeOpError("StreamContacts", "", errStr))
+		}
+	}
+}
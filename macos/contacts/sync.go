@@ -0,0 +1,347 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RemoteContact is a contact held by another address book, such as a Google
+// account reached through the People API or CardDAV.
+type RemoteContact struct {
+	// RemoteID identifies the contact in the remote address book.
+	RemoteID string `json:"remote_id"`
+	// Contact holds the remote values. Identifier and ContainerID are
+	// ignored.
+	Contact Contact `json:"contact"`
+}
+
+// RemoteAddressBook is the transport [SyncContacts] uses to read and write a
+// second address book. [CardDAVAddressBook] implements it for CardDAV
+// servers; other services, such as the Google People API, adapt to it in a
+// few methods.
+//
+// Implementations should return the contact as stored after each write, so
+// sync results reflect what the remote side accepted.
+type RemoteAddressBook interface {
+	ListContacts(ctx context.Context) ([]RemoteContact, error)
+	CreateContact(ctx context.Context, c Contact) (RemoteContact, error)
+	UpdateContact(ctx context.Context, rc RemoteContact) (RemoteContact, error)
+}
+
+// SyncDirection selects which address book [SyncContacts] writes to.
+type SyncDirection string
+
+const (
+	// SyncToRemote copies macOS contacts into the remote address book.
+	SyncToRemote SyncDirection = "to_remote"
+	// SyncToLocal copies remote contacts into macOS Contacts.
+	SyncToLocal SyncDirection = "to_local"
+)
+
+// SyncAction is what [SyncContacts] does, or would do, for one source record.
+type SyncAction string
+
+const (
+	// SyncActionCreate adds the record to the target book.
+	SyncActionCreate SyncAction = "create"
+	// SyncActionUpdate merges the record into its single match.
+	SyncActionUpdate SyncAction = "update"
+	// SyncActionNone means the match already holds every value.
+	SyncActionNone SyncAction = "none"
+	// SyncActionSkip means the record has no value for any MatchOn key, so it
+	// cannot be matched safely and is left alone.
+	SyncActionSkip SyncAction = "skip"
+)
+
+// SyncContactsInput specifies a one-way sync between macOS Contacts and a
+// [RemoteAddressBook].
+type SyncContactsInput struct {
+	Remote    RemoteAddressBook `json:"-"`
+	Direction SyncDirection     `json:"direction"`
+	// MatchOn lists the keys that identify the same person in both books,
+	// with the semantics of [UpsertContactInput].
+	MatchOn []UpsertMatchKey `json:"match_on"`
+	// ContainerID scopes the macOS side: only its contacts are read, and
+	// SyncToLocal creates new contacts there. Empty means every container
+	// for reads and the default container for creates.
+	ContainerID string `json:"container_id,omitempty"`
+	// DryRun plans every change without writing to either book.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// SyncChange is the per-record outcome of [SyncContacts].
+type SyncChange struct {
+	Action SyncAction `json:"action"`
+	// SourceID identifies the record in the source book: a contact
+	// identifier for SyncToRemote, a RemoteID for SyncToLocal.
+	SourceID string `json:"source_id"`
+	// TargetID identifies the matched or created record in the target book.
+	// It is empty for planned creates, and in a dry run for updates of a
+	// record that an earlier change in the same sync creates.
+	TargetID string `json:"target_id,omitempty"`
+	// Contact is the target record after the change, or as it would be after
+	// the change in a dry run.
+	Contact Contact `json:"contact"`
+	Err     error   `json:"-"`
+}
+
+// SyncContacts copies contacts from one address book into the other,
+// following the merge rules of [UpsertContact]: records are matched on the
+// MatchOn keys, unmatched records are created, matched ones gain missing
+// values and have non-empty single-value fields overwritten, and nothing is
+// ever deleted. Run it once per direction for a two-way sync.
+//
+// Source records are planned in order, and each planned create counts as a
+// target for the records after it: two source records that share an email
+// or phone produce one create and one update of the created record, never
+// two copies.
+//
+// Failures for individual records, including [ErrAmbiguous] when a record
+// matches several targets, are reported in SyncChange.Err and do not stop the
+// sync. The returned error is non-nil only when an address book cannot be
+// listed or the input is invalid.
+func SyncContacts(ctx context.Context, input SyncContactsInput) ([]SyncChange, error) {
+	if input.Remote == nil {
+		return nil, newInvalidArg("SyncContacts", "", "remote is required")
+	}
+	if input.Direction != SyncToRemote && input.Direction != SyncToLocal {
+		return nil, newInvalidArg("SyncContacts", "", fmt.Sprintf("unsupported direction %q", input.Direction))
+	}
+	if err := validateMatchKeys(input.MatchOn); err != nil {
+		return nil, newInvalidArg("SyncContacts", "", err.Error())
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filters := []Filter{{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"}}
+	if cid := strings.TrimSpace(input.ContainerID); cid != "" {
+		filters = append(filters, Filter{Field: ContactFieldContainerID, Op: FilterEquals, Value: cid})
+	}
+	var local []syncRecord
+	for c, err := range ListContacts(ctx, ListContactsInput{Filters: filters}) {
+		if err != nil {
+			return nil, &OpError{Op: "SyncContacts", Err: err}
+		}
+		local = append(local, syncRecord{id: c.Identifier, contact: c})
+	}
+	remoteContacts, err := input.Remote.ListContacts(ctx)
+	if err != nil {
+		return nil, &OpError{Op: "SyncContacts", Err: fmt.Errorf("list remote contacts: %w", err)}
+	}
+	remote := make([]syncRecord, len(remoteContacts))
+	for i, rc := range remoteContacts {
+		remote[i] = syncRecord{id: rc.RemoteID, contact: rc.Contact}
+	}
+
+	source, target := local, remote
+	if input.Direction == SyncToLocal {
+		source, target = remote, local
+	}
+	changes, creators := planSync(source, target, input.MatchOn)
+	if input.DryRun {
+		return changes, nil
+	}
+
+	// latest holds the current state of each local target, so a record
+	// updated twice is patched against what the first update saved.
+	latest := make(map[string]Contact, len(local))
+	for _, r := range local {
+		latest[r.id] = r.contact
+	}
+	for i := range changes {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		ch := &changes[i]
+		if ch.Err != nil || (ch.Action != SyncActionCreate && ch.Action != SyncActionUpdate) {
+			continue
+		}
+		if c := creators[i]; c >= 0 {
+			if created := changes[c]; created.Err != nil || created.TargetID == "" {
+				ch.Err = &OpError{Op: "SyncContacts", ID: ch.SourceID, Err: fmt.Errorf("matching record from %s was not created", created.SourceID)}
+				continue
+			}
+			ch.TargetID = changes[c].TargetID
+		}
+		if input.Direction == SyncToRemote {
+			applyRemoteSyncChange(ctx, input.Remote, ch)
+		} else {
+			applyLocalSyncChange(ctx, input.ContainerID, latest, ch)
+		}
+	}
+	return changes, nil
+}
+
+type syncRecord struct {
+	id      string
+	contact Contact
+}
+
+func validateMatchKeys(keys []UpsertMatchKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("matchOn is required")
+	}
+	for _, key := range keys {
+		if key != UpsertMatchEmail && key != UpsertMatchPhone {
+			return fmt.Errorf("unsupported match key %q", key)
+		}
+	}
+	return nil
+}
+
+// planSync decides the change for every source record against target. It
+// does not touch either address book.
+//
+// Planned creates and updates are folded back into the targets, so later
+// source records match what earlier ones will have written. creators[i] is
+// the index of the create whose record change i updates, or -1 when change
+// i does not update a planned create.
+func planSync(source, target []syncRecord, keys []UpsertMatchKey) (changes []SyncChange, creators []int) {
+	type plannedRecord struct {
+		syncRecord
+		creator int
+	}
+	targets := make([]plannedRecord, len(target))
+	for i, t := range target {
+		targets[i] = plannedRecord{syncRecord: t, creator: -1}
+	}
+	changes = make([]SyncChange, 0, len(source))
+	creators = make([]int, 0, len(source))
+	for _, src := range source {
+		ch := SyncChange{SourceID: src.id}
+		creator := -1
+		if !hasMatchKeyValue(src.contact, keys) {
+			ch.Action = SyncActionSkip
+			changes = append(changes, ch)
+			creators = append(creators, creator)
+			continue
+		}
+		var matches []int
+		for i, t := range targets {
+			if contactsShareKey(src.contact, t.contact, keys) {
+				matches = append(matches, i)
+			}
+		}
+		desired := src.contact
+		desired.Identifier, desired.ContainerID, desired.LinkedIDs = "", "", nil
+		switch len(matches) {
+		case 0:
+			ch.Action = SyncActionCreate
+			ch.Contact = desired
+			targets = append(targets, plannedRecord{syncRecord: syncRecord{contact: desired}, creator: len(changes)})
+		case 1:
+			t := &targets[matches[0]]
+			ch.TargetID = t.id
+			ch.Contact = upsertMerge(t.contact, desired)
+			if _, changed := upsertPatch(t.contact, desired); changed {
+				ch.Action = SyncActionUpdate
+				t.contact = ch.Contact
+				creator = t.creator
+			} else {
+				ch.Action = SyncActionNone
+			}
+		default:
+			ids := make([]string, len(matches))
+			for i, m := range matches {
+				ids[i] = targets[m].id
+				if ids[i] == "" {
+					ids[i] = "planned create of " + changes[targets[m].creator].SourceID
+				}
+			}
+			ch.Action = SyncActionUpdate
+			ch.Err = &OpError{Op: "SyncContacts", ID: src.id, Err: fmt.Errorf("%w: %d contacts match: %s", ErrAmbiguous, len(matches), strings.Join(ids, ", "))}
+		}
+		changes = append(changes, ch)
+		creators = append(creators, creator)
+	}
+	return changes, creators
+}
+
+func hasMatchKeyValue(c Contact, keys []UpsertMatchKey) bool {
+	for _, key := range keys {
+		switch key {
+		case UpsertMatchEmail:
+			if slices.ContainsFunc(c.EmailAddresses, func(e LabeledValue[string]) bool { return strings.TrimSpace(e.Value) != "" }) {
+				return true
+			}
+		case UpsertMatchPhone:
+			if slices.ContainsFunc(c.PhoneNumbers, func(p LabeledValue[string]) bool { return strings.TrimSpace(p.Value) != "" }) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// contactsShareKey reports whether a and b share a value for any of keys.
+func contactsShareKey(a, b Contact, keys []UpsertMatchKey) bool {
+	share := func(x, y []LabeledValue[string], match func(a, b string) bool) bool {
+		for _, v := range x {
+			if strings.TrimSpace(v.Value) == "" {
+				continue
+			}
+			if slices.ContainsFunc(y, func(w LabeledValue[string]) bool { return match(v.Value, w.Value) }) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, key := range keys {
+		switch key {
+		case UpsertMatchEmail:
+			if share(a.EmailAddresses, b.EmailAddresses, emailAddressesMatch) {
+				return true
+			}
+		case UpsertMatchPhone:
+			if share(a.PhoneNumbers, b.PhoneNumbers, PhoneNumbersMatch) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func applyRemoteSyncChange(ctx context.Context, remote RemoteAddressBook, ch *SyncChange) {
+	var (
+		saved RemoteContact
+		err   error
+	)
+	if ch.Action == SyncActionCreate {
+		saved, err = remote.CreateContact(ctx, ch.Contact)
+	} else {
+		saved, err = remote.UpdateContact(ctx, RemoteContact{RemoteID: ch.TargetID, Contact: ch.Contact})
+	}
+	if err != nil {
+		ch.Err = &OpError{Op: "SyncContacts", ID: ch.SourceID, Err: err}
+		return
+	}
+	ch.TargetID, ch.Contact = saved.RemoteID, saved.Contact
+}
+
+func applyLocalSyncChange(ctx context.Context, containerID string, latest map[string]Contact, ch *SyncChange) {
+	if ch.Action == SyncActionCreate {
+		c := ch.Contact
+		c.ContainerID = strings.TrimSpace(containerID)
+		created, err := CreateContact(ctx, CreateContactInput{Contact: c})
+		if err != nil {
+			ch.Err = err
+			return
+		}
+		ch.TargetID, ch.Contact = created.Identifier, created
+		latest[created.Identifier] = created
+		return
+	}
+	patch, _ := upsertPatch(latest[ch.TargetID], ch.Contact)
+	updated, err := UpdateContact(ctx, patch)
+	if err != nil {
+		ch.Err = err
+		return
+	}
+	ch.Contact = updated
+	latest[ch.TargetID] = updated
+}
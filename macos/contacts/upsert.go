@@ -0,0 +1,296 @@
+//go:build darwin
+
+package contacts
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// UpsertMatchKey selects which contact values identify an existing record in
+// [UpsertContact].
+type UpsertMatchKey string
+
+const (
+	// UpsertMatchEmail matches contacts sharing any email address
+	// (case-insensitive).
+	UpsertMatchEmail UpsertMatchKey = "email"
+	// UpsertMatchPhone matches contacts sharing any phone number, using the
+	// country-code aware rules of [PhoneNumbersMatch].
+	UpsertMatchPhone UpsertMatchKey = "phone"
+)
+
+// UpsertContactInput specifies a contact to create or patch.
+type UpsertContactInput struct {
+	// Contact holds the desired values. Contact.ContainerID, when set,
+	// restricts matching to that container and is the creation target.
+	Contact Contact `json:"contact"`
+	// MatchOn lists the keys used to find an existing contact. At least one
+	// key is required, and Contact must have a value for it.
+	MatchOn []UpsertMatchKey `json:"match_on"`
+	// DryRun reports what would happen without saving, as in
+	// [CreateContactInput] and [UpdateContactInput].
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// UpsertContactResult is the outcome of [UpsertContact].
+type UpsertContactResult struct {
+	Contact Contact `json:"contact"`
+	// Created is true when no existing contact matched and a new one was
+	// created; false means an existing contact was patched.
+	Created bool `json:"created"`
+	// Err is the per-item failure reported by [UpsertContacts].
+	// UpsertContact returns failures as its error instead.
+	Err error `json:"-"`
+}
+
+// UpsertContact creates Contact unless an existing non-unified contact shares
+// one of the MatchOn keys, in which case that contact is patched instead.
+//
+// When patching, non-empty single-value fields (names, organization, job
+// title, birthday, image) overwrite the existing values. Emails and phones are
+// added with the list operations of [UpdateContactInput], and other
+// multi-value fields are merged without duplicates; nothing is removed.
+//
+// If more than one contact matches, UpsertContact returns [ErrAmbiguous] and
+// changes nothing; resolve the duplicates first, for example with
+// [FindDuplicateContacts] and [MergeContacts].
+func UpsertContact(ctx context.Context, input UpsertContactInput) (UpsertContactResult, error) {
+	plan, err := planUpsert(ctx, "UpsertContact", input)
+	if err != nil {
+		return UpsertContactResult{}, err
+	}
+	switch {
+	case plan.existing == nil:
+		created, err := CreateContact(ctx, CreateContactInput{Contact: input.Contact, DryRun: input.DryRun})
+		if err != nil {
+			return UpsertContactResult{}, err
+		}
+		return UpsertContactResult{Contact: created, Created: true}, nil
+	case !plan.changed:
+		return UpsertContactResult{Contact: *plan.existing}, nil
+	}
+	plan.patch.DryRun = input.DryRun
+	updated, err := UpdateContact(ctx, plan.patch)
+	if err != nil {
+		return UpsertContactResult{}, err
+	}
+	return UpsertContactResult{Contact: updated}, nil
+}
+
+// UpsertContacts runs [UpsertContact] for every input, saving all creates in
+// one [CreateContacts] call and all patches in one [UpdateContacts] call
+// instead of one store round-trip per contact.
+//
+// Results are returned in input order, with failures in
+// UpsertContactResult.Err. The outcome matches running the inputs one after
+// another: an input that shares a MatchOn value with an earlier input, or
+// patches the same contact, is planned again after the earlier one is saved,
+// so it updates that contact rather than creating a duplicate. Inputs with
+// DryRun set are previewed individually. The returned error is non-nil only
+// when ctx is done; results saved so far are returned with it.
+func UpsertContacts(ctx context.Context, inputs []UpsertContactInput) ([]UpsertContactResult, error) {
+	results := make([]UpsertContactResult, len(inputs))
+	pending := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		if input.DryRun {
+			results[i], results[i].Err = UpsertContact(ctx, input)
+			continue
+		}
+		pending = append(pending, i)
+	}
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		var (
+			next      []int
+			claimed   []Contact
+			targets   []string
+			creates   []CreateContactInput
+			createIdx []int
+			updates   []UpdateContactInput
+			updateIdx []int
+		)
+		for _, i := range pending {
+			input := inputs[i]
+			if slices.ContainsFunc(claimed, func(c Contact) bool { return contactsShareKey(input.Contact, c, input.MatchOn) }) {
+				next = append(next, i)
+				continue
+			}
+			plan, err := planUpsert(ctx, "UpsertContacts", input)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return results, ctxErr
+				}
+				results[i].Err = err
+				continue
+			}
+			if plan.existing != nil && slices.Contains(targets, plan.existing.Identifier) {
+				next = append(next, i)
+				continue
+			}
+			claimed = append(claimed, input.Contact)
+			switch {
+			case plan.existing == nil:
+				creates = append(creates, CreateContactInput{Contact: input.Contact})
+				createIdx = append(createIdx, i)
+			case !plan.changed:
+				targets = append(targets, plan.existing.Identifier)
+				results[i].Contact = *plan.existing
+			default:
+				targets = append(targets, plan.existing.Identifier)
+				updates = append(updates, plan.patch)
+				updateIdx = append(updateIdx, i)
+			}
+		}
+
+		created, err := CreateContacts(ctx, creates)
+		if err != nil {
+			return results, err
+		}
+		for j, i := range createIdx {
+			results[i] = UpsertContactResult{Contact: created[j].Created, Created: created[j].Err == nil, Err: created[j].Err}
+		}
+		updated, err := UpdateContacts(ctx, updates)
+		if err != nil {
+			return results, err
+		}
+		for j, i := range updateIdx {
+			results[i] = UpsertContactResult{Contact: updated[j].Updated, Err: updated[j].Err}
+		}
+		pending = next
+	}
+	return results, nil
+}
+
+// upsertPlan is the change [UpsertContact] decided on for one input.
+type upsertPlan struct {
+	// existing is the single matching contact, or nil to create one.
+	existing *Contact
+	// patch applies the input onto existing; changed is false when existing
+	// already holds every value.
+	patch   UpdateContactInput
+	changed bool
+}
+
+// planUpsert validates input and finds the contact it matches. It does not
+// save anything.
+func planUpsert(ctx context.Context, op string, input UpsertContactInput) (upsertPlan, error) {
+	if len(input.MatchOn) == 0 {
+		return upsertPlan{}, newInvalidArg(op, "", "matchOn is required")
+	}
+	desired := input.Contact
+	var keys []Filter
+	for _, key := range input.MatchOn {
+		switch key {
+		case UpsertMatchEmail:
+			for _, e := range desired.EmailAddresses {
+				if v := strings.TrimSpace(e.Value); v != "" {
+					keys = append(keys, Filter{Field: ContactFieldEmailAddresses, Op: FilterEquals, Value: v})
+				}
+			}
+		case UpsertMatchPhone:
+			for _, p := range desired.PhoneNumbers {
+				if v := strings.TrimSpace(p.Value); v != "" {
+					keys = append(keys, Filter{Field: ContactFieldPhoneNumbers, Op: FilterEquals, Value: v})
+				}
+			}
+		default:
+			return upsertPlan{}, newInvalidArg(op, "", fmt.Sprintf("unsupported match key %q", key))
+		}
+	}
+	if len(keys) == 0 {
+		return upsertPlan{}, newInvalidArg(op, "", "contact has no value for the matchOn keys")
+	}
+	if err := validateContactValues(desired); err != nil {
+		return upsertPlan{}, newInvalidArg(op, "", err.Error())
+	}
+
+	var matches []Contact
+	for _, key := range keys {
+		filters := []Filter{{Field: ContactFieldUnified, Op: FilterEquals, Value: "false"}, key}
+		if cid := strings.TrimSpace(desired.ContainerID); cid != "" {
+			filters = append(filters, Filter{Field: ContactFieldContainerID, Op: FilterEquals, Value: cid})
+		}
+		for c, err := range ListContacts(ctx, ListContactsInput{Filters: filters}) {
+			if err != nil {
+				return upsertPlan{}, &OpError{Op: op, Err: err}
+			}
+			if !slices.ContainsFunc(matches, func(m Contact) bool { return m.Identifier == c.Identifier }) {
+				matches = append(matches, c)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return upsertPlan{}, nil
+	case 1:
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.Identifier
+		}
+		return upsertPlan{}, &OpError{Op: op, Err: fmt.Errorf("%w: %d contacts match: %s", ErrAmbiguous, len(matches), strings.Join(ids, ", "))}
+	}
+	existing := matches[0]
+	patch, changed := upsertPatch(existing, desired)
+	return upsertPlan{existing: &existing, patch: patch, changed: changed}, nil
+}
+
+// upsertMerge returns existing with the values of desired applied: non-empty
+// single-value fields overwrite, multi-value fields are merged without
+// duplicates.
+func upsertMerge(existing, desired Contact) Contact {
+	merged := existing
+	set := func(dst *string, v string) {
+		if strings.TrimSpace(v) != "" {
+			*dst = v
+		}
+	}
+	set(&merged.NamePrefix, desired.NamePrefix)
+	set(&merged.GivenName, desired.GivenName)
+	set(&merged.MiddleName, desired.MiddleName)
+	set(&merged.FamilyName, desired.FamilyName)
+	set(&merged.PreviousFamilyName, desired.PreviousFamilyName)
+	set(&merged.NameSuffix, desired.NameSuffix)
+	set(&merged.Nickname, desired.Nickname)
+	set(&merged.PhoneticGivenName, desired.PhoneticGivenName)
+	set(&merged.PhoneticMiddleName, desired.PhoneticMiddleName)
+	set(&merged.PhoneticFamilyName, desired.PhoneticFamilyName)
+	set(&merged.OrganizationName, desired.OrganizationName)
+	set(&merged.DepartmentName, desired.DepartmentName)
+	set(&merged.JobTitle, desired.JobTitle)
+	if len(desired.ImageData) > 0 {
+		merged.ImageData = slices.Clone(desired.ImageData)
+	}
+	if desired.Birthday != nil {
+		b := *desired.Birthday
+		merged.Birthday = &b
+	}
+	return mergeContactValues(merged, desired)
+}
+
+// upsertPatch builds the UpdateContactInput that applies desired onto
+// existing. It reports false when existing already holds every value.
+func upsertPatch(existing, desired Contact) (UpdateContactInput, bool) {
+	in, _ := contactPatch(existing, upsertMerge(existing, desired))
+	if desired.Birthday != nil && (existing.Birthday == nil || *existing.Birthday != *desired.Birthday) {
+		b := *desired.Birthday
+		in.Birthday = &b
+	}
+	// Emails and phones go through list operations so values written by
+	// others since the match was read are kept.
+	if in.EmailAddresses != nil {
+		in.EmailAddresses = nil
+		in.AddEmailAddresses = cloneSlice(desired.EmailAddresses)
+	}
+	if in.PhoneNumbers != nil {
+		in.PhoneNumbers = nil
+		in.AddPhoneNumbers = cloneSlice(desired.PhoneNumbers)
+	}
+	return in, hasUpdateContactChanges(in)
+}
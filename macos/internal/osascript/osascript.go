@@ -0,0 +1,124 @@
+//go:build darwin
+
+// Package osascript runs AppleScript for the macOS automation packages and
+// decodes the records their scripts return.
+package osascript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Scripts return records separated by ASCII record separators and fields by
+// unit separators, characters that do not occur in URLs or page titles.
+// Every script can write them as the US and RS properties.
+const (
+	FieldSep  = "\x1f"
+	RecordSep = "\x1e"
+)
+
+// ChangedErrorNumber is the AppleScript error number a script raises when
+// its target no longer matches what the caller read, such as a tab that
+// navigated before it was closed. [Runner] reports it as Runner.Conflict.
+const ChangedErrorNumber = 1100
+
+// prelude is prepended to every script.
+const prelude = `property US : character id 31
+property RS : character id 30
+`
+
+// Runner runs scripts with osascript and maps AppleScript errors to the
+// calling package's sentinel errors.
+type Runner struct {
+	// Prelude holds handlers shared by the caller's scripts. It is prepended
+	// to every script, after the US and RS properties.
+	Prelude string
+	// NotFound is wrapped for errors -1728 and -1719: no such application,
+	// window, or tab.
+	NotFound error
+	// PermissionDenied is wrapped when the user has not allowed Apple events
+	// to the application (-1743).
+	PermissionDenied error
+	// Conflict is wrapped for [ChangedErrorNumber].
+	Conflict error
+}
+
+// Run runs script with osascript, passing args as script arguments so no
+// escaping is needed, and returns its output without the trailing newline.
+func (r Runner) Run(ctx context.Context, script string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "osascript", append([]string{"-e", prelude + r.Prelude + script}, args...)...)
+	out, err := cmd.Output()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return "", ctxErr
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", r.Classify(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("osascript failed: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Classify maps an osascript error message, which ends with the AppleScript
+// error number in parentheses, to a typed error.
+func (r Runner) Classify(msg string) error {
+	switch {
+	case strings.Contains(msg, "(-1728)"), strings.Contains(msg, "(-1719)"):
+		return fmt.Errorf("%w: %s", r.NotFound, msg)
+	case strings.Contains(msg, "(-1743)"), strings.Contains(msg, "Not authorized to send Apple events"):
+		return fmt.Errorf("%w: %s", r.PermissionDenied, msg)
+	case strings.Contains(msg, "("+strconv.Itoa(ChangedErrorNumber)+")"):
+		return fmt.Errorf("%w: %s", r.Conflict, msg)
+	default:
+		return fmt.Errorf("osascript failed: %s", msg)
+	}
+}
+
+// TabRow is one tab record: window id, index, current, front window, URL,
+// and title, in that order.
+type TabRow struct {
+	WindowID    int
+	Index       int
+	Current     bool
+	FrontWindow bool
+	URL         string
+	Title       string
+}
+
+// ParseTabRows decodes tab records. The title is the last field, so a
+// separator inside it is kept as text.
+func ParseTabRows(out string) ([]TabRow, error) {
+	var rows []TabRow
+	for _, rec := range strings.Split(out, RecordSep) {
+		if strings.TrimSpace(rec) == "" {
+			continue
+		}
+		f := strings.SplitN(strings.TrimLeft(rec, "\n"), FieldSep, 6)
+		if len(f) != 6 {
+			return nil, fmt.Errorf("malformed tab record %q", rec)
+		}
+		wid, err := strconv.Atoi(f[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed window id %q", f[0])
+		}
+		idx, err := strconv.Atoi(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed tab index %q", f[1])
+		}
+		rows = append(rows, TabRow{
+			WindowID:    wid,
+			Index:       idx,
+			Current:     f[2] == "true",
+			FrontWindow: f[3] == "true",
+			URL:         f[4],
+			Title:       f[5],
+		})
+	}
+	return rows, nil
+}
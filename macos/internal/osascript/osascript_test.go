@@ -0,0 +1,57 @@
+//go:build darwin
+
+package osascript
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nalgeon/be"
+)
+
+func TestParseTabRows(t *testing.T) {
+	out := "12\x1f1\x1ftrue\x1ftrue\x1fhttps://example.com/\x1fExample Domain\x1e" +
+		"12\x1f2\x1ffalse\x1ftrue\x1f\x1f\x1e" +
+		"\n40\x1f1\x1ftrue\x1ffalse\x1fhttps://a.test/?q=1\x1fTitle with \x1f separator\x1e"
+	rows, err := ParseTabRows(out)
+	be.Err(t, err, nil)
+	be.Equal(t, rows, []TabRow{
+		{WindowID: 12, Index: 1, URL: "https://example.com/", Title: "Example Domain", Current: true, FrontWindow: true},
+		{WindowID: 12, Index: 2, FrontWindow: true},
+		{WindowID: 40, Index: 1, URL: "https://a.test/?q=1", Title: "Title with \x1f separator", Current: true},
+	})
+
+	rows, err = ParseTabRows("")
+	be.Err(t, err, nil)
+	be.Equal(t, len(rows), 0)
+
+	_, err = ParseTabRows("x\x1f1\x1ftrue\x1ftrue\x1fu\x1fn\x1e")
+	be.Err(t, err)
+	_, err = ParseTabRows("1\x1fx\x1ftrue\x1ftrue\x1fu\x1fn\x1e")
+	be.Err(t, err)
+	_, err = ParseTabRows("1\x1f1\x1e")
+	be.Err(t, err)
+}
+
+func TestClassify(t *testing.T) {
+	var (
+		errNotFound   = errors.New("not found")
+		errPermission = errors.New("permission denied")
+		errConflict   = errors.New("conflict")
+	)
+	r := Runner{NotFound: errNotFound, PermissionDenied: errPermission, Conflict: errConflict}
+	cases := []struct {
+		msg  string
+		want error
+	}{
+		{"execution error: Safari got an error: Can’t get window id 99. (-1728)", errNotFound},
+		{"execution error: Google Chrome got an error: Can’t get tab 9 of window id 1. Invalid index. (-1719)", errNotFound},
+		{"execution error: Not authorized to send Apple events to Safari. (-1743)", errPermission},
+		{"execution error: tab changed (1100)", errConflict},
+	}
+	for _, c := range cases {
+		be.True(t, errors.Is(r.Classify(c.msg), c.want))
+	}
+	err := r.Classify("execution error: boom (-2700)")
+	be.True(t, !errors.Is(err, errNotFound) && !errors.Is(err, errPermission) && !errors.Is(err, errConflict))
+}
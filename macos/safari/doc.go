@@ -0,0 +1,65 @@
+//go:build darwin
+
+// Package safari provides agent-oriented primitives for controlling Safari
+// tabs on macOS through AppleScript.
+//
+// It complements the cross-platform browser package for users who do not run
+// Chrome: instead of a DevTools session it drives the Safari the user already
+// has open, so tabs, logins, and windows are the user's own.
+//
+// Primitive groups:
+//
+//   - Find/select: [ListTabs] returns every tab with its URL and title;
+//     [GetTab] re-reads one tab.
+//   - Read: [PageSource] returns the HTML of a tab's current page.
+//   - Act: [OpenURL] opens a page in a new tab or window, [ActivateTab]
+//     brings a tab to the front, and [CloseTab] closes one.
+//
+// Suggested import path from calling code:
+//
+//	import "github.com/spachava753/cuh/macos/safari"
+//
+// # Tab References
+//
+// Safari's scripting interface has no stable tab identifiers, so a [TabRef]
+// is a window id plus a 1-based position. Window ids stay valid while the
+// window is open, but positions shift when earlier tabs close or tabs are
+// reordered. List tabs right before acting on them, and pass the URL you
+// expect to [CloseTabInput].ExpectURL so a shifted ref fails with
+// [ErrConflict] instead of closing the wrong tab.
+//
+// # Safety Model
+//
+//   - [ListTabs] never launches Safari; reads on other primitives fail with
+//     [ErrNotFound] when Safari is not running.
+//   - [CloseTab] supports DryRun and the ExpectURL guard.
+//   - Mutations verify their effect by reading back from Safari and fail with
+//     [ErrVerificationFailed] if it did not stick.
+//   - The first call prompts the user to allow the calling app to control
+//     Safari. A refusal surfaces as [ErrPermissionDenied]; the grant lives in
+//     System Settings > Privacy & Security > Automation.
+//
+// # Composition Pattern
+//
+// Close every tab showing a given site, checking each URL before closing.
+// Iterating in reverse keeps earlier positions in the same window valid:
+//
+//	tabs, err := safari.ListTabs(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, t := range slices.Backward(tabs) {
+//		if !strings.HasPrefix(t.URL, "https://news.example.com/") {
+//			continue
+//		}
+//		if _, err := safari.CloseTab(ctx, safari.CloseTabInput{Tab: t.TabRef, ExpectURL: t.URL}); err != nil {
+//			return err
+//		}
+//	}
+//
+// # Error Handling Pattern
+//
+// Errors are typed sentinels ([ErrNotFound], [ErrPermissionDenied],
+// [ErrInvalidArgument], [ErrConflict], [ErrVerificationFailed]) wrapped in
+// [OpError] for operation context. Check them with errors.Is.
+package safari
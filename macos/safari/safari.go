@@ -0,0 +1,228 @@
+//go:build darwin
+
+package safari
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spachava753/cuh/macos/internal/osascript"
+)
+
+// Typed package-level errors.
+var (
+	// ErrNotFound indicates the window or tab does not exist, or Safari is
+	// not running.
+	ErrNotFound = errors.New("safari: not found")
+	// ErrPermissionDenied indicates the calling process is not allowed to
+	// control Safari. Grant it under System Settings > Privacy & Security >
+	// Automation.
+	ErrPermissionDenied = errors.New("safari: permission denied")
+	// ErrInvalidArgument indicates a caller-provided input was invalid.
+	ErrInvalidArgument = errors.New("safari: invalid argument")
+	// ErrConflict indicates the tab no longer shows the expected page, usually
+	// because tabs were opened or closed since it was listed.
+	ErrConflict = errors.New("safari: conflict")
+	// ErrVerificationFailed indicates read-after-write verification failed.
+	ErrVerificationFailed = errors.New("safari: verification failed")
+)
+
+// OpError captures operation-level failures with typed causes.
+type OpError struct {
+	Op  string
+	ID  string
+	Err error
+}
+
+func (e *OpError) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.ID != "" {
+		return fmt.Sprintf("safari: %s (%s): %v", e.Op, e.ID, e.Err)
+	}
+	return fmt.Sprintf("safari: %s: %v", e.Op, e.Err)
+}
+
+func (e *OpError) Unwrap() error { return e.Err }
+
+func newInvalidArg(op, id, message string) error {
+	return &OpError{Op: op, ID: id, Err: fmt.Errorf("%w: %s", ErrInvalidArgument, strings.TrimSpace(message))}
+}
+
+// TabRef addresses a tab by its Safari window id and 1-based position in
+// that window. Window ids are stable while the window is open; positions shift
+// when earlier tabs close, so refs from [ListTabs] should be used promptly.
+type TabRef struct {
+	WindowID int `json:"window_id"`
+	Index    int `json:"index"`
+}
+
+// String formats the ref as "window/index", the form used in [OpError] IDs.
+func (r TabRef) String() string {
+	return strconv.Itoa(r.WindowID) + "/" + strconv.Itoa(r.Index)
+}
+
+func (r TabRef) validate(op string) error {
+	if r.WindowID <= 0 || r.Index <= 0 {
+		return newInvalidArg(op, r.String(), "window id and index must be positive")
+	}
+	return nil
+}
+
+// Tab is one open Safari tab.
+type Tab struct {
+	TabRef
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	// Current is true for the selected tab of its window.
+	Current bool `json:"current"`
+	// FrontWindow is true when the tab's window is Safari's front window.
+	FrontWindow bool `json:"front_window"`
+}
+
+// ListTabs returns every tab in every Safari window, front window first and
+// tabs in window order. It returns an empty list, without launching Safari,
+// when Safari is not running.
+func ListTabs(ctx context.Context) ([]Tab, error) {
+	out, err := scripts.Run(ctx, listTabsScript)
+	if err != nil {
+		return nil, &OpError{Op: "ListTabs", Err: err}
+	}
+	tabs, err := parseTabs(out)
+	if err != nil {
+		return nil, &OpError{Op: "ListTabs", Err: err}
+	}
+	return tabs, nil
+}
+
+// GetTab returns the tab at ref.
+func GetTab(ctx context.Context, ref TabRef) (Tab, error) {
+	if err := ref.validate("GetTab"); err != nil {
+		return Tab{}, err
+	}
+	out, err := scripts.Run(ctx, getTabScript, strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index))
+	if err != nil {
+		return Tab{}, &OpError{Op: "GetTab", ID: ref.String(), Err: err}
+	}
+	tabs, err := parseTabs(out)
+	if err != nil || len(tabs) != 1 {
+		return Tab{}, &OpError{Op: "GetTab", ID: ref.String(), Err: fmt.Errorf("unexpected script output %q", out)}
+	}
+	return tabs[0], nil
+}
+
+// OpenURLInput specifies a page to open in Safari.
+type OpenURLInput struct {
+	// URL must be absolute, for example "https://example.com".
+	URL string `json:"url"`
+	// NewWindow opens the page in a new window instead of a new tab.
+	NewWindow bool `json:"new_window,omitempty"`
+	// WindowID selects the window for the new tab. Zero means the front
+	// window; a new window is created when Safari has none.
+	WindowID int `json:"window_id,omitempty"`
+	// Activate selects the new tab and brings Safari to the front.
+	Activate bool `json:"activate,omitempty"`
+}
+
+// OpenURL opens input.URL in a new Safari tab or window, launching Safari if
+// needed, and returns the new tab as read back from Safari. It does not wait
+// for the page to load.
+func OpenURL(ctx context.Context, input OpenURLInput) (Tab, error) {
+	raw := strings.TrimSpace(input.URL)
+	if raw == "" {
+		return Tab{}, newInvalidArg("OpenURL", "", "url is required")
+	}
+	if u, err := url.Parse(raw); err != nil || !u.IsAbs() {
+		return Tab{}, newInvalidArg("OpenURL", raw, "url must be absolute")
+	}
+	if input.WindowID < 0 {
+		return Tab{}, newInvalidArg("OpenURL", raw, "window id must not be negative")
+	}
+	if input.NewWindow && input.WindowID != 0 {
+		return Tab{}, newInvalidArg("OpenURL", raw, "newWindow and windowID are mutually exclusive")
+	}
+	out, err := scripts.Run(ctx, openURLScript, raw, strconv.FormatBool(input.NewWindow),
+		strconv.Itoa(input.WindowID), strconv.FormatBool(input.Activate))
+	if err != nil {
+		return Tab{}, &OpError{Op: "OpenURL", ID: raw, Err: err}
+	}
+	tabs, err := parseTabs(out)
+	if err != nil || len(tabs) != 1 {
+		return Tab{}, &OpError{Op: "OpenURL", ID: raw, Err: fmt.Errorf("unexpected script output %q", out)}
+	}
+	return tabs[0], nil
+}
+
+// ActivateTab selects the tab at ref, raises its window, and brings Safari to
+// the front.
+func ActivateTab(ctx context.Context, ref TabRef) error {
+	if err := ref.validate("ActivateTab"); err != nil {
+		return err
+	}
+	if _, err := scripts.Run(ctx, activateTabScript, strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index)); err != nil {
+		return &OpError{Op: "ActivateTab", ID: ref.String(), Err: err}
+	}
+	tab, err := GetTab(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if !tab.Current || !tab.FrontWindow {
+		return &OpError{Op: "ActivateTab", ID: ref.String(), Err: fmt.Errorf("%w: tab is not the current tab of the front window", ErrVerificationFailed)}
+	}
+	return nil
+}
+
+// CloseTabInput specifies a tab to close.
+type CloseTabInput struct {
+	Tab TabRef `json:"tab"`
+	// ExpectURL, when set, must equal the tab's current URL. It guards
+	// against closing the wrong tab after positions shift; a mismatch fails
+	// with [ErrConflict].
+	ExpectURL string `json:"expect_url,omitempty"`
+	// DryRun resolves and checks the tab without closing it.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// CloseTab closes one tab and returns it as it was before closing. Closing
+// the last tab of a window closes the window.
+func CloseTab(ctx context.Context, input CloseTabInput) (Tab, error) {
+	ref := input.Tab
+	if err := ref.validate("CloseTab"); err != nil {
+		return Tab{}, err
+	}
+	tab, err := GetTab(ctx, ref)
+	if err != nil {
+		return Tab{}, err
+	}
+	if input.ExpectURL != "" && tab.URL != input.ExpectURL {
+		return tab, &OpError{Op: "CloseTab", ID: ref.String(), Err: fmt.Errorf("%w: tab shows %q, expected %q", ErrConflict, tab.URL, input.ExpectURL)}
+	}
+	if input.DryRun {
+		return tab, nil
+	}
+	out, err := scripts.Run(ctx, closeTabScript, strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index), tab.URL)
+	if err != nil {
+		return tab, &OpError{Op: "CloseTab", ID: ref.String(), Err: err}
+	}
+	if before, after, ok := strings.Cut(out, osascript.FieldSep); !ok || before == after {
+		return tab, &OpError{Op: "CloseTab", ID: ref.String(), Err: fmt.Errorf("%w: tab count unchanged", ErrVerificationFailed)}
+	}
+	return tab, nil
+}
+
+// PageSource returns the HTML source of the page loaded in the tab at ref.
+func PageSource(ctx context.Context, ref TabRef) (string, error) {
+	if err := ref.validate("PageSource"); err != nil {
+		return "", err
+	}
+	out, err := scripts.Run(ctx, pageSourceScript, strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index))
+	if err != nil {
+		return "", &OpError{Op: "PageSource", ID: ref.String(), Err: err}
+	}
+	return out, nil
+}
@@ -0,0 +1,145 @@
+//go:build darwin
+
+package safari
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+)
+
+// requireLive skips tests that open and close real Safari tabs unless
+// CUH_SAFARI_LIVE=1.
+func requireLive(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CUH_SAFARI_LIVE") != "1" {
+		t.Skip("set CUH_SAFARI_LIVE=1 to run Safari live tests")
+	}
+}
+
+func TestParseTabs(t *testing.T) {
+	out := "12\x1f1\x1ftrue\x1ftrue\x1fhttps://example.com/\x1fExample Domain\x1e" +
+		"12\x1f2\x1ffalse\x1ftrue\x1f\x1f\x1e" +
+		"40\x1f1\x1ftrue\x1ffalse\x1fhttps://a.test/?q=1\x1fTitle with \x1f separator\x1e"
+	tabs, err := parseTabs(out)
+	be.Err(t, err, nil)
+	be.Equal(t, tabs, []Tab{
+		{TabRef: TabRef{WindowID: 12, Index: 1}, URL: "https://example.com/", Title: "Example Domain", Current: true, FrontWindow: true},
+		{TabRef: TabRef{WindowID: 12, Index: 2}, FrontWindow: true},
+		{TabRef: TabRef{WindowID: 40, Index: 1}, URL: "https://a.test/?q=1", Title: "Title with \x1f separator", Current: true},
+	})
+
+	tabs, err = parseTabs("")
+	be.Err(t, err, nil)
+	be.Equal(t, len(tabs), 0)
+
+	_, err = parseTabs("x\x1f1\x1ftrue\x1ftrue\x1fu\x1fn\x1e")
+	be.Err(t, err)
+	_, err = parseTabs("1\x1f1\x1e")
+	be.Err(t, err)
+}
+
+func TestClassifyScriptError(t *testing.T) {
+	cases := []struct {
+		msg  string
+		want error
+	}{
+		{"execution error: Safari got an error: Can’t get window id 99. (-1728)", ErrNotFound},
+		{"execution error: Safari got an error: Can’t get tab 9 of window id 1. Invalid index. (-1719)", ErrNotFound},
+		{"execution error: Not authorized to send Apple events to Safari. (-1743)", ErrPermissionDenied},
+		{"execution error: tab changed (1100)", ErrConflict},
+	}
+	for _, c := range cases {
+		be.True(t, errors.Is(scripts.Classify(c.msg), c.want))
+	}
+	err := scripts.Classify("execution error: boom (-2700)")
+	be.True(t, !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrConflict))
+}
+
+func TestInvalidArguments(t *testing.T) {
+	ctx := context.Background()
+	_, err := GetTab(ctx, TabRef{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = PageSource(ctx, TabRef{WindowID: 1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	err = ActivateTab(ctx, TabRef{Index: 1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = CloseTab(ctx, CloseTabInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = OpenURL(ctx, OpenURLInput{})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = OpenURL(ctx, OpenURLInput{URL: "example.com"})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = OpenURL(ctx, OpenURLInput{URL: "https://example.com", NewWindow: true, WindowID: 3})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+}
+
+func TestTabLifecycle(t *testing.T) {
+	requireLive(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	page := filepath.Join(t.TempDir(), "page.html")
+	be.Err(t, os.WriteFile(page, []byte("<title>CUHTest_Safari</title><p>cuh safari live test</p>"), 0o644), nil)
+	pageURL := (&url.URL{Scheme: "file", Path: page}).String()
+
+	tab, err := OpenURL(ctx, OpenURLInput{URL: pageURL, NewWindow: true})
+	be.Err(t, err, nil)
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if cur, err := GetTab(ctx, tab.TabRef); err == nil && cur.Title == "CUHTest_Safari" {
+			CloseTab(ctx, CloseTabInput{Tab: cur.TabRef, ExpectURL: cur.URL})
+		}
+	})
+	be.True(t, tab.WindowID > 0)
+
+	second, err := OpenURL(ctx, OpenURLInput{URL: pageURL, WindowID: tab.WindowID})
+	be.Err(t, err, nil)
+	be.Equal(t, second.WindowID, tab.WindowID)
+	be.Equal(t, second.Index, tab.Index+1)
+
+	be.Err(t, ActivateTab(ctx, second.TabRef), nil)
+
+	// Wait for the page to load before reading it.
+	var got Tab
+	for range 50 {
+		if got, err = GetTab(ctx, second.TabRef); err == nil && got.Title == "CUHTest_Safari" {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	be.Equal(t, got.Title, "CUHTest_Safari")
+	be.True(t, got.Current)
+
+	tabs, err := ListTabs(ctx)
+	be.Err(t, err, nil)
+	found := 0
+	for _, x := range tabs {
+		if x.WindowID == tab.WindowID {
+			found++
+		}
+	}
+	be.Equal(t, found, 2)
+
+	src, err := PageSource(ctx, second.TabRef)
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(src, "cuh safari live test"))
+
+	_, err = CloseTab(ctx, CloseTabInput{Tab: second.TabRef, ExpectURL: "https://other.example/"})
+	be.True(t, errors.Is(err, ErrConflict))
+	preview, err := CloseTab(ctx, CloseTabInput{Tab: second.TabRef, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, preview.URL, got.URL)
+	_, err = CloseTab(ctx, CloseTabInput{Tab: second.TabRef, ExpectURL: got.URL})
+	be.Err(t, err, nil)
+
+	_, err = GetTab(ctx, second.TabRef)
+	be.True(t, errors.Is(err, ErrNotFound))
+}
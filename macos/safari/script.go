@@ -0,0 +1,157 @@
+//go:build darwin
+
+package safari
+
+import "github.com/spachava753/cuh/macos/internal/osascript"
+
+// scripts runs Safari's scripts. Its prelude is prepended to every script;
+// tabRow formats one tab as an [osascript.TabRow] record.
+var scripts = osascript.Runner{
+	Prelude:          scriptPrelude,
+	NotFound:         ErrNotFound,
+	PermissionDenied: ErrPermissionDenied,
+	Conflict:         ErrConflict,
+}
+
+const scriptPrelude = `on tabRow(w, t)
+	tell application "Safari"
+		set wid to id of w
+		set u to URL of t
+		if u is missing value then set u to ""
+		set n to name of t
+		if n is missing value then set n to ""
+		set isCurrent to false
+		try
+			set isCurrent to ((index of current tab of w) = (index of t))
+		end try
+		set isFront to ((id of front window) = wid)
+		return (wid as string) & US & ((index of t) as string) & US & (isCurrent as string) & US & (isFront as string) & US & u & US & n & RS
+	end tell
+end tabRow
+
+on requireRunning()
+	if application "Safari" is not running then error "Safari is not running" number -1728
+end requireRunning
+`
+
+const listTabsScript = `on run argv
+	if application "Safari" is not running then return ""
+	set out to ""
+	tell application "Safari"
+		repeat with w in windows
+			-- Windows without tabs, such as Settings, are skipped.
+			try
+				repeat with t in tabs of w
+					set out to out & my tabRow(w, t)
+				end repeat
+			end try
+		end repeat
+	end tell
+	return out
+end run`
+
+const getTabScript = `on run argv
+	my requireRunning()
+	tell application "Safari"
+		set w to window id ((item 1 of argv) as integer)
+		set t to tab ((item 2 of argv) as integer) of w
+		return my tabRow(w, t)
+	end tell
+end run`
+
+const openURLScript = `on run argv
+	set theURL to item 1 of argv
+	set newWindow to (item 2 of argv) is "true"
+	set wantID to (item 3 of argv) as integer
+	set doActivate to (item 4 of argv) is "true"
+	tell application "Safari"
+		set w to missing value
+		if wantID is not 0 then
+			set w to window id wantID
+		else if not newWindow then
+			repeat with candidate in windows
+				try
+					if (count of tabs of candidate) > 0 then
+						set w to window id (id of candidate)
+						exit repeat
+					end if
+				end try
+			end repeat
+			if w is missing value then set newWindow to true
+		end if
+		if newWindow then
+			make new document with properties {URL:theURL}
+			set w to window id (id of front window)
+			set t to current tab of w
+		else
+			set t to make new tab at end of tabs of w with properties {URL:theURL}
+			if doActivate then set current tab of w to t
+		end if
+		if doActivate then
+			set index of w to 1
+			activate
+		end if
+		return my tabRow(w, t)
+	end tell
+end run`
+
+const activateTabScript = `on run argv
+	my requireRunning()
+	tell application "Safari"
+		set w to window id ((item 1 of argv) as integer)
+		set current tab of w to tab ((item 2 of argv) as integer) of w
+		set index of w to 1
+		activate
+	end tell
+	return ""
+end run`
+
+// closeTabScript raises [osascript.ChangedErrorNumber] when the tab no
+// longer shows the URL in argv.
+const closeTabScript = `on run argv
+	my requireRunning()
+	tell application "Safari"
+		set wid to (item 1 of argv) as integer
+		set w to window id wid
+		set t to tab ((item 2 of argv) as integer) of w
+		set u to URL of t
+		if u is missing value then set u to ""
+		if u is not (item 3 of argv) then error "tab changed" number 1100
+		set countBefore to count of tabs of w
+		close t
+		set countAfter to 0
+		try
+			set countAfter to count of tabs of window id wid
+		end try
+	end tell
+	return (countBefore as string) & US & (countAfter as string)
+end run`
+
+const pageSourceScript = `on run argv
+	my requireRunning()
+	tell application "Safari"
+		set t to tab ((item 2 of argv) as integer) of window id ((item 1 of argv) as integer)
+		set s to source of t
+		if s is missing value then return ""
+		return s
+	end tell
+end run`
+
+// parseTabs decodes tabRow records.
+func parseTabs(out string) ([]Tab, error) {
+	rows, err := osascript.ParseTabRows(out)
+	if err != nil {
+		return nil, err
+	}
+	tabs := make([]Tab, len(rows))
+	for i, r := range rows {
+		tabs[i] = Tab{
+			TabRef:      TabRef{WindowID: r.WindowID, Index: r.Index},
+			Current:     r.Current,
+			FrontWindow: r.FrontWindow,
+			URL:         r.URL,
+			Title:       r.Title,
+		}
+	}
+	return tabs, nil
+}
@@ -0,0 +1,82 @@
+//go:build darwin
+
+// Package tabs provides agent-oriented primitives for finding, focusing, and
+// closing tabs across the browsers running on macOS.
+//
+// Agents tend to open a new tab for every task. This package lets them look
+// at what the user already has open first: find the tab showing a page and
+// bring it forward, or clean up the tabs they opened earlier. It works
+// against whichever supported browsers are running, without launching any.
+//
+// Supported browsers are Safari, driven through the safari package, and the
+// Chromium family ([Chrome], [ChromeCanary], [Chromium], [Brave], [Edge],
+// [Vivaldi]), which share one AppleScript dictionary. [SupportedBrowsers]
+// lists them.
+//
+// Primitive groups:
+//
+//   - Find/select: [ListTabs] returns every tab of every running browser
+//     with its browser, window, position, URL, and title; [GetTab] re-reads
+//     one tab.
+//   - Act: [FocusTab] brings a tab to the front and [CloseTab] closes one.
+//
+// Suggested import path from calling code:
+//
+//	import "github.com/spachava753/cuh/macos/tabs"
+//
+// # Tab References
+//
+// Browsers' scripting interfaces have no stable tab identifiers, so a
+// [TabRef] is a browser, a window id, and a 1-based position. Window ids stay
+// valid while the window is open, but positions shift when earlier tabs close
+// or tabs are reordered. List tabs right before acting on them, and pass the
+// URL you expect to [CloseTabInput].ExpectURL so a shifted ref fails with
+// [ErrConflict] instead of closing the wrong tab.
+//
+// # Safety Model
+//
+//   - [ListTabs] never launches a browser. Browsers that are not running
+//     are reported as such, and one browser failing, for example on
+//     permissions, does not hide the others' tabs.
+//   - [CloseTab] supports DryRun and the ExpectURL guard.
+//   - Mutations verify their effect by reading back from the browser and
+//     fail with [ErrVerificationFailed] if it did not stick.
+//   - The first call to each browser prompts the user to allow the calling
+//     app to control it. A refusal surfaces as [ErrPermissionDenied]; the
+//     grant lives in System Settings > Privacy & Security > Automation.
+//
+// # Composition Pattern
+//
+// Reuse a tab that already shows a page instead of opening another:
+//
+//	res, err := tabs.ListTabs(ctx, tabs.ListTabsInput{})
+//	if err != nil {
+//		return err
+//	}
+//	for _, t := range res.Tabs {
+//		if strings.HasPrefix(t.URL, "https://mail.example.com/") {
+//			_, err := tabs.FocusTab(ctx, t.TabRef)
+//			return err
+//		}
+//	}
+//
+// Close every tab showing a given site, checking each URL before closing.
+// Iterating in reverse keeps earlier positions in the same window valid:
+//
+//	for _, t := range slices.Backward(res.Tabs) {
+//		if !strings.HasPrefix(t.URL, "https://news.example.com/") {
+//			continue
+//		}
+//		if _, err := tabs.CloseTab(ctx, tabs.CloseTabInput{Tab: t.TabRef, ExpectURL: t.URL}); err != nil {
+//			return err
+//		}
+//	}
+//
+// # Error Handling Pattern
+//
+// Errors are typed sentinels ([ErrNotFound], [ErrPermissionDenied],
+// [ErrInvalidArgument], [ErrUnsupported], [ErrConflict],
+// [ErrVerificationFailed]) wrapped in [OpError] for operation context. Check
+// them with errors.Is; errors from Safari also match the safari package's
+// sentinels. [ErrorCode] maps an error to a stable string for JSON payloads.
+package tabs
@@ -0,0 +1,74 @@
+//go:build darwin
+
+package tabs
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// JSON encoding
+//
+// Public types carry snake_case JSON tags so they can be used directly as
+// agent tool payloads. Errors encode as {"op", "id", "code", "message"}
+// objects.
+
+// ErrorCode returns a stable snake_case code for err's sentinel cause, such
+// as "not_found" or "invalid_argument", or "internal" for other errors. It
+// returns "" for a nil error.
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrPermissionDenied):
+		return "permission_denied"
+	case errors.Is(err, ErrInvalidArgument):
+		return "invalid_argument"
+	case errors.Is(err, ErrUnsupported):
+		return "unsupported"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrVerificationFailed):
+		return "verification_failed"
+	default:
+		return "internal"
+	}
+}
+
+type errorJSON struct {
+	Op      string `json:"op,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	out := &errorJSON{Code: ErrorCode(err), Message: err.Error()}
+	var op *OpError
+	if errors.As(err, &op) {
+		out.Op, out.ID = op.Op, op.ID
+	}
+	return out
+}
+
+// MarshalJSON encodes the error as {"op", "id", "code", "message"}.
+func (e *OpError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newErrorJSON(e))
+}
+
+// MarshalJSON encodes Err as an "error" object.
+func (s BrowserStatus) MarshalJSON() ([]byte, error) {
+	type plain BrowserStatus
+	return json.Marshal(struct {
+		plain
+		Err *errorJSON `json:"error,omitempty"`
+	}{plain(s), newErrorJSON(s.Err)})
+}
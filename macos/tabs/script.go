@@ -0,0 +1,172 @@
+//go:build darwin
+
+package tabs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spachava753/cuh/macos/internal/osascript"
+)
+
+// scripts runs the tabs package's own scripts.
+var scripts = osascript.Runner{
+	NotFound:         ErrNotFound,
+	PermissionDenied: ErrPermissionDenied,
+	Conflict:         ErrConflict,
+}
+
+// runningScript reports, for each bundle id in argv, whether that application
+// is running. Testing by id never launches the application, and an id that is
+// not installed reads as not running.
+const runningScript = `on run argv
+	set out to ""
+	repeat with bid in argv
+		set isRunning to false
+		try
+			set isRunning to application id (bid as text) is running
+		end try
+		set out to out & (isRunning as string) & US
+	end repeat
+	return out
+end run`
+
+// chromiumPrelude holds the handlers shared by the Chromium scripts. The
+// application name is substituted for {{app}} by [chromiumScript], since a
+// tell block needs a literal name to load the browser's dictionary. tabRow
+// formats one tab as an [osascript.TabRow] record, with the active tab as
+// current.
+const chromiumPrelude = `on tabRow(w, t, i)
+	tell application "{{app}}"
+		set wid to id of w
+		set u to URL of t
+		if u is missing value then set u to ""
+		set n to title of t
+		if n is missing value then set n to ""
+		set isActive to ((active tab index of w) = i)
+		set isFront to ((id of front window) = wid)
+		return (wid as string) & US & (i as string) & US & (isActive as string) & US & (isFront as string) & US & u & US & n & RS
+	end tell
+end tabRow
+
+on requireRunning()
+	if application "{{app}}" is not running then error "{{app}} is not running" number -1728
+end requireRunning
+`
+
+const listTabsScript = `on run argv
+	if application "{{app}}" is not running then return ""
+	set out to ""
+	tell application "{{app}}"
+		repeat with w in windows
+			set i to 0
+			repeat with t in tabs of w
+				set i to i + 1
+				set out to out & my tabRow(w, t, i)
+			end repeat
+		end repeat
+	end tell
+	return out
+end run`
+
+const getTabScript = `on run argv
+	my requireRunning()
+	tell application "{{app}}"
+		set w to window id ((item 1 of argv) as integer)
+		set i to (item 2 of argv) as integer
+		return my tabRow(w, tab i of w, i)
+	end tell
+end run`
+
+const focusTabScript = `on run argv
+	my requireRunning()
+	tell application "{{app}}"
+		set w to window id ((item 1 of argv) as integer)
+		set i to (item 2 of argv) as integer
+		get tab i of w
+		set active tab index of w to i
+		set index of w to 1
+		activate
+	end tell
+	return ""
+end run`
+
+// closeTabScript raises [osascript.ChangedErrorNumber] when the tab no
+// longer shows the URL in argv.
+const closeTabScript = `on run argv
+	my requireRunning()
+	tell application "{{app}}"
+		set wid to (item 1 of argv) as integer
+		set w to window id wid
+		set t to tab ((item 2 of argv) as integer) of w
+		set u to URL of t
+		if u is missing value then set u to ""
+		if u is not (item 3 of argv) then error "tab changed" number 1100
+		set countBefore to count of tabs of w
+		close t
+		set countAfter to 0
+		try
+			set countAfter to count of tabs of window id wid
+		end try
+	end tell
+	return (countBefore as string) & US & (countAfter as string)
+end run`
+
+// chromiumScript returns script with the Chromium handlers, addressed to
+// browser b.
+func chromiumScript(b Browser, script string) string {
+	return strings.ReplaceAll(chromiumPrelude+script, "{{app}}", string(b))
+}
+
+// runningBrowsers reports which of browsers are running.
+func runningBrowsers(ctx context.Context, browsers []Browser) (map[Browser]bool, error) {
+	ids := make([]string, 0, len(browsers))
+	for _, b := range browsers {
+		for _, e := range bundleIDs {
+			if e.browser == b {
+				ids = append(ids, e.bundleID)
+			}
+		}
+	}
+	out, err := scripts.Run(ctx, runningScript, ids...)
+	if err != nil {
+		return nil, err
+	}
+	return parseRunning(browsers, out)
+}
+
+// parseRunning decodes runningScript output for browsers.
+func parseRunning(browsers []Browser, out string) (map[Browser]bool, error) {
+	f := strings.Split(strings.TrimSuffix(out, osascript.FieldSep), osascript.FieldSep)
+	if out == "" {
+		f = nil
+	}
+	if len(f) != len(browsers) {
+		return nil, fmt.Errorf("unexpected script output %q", out)
+	}
+	running := make(map[Browser]bool, len(browsers))
+	for i, b := range browsers {
+		running[b] = f[i] == "true"
+	}
+	return running, nil
+}
+
+// parseTabs decodes tabRow records for browser b.
+func parseTabs(b Browser, out string) ([]Tab, error) {
+	rows, err := osascript.ParseTabRows(out)
+	if err != nil {
+		return nil, err
+	}
+	tabs := make([]Tab, len(rows))
+	for i, r := range rows {
+		tabs[i] = Tab{
+			TabRef:      TabRef{Browser: b, WindowID: r.WindowID, Index: r.Index},
+			Active:      r.Current,
+			FrontWindow: r.FrontWindow,
+			URL:         r.URL,
+			Title:       r.Title,
+		}
+	}
+	return tabs, nil
+}
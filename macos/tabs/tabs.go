@@ -0,0 +1,354 @@
+//go:build darwin
+
+package tabs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spachava753/cuh/macos/internal/osascript"
+	"github.com/spachava753/cuh/macos/safari"
+)
+
+// Typed package-level errors.
+var (
+	// ErrNotFound indicates the browser is not running or the window or tab
+	// does not exist.
+	ErrNotFound = errors.New("tabs: not found")
+	// ErrPermissionDenied indicates the calling process is not allowed to
+	// control the browser. Grant it under System Settings > Privacy &
+	// Security > Automation.
+	ErrPermissionDenied = errors.New("tabs: permission denied")
+	// ErrInvalidArgument indicates a caller-provided input was invalid.
+	ErrInvalidArgument = errors.New("tabs: invalid argument")
+	// ErrUnsupported indicates the browser is not one this package can
+	// control.
+	ErrUnsupported = errors.New("tabs: unsupported browser")
+	// ErrConflict indicates the tab no longer shows the expected page, usually
+	// because tabs were opened or closed since it was listed.
+	ErrConflict = errors.New("tabs: conflict")
+	// ErrVerificationFailed indicates read-after-write verification failed.
+	ErrVerificationFailed = errors.New("tabs: verification failed")
+)
+
+// OpError captures operation-level failures with typed causes.
+type OpError struct {
+	Op  string
+	ID  string
+	Err error
+}
+
+func (e *OpError) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.ID != "" {
+		return fmt.Sprintf("tabs: %s (%s): %v", e.Op, e.ID, e.Err)
+	}
+	return fmt.Sprintf("tabs: %s: %v", e.Op, e.Err)
+}
+
+func (e *OpError) Unwrap() error { return e.Err }
+
+func newInvalidArg(op, id, message string) error {
+	return &OpError{Op: op, ID: id, Err: fmt.Errorf("%w: %s", ErrInvalidArgument, strings.TrimSpace(message))}
+}
+
+// Browser is a supported browser, named as its application.
+type Browser string
+
+const (
+	Safari       Browser = "Safari"
+	Chrome       Browser = "Google Chrome"
+	ChromeCanary Browser = "Google Chrome Canary"
+	Chromium     Browser = "Chromium"
+	Brave        Browser = "Brave Browser"
+	Edge         Browser = "Microsoft Edge"
+	Vivaldi      Browser = "Vivaldi"
+)
+
+// bundleIDs maps each supported browser to its bundle identifier, in the
+// order results are reported.
+var bundleIDs = []struct {
+	browser  Browser
+	bundleID string
+}{
+	{Safari, "com.apple.Safari"},
+	{Chrome, "com.google.Chrome"},
+	{ChromeCanary, "com.google.Chrome.canary"},
+	{Chromium, "org.chromium.Chromium"},
+	{Brave, "com.brave.Browser"},
+	{Edge, "com.microsoft.edgemac"},
+	{Vivaldi, "com.vivaldi.Vivaldi"},
+}
+
+// SupportedBrowsers lists the browsers this package controls. Safari is
+// driven through the [safari] package; the others share Chromium's
+// AppleScript dictionary.
+func SupportedBrowsers() []Browser {
+	out := make([]Browser, len(bundleIDs))
+	for i, b := range bundleIDs {
+		out[i] = b.browser
+	}
+	return out
+}
+
+func (b Browser) supported() bool {
+	return slices.Contains(SupportedBrowsers(), b)
+}
+
+// TabRef addresses a tab by browser, window id, and 1-based position in that
+// window. Window ids are stable while the window is open; positions shift
+// when earlier tabs close, so refs from [ListTabs] should be used promptly.
+type TabRef struct {
+	Browser  Browser `json:"browser"`
+	WindowID int     `json:"window_id"`
+	Index    int     `json:"index"`
+}
+
+// String formats the ref as "browser:window/index", the form used in
+// [OpError] IDs.
+func (r TabRef) String() string {
+	return string(r.Browser) + ":" + strconv.Itoa(r.WindowID) + "/" + strconv.Itoa(r.Index)
+}
+
+func (r TabRef) validate(op string) error {
+	if !r.Browser.supported() {
+		return &OpError{Op: op, ID: r.String(), Err: fmt.Errorf("%w: %q", ErrUnsupported, r.Browser)}
+	}
+	if r.WindowID <= 0 || r.Index <= 0 {
+		return newInvalidArg(op, r.String(), "window id and index must be positive")
+	}
+	return nil
+}
+
+// Tab is one open browser tab.
+type Tab struct {
+	TabRef
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	// Active is true for the selected tab of its window.
+	Active bool `json:"active"`
+	// FrontWindow is true when the tab's window is its browser's front
+	// window.
+	FrontWindow bool `json:"front_window"`
+}
+
+// ListTabsInput selects the browsers [ListTabs] reads.
+type ListTabsInput struct {
+	// Browsers limits the listing. Empty means every supported browser.
+	Browsers []Browser `json:"browsers,omitempty"`
+}
+
+// BrowserStatus reports how listing one browser went.
+type BrowserStatus struct {
+	Browser Browser `json:"browser"`
+	Running bool    `json:"running"`
+	// Tabs is the number of tabs listed for the browser.
+	Tabs int `json:"tabs"`
+	// Err is set when the browser is running but could not be read, for
+	// example with [ErrPermissionDenied].
+	Err error `json:"-"`
+}
+
+// ListTabsResult is the outcome of [ListTabs].
+type ListTabsResult struct {
+	// Tabs lists every tab, grouped by browser in [SupportedBrowsers]
+	// order, front window first and tabs in window order.
+	Tabs []Tab `json:"tabs"`
+	// Browsers has one entry per browser considered.
+	Browsers []BrowserStatus `json:"browsers"`
+}
+
+// ListTabs returns the tabs of every running browser in input. Browsers that
+// are not running are skipped without being launched. A browser that cannot
+// be read does not fail the call; its error is reported in Browsers.
+func ListTabs(ctx context.Context, input ListTabsInput) (ListTabsResult, error) {
+	for _, b := range input.Browsers {
+		if !b.supported() {
+			return ListTabsResult{}, &OpError{Op: "ListTabs", ID: string(b), Err: fmt.Errorf("%w: %q", ErrUnsupported, b)}
+		}
+	}
+	var selected []Browser
+	for _, b := range SupportedBrowsers() {
+		if len(input.Browsers) == 0 || slices.Contains(input.Browsers, b) {
+			selected = append(selected, b)
+		}
+	}
+	running, err := runningBrowsers(ctx, selected)
+	if err != nil {
+		return ListTabsResult{}, &OpError{Op: "ListTabs", Err: err}
+	}
+	res := ListTabsResult{Tabs: []Tab{}}
+	for _, b := range selected {
+		st := BrowserStatus{Browser: b, Running: running[b]}
+		if st.Running {
+			tabs, err := listBrowserTabs(ctx, b)
+			if err != nil {
+				st.Err = &OpError{Op: "ListTabs", ID: string(b), Err: err}
+			}
+			st.Tabs = len(tabs)
+			res.Tabs = append(res.Tabs, tabs...)
+		}
+		res.Browsers = append(res.Browsers, st)
+	}
+	if err := ctx.Err(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// GetTab returns the tab at ref.
+func GetTab(ctx context.Context, ref TabRef) (Tab, error) {
+	if err := ref.validate("GetTab"); err != nil {
+		return Tab{}, err
+	}
+	tab, err := getTab(ctx, ref)
+	if err != nil {
+		return Tab{}, &OpError{Op: "GetTab", ID: ref.String(), Err: err}
+	}
+	return tab, nil
+}
+
+// FocusTab selects the tab at ref, raises its window, and brings the browser
+// to the front. It reads the tab back and fails with [ErrVerificationFailed]
+// if it is not the active tab of the front window.
+func FocusTab(ctx context.Context, ref TabRef) (Tab, error) {
+	if err := ref.validate("FocusTab"); err != nil {
+		return Tab{}, err
+	}
+	var err error
+	if ref.Browser == Safari {
+		err = fromSafari(safari.ActivateTab(ctx, safari.TabRef{WindowID: ref.WindowID, Index: ref.Index}))
+	} else {
+		_, err = scripts.Run(ctx, chromiumScript(ref.Browser, focusTabScript), strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index))
+	}
+	if err != nil {
+		return Tab{}, &OpError{Op: "FocusTab", ID: ref.String(), Err: err}
+	}
+	tab, err := getTab(ctx, ref)
+	if err != nil {
+		return Tab{}, &OpError{Op: "FocusTab", ID: ref.String(), Err: err}
+	}
+	if !tab.Active || !tab.FrontWindow {
+		return tab, &OpError{Op: "FocusTab", ID: ref.String(), Err: fmt.Errorf("%w: tab is not the active tab of the front window", ErrVerificationFailed)}
+	}
+	return tab, nil
+}
+
+// CloseTabInput specifies a tab to close.
+type CloseTabInput struct {
+	Tab TabRef `json:"tab"`
+	// ExpectURL, when set, must equal the tab's current URL. It guards
+	// against closing the wrong tab after positions shift; a mismatch fails
+	// with [ErrConflict].
+	ExpectURL string `json:"expect_url,omitempty"`
+	// DryRun resolves and checks the tab without closing it.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// CloseTab closes one tab and returns it as it was before closing. Closing
+// the last tab of a window closes the window. The browser's tab count is
+// read back and the call fails with [ErrVerificationFailed] if it did not
+// drop.
+func CloseTab(ctx context.Context, input CloseTabInput) (Tab, error) {
+	ref := input.Tab
+	if err := ref.validate("CloseTab"); err != nil {
+		return Tab{}, err
+	}
+	tab, err := getTab(ctx, ref)
+	if err != nil {
+		return Tab{}, &OpError{Op: "CloseTab", ID: ref.String(), Err: err}
+	}
+	if input.ExpectURL != "" && tab.URL != input.ExpectURL {
+		return tab, &OpError{Op: "CloseTab", ID: ref.String(), Err: fmt.Errorf("%w: tab shows %q, expected %q", ErrConflict, tab.URL, input.ExpectURL)}
+	}
+	if input.DryRun {
+		return tab, nil
+	}
+	if ref.Browser == Safari {
+		_, err = safari.CloseTab(ctx, safari.CloseTabInput{Tab: safari.TabRef{WindowID: ref.WindowID, Index: ref.Index}, ExpectURL: tab.URL})
+		err = fromSafari(err)
+	} else {
+		var out string
+		out, err = scripts.Run(ctx, chromiumScript(ref.Browser, closeTabScript), strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index), tab.URL)
+		if before, after, ok := strings.Cut(out, osascript.FieldSep); err == nil && (!ok || before == after) {
+			err = fmt.Errorf("%w: tab count unchanged", ErrVerificationFailed)
+		}
+	}
+	if err != nil {
+		return tab, &OpError{Op: "CloseTab", ID: ref.String(), Err: err}
+	}
+	return tab, nil
+}
+
+// listBrowserTabs lists the tabs of one running browser.
+func listBrowserTabs(ctx context.Context, b Browser) ([]Tab, error) {
+	if b == Safari {
+		st, err := safari.ListTabs(ctx)
+		if err != nil {
+			return nil, fromSafari(err)
+		}
+		out := make([]Tab, len(st))
+		for i, t := range st {
+			out[i] = fromSafariTab(t)
+		}
+		return out, nil
+	}
+	out, err := scripts.Run(ctx, chromiumScript(b, listTabsScript))
+	if err != nil {
+		return nil, err
+	}
+	return parseTabs(b, out)
+}
+
+// getTab reads the tab at a validated ref.
+func getTab(ctx context.Context, ref TabRef) (Tab, error) {
+	if ref.Browser == Safari {
+		t, err := safari.GetTab(ctx, safari.TabRef{WindowID: ref.WindowID, Index: ref.Index})
+		if err != nil {
+			return Tab{}, fromSafari(err)
+		}
+		return fromSafariTab(t), nil
+	}
+	out, err := scripts.Run(ctx, chromiumScript(ref.Browser, getTabScript), strconv.Itoa(ref.WindowID), strconv.Itoa(ref.Index))
+	if err != nil {
+		return Tab{}, err
+	}
+	tabs, err := parseTabs(ref.Browser, out)
+	if err != nil || len(tabs) != 1 {
+		return Tab{}, fmt.Errorf("unexpected script output %q", out)
+	}
+	return tabs[0], nil
+}
+
+func fromSafariTab(t safari.Tab) Tab {
+	return Tab{
+		TabRef:      TabRef{Browser: Safari, WindowID: t.WindowID, Index: t.Index},
+		URL:         t.URL,
+		Title:       t.Title,
+		Active:      t.Current,
+		FrontWindow: t.FrontWindow,
+	}
+}
+
+// fromSafari adds this package's sentinel to an error from the safari
+// package, keeping the original in the chain.
+func fromSafari(err error) error {
+	for _, m := range []struct{ from, to error }{
+		{safari.ErrNotFound, ErrNotFound},
+		{safari.ErrPermissionDenied, ErrPermissionDenied},
+		{safari.ErrInvalidArgument, ErrInvalidArgument},
+		{safari.ErrConflict, ErrConflict},
+		{safari.ErrVerificationFailed, ErrVerificationFailed},
+	} {
+		if errors.Is(err, m.from) {
+			return fmt.Errorf("%w: %w", m.to, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+//go:build darwin
+
+package tabs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nalgeon/be"
+	"github.com/spachava753/cuh/macos/safari"
+)
+
+// requireLive skips tests that open and close real browser tabs unless
+// CUH_TABS_LIVE=1.
+func requireLive(t *testing.T) {
+	t.Helper()
+	if os.Getenv("CUH_TABS_LIVE") != "1" {
+		t.Skip("set CUH_TABS_LIVE=1 to run tabs live tests")
+	}
+}
+
+func TestParseTabs(t *testing.T) {
+	out := "12\x1f1\x1ftrue\x1ftrue\x1fhttps://example.com/\x1fExample Domain\x1e" +
+		"12\x1f2\x1ffalse\x1ftrue\x1f\x1f\x1e" +
+		"40\x1f1\x1ftrue\x1ffalse\x1fhttps://a.test/?q=1\x1fTitle with \x1f separator\x1e"
+	tabs, err := parseTabs(Brave, out)
+	be.Err(t, err, nil)
+	be.Equal(t, tabs, []Tab{
+		{TabRef: TabRef{Browser: Brave, WindowID: 12, Index: 1}, URL: "https://example.com/", Title: "Example Domain", Active: true, FrontWindow: true},
+		{TabRef: TabRef{Browser: Brave, WindowID: 12, Index: 2}, FrontWindow: true},
+		{TabRef: TabRef{Browser: Brave, WindowID: 40, Index: 1}, URL: "https://a.test/?q=1", Title: "Title with \x1f separator", Active: true},
+	})
+
+	tabs, err = parseTabs(Chrome, "")
+	be.Err(t, err, nil)
+	be.Equal(t, len(tabs), 0)
+
+	_, err = parseTabs(Chrome, "x\x1f1\x1ftrue\x1ftrue\x1fu\x1fn\x1e")
+	be.Err(t, err)
+	_, err = parseTabs(Chrome, "1\x1f1\x1e")
+	be.Err(t, err)
+}
+
+func TestParseRunning(t *testing.T) {
+	running, err := parseRunning([]Browser{Safari, Chrome, Edge}, "false\x1ftrue\x1ffalse\x1f")
+	be.Err(t, err, nil)
+	be.Equal(t, running, map[Browser]bool{Safari: false, Chrome: true, Edge: false})
+
+	running, err = parseRunning(nil, "")
+	be.Err(t, err, nil)
+	be.Equal(t, len(running), 0)
+
+	_, err = parseRunning([]Browser{Safari, Chrome}, "true\x1f")
+	be.Err(t, err)
+}
+
+func TestClassifyScriptError(t *testing.T) {
+	cases := []struct {
+		msg  string
+		want error
+	}{
+		{"execution error: Google Chrome got an error: Can’t get window id 99. (-1728)", ErrNotFound},
+		{"execution error: Google Chrome got an error: Can’t get tab 9 of window id 1. Invalid index. (-1719)", ErrNotFound},
+		{"execution error: Not authorized to send Apple events to Brave Browser. (-1743)", ErrPermissionDenied},
+		{"execution error: tab changed (1100)", ErrConflict},
+	}
+	for _, c := range cases {
+		be.True(t, errors.Is(scripts.Classify(c.msg), c.want))
+	}
+	err := scripts.Classify("execution error: boom (-2700)")
+	be.True(t, !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrConflict))
+}
+
+func TestFromSafari(t *testing.T) {
+	err := fromSafari(&safari.OpError{Op: "GetTab", Err: safari.ErrNotFound})
+	be.True(t, errors.Is(err, ErrNotFound))
+	be.True(t, errors.Is(err, safari.ErrNotFound))
+	be.Equal(t, ErrorCode(err), "not_found")
+}
+
+func TestInvalidArguments(t *testing.T) {
+	ctx := context.Background()
+	_, err := GetTab(ctx, TabRef{Browser: Chrome})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = GetTab(ctx, TabRef{Browser: "Netscape Navigator", WindowID: 1, Index: 1})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, err = FocusTab(ctx, TabRef{Browser: Safari, Index: 1})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = CloseTab(ctx, CloseTabInput{})
+	be.True(t, errors.Is(err, ErrUnsupported))
+	_, err = CloseTab(ctx, CloseTabInput{Tab: TabRef{Browser: Edge}})
+	be.True(t, errors.Is(err, ErrInvalidArgument))
+	_, err = ListTabs(ctx, ListTabsInput{Browsers: []Browser{Chrome, "Firefox"}})
+	be.True(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestBrowserStatusJSON(t *testing.T) {
+	data, err := json.Marshal(BrowserStatus{
+		Browser: Chrome,
+		Running: true,
+		Err:     &OpError{Op: "ListTabs", ID: string(Chrome), Err: ErrPermissionDenied},
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, string(data), `{"browser":"Google Chrome","running":true,"tabs":0,"error":{"op":"ListTabs","id":"Google Chrome","code":"permission_denied","message":"tabs: ListTabs (Google Chrome): tabs: permission denied"}}`)
+}
+
+// TestTabLifecycle opens a page in the first running browser with windows,
+// then finds, focuses, and closes it through this package.
+func TestTabLifecycle(t *testing.T) {
+	requireLive(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	res, err := ListTabs(ctx, ListTabsInput{})
+	be.Err(t, err, nil)
+	var target Tab
+	for _, tab := range res.Tabs {
+		if tab.FrontWindow && tab.Active {
+			target = tab
+			break
+		}
+	}
+	if target.Browser == "" {
+		t.Skip("no running browser has an open window")
+	}
+	for _, st := range res.Browsers {
+		be.Err(t, st.Err, nil)
+	}
+
+	page := filepath.Join(t.TempDir(), "page.html")
+	be.Err(t, os.WriteFile(page, []byte("<title>CUHTest_Tabs</title><p>cuh tabs live test</p>"), 0o644), nil)
+	pageURL := (&url.URL{Scheme: "file", Path: page}).String()
+	be.Err(t, openTab(ctx, target.Browser, target.WindowID, pageURL), nil)
+
+	var opened Tab
+	for range 50 {
+		if res, err = ListTabs(ctx, ListTabsInput{Browsers: []Browser{target.Browser}}); err == nil {
+			for _, tab := range res.Tabs {
+				if tab.Title == "CUHTest_Tabs" {
+					opened = tab
+				}
+			}
+		}
+		if opened.Browser != "" {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	be.Equal(t, opened.Title, "CUHTest_Tabs")
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if cur, err := GetTab(ctx, opened.TabRef); err == nil && cur.Title == "CUHTest_Tabs" {
+			CloseTab(ctx, CloseTabInput{Tab: cur.TabRef, ExpectURL: cur.URL})
+		}
+	})
+
+	focused, err := FocusTab(ctx, opened.TabRef)
+	be.Err(t, err, nil)
+	be.True(t, focused.Active && focused.FrontWindow)
+
+	_, err = CloseTab(ctx, CloseTabInput{Tab: opened.TabRef, ExpectURL: "https://other.example/"})
+	be.True(t, errors.Is(err, ErrConflict))
+	preview, err := CloseTab(ctx, CloseTabInput{Tab: opened.TabRef, DryRun: true})
+	be.Err(t, err, nil)
+	be.Equal(t, preview.URL, opened.URL)
+	_, err = CloseTab(ctx, CloseTabInput{Tab: opened.TabRef, ExpectURL: opened.URL})
+	be.Err(t, err, nil)
+
+	if cur, err := GetTab(ctx, opened.TabRef); err == nil {
+		be.True(t, cur.Title != "CUHTest_Tabs")
+	}
+}
+
+// openTab opens pageURL in a new tab of window wid of browser b.
+func openTab(ctx context.Context, b Browser, wid int, pageURL string) error {
+	if b == Safari {
+		_, err := safari.OpenURL(ctx, safari.OpenURLInput{URL: pageURL, WindowID: wid})
+		return err
+	}
+	_, err := scripts.Run(ctx, strings.ReplaceAll(`on run argv
+	tell application "{{app}}"
+		make new tab at end of tabs of window id ((item 1 of argv) as integer) with properties {URL:(item 2 of argv)}
+	end tell
+end run`, "{{app}}", string(b)), strconv.Itoa(wid), pageURL)
+	return err
+}